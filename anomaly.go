@@ -0,0 +1,240 @@
+package errorlogger
+
+import (
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// anomalyKey marks the synthetic entries logged by an
+// AnomalyDetector so that they are not counted themselves.
+const anomalyKey = "anomaly"
+
+// variablePart matches the parts of a message that vary
+// between occurrences of the same error: hex ids and
+// numbers.
+var variablePart = regexp.MustCompile(`\b[0-9a-fA-F]{8,}\b|\d+`)
+
+// Fingerprint returns a short, stable identifier for the
+// kind of entry: a hash of its level and its message with
+// numbers and hex ids removed, so that "timeout after 30s
+// on conn 12" and "timeout after 31s on conn 7" share a
+// fingerprint.
+func Fingerprint(entry *Entry) string {
+	return fingerprint(entry.Level.String(), variablePart.ReplaceAllString(entry.Message, "#"))
+}
+
+// fingerprint returns the hex FNV-1a hash of kind and msg,
+// as used by Fingerprint and ErrFingerprint.
+func fingerprint(kind, msg string) string {
+	h := fnv.New64a()
+	h.Write([]byte(kind))
+	h.Write([]byte{0})
+	h.Write([]byte(msg))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+type (
+	// AnomalyOptions configures an AnomalyDetector. Zero
+	// values select the defaults.
+	AnomalyOptions struct {
+		// Interval is the period over which rates are
+		// measured. The default is one minute.
+		Interval time.Duration
+
+		// Alpha is the EWMA smoothing factor in (0, 1]
+		// applied to the count of each completed interval.
+		// The default is 0.3.
+		Alpha float64
+
+		// Factor is how many times the baseline rate the
+		// current rate must reach to be a spike. The default
+		// is 3.
+		Factor float64
+
+		// MinCount is the minimum number of entries in an
+		// interval for a spike, so that low volume noise is
+		// ignored. The default is 10.
+		MinCount int
+
+		// Levels are the levels that are tracked. The default
+		// is WarnLevel and above.
+		Levels []Level
+
+		// Fingerprint groups entries. The default is
+		// Fingerprint.
+		Fingerprint func(*Entry) string
+	}
+
+	// Spike describes a rate spike of a fingerprint.
+	Spike struct {
+		Fingerprint string
+		Message     string // message of the entry that triggered the spike
+		Count       int    // entries in the current interval
+		Baseline    float64
+		At          time.Time
+	}
+
+	// AnomalyDetector is a logrus hook that tracks the rate
+	// of each entry fingerprint with an exponentially
+	// weighted moving average and reports a Spike when the
+	// count in the current interval exceeds Factor times the
+	// average, giving early warning before anyone notices a
+	// dashboard.
+	//
+	//  d := NewAnomalyDetector(log, AnomalyOptions{Factor: 5})
+	//  d.OnSpike(func(s Spike) { pager.Notify(s) })
+	//
+	// By default, a spike is logged as a warning to the
+	// logger the detector is attached to. Each fingerprint
+	// spikes at most once per interval. A fingerprint seen
+	// for the first time has a baseline of zero, so a new
+	// kind of error that reaches MinCount in one interval is
+	// also a spike.
+	//
+	// Once per interval, the detector forgets the fingerprints
+	// that were idle for the whole interval and whose baseline
+	// is too low to prevent a spike, so that the memory it
+	// uses is bounded by the number of recent fingerprints.
+	AnomalyDetector struct {
+		mu      sync.Mutex
+		opts    AnomalyOptions
+		rates   map[string]*fingerprintRate
+		swept   time.Time
+		onSpike func(Spike)
+		now     func() time.Time
+	}
+
+	// fingerprintRate is the rate state of a fingerprint.
+	fingerprintRate struct {
+		windowStart time.Time
+		count       int
+		ewma        float64
+		spiked      bool
+	}
+)
+
+// NewAnomalyDetector returns a new AnomalyDetector that is
+// added as a hook to e.
+func NewAnomalyDetector(e ErrorLogger, opts AnomalyOptions) *AnomalyDetector {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.Alpha <= 0 || opts.Alpha > 1 {
+		opts.Alpha = 0.3
+	}
+	if opts.Factor <= 0 {
+		opts.Factor = 3
+	}
+	if opts.MinCount <= 0 {
+		opts.MinCount = 10
+	}
+	if len(opts.Levels) == 0 {
+		opts.Levels = []Level{PanicLevel, FatalLevel, ErrorLevel, WarnLevel}
+	}
+	if opts.Fingerprint == nil {
+		opts.Fingerprint = Fingerprint
+	}
+
+	d := &AnomalyDetector{
+		opts:  opts,
+		rates: make(map[string]*fingerprintRate),
+		now:   time.Now,
+	}
+	d.onSpike = func(s Spike) {
+		e.WithFields(Fields{
+			anomalyKey:    true,
+			"fingerprint": s.Fingerprint,
+			"count":       s.Count,
+			"baseline":    math.Round(s.Baseline*100) / 100,
+			"sample":      s.Message,
+		}).Warn("error rate spike")
+	}
+	e.AddHook(d)
+	return d
+}
+
+// OnSpike sets the function called when a fingerprint
+// spikes.
+func (d *AnomalyDetector) OnSpike(fn func(Spike)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onSpike = fn
+}
+
+// Levels returns the levels the detector fires on.
+func (d *AnomalyDetector) Levels() []Level { return d.opts.Levels }
+
+// Fire counts entry against its fingerprint.
+func (d *AnomalyDetector) Fire(entry *Entry) error {
+	if _, ok := entry.Data[anomalyKey]; ok {
+		return nil
+	}
+	fp := d.opts.Fingerprint(entry)
+
+	d.mu.Lock()
+	now := d.now()
+	if now.Sub(d.swept) >= d.opts.Interval {
+		d.evict(now)
+	}
+	r, ok := d.rates[fp]
+	if !ok {
+		r = &fingerprintRate{windowStart: now}
+		d.rates[fp] = r
+	}
+	d.roll(r, now)
+	r.count++
+
+	var spike *Spike
+	if !r.spiked && r.count >= d.opts.MinCount && float64(r.count) > d.opts.Factor*r.ewma {
+		r.spiked = true
+		spike = &Spike{Fingerprint: fp, Message: entry.Message, Count: r.count, Baseline: r.ewma, At: now}
+	}
+	fn := d.onSpike
+	d.mu.Unlock()
+
+	if spike != nil && fn != nil {
+		fn(*spike)
+	}
+	return nil
+}
+
+// roll folds the counts of completed intervals, including
+// empty ones, into the moving average.
+func (d *AnomalyDetector) roll(r *fingerprintRate, now time.Time) {
+	elapsed := int(now.Sub(r.windowStart) / d.opts.Interval)
+	if elapsed <= 0 {
+		return
+	}
+	a := d.opts.Alpha
+	r.ewma = a*float64(r.count) + (1-a)*r.ewma
+	if elapsed > 1 {
+		r.ewma *= math.Pow(1-a, float64(elapsed-1))
+	}
+	r.windowStart = r.windowStart.Add(time.Duration(elapsed) * d.opts.Interval)
+	r.count = 0
+	r.spiked = false
+}
+
+// evict removes the fingerprints with no entries in the
+// current interval and a baseline below MinCount/Factor.
+// Removing them does not change which entries spike: a
+// spike needs at least MinCount entries, which is more than
+// Factor times such a baseline, and the baseline only
+// decreases while a fingerprint is idle.
+func (d *AnomalyDetector) evict(now time.Time) {
+	for fp, r := range d.rates {
+		d.roll(r, now)
+		if r.count == 0 && d.opts.Factor*r.ewma < float64(d.opts.MinCount) {
+			delete(d.rates, fp)
+		}
+	}
+	d.swept = now
+}
+
+var _ logrus.Hook = (*AnomalyDetector)(nil)
@@ -0,0 +1,116 @@
+package errorlogger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFingerprint(t *testing.T) {
+	a := &Entry{Level: ErrorLevel, Message: "timeout after 30s on conn 12"}
+	b := &Entry{Level: ErrorLevel, Message: "timeout after 31s on conn 7"}
+	c := &Entry{Level: WarnLevel, Message: "timeout after 31s on conn 7"}
+	d := &Entry{Level: ErrorLevel, Message: "request deadbeef01 failed"}
+	e := &Entry{Level: ErrorLevel, Message: "request cafebabe02 failed"}
+
+	if Fingerprint(a) != Fingerprint(b) {
+		t.Error("Fingerprint() differs for messages that differ only in numbers")
+	}
+	if Fingerprint(b) == Fingerprint(c) {
+		t.Error("Fingerprint() is equal for different levels")
+	}
+	if Fingerprint(d) != Fingerprint(e) {
+		t.Error("Fingerprint() differs for messages that differ only in hex ids")
+	}
+}
+
+func TestAnomalyDetector(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	d := NewAnomalyDetector(e, AnomalyOptions{Interval: time.Minute, Alpha: 1, Factor: 3, MinCount: 4})
+	now := time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	var spikes []Spike
+	defaultOnSpike := d.onSpike
+	d.OnSpike(func(s Spike) {
+		spikes = append(spikes, s)
+		defaultOnSpike(s)
+	})
+
+	logN := func(n int, msg string) {
+		for i := 0; i < n; i++ {
+			e.Error(msg)
+		}
+	}
+
+	// With Alpha 1 the baseline is the previous count: the first
+	// interval is new, so it spikes once.
+	logN(4, "db timeout 1")
+	now = now.Add(time.Minute)
+	logN(4, "db timeout 2")
+	if len(spikes) != 1 {
+		t.Fatalf("spikes after warm up = %d, want 1", len(spikes))
+	}
+
+	// 12 is not more than 3x the baseline of 4; 13 is.
+	now = now.Add(time.Minute)
+	logN(12, "db timeout 3")
+	if len(spikes) != 1 {
+		t.Fatalf("spikes at 3x baseline = %d, want 1", len(spikes))
+	}
+	logN(5, "db timeout 4")
+	if len(spikes) != 2 {
+		t.Fatalf("spikes above 3x baseline = %d, want 2 (once per interval)", len(spikes))
+	}
+	if s := spikes[1]; s.Count != 13 || s.Baseline != 4 {
+		t.Errorf("spike = %+v, want count 13 and baseline 4", s)
+	}
+
+	// Info entries and other fingerprints are not counted.
+	e.Info("db timeout 5")
+	logN(3, "disk full")
+	if len(spikes) != 2 {
+		t.Errorf("spikes = %d, want 2", len(spikes))
+	}
+
+	if got := strings.Count(buf.String(), "error rate spike"); got != 2 {
+		t.Errorf("logged %d spike warnings, want 2", got)
+	}
+}
+
+func TestAnomalyDetector_evict(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	d := NewAnomalyDetector(e, AnomalyOptions{Interval: time.Minute, Alpha: 0.5, Factor: 2, MinCount: 4})
+	now := time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+	d.OnSpike(func(Spike) {})
+
+	for i := 0; i < 100; i++ {
+		e.Errorf("error %c", 'a'+i%26)
+	}
+	e.Error("busy")
+	if got := len(d.rates); got != 27 {
+		t.Fatalf("tracked %d fingerprints, want 27", got)
+	}
+
+	// After one interval, "error a" to "error v" had 4
+	// entries and keep a baseline of 2, which is not below
+	// MinCount/Factor; the others had fewer and are evicted.
+	// Fingerprints logged again are tracked anew.
+	tests := []struct {
+		name string
+		log  string
+		want int
+	}{
+		{"one interval", "busy", 22 + 1},
+		{"two intervals", "busy", 1},
+		{"other", "other", 1},
+	}
+	for _, tt := range tests {
+		now = now.Add(time.Minute)
+		e.Error(tt.log)
+		if got := len(d.rates); got != tt.want {
+			t.Errorf("%s: tracked %d fingerprints, want %d", tt.name, got, tt.want)
+		}
+	}
+}
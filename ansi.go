@@ -0,0 +1,101 @@
+package errorlogger
+
+import "io"
+
+// ansiState is the state of an ansiStripper between bytes.
+type ansiState uint8
+
+const (
+	ansiText      ansiState = iota
+	ansiEsc                 // after ESC
+	ansiCSI                 // in a control sequence, ESC [
+	ansiString              // in a control string, e.g. ESC ]
+	ansiStringEsc           // after ESC in a control string
+)
+
+// ansiStripper removes ANSI escape sequences from a stream
+// of bytes, keeping its state between calls so that
+// sequences split across reads are removed as well.
+type ansiStripper struct {
+	state ansiState
+}
+
+// strip removes escape sequences from b in place and
+// returns the remaining bytes.
+func (s *ansiStripper) strip(b []byte) []byte {
+	n := 0
+	for _, c := range b {
+		switch s.state {
+		case ansiText:
+			if c == 0x1b {
+				s.state = ansiEsc
+				continue
+			}
+			b[n] = c
+			n++
+		case ansiEsc:
+			switch {
+			case c == '[':
+				s.state = ansiCSI
+			case c == ']' || c == 'P' || c == 'X' || c == '^' || c == '_':
+				s.state = ansiString
+			case c >= 0x20 && c <= 0x2f:
+				// intermediate byte, e.g. ESC ( B
+			default:
+				s.state = ansiText
+			}
+		case ansiCSI:
+			if c >= 0x40 && c <= 0x7e {
+				s.state = ansiText
+			}
+		case ansiString:
+			switch c {
+			case 0x07:
+				s.state = ansiText
+			case 0x1b:
+				s.state = ansiStringEsc
+			}
+		case ansiStringEsc:
+			if c == '\\' {
+				s.state = ansiText
+			} else {
+				s.state = ansiString
+			}
+		}
+	}
+	return b[:n]
+}
+
+// ansiReader is the io.Reader returned by StripANSI.
+type ansiReader struct {
+	r io.Reader
+	s ansiStripper
+}
+
+func (a *ansiReader) Read(p []byte) (int, error) {
+	for {
+		n, err := a.r.Read(p)
+		n = len(a.s.strip(p[:n]))
+		if n > 0 || err != nil || len(p) == 0 {
+			return n, err
+		}
+	}
+}
+
+// StripANSI returns a reader of r with the ANSI escape
+// sequences removed, such as the colors written by the
+// TextFormatter to terminals, so that tests, tailing tools,
+// and file sinks can consume plain text. Sequences that
+// span reads of r are removed as well, so the result does
+// not depend on how r splits its data.
+//  io.Copy(file, errorlogger.StripANSI(colored))
+func StripANSI(r io.Reader) io.Reader {
+	return &ansiReader{r: r}
+}
+
+// StripANSIBytes returns a copy of b with the ANSI escape
+// sequences removed.
+func StripANSIBytes(b []byte) []byte {
+	var s ansiStripper
+	return s.strip(append([]byte(nil), b...))
+}
@@ -0,0 +1,58 @@
+package errorlogger
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "level=info msg=ok\n", "level=info msg=ok\n"},
+		{"color", "\x1b[31mERRO\x1b[0m[0000] failed", "ERRO[0000] failed"},
+		{"params", "\x1b[1;38;5;208mwarn\x1b[m", "warn"},
+		{"cursor", "a\x1b[2K\x1b[1Ab", "ab"},
+		{"osc bel", "\x1b]0;title\x07text", "text"},
+		{"osc st", "\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"charset", "\x1b(Bok", "ok"},
+		{"two byte", "\x1bMup", "up"},
+		{"unicode", "\x1b[32m✓ héllo\x1b[0m", "✓ héllo"},
+		{"trailing esc", "text\x1b", "text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(StripANSIBytes([]byte(tt.in))); got != tt.want {
+				t.Errorf("StripANSIBytes(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			// Reading one byte at a time splits every sequence.
+			b, err := io.ReadAll(StripANSI(iotest.OneByteReader(strings.NewReader(tt.in))))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("StripANSI(%q) = %q, want %q", tt.in, b, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripANSI_textFormatter(t *testing.T) {
+	colored := &TextFormatter{TextFormatter: logrus.TextFormatter{ForceColors: true, DisableTimestamp: true}}
+	b, err := colored.Format(&Entry{Level: ErrorLevel, Message: "disk full", Data: Fields{"path": "/var"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "\x1b[") {
+		t.Fatalf("Format() = %q, want colors", b)
+	}
+	if got := string(StripANSIBytes(b)); strings.Contains(got, "\x1b") || !strings.Contains(got, "disk full") || !strings.Contains(got, "path=/var") {
+		t.Errorf("StripANSIBytes() = %q", got)
+	}
+}
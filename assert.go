@@ -0,0 +1,49 @@
+package errorlogger
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrAssertion is the error logged (and, with a development
+// preset, panicked) when an invariant is violated.
+var ErrAssertion = errors.New("assertion failed")
+
+// Assert logs an invariant violation at ErrorLevel with a
+// stack trace if cond is false. Additional fields may be
+// supplied to describe the state.
+//
+// With a Development preset, a failed assertion panics after
+// it is logged; otherwise, execution continues.
+//  log.Assert(len(queue) <= max, "queue overflow", Fields{"len": len(queue)})
+func (e *errorLogger) Assert(cond bool, msg string, fields ...Fields) {
+	if cond {
+		return
+	}
+	e.assertionFailed(msg, fields...)
+}
+
+// Never records that code which should be unreachable was
+// reached. It behaves like a failed Assert.
+//  default:
+//  	log.Never("unknown state")
+func (e *errorLogger) Never(msg string) {
+	e.assertionFailed("unreachable: " + msg)
+}
+
+func (e *errorLogger) assertionFailed(msg string, fields ...Fields) {
+	f := Fields{StackKey: string(debug.Stack())}
+	for _, ff := range fields {
+		for k, v := range ff {
+			f[k] = v
+		}
+	}
+
+	err := fmt.Errorf("%w: %s", ErrAssertion, msg)
+	e.WithFields(f).Error(err)
+
+	if e.development() {
+		panic(err)
+	}
+}
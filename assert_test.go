@@ -0,0 +1,64 @@
+package errorlogger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_errorLogger_Assert(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+
+	e.Assert(true, "holds")
+	if buf.Len() != 0 {
+		t.Errorf("Assert(true) logged: %q", buf.String())
+	}
+
+	e.Assert(false, "queue overflow", Fields{"len": 11})
+	for _, want := range []string{"assertion failed: queue overflow", "len=11", "stack="} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Assert(false) output missing %q: %q", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	e.Never("unknown state")
+	if !strings.Contains(buf.String(), "unreachable: unknown state") {
+		t.Errorf("Never() output = %q", buf.String())
+	}
+}
+
+func Test_errorLogger_Assert_development(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	e.SetPreset(DevelopmentPreset)
+	defer e.SetPreset(Preset{Level: InfoLevel})
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok || !errors.Is(err, ErrAssertion) {
+			t.Errorf("Assert(false) with development preset recovered %v, want ErrAssertion", r)
+		}
+	}()
+	e.Assert(false, "panics")
+}
+
+func Test_errorLogger_SetPreset(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+
+	e.SetPreset(ProductionPreset)
+	if _, ok := e.formatter().(*JSONFormatter); !ok {
+		t.Errorf("SetPreset(production) formatter = %T, want *JSONFormatter", e.formatter())
+	}
+	if got := e.GetLevel(); got != InfoLevel {
+		t.Errorf("SetPreset(production) level = %v, want %v", got, InfoLevel)
+	}
+
+	e.SetPreset(DevelopmentPreset)
+	if got := e.GetLevel(); got != DebugLevel {
+		t.Errorf("SetPreset(development) level = %v, want %v", got, DebugLevel)
+	}
+	if got := e.Preset().Name; got != "development" {
+		t.Errorf("Preset().Name = %q, want %q", got, "development")
+	}
+}
@@ -0,0 +1,186 @@
+package errorlogger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultAsyncQueueSize is the number of entries buffered by
+// an AsyncWriter if no size is given.
+const DefaultAsyncQueueSize = 1024
+
+type (
+	// DrainReport records the outcome of closing an AsyncWriter.
+	//
+	// Flushed is the number of entries written to the output.
+	// Dropped is the number of entries discarded because the
+	// queue was full or the output returned an error.
+	// Abandoned is the number of entries still queued when
+	// the shutdown deadline expired.
+	DrainReport struct {
+		Flushed   uint64
+		Dropped   uint64
+		Abandoned uint64
+	}
+
+	// AsyncWriter is a Writer that queues writes and performs
+	// them on a background goroutine, removing output latency
+	// from the logging call. Writes never block; if the queue
+	// is full, the entry is dropped and counted.
+	//
+	// Use it as the output of a logger:
+	//  w := NewAsyncWriter(os.Stderr, 0)
+	//  log.SetLogOutput(w)
+	//  defer w.Close(ctx)
+	AsyncWriter struct {
+		out   Writer
+		queue chan asyncItem
+		done  chan struct{}
+		diag  Writer // diagnostics on lossy shutdown
+
+		mu     sync.RWMutex
+		closed bool
+
+		pending   int64  // atomic
+		stopping  int32  // atomic
+		flushed   uint64 // atomic
+		dropped   uint64 // atomic
+		abandoned uint64 // atomic
+	}
+
+	// asyncItem is a queued write or, if ack is not nil,
+	// a flush marker.
+	asyncItem struct {
+		p   []byte
+		ack chan struct{}
+	}
+)
+
+// NewAsyncWriter returns a new AsyncWriter that writes to w
+// with a queue of size entries. A size of zero or less uses
+// DefaultAsyncQueueSize.
+func NewAsyncWriter(w Writer, size int) *AsyncWriter {
+	if size <= 0 {
+		size = DefaultAsyncQueueSize
+	}
+	a := &AsyncWriter{
+		out:   w,
+		queue: make(chan asyncItem, size),
+		done:  make(chan struct{}),
+		diag:  os.Stderr,
+	}
+	go a.run()
+	return a
+}
+
+func (a *AsyncWriter) run() {
+	defer close(a.done)
+	for it := range a.queue {
+		if it.ack != nil {
+			close(it.ack)
+			continue
+		}
+		if atomic.LoadInt32(&a.stopping) == 1 {
+			atomic.AddUint64(&a.abandoned, 1)
+		} else if _, err := a.out.Write(it.p); err != nil {
+			atomic.AddUint64(&a.dropped, 1)
+		} else {
+			atomic.AddUint64(&a.flushed, 1)
+		}
+		atomic.AddInt64(&a.pending, -1)
+	}
+}
+
+// Write queues a copy of p to be written to the output. It
+// always reports success unless the writer is closed, in
+// which case ErrClosed is returned.
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return 0, ErrClosed
+	}
+
+	buf := make([]byte, len(p))
+	copy(buf, p)
+
+	atomic.AddInt64(&a.pending, 1)
+	select {
+	case a.queue <- asyncItem{p: buf}:
+	default:
+		atomic.AddInt64(&a.pending, -1)
+		atomic.AddUint64(&a.dropped, 1)
+	}
+	return len(p), nil
+}
+
+// Flush blocks until all entries queued before the call
+// have been written.
+func (a *AsyncWriter) Flush() {
+	a.mu.RLock()
+	if a.closed {
+		a.mu.RUnlock()
+		return
+	}
+	ack := make(chan struct{})
+	a.queue <- asyncItem{ack: ack}
+	a.mu.RUnlock()
+	<-ack
+}
+
+// Report returns the current counts of the writer.
+func (a *AsyncWriter) Report() DrainReport {
+	return DrainReport{
+		Flushed:   atomic.LoadUint64(&a.flushed),
+		Dropped:   atomic.LoadUint64(&a.dropped),
+		Abandoned: atomic.LoadUint64(&a.abandoned) + a.abandonedPending(),
+	}
+}
+
+// abandonedPending returns the number of queued entries that
+// will be abandoned because a shutdown deadline expired.
+func (a *AsyncWriter) abandonedPending() uint64 {
+	if atomic.LoadInt32(&a.stopping) == 0 {
+		return 0
+	}
+	if n := atomic.LoadInt64(&a.pending); n > 0 {
+		return uint64(n)
+	}
+	return 0
+}
+
+// Close stops accepting writes and waits for queued entries
+// to be written until ctx is done. It returns a report of
+// how many entries were flushed, dropped, and abandoned.
+//
+// If any entries were lost, a final diagnostics line is
+// written to os.Stderr so operators know whether a crash
+// or a slow output lost data. If the deadline expires
+// before the queue is drained, ctx.Err() is returned.
+func (a *AsyncWriter) Close(ctx context.Context) (DrainReport, error) {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return a.Report(), ErrClosed
+	}
+	a.closed = true
+	close(a.queue)
+	a.mu.Unlock()
+
+	var err error
+	select {
+	case <-a.done:
+	case <-ctx.Done():
+		atomic.StoreInt32(&a.stopping, 1)
+		err = ctx.Err()
+	}
+
+	r := a.Report()
+	if r.Dropped > 0 || r.Abandoned > 0 {
+		fmt.Fprintf(a.diag, "errorlogger: async writer closed with lost entries: flushed=%d dropped=%d abandoned=%d\n", r.Flushed, r.Dropped, r.Abandoned)
+	}
+	return r, err
+}
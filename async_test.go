@@ -0,0 +1,88 @@
+package errorlogger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingWriter blocks every Write until release is closed.
+type blockingWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func TestAsyncWriter(t *testing.T) {
+	w := &blockingWriter{release: make(chan struct{})}
+	close(w.release)
+
+	a := NewAsyncWriter(w, 0)
+	e, _ := newBufferLogger(InfoLevel)
+	e.SetOutput(a)
+
+	e.Info("first")
+	e.Info("second")
+	a.Flush()
+
+	w.mu.Lock()
+	got := w.buf.String()
+	w.mu.Unlock()
+	if !strings.Contains(got, "first") || !strings.Contains(got, "second") {
+		t.Errorf("AsyncWriter.Flush() output = %q, want both entries", got)
+	}
+
+	r, err := a.Close(context.Background())
+	if err != nil {
+		t.Fatalf("AsyncWriter.Close() error = %v", err)
+	}
+	if r != (DrainReport{Flushed: 2}) {
+		t.Errorf("AsyncWriter.Close() report = %+v, want 2 flushed", r)
+	}
+
+	if _, err := a.Write([]byte("late")); err != ErrClosed {
+		t.Errorf("AsyncWriter.Write() after Close error = %v, want %v", err, ErrClosed)
+	}
+	if _, err := a.Close(context.Background()); err != ErrClosed {
+		t.Errorf("AsyncWriter.Close() twice error = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestAsyncWriter_lossy(t *testing.T) {
+	w := &blockingWriter{release: make(chan struct{})}
+	a := NewAsyncWriter(w, 2)
+	diag := &bytes.Buffer{}
+	a.diag = diag
+
+	for i := 0; i < 5; i++ {
+		a.Write([]byte("entry\n"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	r, err := a.Close(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf("AsyncWriter.Close() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	close(w.release)
+
+	if r.Flushed+r.Dropped+r.Abandoned != 5 {
+		t.Errorf("AsyncWriter.Close() report = %+v, want 5 entries accounted for", r)
+	}
+	if r.Dropped == 0 || r.Abandoned == 0 {
+		t.Errorf("AsyncWriter.Close() report = %+v, want dropped and abandoned entries", r)
+	}
+	if !strings.Contains(diag.String(), "abandoned=") {
+		t.Errorf("AsyncWriter.Close() diagnostics = %q, want lost entry report", diag.String())
+	}
+}
@@ -0,0 +1,120 @@
+package errorlogger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+)
+
+// DefaultAttachmentDir returns the directory used by Attach
+// when none is set with SetAttachmentDir: a directory in the
+// cache directory of the user, or in the temporary directory
+// if the user has none.
+//
+// Attach creates the directory with mode 0700 and refuses to
+// use it if it exists but is not a directory owned by the
+// user that only the user can access, because payloads may
+// hold sensitive data.
+func DefaultAttachmentDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "errorlogger", "attachments")
+}
+
+// SetAttachmentDir sets the directory that Attach stores
+// payloads in. An empty dir selects DefaultAttachmentDir.
+func (e *errorLogger) SetAttachmentDir(dir string) { e.blobDir = dir }
+
+// Attach logs err like Err and stores data, such as a
+// request body or a core dump, in the attachment directory
+// under the hex SHA-256 hash of its content. Only a
+// reference is logged, keeping entries small while
+// preserving forensic data:
+//  attachment=body.json attachment_sha256=9f86d0... attachment_size=48213 attachment_path=/home/...
+//
+// Identical payloads are stored once. If data cannot be
+// stored, err is still logged with an attachment_error
+// field. Like Err, Attach returns err and does nothing
+// while the logger is disabled.
+func (e *errorLogger) Attach(err error, name string, data []byte) error {
+	if err == nil || !e.enabled() {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	fields := Fields{
+		"attachment":        name,
+		"attachment_sha256": hash,
+		"attachment_size":   len(data),
+	}
+
+	dir, private := e.blobDir, e.blobDir == ""
+	if private {
+		dir = DefaultAttachmentDir()
+	}
+	if path, werr := storeBlob(dir, hash, data, private); werr != nil {
+		fields["attachment_error"] = werr.Error()
+	} else {
+		fields["attachment_path"] = path
+	}
+	return e.errWithFields(err, fields)
+}
+
+// storeBlob writes data to dir/hash unless it is already
+// there. The write is atomic so that readers never see a
+// partial blob. If private is set, dir must be private to
+// the user; see privateDir.
+func storeBlob(dir, hash string, data []byte, private bool) (string, error) {
+	if private {
+		if err := privateDir(dir); err != nil {
+			return "", err
+		}
+	}
+	path := filepath.Join(dir, hash)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-"+hash[:8]+"-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// privateDir creates dir with mode 0700 if it does not
+// exist, and returns an error wrapping ErrPermission if it
+// is not a directory owned by the user that only the user
+// can access.
+func privateDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return &PathError{Op: "attach", Path: dir, Err: ErrPermission}
+	}
+	return checkPrivate(dir, fi)
+}
@@ -0,0 +1,19 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package errorlogger
+
+import (
+	"os"
+	"syscall"
+)
+
+// checkPrivate returns an error wrapping ErrPermission if
+// the directory dir, described by fi, is not owned by the
+// user or can be accessed by the group or others.
+func checkPrivate(dir string, fi os.FileInfo) error {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok || int(st.Uid) != os.Geteuid() || fi.Mode().Perm()&0o077 != 0 {
+		return &PathError{Op: "attach", Path: dir, Err: ErrPermission}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package errorlogger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_Attach(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	dir := t.TempDir()
+	e.SetAttachmentDir(dir)
+
+	errBad := errors.New("bad request")
+	body := []byte(`{"user": "gopher"}`)
+
+	if got := e.Attach(errBad, "body.json", body); got != errBad {
+		t.Errorf("Attach() = %v, want %v", got, errBad)
+	}
+	if got := e.Attach(errBad, "body.json", body); got != errBad {
+		t.Errorf("Attach() = %v, want %v", got, errBad)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("attachment dir has %d files, want 1", len(entries))
+	}
+	name := entries[0].Name()
+	stored, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil || !bytes.Equal(stored, body) {
+		t.Errorf("stored blob = %q, %v, want %q", stored, err, body)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"msg=\"bad request\"", "attachment=body.json", "attachment_sha256=" + name, "attachment_size=18"} {
+		if strings.Count(out, want) != 2 {
+			t.Errorf("Attach() output = %q, want %q twice", out, want)
+		}
+	}
+	if strings.Contains(out, "gopher") {
+		t.Errorf("Attach() logged the payload: %q", out)
+	}
+}
+
+func TestErrorLogger_Attach_Unwritable(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	e.SetAttachmentDir(filepath.Join(file, "blobs"))
+
+	_ = e.Attach(errors.New("bad request"), "body", []byte("x"))
+	if out := buf.String(); !strings.Contains(out, "attachment_error=") || !strings.Contains(out, "bad request") {
+		t.Errorf("Attach() output = %q, want error logged with attachment_error", out)
+	}
+}
+
+func TestErrorLogger_Attach_Disabled(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	dir := t.TempDir()
+	e.SetAttachmentDir(dir)
+	e.Disable()
+
+	_ = e.Attach(errors.New("bad request"), "body", []byte("x"))
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 || buf.Len() != 0 {
+		t.Errorf("Attach() while disabled stored %d files and logged %q", len(entries), buf.String())
+	}
+}
+
+func TestErrorLogger_Attach_DefaultDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG_CACHE_HOME selects the cache directory on linux only")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := DefaultAttachmentDir()
+
+	tests := []struct {
+		name    string
+		perm    os.FileMode
+		wantErr bool
+	}{
+		{"created", 0, false},
+		{"private", 0o700, false},
+		{"group readable", 0o750, true},
+		{"world writable", 0o777, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.perm != 0 {
+				if err := os.Chmod(dir, tt.perm); err != nil {
+					t.Fatal(err)
+				}
+			}
+			e, buf := newBufferLogger(InfoLevel)
+			_ = e.Attach(errors.New("bad request"), "body", []byte("x"))
+
+			out := buf.String()
+			if got := strings.Contains(out, "attachment_error="); got != tt.wantErr {
+				t.Errorf("Attach() output = %q, want attachment_error %v", out, tt.wantErr)
+			}
+			if !tt.wantErr && !strings.Contains(out, "attachment_path="+dir) {
+				t.Errorf("Attach() output = %q, want a path in %s", out, dir)
+			}
+			fi, err := os.Stat(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.perm == 0 && fi.Mode().Perm() != 0o700 {
+				t.Errorf("mode = %v, want 0700", fi.Mode().Perm())
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package errorlogger
+
+import (
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// The channels through which the configuration of a logger
+// is changed, recorded as the "via" field of audit entries.
+const (
+	ViaAPI    = "api"    // a method of the logger
+	ViaConfig = "config" // ApplyConfig
+	ViaSignal = "signal" // a signal handler
+	ViaAdmin  = "admin"  // an admin endpoint
+)
+
+type (
+	// auditTrail writes audit entries to a dedicated
+	// stream.
+	auditTrail struct {
+		mu  sync.Mutex
+		log *Logger
+	}
+
+	// changeSource identifies who or what made a change.
+	changeSource struct {
+		via   string
+		actor string
+	}
+
+	// Changer changes the configuration of a logger on
+	// behalf of a source other than a direct API call, such
+	// as an admin endpoint or a signal handler, so that the
+	// audit trail records who made the change.
+	//  log.ChangedBy(errorlogger.ViaAdmin, r.RemoteAddr).SetLevel(errorlogger.DebugLevel)
+	Changer struct {
+		e   *errorLogger
+		src *changeSource
+	}
+)
+
+// SetAuditOutput enables the audit trail: every runtime
+// change of the level, enabled state, output, level
+// overrides, and configuration of the logger is written
+// to w as a JSON entry with the fields setting, old, new,
+// via, and actor, since silent verbosity changes during
+// incidents cause confusion later. Changes made through
+// the API name the call site as the actor.
+//
+// A nil w disables the audit trail.
+func (e *errorLogger) SetAuditOutput(w Writer) {
+	if w == nil {
+		e.audit = nil
+		return
+	}
+	e.audit = &auditTrail{log: &Logger{
+		Out:       w,
+		Formatter: &logrus.JSONFormatter{},
+		Hooks:     make(logrus.LevelHooks),
+		Level:     InfoLevel,
+		ExitFunc:  func(int) {},
+	}}
+}
+
+// ChangedBy returns a Changer that records changes as made
+// via the channel via (such as ViaAdmin or ViaSignal) by
+// actor (such as a user, an address, or a signal name).
+func (e *errorLogger) ChangedBy(via, actor string) *Changer {
+	return &Changer{e: e, src: &changeSource{via: via, actor: actor}}
+}
+
+// recordChange writes an audit entry for a change of
+// setting, unless the audit trail is disabled or nothing
+// changed. A nil src is a direct API call. Without an
+// actor, the call site is recorded.
+func (e *errorLogger) recordChange(src *changeSource, setting, old, new string) {
+	a := e.audit
+	if a == nil || old == new {
+		return
+	}
+	if src == nil {
+		src = &changeSource{via: ViaAPI}
+	}
+	actor := src.actor
+	if actor == "" {
+		actor = callSite()
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.log.WithFields(Fields{
+		"setting":  setting,
+		"old":      old,
+		"new":      new,
+		"via":      src.via,
+		"actor":    actor,
+		SessionKey: sessionID,
+	}).Info("configuration changed")
+}
+
+// SetOutput sets the output of the logger.
+func (e *errorLogger) SetOutput(out io.Writer) {
+	e.setOutput(out, nil)
+}
+
+func (e *errorLogger) setOutput(out io.Writer, src *changeSource) {
+	old := e.output()
+	e.Logger.SetOutput(pipelineOutput{out})
+	if e.backend != nil {
+		e.backend.SetOutput(out)
+	}
+	if e.audit != nil {
+		e.recordChange(src, "output", outputName(old), outputName(out))
+	}
+}
+
+// setEnabled enables or disables logging.
+func (e *errorLogger) setEnabled(enabled bool, src *changeSource) {
+	e.enableMu.Lock()
+	old := e.enabled()
+	if enabled {
+		atomic.StoreUint32(&e.disabled, 0)
+	} else {
+		atomic.StoreUint32(&e.disabled, 1)
+	}
+	e.storeErrFn()
+	e.enableMu.Unlock()
+	if e.audit != nil {
+		e.recordChange(src, "enabled", strconv.FormatBool(old), strconv.FormatBool(enabled))
+	}
+}
+
+// SetLevel sets the level of the logger.
+func (c *Changer) SetLevel(level Level) { c.e.setLevel(level, c.src) }
+
+// Enable enables logging.
+func (c *Changer) Enable() { c.e.setEnabled(true, c.src) }
+
+// Disable disables logging.
+func (c *Changer) Disable() { c.e.setEnabled(false, c.src) }
+
+// SetOutput sets the output of the logger.
+func (c *Changer) SetOutput(out io.Writer) { c.e.setOutput(out, c.src) }
+
+// SetOverride sets a level override; see SetOverride of
+// ErrorLogger.
+func (c *Changer) SetOverride(key, value string, lvl Level, ttl time.Duration) {
+	c.e.setOverride(key, value, &lvl, ttl, c.src)
+}
+
+// ClearOverride removes a level override.
+func (c *Changer) ClearOverride(key, value string) {
+	c.e.setOverride(key, value, nil, 0, c.src)
+}
+
+// SetSchedule applies a level and sampling schedule; see
+// SetSchedule of ErrorLogger.
+func (c *Changer) SetSchedule(spec string) error {
+	if _, err := ParseSchedule(spec); err != nil {
+		return err
+	}
+	base, _ := c.e.scheduleBase()
+	c.e.setSchedule(spec, base, c.src)
+	return nil
+}
+
+// ApplyConfig applies a configuration.
+func (c *Changer) ApplyConfig(cfg Config) error { return c.e.applyConfig(cfg, c.src) }
@@ -0,0 +1,79 @@
+package errorlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorLogger_SetAuditOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(e *errorLogger)
+		setting string
+		old     string
+		new     string
+		via     string
+		actor   string
+	}{
+		{"level", func(e *errorLogger) { e.SetLevel(DebugLevel) }, "level", "info", "debug", ViaAPI, "audit_test.go:"},
+		{"log level", func(e *errorLogger) { _ = e.SetLogLevel("warn") }, "level", "info", "warning", ViaAPI, "audit_test.go:"},
+		{"disable", func(e *errorLogger) { e.Disable() }, "enabled", "true", "false", ViaAPI, "audit_test.go:"},
+		{"output", func(e *errorLogger) { e.SetOutput(os.Stdout) }, "output", "*bytes.Buffer", "stdout", ViaAPI, "audit_test.go:"},
+		{"override", func(e *errorLogger) { e.SetOverride("tenant", "acme", DebugLevel, time.Minute) }, "override tenant=acme", "none", "debug", ViaAPI, "audit_test.go:"},
+		{"admin", func(e *errorLogger) { e.ChangedBy(ViaAdmin, "10.0.0.7").SetLevel(TraceLevel) }, "level", "info", "trace", ViaAdmin, "10.0.0.7"},
+		{"signal", func(e *errorLogger) { e.ChangedBy(ViaSignal, "SIGUSR1").Disable() }, "enabled", "true", "false", ViaSignal, "SIGUSR1"},
+		{"config", func(e *errorLogger) {
+			c := e.Config()
+			c.Level = "error"
+			_ = e.ApplyConfig(c)
+		}, "level", "info", "error", ViaConfig, "audit_test.go:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newBufferLogger(InfoLevel)
+			var audit bytes.Buffer
+			e.SetAuditOutput(&audit)
+			tt.change(e)
+
+			var rec map[string]interface{}
+			dec := json.NewDecoder(&audit)
+			if err := dec.Decode(&rec); err != nil {
+				t.Fatal(err)
+			}
+			if dec.More() {
+				t.Errorf("audit = %q, want a single entry", audit.String())
+			}
+			if rec["setting"] != tt.setting || rec["old"] != tt.old || rec["new"] != tt.new || rec["via"] != tt.via {
+				t.Errorf("audit entry = %v", rec)
+			}
+			if actor, _ := rec["actor"].(string); !strings.Contains(actor, tt.actor) {
+				t.Errorf("audit actor = %q, want %q", actor, tt.actor)
+			}
+			if rec["msg"] != "configuration changed" || rec[SessionKey] != SessionID() {
+				t.Errorf("audit entry = %v", rec)
+			}
+		})
+	}
+}
+
+func TestErrorLogger_SetAuditOutput_unchanged(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	var audit bytes.Buffer
+	e.SetAuditOutput(&audit)
+	e.SetLevel(InfoLevel)
+	e.Enable()
+	e.ClearOverride("tenant", "none")
+	if audit.Len() != 0 {
+		t.Errorf("audit trail recorded changes that changed nothing: %s", audit.String())
+	}
+
+	e.SetAuditOutput(nil)
+	e.SetLevel(DebugLevel)
+	if audit.Len() != 0 {
+		t.Errorf("disabled audit trail recorded: %s", audit.String())
+	}
+}
@@ -0,0 +1,137 @@
+package errorlogger
+
+import (
+	"fmt"
+	"io"
+	stdlog "log"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	// Backend writes the entries of an ErrorLogger created
+	// with NewWithBackend, so that the standard library log
+	// package or another logger can take the place of the
+	// output and formatter of logrus.
+	Backend interface {
+		// Log writes an entry with the message and fields
+		// at level.
+		Log(level Level, msg string, fields Fields)
+
+		// SetLevel sets the least severe level written.
+		SetLevel(level Level)
+
+		// SetOutput sets the destination of the entries.
+		SetOutput(w io.Writer)
+
+		// SetFormatter sets the format of the entries, if
+		// the backend supports formatters.
+		SetFormatter(f Formatter)
+	}
+
+	// LogrusBackend is a Backend that writes to a logrus
+	// Logger, e.g. one shared with code that does not use
+	// this package.
+	LogrusBackend struct{ *Logger }
+
+	// StdBackend is a Backend that writes to a standard
+	// library *log.Logger, one line per entry:
+	//  ERROR open config: file does not exist path=/etc/app.yaml
+	// Fields are written in sorted order. Formatters are not
+	// supported; the flags and prefix of the *log.Logger
+	// apply instead.
+	StdBackend struct {
+		l     *stdlog.Logger
+		level uint32 // atomic
+	}
+)
+
+// NewWithBackend returns a new ErrorLogger, with logging
+// enabled, that writes its entries to b:
+//  log := errorlogger.NewWithBackend(errorlogger.NewStdBackend(nil))
+//
+// Entries are created, filtered by level, passed to hooks,
+// and processed by mutators as with any ErrorLogger, and then
+// written with b.Log instead of the formatter and output of
+// the logger. SetOutput and SetFormatter are passed on to b.
+// The level of b is set to TraceLevel, so that the level of
+// the ErrorLogger, including its overrides, decides which
+// entries are written.
+func NewWithBackend(b Backend) ErrorLogger {
+	logger := &Logger{
+		Out:       Discard,
+		Formatter: DefaultTextFormatter,
+		Hooks:     make(logrus.LevelHooks),
+		Level:     DefaultLogLevel,
+		ExitFunc:  os.Exit,
+	}
+	e := newTestStruct(true, "", nil, nil, logger)
+	e.backend = b
+	b.SetLevel(TraceLevel)
+	return e
+}
+
+// Log writes an entry to the logrus Logger.
+func (b LogrusBackend) Log(level Level, msg string, fields Fields) {
+	b.Logger.WithFields(fields).Log(level, msg)
+}
+
+// SetFormatter sets the formatter of the logrus Logger.
+func (b LogrusBackend) SetFormatter(f Formatter) {
+	b.Logger.SetFormatter(f)
+}
+
+// NewStdBackend returns a StdBackend writing to l. If l is
+// nil, log.Default() is used.
+func NewStdBackend(l *stdlog.Logger) *StdBackend {
+	if l == nil {
+		l = stdlog.Default()
+	}
+	return &StdBackend{l: l, level: uint32(DefaultLogLevel)}
+}
+
+// Log writes an entry as a line to the *log.Logger.
+func (b *StdBackend) Log(level Level, msg string, fields Fields) {
+	if uint32(level) > atomic.LoadUint32(&b.level) {
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString(strings.ToUpper(level.String()))
+	sb.WriteByte(' ')
+	sb.WriteString(msg)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := fmt.Sprint(fields[k])
+		if v == "" || strings.ContainsAny(v, " \t\"=") {
+			v = fmt.Sprintf("%q", v)
+		}
+		fmt.Fprintf(&sb, " %s=%s", k, v)
+	}
+	b.l.Print(sb.String())
+}
+
+// SetLevel sets the least severe level written.
+func (b *StdBackend) SetLevel(level Level) {
+	atomic.StoreUint32(&b.level, uint32(level))
+}
+
+// SetOutput sets the output of the *log.Logger.
+func (b *StdBackend) SetOutput(w io.Writer) { b.l.SetOutput(w) }
+
+// SetFormatter does nothing, since the *log.Logger formats
+// its lines itself.
+func (b *StdBackend) SetFormatter(Formatter) {}
+
+var (
+	_ Backend = LogrusBackend{}
+	_ Backend = (*StdBackend)(nil)
+)
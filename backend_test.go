@@ -0,0 +1,100 @@
+package errorlogger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	stdlog "log"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+type recordBackend struct {
+	level   Level
+	entries []string
+	out     io.Writer
+	format  Formatter
+}
+
+func (b *recordBackend) Log(level Level, msg string, fields Fields) {
+	keys := make([]string, 0, len(fields))
+	for k, v := range fields {
+		keys = append(keys, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(keys)
+	b.entries = append(b.entries, level.String()+" "+msg+" "+strings.Join(keys, " "))
+}
+func (b *recordBackend) SetLevel(level Level)     { b.level = level }
+func (b *recordBackend) SetOutput(w io.Writer)    { b.out = w }
+func (b *recordBackend) SetFormatter(f Formatter) { b.format = f }
+
+func TestNewWithBackend(t *testing.T) {
+	b := &recordBackend{}
+	e := NewWithBackend(b)
+	if b.level != TraceLevel {
+		t.Errorf("backend level = %v, want %v", b.level, TraceLevel)
+	}
+
+	e.SetLevel(WarnLevel)
+	e.Info("dropped")
+	e.WithField("user", "ann").Warn("kept")
+	e.Err(errFake)
+	want := []string{"warning kept user=ann", "error fake "}
+	if len(b.entries) != len(want) {
+		t.Fatalf("backend entries = %q, want %q", b.entries, want)
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(b.entries[i], w) {
+			t.Errorf("backend entries[%d] = %q, want %q", i, b.entries[i], w)
+		}
+	}
+
+	var buf bytes.Buffer
+	e.SetOutput(&buf)
+	e.SetFormatter(&logrus.JSONFormatter{})
+	e.Warn("again")
+	if b.out != &buf || b.format == nil {
+		t.Errorf("backend output = %v, formatter = %v; want them passed on", b.out, b.format)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("logger output = %q, want entries written only by the backend", buf.String())
+	}
+}
+
+func TestStdBackend(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(e ErrorLogger)
+		want string
+	}{
+		{"message", func(e ErrorLogger) { e.Info("started") }, "INFO started\n"},
+		{"fields", func(e ErrorLogger) { e.WithFields(Fields{"b": 2, "a": "x y"}).Warn("slow") }, "WARNING slow a=\"x y\" b=2\n"},
+		{"empty field", func(e ErrorLogger) { e.WithField("a", "").Error("oops") }, "ERROR oops a=\"\"\n"},
+		{"below level", func(e ErrorLogger) { e.Debug("hidden") }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			e := NewWithBackend(NewStdBackend(stdlog.New(&buf, "", 0)))
+			tt.log(e)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogrusBackend(t *testing.T) {
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+	l.SetOutput(&buf)
+	e := NewWithBackend(LogrusBackend{l})
+	e.WithField("id", 7).Info("shared")
+	if got, want := buf.String(), "level=info msg=shared id=7\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
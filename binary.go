@@ -0,0 +1,424 @@
+package errorlogger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// MaxBinaryEntrySize is the largest encoded entry accepted
+// by a BinaryDecoder.
+var MaxBinaryEntrySize = 16 << 20
+
+// The binary format is a stream of entries, each prefixed
+// with its length as a uvarint, in the protobuf wire format
+// of:
+//
+//  message Entry {
+//      int64  time   = 1; // Unix nanoseconds, 0 if unset
+//      uint32 level  = 2; // logrus level, 0 (panic) to 6 (trace)
+//      string msg    = 3;
+//      repeated Field fields = 4;
+//  }
+//  message Field {
+//      string key   = 1;
+//      bytes  value = 2; // JSON encoded
+//  }
+//
+// Fields are sorted by key. The length prefix is the same
+// as that of protobuf delimited messages, so the stream may
+// be read by any protobuf library.
+const (
+	binTime   protowire.Number = 1
+	binLevel  protowire.Number = 2
+	binMsg    protowire.Number = 3
+	binFields protowire.Number = 4
+	binKey    protowire.Number = 1
+	binValue  protowire.Number = 2
+)
+
+type (
+	// binRecord is an entry in the binary format.
+	binRecord struct {
+		time   int64
+		level  Level
+		msg    string
+		fields []binField
+	}
+
+	binField struct {
+		key   string
+		value []byte // JSON
+	}
+)
+
+// recordOf returns the record of entry. Field values that
+// cannot be encoded as JSON are encoded as strings, and
+// errors as their message.
+func recordOf(entry *Entry) binRecord {
+	r := binRecord{level: entry.Level, msg: entry.Message}
+	if !entry.Time.IsZero() {
+		r.time = entry.Time.UnixNano()
+	}
+	for k, v := range jsonSafe(entry.Data) {
+		b, _ := json.Marshal(v)
+		r.fields = append(r.fields, binField{k, b})
+	}
+	sort.Slice(r.fields, func(i, j int) bool { return r.fields[i].key < r.fields[j].key })
+	return r
+}
+
+// entry returns r as an Entry without a Logger. Field
+// values are decoded from JSON with numbers as json.Number.
+func (r *binRecord) entry() *Entry {
+	e := &Entry{Level: r.level, Message: r.msg, Data: make(Fields, len(r.fields))}
+	if r.time != 0 {
+		e.Time = time.Unix(0, r.time)
+	}
+	for _, f := range r.fields {
+		var v interface{}
+		dec := json.NewDecoder(bytes.NewReader(f.value))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			v = string(f.value)
+		}
+		e.Data[f.key] = v
+	}
+	return e
+}
+
+func (r *binRecord) appendProto(b []byte) []byte {
+	if r.time != 0 {
+		b = protowire.AppendTag(b, binTime, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(r.time))
+	}
+	if r.level != 0 {
+		b = protowire.AppendTag(b, binLevel, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(r.level))
+	}
+	if r.msg != "" {
+		b = protowire.AppendTag(b, binMsg, protowire.BytesType)
+		b = protowire.AppendString(b, r.msg)
+	}
+	for _, f := range r.fields {
+		var fb []byte
+		fb = protowire.AppendTag(fb, binKey, protowire.BytesType)
+		fb = protowire.AppendString(fb, f.key)
+		fb = protowire.AppendTag(fb, binValue, protowire.BytesType)
+		fb = protowire.AppendBytes(fb, f.value)
+		b = protowire.AppendTag(b, binFields, protowire.BytesType)
+		b = protowire.AppendBytes(b, fb)
+	}
+	return b
+}
+
+// unmarshalProto decodes b into r. Unknown fields are
+// skipped, so that the schema can be extended.
+func (r *binRecord) unmarshalProto(b []byte) error {
+	*r = binRecord{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch {
+		case num == binTime && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			r.time, b = int64(v), b[n:]
+		case num == binLevel && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			r.level, b = Level(v), b[n:]
+		case num == binMsg && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			r.msg, b = v, b[n:]
+		case num == binFields && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			f, err := unmarshalField(v)
+			if err != nil {
+				return err
+			}
+			r.fields, b = append(r.fields, f), b[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return nil
+}
+
+func unmarshalField(b []byte) (binField, error) {
+	var f binField
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return f, protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ != protowire.BytesType || (num != binKey && num != binValue) {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return f, protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return f, protowire.ParseError(n)
+		}
+		if num == binKey {
+			f.key = string(v)
+		} else {
+			f.value = append([]byte(nil), v...)
+		}
+		b = b[n:]
+	}
+	return f, nil
+}
+
+// BinaryEncoder writes entries in the compact binary format,
+// for shipping logs over sockets or storing them densely.
+//  enc := NewBinaryEncoder(conn)
+//  err := enc.Encode(entry)
+type BinaryEncoder struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewBinaryEncoder returns a new BinaryEncoder that writes
+// to w.
+func NewBinaryEncoder(w io.Writer) *BinaryEncoder {
+	return &BinaryEncoder{w: w}
+}
+
+// Encode writes entry to the stream with a single write.
+func (enc *BinaryEncoder) Encode(entry *Entry) error {
+	r := recordOf(entry)
+	enc.buf = appendFrame(enc.buf[:0], &r)
+	_, err := enc.w.Write(enc.buf)
+	return err
+}
+
+func appendFrame(b []byte, r *binRecord) []byte {
+	msg := r.appendProto(nil)
+	b = protowire.AppendVarint(b, uint64(len(msg)))
+	return append(b, msg...)
+}
+
+// BinaryDecoder reads entries in the binary format.
+type BinaryDecoder struct {
+	r   *bufio.Reader
+	buf []byte
+}
+
+// NewBinaryDecoder returns a new BinaryDecoder that reads
+// from r.
+func NewBinaryDecoder(r io.Reader) *BinaryDecoder {
+	return &BinaryDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next entry. The entry has no Logger. It
+// returns io.EOF at the end of the stream and
+// io.ErrUnexpectedEOF if the stream ends within an entry.
+func (dec *BinaryDecoder) Decode() (*Entry, error) {
+	r, err := dec.next()
+	if err != nil {
+		return nil, err
+	}
+	return r.entry(), nil
+}
+
+// MarshalBinaryEntry returns entry as an Entry message of
+// the binary format, without the length prefix, for
+// transports that frame messages themselves, such as gRPC.
+func MarshalBinaryEntry(entry *Entry) []byte {
+	r := recordOf(entry)
+	return r.appendProto(nil)
+}
+
+// UnmarshalBinaryEntry decodes an Entry message of the
+// binary format without the length prefix. The entry has no
+// Logger.
+func UnmarshalBinaryEntry(b []byte) (*Entry, error) {
+	var r binRecord
+	if err := r.unmarshalProto(b); err != nil {
+		return nil, fmt.Errorf("invalid binary entry: %v: %w", err, ErrInvalid)
+	}
+	return r.entry(), nil
+}
+
+func (dec *BinaryDecoder) next() (*binRecord, error) {
+	n, err := readUvarint(dec.r)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(MaxBinaryEntrySize) {
+		return nil, fmt.Errorf("binary entry of %d bytes exceeds the maximum of %d: %w", n, MaxBinaryEntrySize, ErrInvalid)
+	}
+	if uint64(cap(dec.buf)) < n {
+		dec.buf = make([]byte, n)
+	}
+	dec.buf = dec.buf[:n]
+	if _, err := io.ReadFull(dec.r, dec.buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	var r binRecord
+	if err := r.unmarshalProto(dec.buf); err != nil {
+		return nil, fmt.Errorf("invalid binary entry: %v: %w", err, ErrInvalid)
+	}
+	return &r, nil
+}
+
+// readUvarint reads a uvarint, returning io.EOF only if the
+// stream ends before its first byte.
+func readUvarint(r io.ByteReader) (uint64, error) {
+	var x uint64
+	for i := 0; i < binary.MaxVarintLen64; i++ {
+		c, err := r.ReadByte()
+		if err != nil {
+			if err == io.EOF && i > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		x |= uint64(c&0x7f) << (7 * i)
+		if c < 0x80 {
+			return x, nil
+		}
+	}
+	return 0, fmt.Errorf("binary entry length overflows: %w", ErrInvalid)
+}
+
+// BinaryFormatter formats entries in the binary format, so
+// that a logger can write it directly to a socket or file.
+//  log.SetFormatter(&BinaryFormatter{})
+type BinaryFormatter struct{}
+
+// Format returns the length prefixed encoding of entry.
+func (f *BinaryFormatter) Format(entry *Entry) ([]byte, error) {
+	r := recordOf(entry)
+	return appendFrame(nil, &r), nil
+}
+
+// The JSON keys of the entry attributes, as written by
+// JSONFormatter with the default field map.
+const (
+	jsonTimeKey  = "time"
+	jsonLevelKey = "level"
+	jsonMsgKey   = "msg"
+)
+
+// BinaryToJSON converts a binary stream read from r to
+// JSON lines written to w, in the format of JSONFormatter.
+func BinaryToJSON(r io.Reader, w io.Writer) error {
+	dec := NewBinaryDecoder(r)
+	bw := bufio.NewWriter(w)
+	for {
+		rec, err := dec.next()
+		if err == io.EOF {
+			return bw.Flush()
+		}
+		if err != nil {
+			return err
+		}
+		obj := make(map[string]json.RawMessage, len(rec.fields)+3)
+		for _, f := range rec.fields {
+			obj[f.key] = f.value
+		}
+		obj[jsonLevelKey], _ = json.Marshal(rec.level.String())
+		obj[jsonMsgKey], _ = json.Marshal(rec.msg)
+		if rec.time != 0 {
+			obj[jsonTimeKey], _ = json.Marshal(time.Unix(0, rec.time).Format(time.RFC3339Nano))
+		}
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		bw.Write(b)
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+}
+
+// JSONToBinary converts JSON lines written by JSONFormatter
+// read from r to a binary stream written to w. Lines that
+// are not JSON objects are skipped. Field values are kept as
+// written; entries without a level are at InfoLevel.
+func JSONToBinary(r io.Reader, w io.Writer) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	var frame []byte
+	for {
+		line, err := br.ReadBytes('\n')
+		if rec, ok := recordFromJSON(line); ok {
+			frame = appendFrame(frame[:0], &rec)
+			if _, werr := bw.Write(frame); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return bw.Flush()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func recordFromJSON(line []byte) (binRecord, bool) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(line, &obj); err != nil || obj == nil {
+		return binRecord{}, false
+	}
+
+	rec := binRecord{level: InfoLevel}
+	var s string
+	if json.Unmarshal(obj[jsonLevelKey], &s) == nil {
+		if l, err := ParseLevel(s); err == nil {
+			rec.level = l
+			delete(obj, jsonLevelKey)
+		}
+	}
+	if json.Unmarshal(obj[jsonMsgKey], &s) == nil {
+		rec.msg = s
+		delete(obj, jsonMsgKey)
+	}
+	if json.Unmarshal(obj[jsonTimeKey], &s) == nil {
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			rec.time = t.UnixNano()
+			delete(obj, jsonTimeKey)
+		}
+	}
+	for k, v := range obj {
+		rec.fields = append(rec.fields, binField{k, v})
+	}
+	sort.Slice(rec.fields, func(i, j int) bool { return rec.fields[i].key < rec.fields[j].key })
+	return rec, true
+}
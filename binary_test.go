@@ -0,0 +1,146 @@
+package errorlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBinaryEncoder(t *testing.T) {
+	now := time.Date(2022, 3, 4, 5, 6, 7, 890, time.UTC)
+	tests := []struct {
+		name  string
+		entry *Entry
+		want  Fields
+	}{
+		{"empty", &Entry{}, Fields{}},
+		{"panic level", &Entry{Level: PanicLevel, Message: "boom", Time: now}, Fields{}},
+		{"fields", &Entry{Level: WarnLevel, Message: "slow", Time: now, Data: Fields{
+			"n":   42,
+			"err": errors.New("timeout"),
+			"ok":  true,
+			"ch":  make(chan int),
+			"obj": map[string]int{"a": 1},
+		}}, Fields{
+			"n":   json.Number("42"),
+			"err": "timeout",
+			"ok":  true,
+			"obj": map[string]interface{}{"a": json.Number("1")},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			enc := NewBinaryEncoder(&buf)
+			for i := 0; i < 2; i++ {
+				if err := enc.Encode(tt.entry); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			dec := NewBinaryDecoder(&buf)
+			for i := 0; i < 2; i++ {
+				got, err := dec.Decode()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Level != tt.entry.Level || got.Message != tt.entry.Message || !got.Time.Equal(tt.entry.Time) {
+					t.Errorf("Decode() = %v %q %v, want %v %q %v", got.Level, got.Message, got.Time, tt.entry.Level, tt.entry.Message, tt.entry.Time)
+				}
+				for k, v := range tt.want {
+					if gb, wb := mustJSON(got.Data[k]), mustJSON(v); gb != wb {
+						t.Errorf("Decode() field %s = %s, want %s", k, gb, wb)
+					}
+				}
+				if _, ok := got.Data["ch"].(string); tt.entry.Data["ch"] != nil && !ok {
+					t.Errorf("Decode() field ch = %#v, want a string", got.Data["ch"])
+				}
+			}
+			if _, err := dec.Decode(); err != io.EOF {
+				t.Errorf("Decode() at end = %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func mustJSON(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func TestBinaryDecoder_errors(t *testing.T) {
+	var buf bytes.Buffer
+	_ = NewBinaryEncoder(&buf).Encode(&Entry{Message: "truncated"})
+	tests := []struct {
+		name  string
+		input []byte
+		want  error
+	}{
+		{"truncated entry", buf.Bytes()[:buf.Len()-1], io.ErrUnexpectedEOF},
+		{"truncated length", []byte{0x80}, io.ErrUnexpectedEOF},
+		{"invalid entry", []byte{2, 0xff, 0xff}, ErrInvalid},
+		{"oversized", []byte{0xff, 0xff, 0xff, 0xff, 0x7f}, ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewBinaryDecoder(bytes.NewReader(tt.input)).Decode(); !errors.Is(err, tt.want) {
+				t.Errorf("Decode() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONToBinary(t *testing.T) {
+	e, out := newBufferLogger(InfoLevel)
+	e.SetFormatter(&JSONFormatter{})
+	e.WithFields(Fields{"user": "ann", "n": 1.5, "nested": Fields{"k": []int{1, 2}}}).Warn("first")
+	e.Info("second <html>")
+	want := out.String()
+
+	var bin, got bytes.Buffer
+	if err := JSONToBinary(strings.NewReader(want+"not json\n"), &bin); err != nil {
+		t.Fatal(err)
+	}
+	if bin.Len() >= len(want) {
+		t.Errorf("binary size %d is not smaller than JSON size %d", bin.Len(), len(want))
+	}
+	if err := BinaryToJSON(&bin, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want {
+		t.Errorf("round trip =\n%s\nwant\n%s", got.String(), want)
+	}
+}
+
+func TestBinaryFormatter(t *testing.T) {
+	e, out := newBufferLogger(InfoLevel)
+	e.SetFormatter(&BinaryFormatter{})
+	e.WithField("k", "v").Error("formatted")
+
+	got, err := NewBinaryDecoder(out).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Level != ErrorLevel || got.Message != "formatted" || got.Data["k"] != "v" {
+		t.Errorf("Decode() = %v %q %v", got.Level, got.Message, got.Data)
+	}
+}
+
+func TestMarshalBinaryEntry(t *testing.T) {
+	in := &Entry{Time: time.Unix(0, 42), Level: WarnLevel, Message: "slow", Data: Fields{"ms": 250}}
+	got, err := UnmarshalBinaryEntry(MarshalBinaryEntry(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Time.Equal(in.Time) || got.Level != WarnLevel || got.Message != "slow" || got.Data["ms"] != json.Number("250") {
+		t.Errorf("UnmarshalBinaryEntry() = %v %v %q %v", got.Time, got.Level, got.Message, got.Data)
+	}
+
+	if _, err := UnmarshalBinaryEntry([]byte{0x1a, 0x05}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("UnmarshalBinaryEntry(truncated) error = %v, want ErrInvalid", err)
+	}
+}
@@ -0,0 +1,162 @@
+package errorlogger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	// BudgetStatus reports the consumption of an error budget
+	// in the current hourly window.
+	BudgetStatus struct {
+		Name        string
+		Allowed     int
+		Used        int
+		Remaining   int
+		Exhausted   bool
+		WindowStart time.Time
+	}
+
+	// budget is an error budget defined on a BudgetTracker.
+	budget struct {
+		name        string
+		allowed     int
+		match       func(*Entry) bool
+		used        int
+		windowStart time.Time
+	}
+
+	// BudgetTracker is a small SLO helper that counts logged
+	// errors against hourly error budgets. It is a logrus
+	// hook that fires on ErrorLevel and above.
+	//
+	//  t := NewBudgetTracker(log)
+	//  t.DefineBudget("db", 100)
+	//  ...
+	//  for _, s := range t.Status() { ... }
+	//
+	// When a budget is exhausted, the OnExhausted function is
+	// called once per window. By default it logs a warning
+	// to the logger the tracker is attached to.
+	BudgetTracker struct {
+		mu          sync.Mutex
+		budgets     []*budget
+		onExhausted func(BudgetStatus)
+		now         func() time.Time
+	}
+)
+
+// NewBudgetTracker returns a new BudgetTracker that is added
+// as a hook to e.
+func NewBudgetTracker(e ErrorLogger) *BudgetTracker {
+	t := &BudgetTracker{now: time.Now}
+	t.onExhausted = func(s BudgetStatus) {
+		e.WithFields(Fields{
+			"budget":  s.Name,
+			"allowed": s.Allowed,
+			"used":    s.Used,
+		}).Warn("error budget exhausted")
+	}
+	e.AddHook(t)
+	return t
+}
+
+// DefineBudget defines a budget that allows allowedPerHour
+// errors of any kind per hour.
+func (t *BudgetTracker) DefineBudget(name string, allowedPerHour int) {
+	t.DefineBudgetFunc(name, allowedPerHour, nil)
+}
+
+// DefineBudgetFunc defines a budget that allows allowedPerHour
+// errors per hour for entries where match returns true. A
+// nil match counts every error.
+func (t *BudgetTracker) DefineBudgetFunc(name string, allowedPerHour int, match func(*Entry) bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.budgets = append(t.budgets, &budget{
+		name:        name,
+		allowed:     allowedPerHour,
+		match:       match,
+		windowStart: t.now(),
+	})
+}
+
+// OnExhausted sets the function called when a budget is
+// exhausted. It is called at most once per budget per hour.
+func (t *BudgetTracker) OnExhausted(fn func(BudgetStatus)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onExhausted = fn
+}
+
+// Status returns the status of all budgets.
+func (t *BudgetTracker) Status() []BudgetStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	list := make([]BudgetStatus, 0, len(t.budgets))
+	for _, b := range t.budgets {
+		b.roll(now)
+		list = append(list, b.status())
+	}
+	return list
+}
+
+// Levels returns the levels the tracker fires on.
+func (t *BudgetTracker) Levels() []Level {
+	return []Level{PanicLevel, FatalLevel, ErrorLevel}
+}
+
+// Fire consumes budget for every budget matching entry.
+func (t *BudgetTracker) Fire(entry *Entry) error {
+	t.mu.Lock()
+	now := t.now()
+	var exhausted []BudgetStatus
+	for _, b := range t.budgets {
+		if b.match != nil && !b.match(entry) {
+			continue
+		}
+		b.roll(now)
+		b.used++
+		if b.used == b.allowed+1 {
+			exhausted = append(exhausted, b.status())
+		}
+	}
+	fn := t.onExhausted
+	t.mu.Unlock()
+
+	if fn != nil {
+		for _, s := range exhausted {
+			fn(s)
+		}
+	}
+	return nil
+}
+
+// roll starts a new window if the current one is over.
+func (b *budget) roll(now time.Time) {
+	if now.Sub(b.windowStart) >= time.Hour {
+		b.windowStart = now
+		b.used = 0
+	}
+}
+
+func (b *budget) status() BudgetStatus {
+	remaining := b.allowed - b.used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return BudgetStatus{
+		Name:        b.name,
+		Allowed:     b.allowed,
+		Used:        b.used,
+		Remaining:   remaining,
+		Exhausted:   b.used > b.allowed,
+		WindowStart: b.windowStart,
+	}
+}
+
+var _ logrus.Hook = (*BudgetTracker)(nil)
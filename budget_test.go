@@ -0,0 +1,53 @@
+package errorlogger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBudgetTracker(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	tracker := NewBudgetTracker(e)
+
+	now := time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	tracker.DefineBudget("all", 2)
+	tracker.DefineBudgetFunc("db", 5, func(entry *Entry) bool { return entry.Data["component"] == "db" })
+
+	for i := 0; i < 3; i++ {
+		e.Error("failure")
+	}
+	e.WithField("component", "db").Error("db failure")
+	e.Warn("warnings are not counted")
+
+	want := []BudgetStatus{
+		{Name: "all", Allowed: 2, Used: 4, Remaining: 0, Exhausted: true, WindowStart: now},
+		{Name: "db", Allowed: 5, Used: 1, Remaining: 4, Exhausted: false, WindowStart: now},
+	}
+	got := tracker.Status()
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("BudgetTracker.Status()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if n := strings.Count(buf.String(), "error budget exhausted"); n != 1 {
+		t.Errorf("BudgetTracker exhausted warning count = %d, want 1", n)
+	}
+
+	now = now.Add(time.Hour)
+	if got := tracker.Status()[0]; got.Used != 0 || got.Exhausted {
+		t.Errorf("BudgetTracker.Status() after window = %+v, want reset", got)
+	}
+
+	var exhausted []string
+	tracker.OnExhausted(func(s BudgetStatus) { exhausted = append(exhausted, s.Name) })
+	for i := 0; i < 3; i++ {
+		e.Error("failure")
+	}
+	if len(exhausted) != 1 || exhausted[0] != "all" {
+		t.Errorf("BudgetTracker.OnExhausted() calls = %v, want [all]", exhausted)
+	}
+}
@@ -0,0 +1,36 @@
+package errorlogger
+
+import "sync/atomic"
+
+// SetCallerSkip sets the number of frames skipped above the
+// code that called the logger when the caller is reported,
+// for wrapper layers around the logger:
+//  log.SetReportCaller(true)
+//  log.SetCallerSkip(1) // report the caller of logError
+//
+//  func logError(err error) error { return log.Err(err) }
+//
+// With SetReportCaller(true), logrus reports the frame of
+// this package that called it. The logger replaces it with
+// the function, file, and line of the code that called Err
+// or any other logging method, skipping the frames of
+// logrus and this package, and then skip more.
+func (e *errorLogger) SetCallerSkip(skip int) {
+	if skip < 0 {
+		skip = 0
+	}
+	atomic.StoreInt32(&e.callerSkip, int32(skip))
+}
+
+// fixCaller replaces the caller reported by logrus, if any,
+// with the caller outside of logrus and this package.
+func (e *errorLogger) fixCaller(entry *Entry) {
+	if entry.Caller == nil {
+		return
+	}
+	f := callerFrameSkip(int(atomic.LoadInt32(&e.callerSkip)))
+	if f.PC == 0 {
+		return
+	}
+	entry.Caller = &f
+}
@@ -0,0 +1,42 @@
+package errorlogger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_SetCallerSkip(t *testing.T) {
+	tests := []struct {
+		name     string
+		skip     int
+		log      func(e *errorLogger)
+		wantFunc string
+	}{
+		{"Err", 0, func(e *errorLogger) { e.Err(errFake) }, "TestErrorLogger_SetCallerSkip.func1"},
+		{"Info", 0, func(e *errorLogger) { e.Info("x") }, "TestErrorLogger_SetCallerSkip.func2"},
+		{"entry", 0, func(e *errorLogger) { e.WithField("k", 1).Warn("x") }, "TestErrorLogger_SetCallerSkip.func3"},
+		{"wrapper", 0, func(e *errorLogger) { logThroughWrapper(e) }, "logThroughWrapper"},
+		{"wrapper skipped", 1, func(e *errorLogger) { logThroughWrapper(e) }, "TestErrorLogger_SetCallerSkip.func5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			e.SetReportCaller(true)
+			e.SetCallerSkip(tt.skip)
+			tt.log(e)
+
+			out := buf.String()
+			if !strings.Contains(out, "caller_test.go:") || !strings.Contains(out, tt.wantFunc) {
+				t.Errorf("output = %q, want the caller %s in caller_test.go", out, tt.wantFunc)
+			}
+		})
+	}
+
+	e, buf := newBufferLogger(InfoLevel)
+	e.Err(errFake)
+	if strings.Contains(buf.String(), "caller_test.go") {
+		t.Errorf("output = %q, want no caller without SetReportCaller", buf.String())
+	}
+}
+
+func logThroughWrapper(e *errorLogger) { e.Err(errFake) }
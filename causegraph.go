@@ -0,0 +1,180 @@
+package errorlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// maxCauseDepth limits the depth of the chains walked by
+// ExportCauseGraph.
+const maxCauseDepth = 64
+
+type (
+	// CauseGraph is the graph of the causes of an error, as
+	// returned by ExportCauseGraph. Node 0 is the error
+	// itself, and an edge leads from an error to each error
+	// it wraps.
+	CauseGraph struct {
+		Nodes []CauseNode `json:"nodes"`
+		Edges []CauseEdge `json:"edges"`
+	}
+
+	// CauseNode is an error in a CauseGraph.
+	CauseNode struct {
+		ID   int    `json:"id"`
+		Type string `json:"type"`
+
+		// Message is the message of the error without the
+		// message of the error it wraps, if it ends with it.
+		Message string `json:"message"`
+	}
+
+	// CauseEdge leads from an error to an error it wraps.
+	CauseEdge struct {
+		From int `json:"from"`
+		To   int `json:"to"`
+	}
+)
+
+// ExportCauseGraph walks the errors wrapped by err, through
+// both Unwrap() error and Unwrap() []error as returned by
+// errors.Join, and returns the graph of its causes with
+// their types and messages, to make failures of concurrent
+// pipelines that combine many errors comprehensible:
+//  g := errorlogger.ExportCauseGraph(err)
+//  g.WriteFile("postmortem/causes.dot")
+//
+// An error wrapped more than once through the same pointer
+// is a single node. It returns nil if err is nil.
+func ExportCauseGraph(err error) *CauseGraph {
+	if err == nil {
+		return nil
+	}
+	g := &CauseGraph{}
+	g.add(err, map[error]int{}, 0)
+	return g
+}
+
+// add adds err and its causes to g and returns its node.
+func (g *CauseGraph) add(err error, seen map[error]int, depth int) int {
+	ptr := reflect.ValueOf(err).Kind() == reflect.Ptr
+	if ptr {
+		if id, ok := seen[err]; ok {
+			return id
+		}
+	}
+
+	id := len(g.Nodes)
+	g.Nodes = append(g.Nodes, CauseNode{ID: id, Type: fmt.Sprintf("%T", err)})
+	if ptr {
+		seen[err] = id
+	}
+
+	var causes []error
+	if depth < maxCauseDepth {
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			causes = u.Unwrap()
+		case interface{ Unwrap() error }:
+			causes = []error{u.Unwrap()}
+		}
+	}
+
+	msg := safeSprint(err)
+	var only error
+	n := 0
+	for _, c := range causes {
+		if c == nil {
+			continue
+		}
+		only = c
+		n++
+		i := len(g.Edges)
+		g.Edges = append(g.Edges, CauseEdge{From: id})
+		g.Edges[i].To = g.add(c, seen, depth+1)
+	}
+	if n == 1 {
+		msg = strings.TrimSuffix(msg, ": "+safeSprint(only))
+	} else if n > 1 {
+		msg = ""
+	}
+	g.Nodes[id].Message = msg
+	return id
+}
+
+// DOT returns the graph in the Graphviz DOT language:
+//  dot -Tsvg causes.dot > causes.svg
+func (g *CauseGraph) DOT() []byte {
+	var b bytes.Buffer
+	b.WriteString("digraph causes {\n\tnode [shape=box];\n")
+	for _, n := range g.Nodes {
+		label := n.Type
+		if n.Message != "" {
+			label += "\n" + n.Message
+		}
+		fmt.Fprintf(&b, "\tn%d [label=%s];\n", n.ID, dotQuote(label))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\tn%d -> n%d;\n", e.From, e.To)
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+// dotQuote returns s as a quoted DOT string.
+func dotQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		default:
+			if r < 0x20 {
+				r += 0x2400 // as SanitizeControl
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// JSON returns the graph as indented JSON.
+func (g *CauseGraph) JSON() []byte {
+	b, _ := json.MarshalIndent(g, "", "  ")
+	return append(b, '\n')
+}
+
+// WriteFile writes the graph to the named file, as DOT if
+// the name ends in ".dot" or ".gv" and as JSON otherwise,
+// creating its directory if needed.
+func (g *CauseGraph) WriteFile(name string) error {
+	data := g.JSON()
+	switch filepath.Ext(name) {
+	case ".dot", ".gv":
+		data = g.DOT()
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0o640)
+}
+
+// AttachCauseGraph logs err like Attach with the JSON graph
+// of its causes returned by ExportCauseGraph as the
+// attachment "causes.json".
+func (e *errorLogger) AttachCauseGraph(err error) error {
+	if err == nil || !e.enabled() {
+		return err
+	}
+	return e.Attach(err, "causes.json", ExportCauseGraph(err).JSON())
+}
@@ -0,0 +1,129 @@
+package errorlogger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// joinedErr is an error that wraps several errors, as
+// returned by errors.Join.
+type joinedErr []error
+
+func (j joinedErr) Error() string {
+	msgs := make([]string, len(j))
+	for i, err := range j {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (j joinedErr) Unwrap() []error { return j }
+
+func TestExportCauseGraph(t *testing.T) {
+	errDisk := errors.New("disk full")
+	errNet := errors.New("connection reset")
+	tests := []struct {
+		name      string
+		err       error
+		wantNodes []CauseNode
+		wantEdges []CauseEdge
+	}{
+		{"nil", nil, nil, nil},
+		{"single", errDisk, []CauseNode{{0, "*errors.errorString", "disk full"}}, nil},
+		{"wrapped", fmt.Errorf("save: %w", errDisk),
+			[]CauseNode{{0, "*fmt.wrapError", "save"}, {1, "*errors.errorString", "disk full"}},
+			[]CauseEdge{{0, 1}}},
+		{"joined", fmt.Errorf("worker: %w", joinedErr{errDisk, fmt.Errorf("upload: %w", errNet), errDisk}),
+			[]CauseNode{
+				{0, "*fmt.wrapError", "worker"},
+				{1, "errorlogger.joinedErr", ""},
+				{2, "*errors.errorString", "disk full"},
+				{3, "*fmt.wrapError", "upload"},
+				{4, "*errors.errorString", "connection reset"},
+			},
+			[]CauseEdge{{0, 1}, {1, 2}, {1, 3}, {3, 4}, {1, 2}}},
+		{"message not ending with cause", fmt.Errorf("%w (retrying)", errNet),
+			[]CauseNode{{0, "*fmt.wrapError", "connection reset (retrying)"}, {1, "*errors.errorString", "connection reset"}},
+			[]CauseEdge{{0, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := ExportCauseGraph(tt.err)
+			if tt.err == nil {
+				if g != nil {
+					t.Errorf("ExportCauseGraph(nil) = %v, want nil", g)
+				}
+				return
+			}
+			if !reflect.DeepEqual(g.Nodes, tt.wantNodes) {
+				t.Errorf("Nodes = %v, want %v", g.Nodes, tt.wantNodes)
+			}
+			if !reflect.DeepEqual(g.Edges, tt.wantEdges) {
+				t.Errorf("Edges = %v, want %v", g.Edges, tt.wantEdges)
+			}
+		})
+	}
+}
+
+func TestCauseGraph_WriteFile(t *testing.T) {
+	g := ExportCauseGraph(fmt.Errorf("load \"cfg\": %w", errors.New("bad\nfile")))
+	dir := t.TempDir()
+
+	dot := filepath.Join(dir, "out", "causes.dot")
+	if err := g.WriteFile(dot); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(dot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"digraph causes {", `n0 [label="*fmt.wrapError\nload \"cfg\""];`, `n1 [label="*errors.errorString\nbad\nfile"];`, "n0 -> n1;"} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("DOT = %q, want %q", b, want)
+		}
+	}
+
+	js := filepath.Join(dir, "causes.json")
+	if err := g.WriteFile(js); err != nil {
+		t.Fatal(err)
+	}
+	b, err = os.ReadFile(js)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got CauseGraph
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&got, g) {
+		t.Errorf("JSON = %+v, want %+v", got, *g)
+	}
+}
+
+func TestErrorLogger_AttachCauseGraph(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	dir := t.TempDir()
+	e.SetAttachmentDir(dir)
+
+	err := fmt.Errorf("save: %w", errFake)
+	if got := e.AttachCauseGraph(err); got != err {
+		t.Errorf("AttachCauseGraph() = %v, want %v", got, err)
+	}
+	if !strings.Contains(buf.String(), "attachment=causes.json") {
+		t.Errorf("output = %q, want the causes.json attachment", buf.String())
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("attachment dir has %d files, want 1", len(entries))
+	}
+	b, _ := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if !strings.Contains(string(b), `"message": "save"`) {
+		t.Errorf("attachment = %q, want the cause graph", b)
+	}
+}
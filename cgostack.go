@@ -0,0 +1,189 @@
+package errorlogger
+
+import (
+	"debug/elf"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CgoKey is the field set to true on panics whose stack
+// passes through cgo.
+const CgoKey = "cgo"
+
+// cgoMarkers are the stack trace text that shows a stack
+// passes through cgo.
+var cgoMarkers = []string{
+	"runtime.cgocall(",
+	"runtime.asmcgocall",
+	"runtime.cgocallback",
+	"._Cfunc_",
+	"non-Go function",
+}
+
+// CleanCgoStack returns stack, a Go stack trace as returned
+// by debug.Stack, with its frames in C code made readable,
+// and reports whether the stack passes through cgo.
+//
+// Frames that the runtime could not symbolize, printed as
+// "non-Go function at pc=0x...", are looked up in the
+// symbol table of the executable where possible. Runs of
+// frames that remain unreadable are folded into one line:
+//  non-Go function at pc=0x7f3a12c4e1a0 (+5 more frames folded)
+//
+// Symbolization is best effort: functions in shared
+// libraries and stripped executables stay unreadable.
+func CleanCgoStack(stack []byte) ([]byte, bool) {
+	s := string(stack)
+	cgo := false
+	for _, m := range cgoMarkers {
+		if strings.Contains(s, m) {
+			cgo = true
+			break
+		}
+	}
+	if !cgo || !strings.Contains(s, "non-Go function") {
+		return stack, cgo
+	}
+
+	lines := strings.Split(s, "\n")
+	var out []string
+	var folded []uintptr
+	flush := func() {
+		if len(folded) == 0 {
+			return
+		}
+		line := fmt.Sprintf("non-Go function at pc=%#x", folded[0])
+		if len(folded) > 1 {
+			line += fmt.Sprintf(" (+%d more frames folded)", len(folded)-1)
+		}
+		out = append(out, line)
+		folded = folded[:0]
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		var pcText string
+		switch {
+		case strings.HasPrefix(line, "non-Go function at pc="):
+			pcText = strings.TrimPrefix(line, "non-Go function at pc=")
+		case line == "non-Go function" && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t"):
+			next := lines[i+1]
+			if j := strings.LastIndex(next, "pc="); j >= 0 {
+				pcText = next[j+len("pc="):]
+				i++
+			}
+		}
+		if pcText == "" {
+			flush()
+			out = append(out, line)
+			continue
+		}
+
+		pc, err := strconv.ParseUint(pcText, 0, 64)
+		if err != nil {
+			flush()
+			out = append(out, line)
+			continue
+		}
+		if name, off, ok := symbolize(uintptr(pc)); ok {
+			flush()
+			out = append(out, fmt.Sprintf("%s+%#x (C)", name, off), fmt.Sprintf("\tpc=%#x", pc))
+			continue
+		}
+		folded = append(folded, uintptr(pc))
+	}
+	flush()
+	return []byte(strings.Join(out, "\n")), true
+}
+
+// symbolize returns the function containing pc and the
+// offset of pc in it.
+func symbolize(pc uintptr) (name string, off uintptr, ok bool) {
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		return fn.Name(), pc - fn.Entry(), true
+	}
+	return lookupExeSymbol(pc)
+}
+
+// lookupExeSymbol returns the function symbol of the
+// executable containing pc and the offset of pc in it.
+func lookupExeSymbol(pc uintptr) (name string, off uintptr, ok bool) {
+	syms := loadExeSymbols()
+	if syms == nil || uint64(pc) < syms.bias {
+		return "", 0, false
+	}
+	addr := uint64(pc) - syms.bias
+	i := sort.Search(len(syms.syms), func(i int) bool { return syms.syms[i].Value > addr }) - 1
+	if i < 0 {
+		return "", 0, false
+	}
+	sym := syms.syms[i]
+	if addr >= sym.Value+sym.Size && addr != sym.Value {
+		return "", 0, false
+	}
+	return sym.Name, uintptr(addr - sym.Value), true
+}
+
+// exeSymbols are the function symbols of the executable,
+// sorted by address, and the difference between the
+// addresses at run time and in the file, which is not zero
+// for position independent executables.
+type exeSymbols struct {
+	syms []elf.Symbol
+	bias uint64
+}
+
+var (
+	exeSymbolsOnce sync.Once
+	exeSymbolsVal  *exeSymbols
+)
+
+// loadExeSymbols reads the symbol table of the executable
+// once. It returns nil if the executable is not an ELF file
+// or has no symbol table.
+func loadExeSymbols() *exeSymbols {
+	exeSymbolsOnce.Do(func() {
+		path, err := os.Executable()
+		if err != nil {
+			return
+		}
+		f, err := elf.Open(path)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		all, err := f.Symbols()
+		if err != nil {
+			return
+		}
+
+		// The address of a known function at run time and
+		// in the file gives the load bias.
+		anchor := runtime.FuncForPC(reflect.ValueOf(loadExeSymbols).Pointer())
+		var bias uint64
+		found := false
+		syms := make([]elf.Symbol, 0, len(all))
+		for _, s := range all {
+			if elf.ST_TYPE(s.Info) != elf.STT_FUNC || s.Value == 0 {
+				continue
+			}
+			syms = append(syms, s)
+			if anchor != nil && s.Name == anchor.Name() {
+				bias = uint64(anchor.Entry()) - s.Value
+				found = true
+			}
+		}
+		if !found {
+			return
+		}
+		sort.Slice(syms, func(i, j int) bool { return syms[i].Value < syms[j].Value })
+		exeSymbolsVal = &exeSymbols{syms: syms, bias: bias}
+	})
+	return exeSymbolsVal
+}
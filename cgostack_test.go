@@ -0,0 +1,89 @@
+package errorlogger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCleanCgoStack(t *testing.T) {
+	tests := []struct {
+		name    string
+		stack   string
+		want    string
+		wantCgo bool
+	}{
+		{
+			"go only",
+			"goroutine 1 [running]:\nmain.main()\n\t/app/main.go:5 +0x1d\n",
+			"goroutine 1 [running]:\nmain.main()\n\t/app/main.go:5 +0x1d\n",
+			false,
+		},
+		{
+			"cgo call",
+			"goroutine 1 [syscall]:\nruntime.cgocall(0x4a2f10, 0xc000052f58)\n\t/go/src/runtime/cgocall.go:157 +0x4b\nmain._Cfunc_crash()\n\t_cgo_gotypes.go:39 +0x45\n",
+			"goroutine 1 [syscall]:\nruntime.cgocall(0x4a2f10, 0xc000052f58)\n\t/go/src/runtime/cgocall.go:157 +0x4b\nmain._Cfunc_crash()\n\t_cgo_gotypes.go:39 +0x45\n",
+			true,
+		},
+		{
+			"folded",
+			"non-Go function at pc=0x10\nnon-Go function at pc=0x20\nnon-Go function at pc=0x30\nruntime.cgocall(0x1, 0x2)\n",
+			"non-Go function at pc=0x10 (+2 more frames folded)\nruntime.cgocall(0x1, 0x2)\n",
+			true,
+		},
+		{
+			"symbolizer format",
+			"non-Go function\n\tpc=0x10\nnon-Go function\n\tcrash.c:3 pc=0x20\nmain.main()\n",
+			"non-Go function at pc=0x10 (+1 more frames folded)\nmain.main()\n",
+			true,
+		},
+		{
+			"single",
+			"non-Go function at pc=0x10\nmain.main()\n",
+			"non-Go function at pc=0x10\nmain.main()\n",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, cgo := CleanCgoStack([]byte(tt.stack))
+			if string(got) != tt.want || cgo != tt.wantCgo {
+				t.Errorf("CleanCgoStack() = %q, %v, want %q, %v", got, cgo, tt.want, tt.wantCgo)
+			}
+		})
+	}
+}
+
+func TestCleanCgoStack_symbolize(t *testing.T) {
+	pc := reflect.ValueOf(TestCleanCgoStack).Pointer()
+	name, off, ok := lookupExeSymbol(pc + 4)
+	if !ok {
+		t.Skip("executable has no readable symbol table")
+	}
+	if !strings.HasSuffix(name, ".TestCleanCgoStack") || off != 4 {
+		t.Errorf("lookupExeSymbol() = %s+%d, want TestCleanCgoStack+4", name, off)
+	}
+
+	stack := fmt.Sprintf("non-Go function at pc=0x10\nnon-Go function at pc=%#x\nruntime.cgocall(0x1, 0x2)\n", pc)
+	got, _ := CleanCgoStack([]byte(stack))
+	if !strings.Contains(string(got), "non-Go function at pc=0x10\n") || !strings.Contains(string(got), ".TestCleanCgoStack+0x0 (C)") {
+		t.Errorf("CleanCgoStack() = %q, want the folded and symbolized frames", got)
+	}
+}
+
+func TestErrorLogger_LogPanic_cgo(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	var got *Entry
+	e.AddHook(&countHook{levels: AllLevels, fn: func(entry *Entry) { got = entry }})
+
+	e.LogPanic(&PanicError{Value: "boom", Stack: []byte("non-Go function at pc=0x10\nnon-Go function at pc=0x20\n")})
+	if got.Data[CgoKey] != true || got.Data[StackKey] != "non-Go function at pc=0x10 (+1 more frames folded)\n" {
+		t.Errorf("fields = %v, want cgo and a folded stack", got.Data)
+	}
+
+	e.LogPanic("boom")
+	if _, ok := got.Data[CgoKey]; ok {
+		t.Errorf("fields = %v, want no cgo field", got.Data)
+	}
+}
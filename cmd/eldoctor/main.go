@@ -0,0 +1,67 @@
+// Command eldoctor checks the environment a logger runs in
+// and prints a report: TTY and color support, write
+// permissions for log files, reachability of network sinks,
+// clock sanity, and color related environment variables.
+// It also explains which source set each configuration
+// field: the defaults, the -config file, or the environment.
+//
+// Usage:
+//  eldoctor [-config path] [-file path]... [-addr [network://]host:port]... [-timeout d]
+//
+// The exit status is 0 if no check failed and 69
+// (EX_UNAVAILABLE) otherwise.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/skeptycal/errorlogger"
+)
+
+// listFlag is a repeatable string flag.
+type listFlag []string
+
+func (l *listFlag) String() string { return fmt.Sprint(*l) }
+
+func (l *listFlag) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+func main() {
+	var opts errorlogger.DoctorOptions
+	flag.Var((*listFlag)(&opts.Files), "file", "log `path` that must be writable (repeatable)")
+	flag.Var((*listFlag)(&opts.Addrs), "addr", "network sink `address` that must be reachable (repeatable)")
+	flag.DurationVar(&opts.Timeout, "timeout", errorlogger.DefaultDoctorTimeout, "dial timeout for network sinks")
+	config := flag.String("config", "", "configuration file `path` to explain")
+	flag.Parse()
+
+	if flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(errorlogger.ExitUsage)
+	}
+
+	opts.Config = []errorlogger.ConfigLayer{errorlogger.DefaultConfigLayer()}
+	if *config != "" {
+		file, err := errorlogger.LoadConfigLayer(*config)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "eldoctor:", err)
+			os.Exit(errorlogger.ExitConfig)
+		}
+		opts.Config = append(opts.Config, file)
+	}
+	env, err := errorlogger.EnvConfigLayer()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "eldoctor:", err)
+		os.Exit(errorlogger.ExitConfig)
+	}
+	opts.Config = append(opts.Config, env)
+
+	r := errorlogger.Doctor(opts)
+	r.WriteTo(os.Stdout)
+	if !r.OK() {
+		os.Exit(errorlogger.ExitUnavailable)
+	}
+}
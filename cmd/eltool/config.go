@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/skeptycal/errorlogger"
+)
+
+// runConfig prints the effective configuration after
+// merging the config file, environment, and flags, or
+// writes a commented starter config with -init.
+func runConfig(args []string) int {
+	fs := flag.NewFlagSet("eltool config", flag.ContinueOnError)
+	fileName := fs.String("config", "", "configuration `file` (YAML or JSON)")
+	asJSON := fs.Bool("json", false, "print JSON instead of YAML")
+	sources := fs.Bool("sources", false, "print the source of each setting to stderr")
+	initFile := fs.String("init", "", "write a commented starter config to `file` (- for stdout)")
+
+	flags := errorlogger.DefaultConfig()
+	flags.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return errorlogger.ExitUsage
+	}
+
+	if *initFile != "" {
+		return writeStarter(*initFile)
+	}
+
+	file := errorlogger.DefaultConfigLayer()
+	if *fileName != "" {
+		var err error
+		if file, err = errorlogger.LoadConfigLayer(*fileName); err != nil {
+			return fail(errorlogger.ExitConfig, err)
+		}
+	}
+	env, err := errorlogger.EnvConfigLayer()
+	if err != nil {
+		return fail(errorlogger.ExitConfig, err)
+	}
+
+	c, src := errorlogger.MergeConfig(errorlogger.DefaultConfigLayer(), file, env, errorlogger.FlagConfigLayer(fs, &flags))
+	if *sources {
+		fmt.Fprintln(os.Stderr, "# sources:", src)
+	}
+
+	if *asJSON {
+		err = c.WriteJSON(os.Stdout)
+	} else {
+		err = c.WriteYAML(os.Stdout)
+	}
+	if err != nil {
+		return fail(errorlogger.ExitCode(err), err)
+	}
+	return errorlogger.ExitOK
+}
+
+func writeStarter(name string) int {
+	var w io.Writer = os.Stdout
+	if name != "-" {
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return fail(errorlogger.ExitCode(err), err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := errorlogger.WriteStarterConfig(w); err != nil {
+		return fail(errorlogger.ExitCode(err), err)
+	}
+	return errorlogger.ExitOK
+}
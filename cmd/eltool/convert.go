@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/skeptycal/errorlogger"
+)
+
+// runConvert converts JSON log files to the binary format or
+// back and writes the result to stdout.
+func runConvert(args []string) int {
+	fs := flag.NewFlagSet("eltool convert", flag.ContinueOnError)
+	to := fs.String("to", "binary", "output `format`: binary or json")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: eltool convert [-to binary|json] [file...] > output")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return errorlogger.ExitUsage
+	}
+
+	var convert func(io.Reader, io.Writer) error
+	switch *to {
+	case "binary":
+		convert = errorlogger.JSONToBinary
+	case "json":
+		convert = errorlogger.BinaryToJSON
+	default:
+		fs.Usage()
+		return errorlogger.ExitUsage
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	if fs.NArg() == 0 {
+		if err := convert(os.Stdin, w); err != nil {
+			return fail(errorlogger.ExitDataErr, err)
+		}
+	}
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return fail(errorlogger.ExitNoInput, err)
+		}
+		err = convert(f, w)
+		f.Close()
+		if err != nil {
+			return fail(errorlogger.ExitDataErr, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fail(errorlogger.ExitIOErr, err)
+	}
+	return errorlogger.ExitOK
+}
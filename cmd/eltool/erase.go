@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/skeptycal/errorlogger"
+)
+
+// saltEnv is the environment variable that holds the salt
+// of erase when neither -salt nor -salt-file is given.
+const saltEnv = "ELTOOL_ERASE_SALT"
+
+// erasure is the result of erasing one file: its manifest,
+// and the error if the erasure failed and the file was left
+// unchanged.
+type erasure struct {
+	errorlogger.ErasureManifest
+	Error string `json:"error,omitempty"`
+}
+
+// runErase erases the entries of a subject from JSON log
+// files in place and prints the audit manifests as JSON.
+//
+// It stops at the first file that cannot be erased. The
+// manifests of the files erased before it are still
+// written, followed by the failed file and its error, so
+// that the audit record of the completed erasures is kept.
+func runErase(args []string) int {
+	fs := flag.NewFlagSet("eltool erase", flag.ContinueOnError)
+	subject := fs.String("subject", "", "subject to erase as `field=value`, e.g. user_id=42")
+	hash := fs.Bool("hash", false, "hash fields of matching entries instead of removing the entries")
+	fields := fs.String("fields", "", "comma separated `fields` to hash with -hash (default: the subject field)")
+	salt := fs.String("salt", "", "secret salt of hashed values, also the key of the subject HMAC in the manifest;\n"+
+		"visible to other users in the process list, so prefer -salt-file or $"+saltEnv)
+	saltFile := fs.String("salt-file", "", "read the salt from `file`")
+	manifest := fs.String("manifest", "", "write the audit manifest to `file` instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: eltool erase -subject field=value [flags] file...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return errorlogger.ExitUsage
+	}
+
+	field, value, ok := strings.Cut(*subject, "=")
+	if !ok || field == "" || fs.NArg() == 0 {
+		fs.Usage()
+		return errorlogger.ExitUsage
+	}
+
+	opts := errorlogger.ErasureOptions{Field: field, Value: value, Salt: *salt}
+	switch {
+	case *saltFile != "":
+		b, err := os.ReadFile(*saltFile)
+		if err != nil {
+			return fail(errorlogger.ExitNoInput, err)
+		}
+		opts.Salt = strings.TrimRight(string(b), "\r\n")
+	case opts.Salt == "":
+		opts.Salt = os.Getenv(saltEnv)
+	}
+	if *hash {
+		opts.Mode = errorlogger.HashFields
+		if opts.Salt == "" {
+			fmt.Fprintln(fs.Output(), "eltool erase: -hash needs a secret salt from -salt-file, $"+saltEnv+", or -salt")
+			return errorlogger.ExitUsage
+		}
+	}
+	if *fields != "" {
+		opts.Fields = strings.Split(*fields, ",")
+	}
+
+	var erasures []erasure
+	var failed error
+	for _, name := range fs.Args() {
+		m, err := errorlogger.EraseFile(name, opts)
+		if err != nil {
+			m.File, m.Field, m.Mode = name, opts.Field, opts.Mode.String()
+			if m.Time.IsZero() {
+				m.Time = time.Now().UTC()
+			}
+			m.Redactions = []errorlogger.Redaction{} // none were made
+			erasures = append(erasures, erasure{m, err.Error()})
+			failed = err
+			break
+		}
+		erasures = append(erasures, erasure{ErasureManifest: m})
+	}
+
+	out := os.Stdout
+	if *manifest != "" {
+		f, err := os.Create(*manifest)
+		if err != nil {
+			return fail(errorlogger.ExitCantCreate, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(erasures); err != nil {
+		return fail(errorlogger.ExitIOErr, err)
+	}
+	if failed != nil {
+		return fail(errorlogger.ExitCode(failed), failed)
+	}
+	return errorlogger.ExitOK
+}
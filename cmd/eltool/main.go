@@ -0,0 +1,63 @@
+// Command eltool is a collection of utilities for working
+// with errorlogger configuration and log files.
+//
+// Usage:
+//  eltool <command> [arguments]
+//
+// Run "eltool <command> -h" for the flags of a command.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/skeptycal/errorlogger"
+)
+
+// command is an eltool subcommand. It returns the exit
+// status of the process.
+type command struct {
+	summary string
+	run     func(args []string) int
+}
+
+var commands = map[string]command{
+	"config":  {"print the effective configuration or generate a starter config", runConfig},
+	"convert": {"convert log files between the JSON and binary formats", runConvert},
+	"erase":   {"erase the entries of a subject from JSON log files", runErase},
+	"tail":    {"print the entries of JSON log files, optionally of one session", runTail},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(errorlogger.ExitUsage)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "eltool: unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(errorlogger.ExitUsage)
+	}
+	os.Exit(cmd.run(os.Args[2:]))
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, "usage: eltool <command> [arguments]\n\ncommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].summary)
+	}
+}
+
+// fail prints err and returns status.
+func fail(status int, err error) int {
+	fmt.Fprintln(os.Stderr, "eltool:", err)
+	return status
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/skeptycal/errorlogger"
+)
+
+// runTail prints the last entries of JSON log files, or of
+// stdin, optionally only those of one session.
+func runTail(args []string) int {
+	fs := flag.NewFlagSet("eltool tail", flag.ContinueOnError)
+	session := fs.String("session", "", "print only the entries of the session with this `id` or id prefix")
+	list := fs.Bool("sessions", false, "list the sessions in the input instead of printing entries")
+	n := fs.Int("n", 0, "print only the last `n` matching entries (0 for all)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: eltool tail [flags] [file...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return errorlogger.ExitUsage
+	}
+
+	t := tailer{filter: errorlogger.SessionFilter{ID: *session}, max: *n}
+	if fs.NArg() == 0 {
+		if err := t.read(os.Stdin); err != nil {
+			return fail(errorlogger.ExitIOErr, err)
+		}
+	}
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return fail(errorlogger.ExitNoInput, err)
+		}
+		err = t.read(f)
+		f.Close()
+		if err != nil {
+			return fail(errorlogger.ExitIOErr, err)
+		}
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	if *list {
+		for _, s := range t.sessions {
+			fmt.Fprintf(w, "%s\t%s\t%d\n", s.id, s.first, s.count)
+		}
+		return errorlogger.ExitOK
+	}
+	for _, line := range t.lines {
+		w.Write(line)
+	}
+	return errorlogger.ExitOK
+}
+
+// tailer collects the matching lines and the sessions of
+// JSON log input. Lines that are not JSON objects are
+// skipped.
+type tailer struct {
+	filter errorlogger.SessionFilter
+	max    int
+
+	lines    [][]byte
+	sessions []*sessionInfo
+	byID     map[string]*sessionInfo
+}
+
+type sessionInfo struct {
+	id    string
+	first string // time of the first entry
+	count int
+}
+
+func (t *tailer) read(r io.Reader) error {
+	sc := errorlogger.NewJSONScanner(r)
+	for sc.Scan() {
+		t.add(append([]byte(nil), sc.Bytes()...))
+	}
+	return sc.Err()
+}
+
+func (t *tailer) add(line []byte) {
+	var entry map[string]interface{}
+	if err := json.Unmarshal(line, &entry); err != nil || entry == nil {
+		return
+	}
+	if !t.filter.Match(entry) {
+		return
+	}
+
+	if id, ok := entry[errorlogger.SessionKey].(string); ok {
+		if t.byID == nil {
+			t.byID = make(map[string]*sessionInfo)
+		}
+		s, ok := t.byID[id]
+		if !ok {
+			s = &sessionInfo{id: id, first: fmt.Sprint(entry["time"])}
+			t.byID[id] = s
+			t.sessions = append(t.sessions, s)
+		}
+		s.count++
+	}
+
+	if line[len(line)-1] != '\n' {
+		line = append(line, '\n')
+	}
+	t.lines = append(t.lines, line)
+	if t.max > 0 && len(t.lines) > t.max {
+		t.lines = t.lines[1:]
+	}
+}
@@ -1,33 +1,177 @@
+// Command errorlogger is an interactive demo of package
+// errorlogger. Each subcommand exercises a feature live and
+// prints its output to stdout, serving as both a manual test
+// rig and executable documentation.
+//
+// Usage:
+//  errorlogger [command]
+//
+// Commands are formats, levels, hooks, async, disable-benchmark,
+// and all (the default).
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
 	"github.com/skeptycal/errorlogger"
 )
 
-var log = errorlogger.Log
+// commands maps subcommand names to demos.
+var commands = map[string]func(){
+	"formats":           formats,
+	"levels":            levels,
+	"hooks":             hooks,
+	"async":             async,
+	"disable-benchmark": disableBenchmark,
+}
 
 func main() {
-	log.Info("sample log info")
+	name := "all"
+	if len(os.Args) > 1 {
+		name = os.Args[1]
+	}
+
+	if name == "all" {
+		for _, n := range commandNames() {
+			run(n)
+		}
+		return
+	}
+
+	if _, ok := commands[name]; !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\nusage: errorlogger [command]\n\ncommands: all %v\n", name, commandNames())
+		os.Exit(errorlogger.ExitUsage)
+	}
+	run(name)
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func run(name string) {
+	fmt.Printf("\n=== %s\n\n", name)
+	commands[name]()
+}
+
+// newLogger returns a logger that writes to stdout.
+func newLogger() errorlogger.ErrorLogger {
+	return errorlogger.NewWithOptions(true, "", nil, nil, &errorlogger.Logger{
+		Out:       os.Stdout,
+		Formatter: errorlogger.NewTextFormatter(),
+		Hooks:     make(logrus.LevelHooks),
+		Level:     errorlogger.InfoLevel,
+	})
+}
+
+func formats() {
+	e := newLogger()
+
+	fmt.Println("-- text (default)")
+	e.WithField("user", "gopher").Info("text formatter")
+
+	fmt.Println("-- text with full timestamp")
+	f := errorlogger.NewTextFormatter()
+	f.SetFullTimeStamp(true)
+	f.SetTimestampFormat(errorlogger.DefaultTimestampFormat)
+	e.SetFormatter(f)
+	e.WithField("user", "gopher").Info("text formatter with timestamp")
+
+	fmt.Println("-- JSON")
+	e.SetJSON(false)
+	e.WithField("user", "gopher").Info("json formatter")
+
+	fmt.Println("-- pretty JSON")
+	e.SetJSON(true)
+	e.WithField("user", "gopher").Info("pretty json formatter")
 }
 
-// func main() {
-// 	fmt.Printf("%25.25s %s\n", "raw os.Args[0]:", os.Args[0])
+func levels() {
+	e := newLogger()
 
-// 	arg0, err := osargsutils.Arg0()
-// 	if err != nil {
-// 		log.Fatal(err)
-// 	}
+	for _, lvl := range []string{"error", "info", "debug"} {
+		fmt.Printf("-- SetLogLevel(%q)\n", lvl)
+		if err := e.SetLogLevel(lvl); err != nil {
+			e.Error(err)
+			continue
+		}
+		e.Error("error message")
+		e.Info("info message")
+		e.Debug("debug message")
+	}
 
-// 	arg0 = filepath.Base(arg0)
-// 	fmt.Printf("%25.25s %s\n", "using Arg0():", arg0)
+	fmt.Println("-- SetOverride(\"tenant\", \"acme\", DebugLevel, time.Minute) at InfoLevel")
+	e.SetLevel(errorlogger.InfoLevel)
+	e.SetOverride("tenant", "acme", errorlogger.DebugLevel, time.Minute)
+	e.WithField("tenant", "acme").Debug("debug for acme is logged")
+	e.WithField("tenant", "other").Debug("debug for other is not logged")
+}
 
-// 	here, me, err := osargsutils.HereMe()
-// 	if err != nil {
-// 		log.Fatal(err)
-// 	}
+func hooks() {
+	e := newLogger()
+
+	fmt.Println("-- enrichers")
+	e.AddEnricher(errorlogger.KindEnricher)
+	e.AddEnricher(errorlogger.RetryEnricher)
+	e.AddEnricher(errorlogger.SyscallEnricher)
+	_ = e.Err(&os.PathError{Op: "open", Path: "/no/such/file", Err: os.ErrNotExist})
+
+	fmt.Println("-- error budget of 2 per hour")
+	t := errorlogger.NewBudgetTracker(e)
+	t.DefineBudget("demo", 2)
+	for i := 1; i <= 3; i++ {
+		_ = e.Err(fmt.Errorf("failure %d", i))
+	}
+	for _, s := range t.Status() {
+		fmt.Printf("budget %q: used %d of %d, exhausted: %v\n", s.Name, s.Used, s.Allowed, s.Exhausted)
+	}
+}
 
-// 	here = filepath.Base(here)
+func async() {
+	e := newLogger()
+	w := errorlogger.NewAsyncWriter(os.Stdout, 4)
+	e.SetOutput(w)
 
-// 	fmt.Printf("%25.25s - Here: %s Me: %s\n", "using HereMe():", here, me)
+	for i := 1; i <= 10; i++ {
+		e.Infof("async entry %d", i)
+	}
 
-// }
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	r, err := w.Close(ctx)
+	fmt.Printf("drain report: flushed=%d dropped=%d abandoned=%d err=%v\n", r.Flushed, r.Dropped, r.Abandoned, err)
+}
+
+func disableBenchmark() {
+	const n = 100000
+	e := newLogger()
+	e.SetOutput(errorlogger.Discard)
+	err := errors.New("benchmark error")
+
+	measure := func(name string) {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			_ = e.Err(err)
+		}
+		fmt.Printf("%-10s %8.1f ns/op\n", name, float64(time.Since(start).Nanoseconds())/n)
+	}
+
+	measure("enabled")
+	e.Disable()
+	measure("disabled")
+	e.Enable()
+
+	s := e.Stats()
+	fmt.Printf("Stats: %d errors logged, mean Err latency %v\n", s.Errors, s.ErrLatency.Mean())
+}
@@ -0,0 +1,154 @@
+package errorlogger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type (
+	// Coder is implemented by errors that carry a stable,
+	// machine-readable error code.
+	Coder interface {
+		Code() string
+	}
+
+	// Sentinel is a package-level sentinel error identified by
+	// a stable code. Sentinels are created with NewSentinel
+	// and are registered in the code registry so that
+	// errors.Is checks, exit codes, and documentation URLs
+	// stay in sync from a single declaration.
+	Sentinel struct {
+		code string
+		msg  string
+	}
+
+	// CodeInfo describes a registered error code.
+	CodeInfo struct {
+		Code     string
+		Message  string
+		ExitCode int
+		DocsURL  string
+	}
+)
+
+var (
+	codesMu     sync.RWMutex
+	codes       = make(map[string]*Sentinel)
+	exitCodes   = make(map[string]int)
+	docsBaseURL string
+)
+
+// NewSentinel returns a new sentinel error with the given
+// code and message and registers it in the code registry.
+//
+//  var ErrNoConfig = errorlogger.NewSentinel("E1001", "no configuration file found")
+//
+// It panics if code is empty or already registered, since
+// this is a programming error at declaration time.
+func NewSentinel(code, msg string) error {
+	if code == "" {
+		panic("errorlogger: NewSentinel with empty code")
+	}
+
+	codesMu.Lock()
+	defer codesMu.Unlock()
+	if _, dup := codes[code]; dup {
+		panic("errorlogger: NewSentinel called twice for code " + code)
+	}
+	s := &Sentinel{code: code, msg: msg}
+	codes[code] = s
+	return s
+}
+
+func (s *Sentinel) Error() string { return s.msg }
+
+// Code returns the registered code of the sentinel.
+func (s *Sentinel) Code() string { return s.code }
+
+// SetExitCode sets the process exit status used for errors
+// with the given registered code. The default is 1.
+func SetExitCode(code string, status int) error {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+	if _, ok := codes[code]; !ok {
+		return fmt.Errorf("error code %q is not registered: %w", code, ErrNotExist)
+	}
+	exitCodes[code] = status
+	return nil
+}
+
+// SetDocsBaseURL sets the base URL for error code
+// documentation. The docs URL of a code is the base URL
+// followed by the code. An empty base URL disables docs URLs.
+func SetDocsBaseURL(url string) {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+	docsBaseURL = url
+}
+
+// LookupCode returns information about a registered code.
+func LookupCode(code string) (CodeInfo, bool) {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+	s, ok := codes[code]
+	if !ok {
+		return CodeInfo{}, false
+	}
+	return codeInfo(s), true
+}
+
+// Codes returns information about all registered codes,
+// sorted by code.
+func Codes() []CodeInfo {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+	list := make([]CodeInfo, 0, len(codes))
+	for _, s := range codes {
+		list = append(list, codeInfo(s))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
+
+// CodeOf returns the code of the first error in the chain
+// of err that implements Coder.
+func CodeOf(err error) (string, bool) {
+	var c Coder
+	if errors.As(err, &c) {
+		return c.Code(), true
+	}
+	return "", false
+}
+
+// CodeKey is the field that holds the error code added by
+// CodeEnricher.
+const CodeKey = "code"
+
+// CodeEnricher is an Enricher that adds the registered code
+// of an error as the "code" field and its documentation URL,
+// if any, as the "docs_url" field.
+func CodeEnricher(err error, fields Fields) {
+	code, ok := CodeOf(err)
+	if !ok {
+		return
+	}
+	fields[CodeKey] = code
+	if info, ok := LookupCode(code); ok && info.DocsURL != "" {
+		fields["docs_url"] = info.DocsURL
+	}
+}
+
+// codeInfo returns the registry information for s. The
+// caller must hold codesMu.
+func codeInfo(s *Sentinel) CodeInfo {
+	info := CodeInfo{Code: s.code, Message: s.msg, ExitCode: kindExitCode(s)}
+	if status, ok := exitCodes[s.code]; ok {
+		info.ExitCode = status
+	}
+	if docsBaseURL != "" {
+		info.DocsURL = docsBaseURL + s.code
+	}
+	return info
+}
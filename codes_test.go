@@ -0,0 +1,62 @@
+package errorlogger
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var errTestSentinel = NewSentinel("ETEST1", "test sentinel")
+
+func TestNewSentinel(t *testing.T) {
+	wrapped := fmt.Errorf("loading: %w", errTestSentinel)
+	if !errors.Is(wrapped, errTestSentinel) {
+		t.Errorf("errors.Is(wrapped, sentinel) = false, want true")
+	}
+
+	code, ok := CodeOf(wrapped)
+	if !ok || code != "ETEST1" {
+		t.Errorf("CodeOf() = %q, %v, want %q, true", code, ok, "ETEST1")
+	}
+	if _, ok := CodeOf(errFake); ok {
+		t.Errorf("CodeOf() on uncoded error = true, want false")
+	}
+
+	SetDocsBaseURL("https://example.com/errors/")
+	defer SetDocsBaseURL("")
+	if err := SetExitCode("ETEST1", 65); err != nil {
+		t.Fatalf("SetExitCode() error = %v", err)
+	}
+	if err := SetExitCode("ENOPE", 65); !errors.Is(err, ErrNotExist) {
+		t.Errorf("SetExitCode() unregistered error = %v, want ErrNotExist", err)
+	}
+
+	want := CodeInfo{Code: "ETEST1", Message: "test sentinel", ExitCode: 65, DocsURL: "https://example.com/errors/ETEST1"}
+	if got, ok := LookupCode("ETEST1"); !ok || got != want {
+		t.Errorf("LookupCode() = %+v, %v, want %+v", got, ok, want)
+	}
+	if _, ok := LookupCode("ENOPE"); ok {
+		t.Errorf("LookupCode() unregistered = true, want false")
+	}
+
+	found := false
+	for _, info := range Codes() {
+		found = found || info.Code == "ETEST1"
+	}
+	if !found {
+		t.Errorf("Codes() missing ETEST1")
+	}
+}
+
+func TestNewSentinel_duplicate(t *testing.T) {
+	for _, code := range []string{"ETEST1", ""} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewSentinel(%q) did not panic", code)
+				}
+			}()
+			_ = NewSentinel(code, "duplicate")
+		}()
+	}
+}
@@ -0,0 +1,151 @@
+package errorlogger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// CmdKey is the field that holds the name of the command
+	// whose output is logged by CommandOutput.
+	CmdKey = "cmd"
+
+	// StreamKey is the field that holds the stream, stdout or
+	// stderr, of a line logged by CommandOutput.
+	StreamKey = "stream"
+
+	// maxCommandLine is the longest line of command output
+	// logged as one entry; longer lines are split.
+	maxCommandLine = 64 << 10
+)
+
+// lineLogger is a writer that logs each line written to it
+// as an entry, at level unless one of rules matches.
+type lineLogger struct {
+	mu     sync.Mutex
+	e      ErrorLogger
+	level  Level
+	fields Fields
+	rules  []PrefixRule
+	buf    []byte
+}
+
+// CommandOutput sets the standard output and standard error
+// of cmd, which must not have been started, to log each line
+// the command writes as an entry at stdoutLevel and
+// stderrLevel respectively, with the CmdKey and StreamKey
+// fields:
+//  cmd := exec.Command("git", "fetch")
+//  if err := log.CommandOutput(cmd, InfoLevel, WarnLevel); err != nil {
+//      return err
+//  }
+//  err := log.Err(cmd.Run())
+//
+// Every line has been logged when cmd.Wait returns, including
+// a last line without a trailing newline. Blank lines are not
+// logged. CommandOutput returns an error wrapping ErrInvalid
+// if cmd has been started or its output is already set.
+func (e *errorLogger) CommandOutput(cmd *exec.Cmd, stdoutLevel, stderrLevel Level) error {
+	if cmd.Process != nil {
+		return fmt.Errorf("command %s already started: %w", cmd, ErrInvalid)
+	}
+	if cmd.Stdout != nil || cmd.Stderr != nil {
+		return fmt.Errorf("command %s output already set: %w", cmd, ErrInvalid)
+	}
+
+	name := cmd.Path
+	if len(cmd.Args) > 0 {
+		name = cmd.Args[0]
+	}
+	name = filepath.Base(name)
+
+	cmd.Stdout = &lineLogger{e: e, level: stdoutLevel, fields: Fields{CmdKey: name, StreamKey: "stdout"}}
+	cmd.Stderr = &lineLogger{e: e, level: stderrLevel, fields: Fields{CmdKey: name, StreamKey: "stderr"}}
+	return nil
+}
+
+// Write logs the complete lines in p and keeps the rest
+// until the next write.
+func (w *lineLogger) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			if len(w.buf) >= maxCommandLine {
+				i = maxCommandLine
+				w.log(w.buf[:i])
+				w.buf = w.buf[i:]
+				continue
+			}
+			break
+		}
+		w.log(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// ReadFrom logs the lines read from r until EOF, including a
+// last line without a trailing newline. The exec package
+// copies the output of a command with io.Copy, which calls
+// ReadFrom, so the output is logged completely before
+// cmd.Wait returns.
+func (w *lineLogger) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	p := make([]byte, 32<<10)
+	for {
+		m, err := r.Read(p)
+		if m > 0 {
+			n += int64(m)
+			_, _ = w.Write(p[:m])
+		}
+		if err == io.EOF {
+			w.flush()
+			return n, nil
+		}
+		if err != nil {
+			w.flush()
+			return n, err
+		}
+	}
+}
+
+// Close logs the rest of the last line.
+func (w *lineLogger) Close() error {
+	w.flush()
+	return nil
+}
+
+// flush logs the rest of the last line.
+func (w *lineLogger) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.log(w.buf)
+	w.buf = nil
+}
+
+// log logs line, without a trailing carriage return, unless
+// it is blank.
+func (w *lineLogger) log(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+	level, msg := w.level, string(line)
+	if w.rules != nil {
+		level, msg = w.classify(msg)
+	}
+	w.e.WithFields(w.fields).Log(level, msg)
+}
+
+var _ io.ReaderFrom = (*lineLogger)(nil)
@@ -0,0 +1,62 @@
+package errorlogger
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_CommandOutput(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+
+	e, buf := newBufferLogger(InfoLevel)
+	cmd := exec.Command(sh, "-c", `printf 'one\r\n\ntwo\n'; printf 'oops' >&2`)
+	if err := e.CommandOutput(cmd, InfoLevel, WarnLevel); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("logged %d lines, want 3: %q", len(lines), lines)
+	}
+	for _, want := range []string{"level=info msg=one cmd=sh stream=stdout", "level=info msg=two cmd=sh stream=stdout", "level=warning msg=oops cmd=sh stream=stderr"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output = %q, want %q", buf.String(), want)
+		}
+	}
+
+	if err := e.CommandOutput(cmd, InfoLevel, WarnLevel); !errors.Is(err, ErrInvalid) {
+		t.Errorf("CommandOutput() of a started command = %v, want ErrInvalid", err)
+	}
+}
+
+func TestLineLogger_Write(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	w := &lineLogger{e: e, level: InfoLevel}
+
+	_, _ = w.Write([]byte("par"))
+	if buf.Len() != 0 {
+		t.Fatalf("partial line logged: %q", buf.String())
+	}
+	_, _ = w.Write([]byte("tial\nnext"))
+	if !strings.Contains(buf.String(), "msg=partial") || strings.Contains(buf.String(), "next") {
+		t.Errorf("output = %q", buf.String())
+	}
+
+	buf.Reset()
+	_, _ = w.Write([]byte(strings.Repeat("x", maxCommandLine)))
+	if n := strings.Count(buf.String(), "msg="); n != 1 {
+		t.Errorf("long line logged as %d entries, want 1", n)
+	}
+	w.flush()
+	if n := strings.Count(buf.String(), "msg="); n != 2 {
+		t.Errorf("flush logged %d entries, want 2", n-1)
+	}
+}
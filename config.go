@@ -0,0 +1,553 @@
+package errorlogger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EnvPrefix is the prefix of the environment variables read
+// by Config.ApplyEnv, e.g. ERRORLOGGER_LEVEL.
+const EnvPrefix = "ERRORLOGGER_"
+
+// Config is the serializable configuration of a logger. It
+// is loaded from a YAML or JSON file with LoadConfig,
+// overridden by environment variables and command line
+// flags, and applied with ApplyConfig.
+//
+// The effective configuration of a logger is returned by
+// its Config method.
+type Config struct {
+	// Level is the log level name, e.g. "info".
+	Level string `json:"level"`
+
+	// Format is "text", "json", or the name of a formatter
+	// added with RegisterFormatter. Empty leaves the
+	// formatter unchanged.
+	Format string `json:"format"`
+
+	// Pretty indents JSON output.
+	Pretty bool `json:"pretty"`
+
+	// Enabled turns logging by Err on or off.
+	Enabled bool `json:"enabled"`
+
+	// Output is "stderr", "stdout", "discard", a sink added
+	// with RegisterSink as "name" or "name:target", or the
+	// path of a file that log entries are appended to.
+	// Empty leaves the output unchanged.
+	Output string `json:"output"`
+
+	// TimestampFormat is the time.Format layout of
+	// timestamps. Empty uses the formatter default.
+	TimestampFormat string `json:"timestamp_format"`
+
+	// Hooks are the filters of the hooks added with
+	// AddFilteredHook, by name; see ParseHookFilters.
+	Hooks string `json:"hooks"`
+
+	// Schedule is the level and sampling schedule of the
+	// logger, applied with SetSchedule; see ParseSchedule.
+	// Outside of its windows, Level is in effect.
+	Schedule string `json:"schedule"`
+}
+
+// DefaultConfig returns the configuration of a new logger.
+func DefaultConfig() Config {
+	return Config{
+		Level:   DefaultLogLevel.String(),
+		Format:  "text",
+		Enabled: defaultEnabled,
+		Output:  "stderr",
+	}
+}
+
+// configField describes a Config field for the YAML codec,
+// environment variables, and command line flags.
+type configField struct {
+	key   string
+	usage string
+	get   func(c *Config) string
+	set   func(c *Config, s string) error
+}
+
+// env returns the environment variable for the field.
+func (f configField) env() string { return EnvPrefix + strings.ToUpper(f.key) }
+
+// flag returns the command line flag name for the field.
+func (f configField) flag() string { return "log-" + strings.ReplaceAll(f.key, "_", "-") }
+
+var configFields = []configField{
+	{
+		key:   "level",
+		usage: "log level: trace, debug, info, warn, error, fatal, or panic",
+		get:   func(c *Config) string { return c.Level },
+		set: func(c *Config, s string) error {
+			if _, err := ParseLevel(s); err != nil {
+				return err
+			}
+			c.Level = s
+			return nil
+		},
+	},
+	{
+		key:   "format",
+		usage: "log format: text, json, or a registered formatter",
+		get:   func(c *Config) string { return c.Format },
+		set: func(c *Config, s string) error {
+			if _, ok := lookupFormatter(s); !ok && s != "" && s != "text" && s != "json" {
+				return fmt.Errorf("invalid format %q: %w", s, ErrInvalid)
+			}
+			c.Format = s
+			return nil
+		},
+	},
+	{
+		key:   "pretty",
+		usage: "indent json output",
+		get:   func(c *Config) string { return strconv.FormatBool(c.Pretty) },
+		set:   func(c *Config, s string) (err error) { c.Pretty, err = strconv.ParseBool(s); return err },
+	},
+	{
+		key:   "enabled",
+		usage: "log errors passed to Err",
+		get:   func(c *Config) string { return strconv.FormatBool(c.Enabled) },
+		set:   func(c *Config, s string) (err error) { c.Enabled, err = strconv.ParseBool(s); return err },
+	},
+	{
+		key:   "output",
+		usage: "log output: stderr, stdout, discard, a registered sink, or a file path",
+		get:   func(c *Config) string { return c.Output },
+		set:   func(c *Config, s string) error { c.Output = s; return nil },
+	},
+	{
+		key:   "timestamp_format",
+		usage: "time.Format layout of timestamps (empty for the formatter default)",
+		get:   func(c *Config) string { return c.TimestampFormat },
+		set:   func(c *Config, s string) error { c.TimestampFormat = s; return nil },
+	},
+	{
+		key:   "hooks",
+		usage: `hook filters by name, e.g. "sentry: level>=error service=payments; slack: level>=warn"`,
+		get:   func(c *Config) string { return c.Hooks },
+		set: func(c *Config, s string) error {
+			if _, err := ParseHookFilters(s); err != nil {
+				return err
+			}
+			c.Hooks = s
+			return nil
+		},
+	},
+	{
+		key:   "schedule",
+		usage: `level schedule by time of day, e.g. "batch: 01:00-05:00 trace; peak: 09:00-17:00 warn sample=10"`,
+		get:   func(c *Config) string { return c.Schedule },
+		set: func(c *Config, s string) error {
+			if _, err := ParseSchedule(s); err != nil {
+				return err
+			}
+			c.Schedule = s
+			return nil
+		},
+	},
+}
+
+func lookupConfigField(key string) (configField, bool) {
+	for _, f := range configFields {
+		if f.key == key {
+			return f, true
+		}
+	}
+	return configField{}, false
+}
+
+// LoadConfig reads the configuration file at path on top of
+// DefaultConfig. Files ending in .json are decoded as JSON;
+// all others as YAML.
+//
+// Errors in the file are returned as a *SourceError with the
+// position of the problem.
+func LoadConfig(path string) (Config, error) {
+	c, _, err := loadConfig(path)
+	return c, err
+}
+
+// loadConfig is LoadConfig that also returns the keys set
+// by the file.
+func loadConfig(path string) (Config, []string, error) {
+	c := DefaultConfig()
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return c, nil, err
+	}
+
+	var keys []string
+	if isJSONPath(path) {
+		keys, err = c.unmarshalJSON(src)
+	} else {
+		keys, err = c.unmarshalYAML(src)
+	}
+	if err != nil {
+		return DefaultConfig(), nil, ConfigError(err, path, src)
+	}
+	return c, keys, nil
+}
+
+// unmarshalJSON decodes data into c and returns the keys
+// present in data.
+func (c *Config) unmarshalJSON(data []byte) ([]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, f := range configFields {
+		if _, ok := raw[f.key]; ok {
+			keys = append(keys, f.key)
+		}
+	}
+	return keys, nil
+}
+
+// SaveConfig writes c to the file at path, as JSON if path
+// ends in .json and as YAML otherwise.
+func SaveConfig(path string, c Config) error {
+	var buf bytes.Buffer
+	var err error
+	if isJSONPath(path) {
+		err = c.WriteJSON(&buf)
+	} else {
+		err = c.WriteYAML(&buf)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+func isJSONPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// Validate reports whether all fields of c hold valid
+// values.
+func (c Config) Validate() error {
+	for _, f := range configFields {
+		if err := f.set(&c, f.get(&c)); err != nil {
+			return fmt.Errorf("%s: %w", f.key, err)
+		}
+	}
+	return nil
+}
+
+// ApplyEnv overrides fields of c with the environment
+// variables that are set, e.g. ERRORLOGGER_LEVEL=debug.
+func (c *Config) ApplyEnv() error {
+	_, err := c.applyEnv()
+	return err
+}
+
+// applyEnv is ApplyEnv that also returns the keys set by
+// environment variables.
+func (c *Config) applyEnv() ([]string, error) {
+	var keys []string
+	for _, f := range configFields {
+		if v, ok := os.LookupEnv(f.env()); ok {
+			if err := f.set(c, v); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.env(), err)
+			}
+			keys = append(keys, f.key)
+		}
+	}
+	return keys, nil
+}
+
+// RegisterFlags defines command line flags in fs that
+// override fields of c, e.g. -log-level=debug. Flags are
+// applied when fs is parsed, so c should be loaded from
+// files and the environment before then.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	for _, f := range configFields {
+		fs.Var(configFlag{c, f}, f.flag(), f.usage)
+	}
+}
+
+type configFlag struct {
+	c *Config
+	f configField
+}
+
+func (v configFlag) String() string {
+	if v.c == nil {
+		return ""
+	}
+	return v.f.get(v.c)
+}
+
+func (v configFlag) Set(s string) error { return v.f.set(v.c, s) }
+
+// IsBoolFlag allows boolean flags to be given without a value.
+func (v configFlag) IsBoolFlag() bool {
+	return v.f.key == "pretty" || v.f.key == "enabled"
+}
+
+// WriteJSON writes c to w as indented JSON.
+func (c Config) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// WriteYAML writes c to w as YAML.
+func (c Config) WriteYAML(w io.Writer) error {
+	return c.writeYAML(w, false)
+}
+
+// WriteStarterConfig writes a commented YAML configuration
+// file with default values to w, as a starting point for
+// customization.
+func WriteStarterConfig(w io.Writer) error {
+	if _, err := io.WriteString(w, "# errorlogger configuration\n#\n# Each setting may be overridden by an environment variable\n# or a command line flag, shown in the comments.\n"); err != nil {
+		return err
+	}
+	return DefaultConfig().writeYAML(w, true)
+}
+
+func (c Config) writeYAML(w io.Writer, comments bool) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range configFields {
+		if comments {
+			fmt.Fprintf(bw, "\n# %s\n# env: %s, flag: -%s\n", f.usage, f.env(), f.flag())
+		}
+		fmt.Fprintf(bw, "%s: %s\n", f.key, yamlScalar(f.get(&c)))
+	}
+	return bw.Flush()
+}
+
+// yamlScalar quotes s if it would not read back as the same
+// plain YAML string.
+func yamlScalar(s string) string {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, ":#'\"{}[],&*!|>%@`") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// UnmarshalYAML decodes the flat "key: value" YAML subset
+// written by WriteYAML into c. Comments, blank lines, and
+// double or single quoted values are supported. Fields not
+// present in data are left unchanged.
+func (c *Config) UnmarshalYAML(data []byte) error {
+	_, err := c.unmarshalYAML(data)
+	return err
+}
+
+// unmarshalYAML is UnmarshalYAML that also returns the keys
+// present in data.
+func (c *Config) unmarshalYAML(data []byte) ([]string, error) {
+	var keys []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' || line == "---" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("yaml: line %d: expected key: value", n)
+		}
+		key = strings.TrimSpace(key)
+		f, ok := lookupConfigField(key)
+		if !ok {
+			return nil, fmt.Errorf("yaml: line %d: unknown key %q", n, key)
+		}
+
+		value, err := yamlValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("yaml: line %d: %v", n, err)
+		}
+		if err := f.set(c, value); err != nil {
+			return nil, fmt.Errorf("yaml: line %d: %s: %v", n, key, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, sc.Err()
+}
+
+// yamlValue decodes a plain or quoted scalar, discarding a
+// trailing comment.
+func yamlValue(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		q, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return "", fmt.Errorf("unterminated quoted value %s", s)
+		}
+		return strconv.Unquote(q)
+	case strings.HasPrefix(s, "'"):
+		end := strings.Index(s[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quoted value %s", s)
+		}
+		return s[1 : end+1], nil
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s), nil
+}
+
+// Config returns the effective configuration of the logger.
+//
+// Output is reported as "stderr", "stdout", "discard", or a
+// file name, and Format as "text" or "json"; other writers
+// and formatters are reported as "", so that applying the
+// configuration leaves them unchanged.
+func (e *errorLogger) Config() Config {
+	level, schedule := e.scheduleBase()
+	c := Config{
+		Level:    level.String(),
+		Enabled:  e.enabled(),
+		Output:   configOutputName(e.output()),
+		Hooks:    e.hookFilterSpec(),
+		Schedule: schedule,
+	}
+
+	switch f := e.formatter().(type) {
+	case *JSONFormatter:
+		c.Format, c.Pretty, c.TimestampFormat = "json", f.PrettyPrint, f.TimestampFormat
+	case *logrus.JSONFormatter:
+		c.Format, c.Pretty, c.TimestampFormat = "json", f.PrettyPrint, f.TimestampFormat
+	case *TextFormatter:
+		c.Format, c.TimestampFormat = "text", f.TimestampFormat
+	case *logrus.TextFormatter:
+		c.Format, c.TimestampFormat = "text", f.TimestampFormat
+	}
+	return c
+}
+
+// ApplyConfig applies c to the logger. If Output is a file
+// path, the file is opened for appending and created if
+// needed. The output is left unchanged if Output names the
+// current output, and a file opened by an earlier call is
+// closed when it is replaced.
+func (e *errorLogger) ApplyConfig(c Config) error {
+	return e.applyConfig(c, &changeSource{via: ViaConfig})
+}
+
+func (e *errorLogger) applyConfig(c Config, src *changeSource) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	level, _ := ParseLevel(c.Level)
+
+	var formatter Formatter
+	if c.Format == "" {
+		// unchanged
+	} else if factory, ok := lookupFormatter(c.Format); ok {
+		f, err := factory(c)
+		if err != nil {
+			return fmt.Errorf("format %s: %w", c.Format, err)
+		}
+		formatter = f
+	} else if c.Format == "json" {
+		f := NewJSONFormatter(c.Pretty)
+		f.SetTimestampFormat(c.TimestampFormat)
+		formatter = f
+	} else {
+		f := NewTextFormatter()
+		if c.TimestampFormat != "" {
+			f.SetFullTimeStamp(true)
+			f.SetTimestampFormat(c.TimestampFormat)
+		}
+		formatter = f
+	}
+
+	var out Writer
+	var file io.Closer
+	switch c.Output {
+	case "", configOutputName(e.output()):
+		// unchanged
+	case "stderr":
+		out = os.Stderr
+	case "stdout":
+		out = os.Stdout
+	case "discard":
+		out = Discard
+	default:
+		if factory, target, ok := lookupSink(c.Output); ok {
+			w, err := factory(target)
+			if err != nil {
+				return fmt.Errorf("output %s: %w", c.Output, err)
+			}
+			out = w
+			break
+		}
+		f, err := os.OpenFile(c.Output, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			return err
+		}
+		out, file = f, f
+	}
+
+	if formatter != nil {
+		e.SetFormatter(formatter)
+	}
+	e.setLevel(level, src)
+	if out != nil {
+		e.setOutput(out, src)
+		e.outputMu.Lock()
+		old := e.configOut
+		e.configOut = file
+		e.outputMu.Unlock()
+		if old != nil {
+			old.Close()
+		}
+	}
+	e.setEnabled(c.Enabled, src)
+	e.setHookFilters(c.Hooks, src)
+	e.setSchedule(c.Schedule, level, src)
+	return nil
+}
+
+// outputName returns the name of w for diagnostics.
+func outputName(w io.Writer) string {
+	if name := configOutputName(w); name != "" {
+		return name
+	}
+	return fmt.Sprintf("%T", w)
+}
+
+// configOutputName returns the Config.Output that selects w,
+// or "" if there is none.
+func configOutputName(w io.Writer) string {
+	switch w {
+	case os.Stderr:
+		return "stderr"
+	case os.Stdout:
+		return "stdout"
+	case Discard, io.Discard:
+		return "discard"
+	}
+	if f, ok := w.(*os.File); ok {
+		return f.Name()
+	}
+	return ""
+}
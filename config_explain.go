@@ -0,0 +1,137 @@
+package errorlogger
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+type (
+	// ConfigSetting is a value given to a configuration
+	// field by a source.
+	ConfigSetting struct {
+		Source string // the layer, e.g. "env"
+		Origin string // where the source read it, e.g. "ERRORLOGGER_LEVEL"
+		Value  string
+	}
+
+	// ConfigExplanation is the final value of a
+	// configuration field, the setting it came from, and the
+	// settings of earlier layers that it overrides.
+	ConfigExplanation struct {
+		Key        string
+		Value      string
+		Source     string
+		Origin     string
+		Overridden []ConfigSetting
+	}
+
+	// Explanation lists the fields of a merged configuration
+	// in field order; see Explain.
+	Explanation []ConfigExplanation
+)
+
+// Explain merges layers on top of base like MergeConfig and
+// reports, for every field, the final value, the source
+// that won, and the values of the sources it overrode, to
+// answer "but I set ERRORLOGGER_LEVEL=debug!":
+//  x := errorlogger.Explain(errorlogger.DefaultConfigLayer(), file, env, flags)
+//  x.WriteTo(os.Stderr)
+//
+// prints
+//  level   info   flag -log-level  overrides env ERRORLOGGER_LEVEL=debug
+//
+// Settings with invalid values are ignored, as by
+// MergeConfig. The doctor command prints the explanation
+// of the file and environment layers.
+func Explain(base ConfigLayer, layers ...ConfigLayer) Explanation {
+	c := base.Config
+	x := make(Explanation, len(configFields))
+	for i, f := range configFields {
+		x[i] = ConfigExplanation{Key: f.key, Value: f.get(&c), Source: base.Source}
+	}
+
+	for _, l := range layers {
+		for i, f := range configFields {
+			if !l.sets(f) {
+				continue
+			}
+			v := f.get(&l.Config)
+			if f.set(&c, v) != nil {
+				continue
+			}
+			fx := &x[i]
+			if fx.Source != base.Source {
+				fx.Overridden = append(fx.Overridden, ConfigSetting{Source: fx.Source, Origin: fx.Origin, Value: fx.Value})
+			}
+			fx.Value, fx.Source, fx.Origin = v, l.Source, configOrigin(l.Source, f)
+		}
+	}
+	return x
+}
+
+// CodeConfigLayer returns c, as set by the program, as the
+// "code" layer. Only keys are set; if none are given, the
+// fields that differ from DefaultConfig are set.
+func CodeConfigLayer(c Config, keys ...string) ConfigLayer {
+	return ConfigLayer{Source: "code", Config: c, Keys: keys}
+}
+
+// configOrigin returns where source reads field f.
+func configOrigin(source string, f configField) string {
+	switch source {
+	case "env":
+		return f.env()
+	case "flag":
+		return "-" + f.flag()
+	}
+	return ""
+}
+
+// Sources returns the source of each field, as returned by
+// MergeConfig.
+func (x Explanation) Sources() ConfigSources {
+	s := make(ConfigSources, len(x))
+	for _, fx := range x {
+		s[fx.Key] = fx.Source
+	}
+	return s
+}
+
+// String returns the setting, e.g.
+//  env ERRORLOGGER_LEVEL=debug
+func (s ConfigSetting) String() string {
+	if s.Origin == "" {
+		return s.Source + " " + s.Value
+	}
+	return s.Source + " " + s.Origin + "=" + s.Value
+}
+
+// WriteTo writes a human readable table of the explanation
+// to w.
+func (x Explanation) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	for _, fx := range x {
+		src := strings.TrimSpace(fx.Source + " " + fx.Origin)
+		fmt.Fprintf(tw, "%s\t%s\t%s", fx.Key, fx.Value, src)
+		if len(fx.Overridden) > 0 {
+			over := make([]string, len(fx.Overridden))
+			for i, s := range fx.Overridden {
+				over[len(over)-1-i] = s.String()
+			}
+			fmt.Fprintf(tw, "\toverrides %s", strings.Join(over, ", "))
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+func (x Explanation) String() string {
+	var sb strings.Builder
+	x.WriteTo(&sb)
+	return sb.String()
+}
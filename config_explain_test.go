@@ -0,0 +1,121 @@
+package errorlogger
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	file := ConfigLayer{Source: "file", Config: Config{Level: "warn", Format: "json"}, Keys: []string{"level", "format"}}
+	env := ConfigLayer{Source: "env", Config: Config{Level: "debug"}, Keys: []string{"level"}}
+	flags := ConfigLayer{Source: "flag", Config: Config{Level: "info", Format: "yaml"}, Keys: []string{"level", "format"}}
+	code := CodeConfigLayer(Config{Level: "error"}, "level")
+
+	tests := []struct {
+		name   string
+		layers []ConfigLayer
+		key    string
+		want   ConfigExplanation
+	}{
+		{"default", nil, "level", ConfigExplanation{Key: "level", Value: "info", Source: "default"}},
+		{"file", []ConfigLayer{file}, "level", ConfigExplanation{Key: "level", Value: "warn", Source: "file"}},
+		{"env over file", []ConfigLayer{file, env}, "level", ConfigExplanation{
+			Key: "level", Value: "debug", Source: "env", Origin: "ERRORLOGGER_LEVEL",
+			Overridden: []ConfigSetting{{Source: "file", Value: "warn"}},
+		}},
+		{"code over all", []ConfigLayer{file, env, code}, "level", ConfigExplanation{
+			Key: "level", Value: "error", Source: "code",
+			Overridden: []ConfigSetting{{Source: "file", Value: "warn"}, {Source: "env", Origin: "ERRORLOGGER_LEVEL", Value: "debug"}},
+		}},
+		{"invalid ignored", []ConfigLayer{file, flags}, "format", ConfigExplanation{Key: "format", Value: "json", Source: "file"}},
+		{"flag", []ConfigLayer{env, flags}, "level", ConfigExplanation{
+			Key: "level", Value: "info", Source: "flag", Origin: "-log-level",
+			Overridden: []ConfigSetting{{Source: "env", Origin: "ERRORLOGGER_LEVEL", Value: "debug"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := Explain(DefaultConfigLayer(), tt.layers...)
+			var got ConfigExplanation
+			for _, fx := range x {
+				if fx.Key == tt.key {
+					got = fx
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Explain() %s = %+v, want %+v", tt.key, got, tt.want)
+			}
+
+			c, sources := MergeConfig(DefaultConfigLayer(), tt.layers...)
+			if !reflect.DeepEqual(x.Sources(), sources) {
+				t.Errorf("Sources() = %v, want %v", x.Sources(), sources)
+			}
+			for _, f := range configFields {
+				if v := f.get(&c); x[indexOfField(f.key)].Value != v {
+					t.Errorf("Explain() %s = %q, want the merged value %q", f.key, x[indexOfField(f.key)].Value, v)
+				}
+			}
+		})
+	}
+}
+
+func indexOfField(key string) int {
+	for i, f := range configFields {
+		if f.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestExplanation_WriteTo(t *testing.T) {
+	file := ConfigLayer{Source: "file", Config: Config{Level: "warn"}, Keys: []string{"level"}}
+	env := ConfigLayer{Source: "env", Config: Config{Level: "debug"}, Keys: []string{"level"}}
+	code := CodeConfigLayer(Config{Level: "error"}, "level")
+	x := Explain(DefaultConfigLayer(), file, env, code)
+
+	var buf bytes.Buffer
+	if _, err := x.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(configFields) {
+		t.Fatalf("WriteTo() wrote %d lines, want %d", len(lines), len(configFields))
+	}
+	got := strings.Join(strings.Fields(lines[0]), " ")
+	if want := "level error code overrides env ERRORLOGGER_LEVEL=debug, file warn"; got != want {
+		t.Errorf("WriteTo() first line = %q, want %q", got, want)
+	}
+	if x.String() != buf.String() {
+		t.Errorf("String() = %q, want %q", x.String(), buf.String())
+	}
+}
+
+func TestDoctor_config(t *testing.T) {
+	env := ConfigLayer{Source: "env", Config: Config{Level: "debug"}, Keys: []string{"level"}}
+	code := CodeConfigLayer(Config{Level: "error"}, "level")
+	tests := []struct {
+		name   string
+		layers []ConfigLayer
+		want   CheckStatus
+	}{
+		{"none overridden", []ConfigLayer{DefaultConfigLayer(), env}, CheckOK},
+		{"overridden", []ConfigLayer{DefaultConfigLayer(), env, code}, CheckWarn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Doctor(DoctorOptions{Output: &bytes.Buffer{}, Config: tt.layers})
+			c := r.Checks[len(r.Checks)-1]
+			if c.Name != "config" || c.Status != tt.want {
+				t.Errorf("config check = %+v, want status %v", c, tt.want)
+			}
+			var buf bytes.Buffer
+			r.WriteTo(&buf)
+			if !strings.Contains(buf.String(), "configuration:\n") {
+				t.Errorf("WriteTo() = %q, want the configuration", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package errorlogger
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+type (
+	// ConfigChange describes a field that differs between
+	// two configurations.
+	ConfigChange struct {
+		Key string
+		Old string
+		New string
+	}
+
+	// ConfigLayer is a configuration from a named source,
+	// such as "file", "env", or "flag".
+	ConfigLayer struct {
+		Source string
+		Config Config
+
+		// Keys lists the keys set by the layer. If Keys is
+		// nil, the fields that differ from DefaultConfig are
+		// considered set.
+		Keys []string
+	}
+
+	// ConfigSources records the source that set each field
+	// of a merged configuration, keyed by the YAML key.
+	ConfigSources map[string]string
+)
+
+func (c ConfigChange) String() string {
+	return fmt.Sprintf("%s: %q -> %q", c.Key, c.Old, c.New)
+}
+
+// Diff returns the fields that differ between c and other,
+// in field order.
+func (c Config) Diff(other Config) []ConfigChange {
+	var changes []ConfigChange
+	for _, f := range configFields {
+		if old, new := f.get(&c), f.get(&other); old != new {
+			changes = append(changes, ConfigChange{Key: f.key, Old: old, New: new})
+		}
+	}
+	return changes
+}
+
+// Merge returns c with the fields of other that are not
+// set to their default value applied on top.
+func (c Config) Merge(other Config) Config {
+	merged, _ := MergeConfig(ConfigLayer{Config: c}, ConfigLayer{Config: other})
+	return merged
+}
+
+// MergeConfig merges layers on top of base in order, so
+// that later layers take precedence, and returns the result
+// along with the source of each field. Fields set by no
+// layer are attributed to base.
+//
+// The usual precedence is a three-way merge of defaults,
+// file, and environment and flags:
+//  file, _ := LoadConfigLayer("app.yaml")
+//  env, _ := EnvConfigLayer()
+//  c, sources := MergeConfig(DefaultConfigLayer(), file, env, FlagConfigLayer(fs, &flags))
+func MergeConfig(base ConfigLayer, layers ...ConfigLayer) (Config, ConfigSources) {
+	c := base.Config
+	sources := make(ConfigSources, len(configFields))
+	for _, f := range configFields {
+		sources[f.key] = base.Source
+	}
+
+	for _, l := range layers {
+		for _, f := range configFields {
+			if !l.sets(f) {
+				continue
+			}
+			// Values were validated when the layer was
+			// loaded; an invalid value leaves c unchanged.
+			if f.set(&c, f.get(&l.Config)) == nil {
+				sources[f.key] = l.Source
+			}
+		}
+	}
+	return c, sources
+}
+
+// sets reports whether the layer sets field f.
+func (l ConfigLayer) sets(f configField) bool {
+	if l.Keys == nil {
+		def := DefaultConfig()
+		return f.get(&l.Config) != f.get(&def)
+	}
+	for _, k := range l.Keys {
+		if k == f.key {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultConfigLayer returns DefaultConfig as the "default"
+// layer.
+func DefaultConfigLayer() ConfigLayer {
+	return ConfigLayer{Source: "default", Config: DefaultConfig(), Keys: []string{}}
+}
+
+// LoadConfigLayer loads the configuration file at path as
+// the "file" layer. Only keys present in the file are set.
+func LoadConfigLayer(path string) (ConfigLayer, error) {
+	c, keys, err := loadConfig(path)
+	if keys == nil {
+		keys = []string{}
+	}
+	return ConfigLayer{Source: "file", Config: c, Keys: keys}, err
+}
+
+// EnvConfigLayer returns the environment variables as the
+// "env" layer. Only keys with a variable set are set.
+func EnvConfigLayer() (ConfigLayer, error) {
+	c := DefaultConfig()
+	keys, err := c.applyEnv()
+	if keys == nil {
+		keys = []string{}
+	}
+	return ConfigLayer{Source: "env", Config: c, Keys: keys}, err
+}
+
+// FlagConfigLayer returns c, which was registered with fs
+// by RegisterFlags, as the "flag" layer. Only keys with a
+// flag given on the command line are set; fs must have been
+// parsed.
+func FlagConfigLayer(fs *flag.FlagSet, c *Config) ConfigLayer {
+	keys := []string{}
+	fs.Visit(func(fl *flag.Flag) {
+		for _, f := range configFields {
+			if f.flag() == fl.Name {
+				keys = append(keys, f.key)
+			}
+		}
+	})
+	return ConfigLayer{Source: "flag", Config: *c, Keys: keys}
+}
+
+// Banner logs c at InfoLevel with the source of each field,
+// as a startup banner that answers "why is my level Info?".
+// The banner includes the session ID of the process.
+func (e *errorLogger) Banner(c Config, sources ConfigSources) {
+	fields := make(Fields, len(configFields)+1)
+	fields[SessionKey] = sessionID
+	for _, f := range configFields {
+		v := f.get(&c)
+		if src := sources[f.key]; src != "" {
+			v += " (" + src + ")"
+		}
+		fields[f.key] = v
+	}
+	e.WithFields(fields).Info("logging configuration")
+}
+
+// String returns the sources in field order, e.g.
+//  level=env format=file pretty=default ...
+func (s ConfigSources) String() string {
+	parts := make([]string, 0, len(configFields))
+	for _, f := range configFields {
+		if src, ok := s[f.key]; ok {
+			parts = append(parts, f.key+"="+src)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,98 @@
+package errorlogger
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfig_Diff(t *testing.T) {
+	a := DefaultConfig()
+	b := a
+	b.Level = "debug"
+	b.Pretty = true
+
+	want := []ConfigChange{
+		{Key: "level", Old: "info", New: "debug"},
+		{Key: "pretty", Old: "false", New: "true"},
+	}
+	if got := a.Diff(b); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+	if got := a.Diff(a); got != nil {
+		t.Errorf("Diff() of equal configs = %v, want nil", got)
+	}
+}
+
+func TestConfig_Merge(t *testing.T) {
+	c := Config{Level: "warn", Format: "json", Enabled: true, Output: "stdout"}
+	other := DefaultConfig()
+	other.Level = "debug"
+
+	want := Config{Level: "debug", Format: "json", Enabled: true, Output: "stdout"}
+	if got := c.Merge(other); got != want {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMergeConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("level: debug\nformat: json\noutput: stderr\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := LoadConfigLayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("ERRORLOGGER_LEVEL", "warn")
+	env, err := EnvConfigLayer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flags := DefaultConfig()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.RegisterFlags(fs)
+	if err := fs.Parse([]string{"-log-format=text"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, sources := MergeConfig(DefaultConfigLayer(), file, env, FlagConfigLayer(fs, &flags))
+
+	want := DefaultConfig()
+	want.Level = "warn"
+	if got != want {
+		t.Errorf("MergeConfig() = %+v, want %+v", got, want)
+	}
+
+	wantSources := ConfigSources{
+		"level":            "env",
+		"format":           "flag",
+		"pretty":           "default",
+		"enabled":          "default",
+		"output":           "file",
+		"timestamp_format": "default",
+		"hooks":            "default",
+		"schedule":         "default",
+	}
+	if !reflect.DeepEqual(sources, wantSources) {
+		t.Errorf("MergeConfig() sources = %v, want %v", sources, wantSources)
+	}
+}
+
+func TestErrorLogger_Banner(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	c, sources := MergeConfig(DefaultConfigLayer(), ConfigLayer{Source: "env", Config: Config{Level: "debug"}, Keys: []string{"level"}})
+	e.Banner(c, sources)
+
+	out := buf.String()
+	for _, want := range []string{"logging configuration", `level="debug (env)"`, `format="text (default)"`, "session=" + SessionID()} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Banner() = %q, want %q", out, want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package errorlogger
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfig_YAMLRoundTrip(t *testing.T) {
+	want := Config{
+		Level:           "debug",
+		Format:          "json",
+		Pretty:          true,
+		Enabled:         false,
+		Output:          "/var/log/app: main.log",
+		TimestampFormat: "2006-01-02 15:04:05",
+		Hooks:           "sentry: level>=error service=payments; slack: level=warn",
+	}
+
+	var buf bytes.Buffer
+	if err := want.WriteYAML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got Config
+	if err := got.UnmarshalYAML(buf.Bytes()); err != nil {
+		t.Fatalf("UnmarshalYAML() error = %v\n%s", err, buf.String())
+	}
+	if got != want {
+		t.Errorf("YAML round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteStarterConfig(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteStarterConfig(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got Config
+	if err := got.UnmarshalYAML(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if want := DefaultConfig(); got != want {
+		t.Errorf("starter config = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		file     string
+		src      string
+		want     Config
+		wantLine int
+		wantErr  bool
+	}{
+		{"yaml", "c.yaml", "# comment\nlevel: warn  # inline\nformat: 'json'\n", Config{Level: "warn", Format: "json", Enabled: true, Output: "stderr"}, 0, false},
+		{"json", "c.json", `{"level": "trace", "output": "stdout"}`, Config{Level: "trace", Format: "text", Enabled: true, Output: "stdout"}, 0, false},
+		{"unknown key", "c.yml", "level: info\ncolour: red\n", DefaultConfig(), 2, true},
+		{"bad level", "bad.yaml", "\n\nlevel: loud\n", DefaultConfig(), 3, true},
+		{"bad json level", "bad.json", `{"level": "loud"}`, DefaultConfig(), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("LoadConfig() = %+v, want %+v", got, tt.want)
+			}
+			if tt.wantLine > 0 {
+				var se *SourceError
+				if !errors.As(err, &se) || se.Line != tt.wantLine {
+					t.Errorf("LoadConfig() error = %#v, want SourceError at line %d", err, tt.wantLine)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveConfig(t *testing.T) {
+	want := Config{Level: "error", Format: "json", Output: "discard"}
+	for _, name := range []string{"c.yaml", "c.json"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := SaveConfig(path, want); err != nil {
+			t.Fatal(err)
+		}
+		got, err := LoadConfig(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("SaveConfig(%s) then LoadConfig() = %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+func TestConfig_EnvAndFlags(t *testing.T) {
+	t.Setenv("ERRORLOGGER_LEVEL", "debug")
+	t.Setenv("ERRORLOGGER_PRETTY", "true")
+
+	c := DefaultConfig()
+	if err := c.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	c.RegisterFlags(fs)
+	if err := fs.Parse([]string{"-log-level=warn", "-log-format", "json", "-log-enabled=false"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := Config{Level: "warn", Format: "json", Pretty: true, Output: "stderr"}
+	if c != want {
+		t.Errorf("env and flags = %+v, want %+v", c, want)
+	}
+
+	t.Setenv("ERRORLOGGER_FORMAT", "xml")
+	if err := c.ApplyEnv(); err == nil {
+		t.Error("ApplyEnv() with invalid format succeeded")
+	}
+}
+
+func TestErrorLogger_ApplyConfig(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	path := filepath.Join(t.TempDir(), "app.log")
+	want := Config{Level: "debug", Format: "json", Pretty: true, Enabled: false, Output: path, TimestampFormat: "15:04"}
+
+	if err := e.ApplyConfig(want); err != nil {
+		t.Fatal(err)
+	}
+	defer e.output().(*os.File).Close()
+	if got := e.Config(); got != want {
+		t.Errorf("Config() = %+v, want %+v", got, want)
+	}
+
+	e.Info("to file")
+	if b, _ := os.ReadFile(path); !bytes.Contains(b, []byte("to file")) {
+		t.Errorf("log file = %q, want entry", b)
+	}
+
+	if err := e.ApplyConfig(Config{Level: "loud"}); err == nil {
+		t.Error("ApplyConfig() with invalid level succeeded")
+	}
+}
+
+func TestErrorLogger_ApplyConfig_output(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	dir := t.TempDir()
+
+	c := e.Config()
+	if c.Output != "" {
+		t.Errorf("Config().Output = %q for a custom writer, want \"\"", c.Output)
+	}
+	if err := e.ApplyConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	if e.output() != buf {
+		t.Errorf("ApplyConfig(Config()) replaced the output with %T", e.output())
+	}
+
+	c.Output = filepath.Join(dir, "a.log")
+	if err := e.ApplyConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	first := e.output().(*os.File)
+	if err := e.ApplyConfig(e.Config()); err != nil {
+		t.Fatal(err)
+	}
+	if e.output() != first {
+		t.Error("ApplyConfig(Config()) reopened the output file")
+	}
+
+	c.Output = filepath.Join(dir, "b.log")
+	if err := e.ApplyConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	defer e.output().(*os.File).Close()
+	if _, err := first.Write([]byte("x")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("write to the replaced file error = %v, want os.ErrClosed", err)
+	}
+}
+
+func TestErrorLogger_Config_customFormatter(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	e.SetFormatter(&prefixFormatter{"custom"})
+	c := e.Config()
+	if c.Format != "" {
+		t.Errorf("Config().Format = %q for a custom formatter, want \"\"", c.Format)
+	}
+	if err := e.ApplyConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := e.formatter().(*prefixFormatter); !ok {
+		t.Errorf("ApplyConfig(Config()) replaced the formatter with %T", e.formatter())
+	}
+}
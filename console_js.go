@@ -0,0 +1,94 @@
+//go:build js && wasm
+
+package errorlogger
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"syscall/js"
+)
+
+// ConsoleBackend is a Backend for GOOS=js programs that
+// writes to the browser or Node.js console, so that
+// WebAssembly front ends can use the same Err-centric code
+// as servers. Error and more severe entries are written
+// with console.error, warnings with console.warn, info with
+// console.info, and debug and trace with console.debug.
+// Fields are passed as an object after the message, so the
+// developer tools can show them expanded.
+//
+// The console formats its own output, so SetOutput and
+// SetFormatter do nothing.
+type ConsoleBackend struct {
+	level uint32 // atomic
+}
+
+// NewWithConsole returns a new ErrorLogger, with logging
+// enabled, that writes to the JavaScript console:
+//  log := errorlogger.NewWithConsole()
+//  log.Err(err) // console.error("...")
+//
+// It is the same as NewWithBackend(NewConsoleBackend()).
+func NewWithConsole() ErrorLogger {
+	return NewWithBackend(NewConsoleBackend())
+}
+
+// NewConsoleBackend returns a new ConsoleBackend.
+func NewConsoleBackend() *ConsoleBackend {
+	return &ConsoleBackend{level: uint32(DefaultLogLevel)}
+}
+
+// Log writes an entry to the console.
+func (b *ConsoleBackend) Log(level Level, msg string, fields Fields) {
+	if uint32(level) > atomic.LoadUint32(&b.level) {
+		return
+	}
+	method := "debug"
+	switch {
+	case level <= ErrorLevel:
+		method = "error"
+	case level == WarnLevel:
+		method = "warn"
+	case level == InfoLevel:
+		method = "info"
+	}
+
+	console := js.Global().Get("console")
+	if len(fields) == 0 {
+		console.Call(method, msg)
+		return
+	}
+	obj := js.Global().Get("Object").New()
+	for k, v := range fields {
+		obj.Set(k, consoleValue(v))
+	}
+	console.Call(method, msg, obj)
+}
+
+// consoleValue returns v as a value that js.ValueOf
+// accepts, formatting values of other types as strings.
+func consoleValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil, bool, string, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, uintptr, float32, float64:
+		return v
+	case error:
+		return v.Error()
+	}
+	return fmt.Sprint(v)
+}
+
+// SetLevel sets the least severe level written.
+func (b *ConsoleBackend) SetLevel(level Level) {
+	atomic.StoreUint32(&b.level, uint32(level))
+}
+
+// SetOutput does nothing; entries are written to the
+// console.
+func (b *ConsoleBackend) SetOutput(io.Writer) {}
+
+// SetFormatter does nothing; the console formats entries.
+func (b *ConsoleBackend) SetFormatter(Formatter) {}
+
+var _ Backend = (*ConsoleBackend)(nil)
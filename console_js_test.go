@@ -0,0 +1,63 @@
+//go:build js && wasm
+
+package errorlogger
+
+import (
+	"errors"
+	"syscall/js"
+	"testing"
+)
+
+func TestNewWithConsole(t *testing.T) {
+	type call struct {
+		method, msg string
+		fields      map[string]string
+	}
+	var calls []call
+	console := js.Global().Get("console")
+	defer js.Global().Set("console", console)
+
+	fake := js.Global().Get("Object").New()
+	for _, method := range []string{"error", "warn", "info", "debug"} {
+		method := method
+		fn := js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+			c := call{method: method, msg: args[0].String()}
+			if len(args) > 1 {
+				c.fields = map[string]string{}
+				keys := js.Global().Get("Object").Call("keys", args[1])
+				for i := 0; i < keys.Length(); i++ {
+					k := keys.Index(i).String()
+					c.fields[k] = js.Global().Call("String", args[1].Get(k)).String()
+				}
+			}
+			calls = append(calls, c)
+			return nil
+		})
+		defer fn.Release()
+		fake.Set(method, fn)
+	}
+	js.Global().Set("console", fake)
+
+	e := NewWithConsole()
+	e.SetLevel(DebugLevel)
+	e.Err(errors.New("boom"))
+	e.WithField("n", 3).Warn("slow")
+	e.Info("ready")
+	e.Debug("detail")
+
+	want := []call{
+		{"error", "boom", nil},
+		{"warn", "slow", map[string]string{"n": "3"}},
+		{"info", "ready", nil},
+		{"debug", "detail", nil},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("console calls = %v, want %v", calls, want)
+	}
+	for i, w := range want {
+		c := calls[i]
+		if c.method != w.method || c.msg != w.msg || len(c.fields) != len(w.fields) || c.fields["n"] != w.fields["n"] {
+			t.Errorf("console call %d = %v, want %v", i, c, w)
+		}
+	}
+}
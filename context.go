@@ -0,0 +1,174 @@
+package errorlogger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// The fields added by ContextErrFields.
+const (
+	CtxCauseKey      = "ctx_cause"
+	CtxDeadlineKey   = "ctx_deadline"
+	CtxExpiredAgoKey = "ctx_expired_ago"
+)
+
+// ContextErrFields returns fields that explain a context
+// error err of ctx, or nil if err is not a context error:
+//
+// - ctx_cause: the cause of the cancellation given to
+// context.WithCancelCause (Go 1.20 and later), if it
+// differs from err
+//
+// - ctx_deadline: the deadline of ctx, if it has one
+//
+// - ctx_expired_ago: how long ago the deadline expired,
+// if it has.
+//
+// This turns generic "context deadline exceeded" lines into
+// diagnosable records.
+func ContextErrFields(ctx context.Context, err error) Fields {
+	if ctx == nil || !(errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		return nil
+	}
+
+	fields := make(Fields, 3)
+	if cause := contextCause(ctx); cause != nil && cause != ctx.Err() && !errors.Is(err, cause) {
+		fields[CtxCauseKey] = cause.Error()
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		fields[CtxDeadlineKey] = deadline.Format(time.RFC3339Nano)
+		if ago := time.Since(deadline); ago >= 0 {
+			fields[CtxExpiredAgoKey] = ago.String()
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// ContextExtractor returns fields carried by ctx, such as a
+// request or user ID. Extractors return nil if ctx carries
+// none of their values.
+type ContextExtractor = func(ctx context.Context) Fields
+
+// AddContextExtractor adds fn to the extractors run by
+// ErrCtx, in the order they were added, so that services
+// log the identifiers carried by their contexts without
+// repeating them at every call site:
+//  log.AddContextExtractor(errorlogger.ContextValue(requestIDKey{}, "request_id"))
+//
+// Extractors should be added before logging starts.
+func (e *errorLogger) AddContextExtractor(fn ContextExtractor) {
+	if fn == nil {
+		return
+	}
+	e.ctxExtractors = append(e.ctxExtractors, fn)
+}
+
+// contextKey is a context key declared with SetContextKeys
+// and the name of its field.
+type contextKey struct {
+	key  interface{}
+	name string
+}
+
+// SetContextKeys declares the context keys whose values ErrCtx
+// logs as fields, replacing any declared before, so that
+// values such as the auth subject, tenant, and locale are
+// logged without WithFields at every call site:
+//  type tenantKey struct{}
+//  func (tenantKey) String() string { return "tenant" }
+//
+//  log.SetContextKeys(tenantKey{}, "locale")
+//
+// The field of a key is named by the key if it is a string,
+// by its String method if it has one, and by the name of
+// its type otherwise. Only the declared keys are read, and
+// nil values are not logged. Keys should be set before
+// logging starts.
+func (e *errorLogger) SetContextKeys(keys ...interface{}) {
+	ck := make([]contextKey, 0, len(keys))
+	for _, key := range keys {
+		if key == nil {
+			continue
+		}
+		ck = append(ck, contextKey{key: key, name: contextKeyName(key)})
+	}
+	e.ctxKeys = ck
+}
+
+// contextKeyName returns the field name of a context key.
+func contextKeyName(key interface{}) string {
+	switch k := key.(type) {
+	case string:
+		return k
+	case fmt.Stringer:
+		return k.String()
+	}
+	t := reflect.TypeOf(key)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Name() != "" {
+		return t.Name()
+	}
+	return fmt.Sprint(key)
+}
+
+// ContextValue returns a ContextExtractor that sets the field
+// with the given name to the value of ctx for key, if it is
+// not nil.
+func ContextValue(key interface{}, name string) ContextExtractor {
+	return func(ctx context.Context) Fields {
+		if v := ctx.Value(key); v != nil {
+			return Fields{name: v}
+		}
+		return nil
+	}
+}
+
+// ErrCtx logs err like Err on an entry with ctx as its
+// context, so that hooks can read it, adding the values of
+// the keys declared with SetContextKeys, the fields of the
+// extractors added with AddContextExtractor, and those of
+// ContextErrFields if err is an error of ctx.
+//  if err := db.QueryContext(ctx, q); err != nil {
+//      return log.ErrCtx(ctx, err)
+//  }
+func (e *errorLogger) ErrCtx(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if !e.enabled() || ctx == nil {
+		return e.Err(err)
+	}
+
+	var fields Fields
+	for _, ck := range e.ctxKeys {
+		if v := ctx.Value(ck.key); v != nil {
+			if fields == nil {
+				fields = make(Fields)
+			}
+			fields[ck.name] = v
+		}
+	}
+	for _, fn := range e.ctxExtractors {
+		for k, v := range fn(ctx) {
+			if fields == nil {
+				fields = make(Fields)
+			}
+			fields[k] = v
+		}
+	}
+	for k, v := range ContextErrFields(ctx, err) {
+		if fields == nil {
+			fields = make(Fields)
+		}
+		fields[k] = v
+	}
+	return e.errWithContext(ctx, err, fields)
+}
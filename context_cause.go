@@ -0,0 +1,7 @@
+//go:build go1.20
+
+package errorlogger
+
+import "context"
+
+func contextCause(ctx context.Context) error { return context.Cause(ctx) }
@@ -0,0 +1,9 @@
+//go:build !go1.20
+
+package errorlogger
+
+import "context"
+
+// contextCause returns ctx.Err(), since causes were added
+// to the context package in Go 1.20.
+func contextCause(ctx context.Context) error { return ctx.Err() }
@@ -0,0 +1,19 @@
+//go:build go1.20
+
+package errorlogger
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestContextErrFields_cause(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errors.New("client disconnected"))
+
+	got := ContextErrFields(ctx, ctx.Err())
+	if got[CtxCauseKey] != "client disconnected" {
+		t.Errorf("ContextErrFields() = %v, want the cause", got)
+	}
+}
@@ -0,0 +1,145 @@
+package errorlogger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContextErrFields(t *testing.T) {
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	pending, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want []string
+	}{
+		{"deadline exceeded", expired, expired.Err(), []string{CtxDeadlineKey, CtxExpiredAgoKey}},
+		{"wrapped", expired, fmt.Errorf("query: %w", context.DeadlineExceeded), []string{CtxDeadlineKey, CtxExpiredAgoKey}},
+		{"not expired", pending, context.Canceled, []string{CtxDeadlineKey}},
+		{"canceled", canceled, canceled.Err(), nil},
+		{"not a context error", expired, errors.New("other"), nil},
+		{"nil context", nil, context.Canceled, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ContextErrFields(tt.ctx, tt.err)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ContextErrFields() = %v, want keys %v", got, tt.want)
+			}
+			for _, k := range tt.want {
+				if _, ok := got[k]; !ok {
+					t.Errorf("ContextErrFields() = %v, want key %s", got, k)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorLogger_ErrCtx(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	e, buf := newBufferLogger(InfoLevel)
+	if err := e.ErrCtx(ctx, ctx.Err()); err != context.DeadlineExceeded {
+		t.Errorf("ErrCtx() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	for _, want := range []string{"context deadline exceeded", CtxDeadlineKey + "=", CtxExpiredAgoKey + "="} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output = %q, want %q", buf.String(), want)
+		}
+	}
+
+	buf.Reset()
+	e.Disable()
+	if err := e.ErrCtx(ctx, ctx.Err()); err != context.DeadlineExceeded || buf.Len() != 0 {
+		t.Errorf("disabled ErrCtx() = %v with output %q", err, buf.String())
+	}
+}
+
+type requestIDKey struct{}
+
+func TestErrorLogger_AddContextExtractor(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.AddContextExtractor(ContextValue(requestIDKey{}, "request_id"))
+	e.AddContextExtractor(func(ctx context.Context) Fields { return Fields{"user": "ann"} })
+	e.AddContextExtractor(nil)
+
+	var hookCtx context.Context
+	e.AddHook(&countHook{levels: AllLevels, fn: func(entry *Entry) {
+		hookCtx = entry.Context
+	}})
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "r-42")
+	if err := e.ErrCtx(ctx, errFake); err != errFake {
+		t.Errorf("ErrCtx() = %v, want %v", err, errFake)
+	}
+	for _, want := range []string{"msg=fake", "request_id=r-42", "user=ann"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output = %q, want %q", buf.String(), want)
+		}
+	}
+	if hookCtx != ctx {
+		t.Error("ErrCtx() did not set the context of the entry")
+	}
+
+	buf.Reset()
+	_ = e.ErrCtx(context.Background(), errFake)
+	if strings.Contains(buf.String(), "request_id") {
+		t.Errorf("output = %q, want no request_id", buf.String())
+	}
+}
+
+type tenantKey struct{}
+
+func (tenantKey) String() string { return "tenant" }
+
+type localeKey int
+
+func TestErrorLogger_SetContextKeys(t *testing.T) {
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	ctx = context.WithValue(ctx, localeKey(0), "de-DE")
+	ctx = context.WithValue(ctx, "subject", "user:7")
+	ctx = context.WithValue(ctx, requestIDKey{}, "r-1")
+
+	tests := []struct {
+		name     string
+		keys     []interface{}
+		want     []string
+		wantNone []string
+	}{
+		{"stringer, type name, and string keys", []interface{}{tenantKey{}, localeKey(0), "subject"}, []string{"tenant=acme", "localeKey=de-DE", "subject=\"user:7\""}, []string{"r-1"}},
+		{"missing values", []interface{}{"missing", nil}, nil, []string{"missing", "tenant"}},
+		{"replaced", nil, nil, []string{"tenant", "subject"}},
+	}
+	e, buf := newBufferLogger(InfoLevel)
+	e.SetContextKeys(tenantKey{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			e.SetContextKeys(tt.keys...)
+			_ = e.ErrCtx(ctx, errFake)
+
+			out := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output = %q, want %q", out, want)
+				}
+			}
+			for _, none := range tt.wantNone {
+				if strings.Contains(out, none) {
+					t.Errorf("output = %q, want no %q", out, none)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package errorlogger
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// CostGuardOptions configures a CostGuard. A zero threshold
+	// disables that check.
+	CostGuardOptions struct {
+		// Interval is the time between load checks. The
+		// default is 10 seconds.
+		Interval time.Duration
+
+		// MaxRate is the number of entries per second written
+		// at all levels above which verbosity is reduced.
+		MaxRate float64
+
+		// MaxLatency is the mean time spent inside Err above
+		// which verbosity is reduced.
+		MaxLatency time.Duration
+
+		// Floor is the least verbose level the guard will step
+		// down to. The default is ErrorLevel.
+		Floor Level
+
+		// MaxSampling is the largest n of SetSampling the
+		// guard will step up to once the level is at Floor.
+		// The default is DefaultCostGuardMaxSampling.
+		MaxSampling int
+	}
+
+	// CostGuard monitors the logging load of an ErrorLogger
+	// and automatically steps down verbosity while
+	// thresholds are crossed: one level at a time (e.g. Info
+	// to Warn) down to Floor, and then by doubling the
+	// sampling of Err set with SetSampling. The original
+	// level is restored and sampling turned off once the load
+	// falls below half of every threshold.
+	//
+	// Every change is logged as a warning, or at the level of
+	// the logger if it is less verbose, so the degradation is
+	// visible even at Floor.
+	CostGuard struct {
+		mu       sync.Mutex
+		e        ErrorLogger
+		opts     CostGuardOptions
+		degraded bool
+		saved    Level
+		sampling int
+		last     Stats
+		lastTime time.Time
+		stop     chan struct{}
+	}
+)
+
+// DefaultCostGuardMaxSampling is the largest sampling set by
+// a CostGuard if none is given.
+const DefaultCostGuardMaxSampling = 64
+
+// NewCostGuard returns a new CostGuard for e. The guard does
+// nothing until Start is called or Check is called manually.
+func NewCostGuard(e ErrorLogger, opts CostGuardOptions) *CostGuard {
+	if opts.Interval <= 0 {
+		opts.Interval = 10 * time.Second
+	}
+	if opts.Floor == PanicLevel {
+		opts.Floor = ErrorLevel
+	}
+	if opts.MaxSampling <= 0 {
+		opts.MaxSampling = DefaultCostGuardMaxSampling
+	}
+	return &CostGuard{
+		e:        e,
+		opts:     opts,
+		sampling: 1,
+		last:     e.Stats(),
+		lastTime: time.Now(),
+	}
+}
+
+// Degraded reports whether the guard has reduced verbosity.
+func (g *CostGuard) Degraded() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.degraded
+}
+
+// Check measures the load since the previous check and steps
+// verbosity down or restores it as needed. It returns true
+// if the level or sampling was changed.
+func (g *CostGuard) Check(now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	cur := g.e.Stats()
+	elapsed := now.Sub(g.lastTime).Seconds()
+	var entries uint64
+	for level, n := range cur.Entries {
+		entries += n - g.last.Entries[level]
+	}
+	errs := cur.ErrLatency.Count - g.last.ErrLatency.Count
+
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(entries) / elapsed
+	}
+	var latency time.Duration
+	if errs > 0 {
+		latency = (cur.ErrLatency.Sum - g.last.ErrLatency.Sum) / time.Duration(errs)
+	}
+	g.last, g.lastTime = cur, now
+
+	over := (g.opts.MaxRate > 0 && rate > g.opts.MaxRate) ||
+		(g.opts.MaxLatency > 0 && latency > g.opts.MaxLatency)
+	under := (g.opts.MaxRate == 0 || rate < g.opts.MaxRate/2) &&
+		(g.opts.MaxLatency == 0 || latency < g.opts.MaxLatency/2)
+
+	fields := Fields{"entry_rate": rate, "err_latency": latency.String()}
+
+	switch {
+	case over:
+		level := g.e.GetLevel()
+		if !g.degraded {
+			g.saved = level
+		}
+		switch {
+		case level > g.opts.Floor:
+			g.degraded = true
+			g.e.SetLevel(level - 1)
+			g.notify(fields, "logging load too high: reducing verbosity to %v", level-1)
+		case g.sampling < g.opts.MaxSampling:
+			g.degraded = true
+			g.sampling *= 2
+			if g.sampling > g.opts.MaxSampling {
+				g.sampling = g.opts.MaxSampling
+			}
+			g.e.SetSampling(g.sampling)
+			g.notify(fields, "logging load too high: logging 1 in %d errors", g.sampling)
+		default:
+			return false
+		}
+		return true
+
+	case under && g.degraded:
+		g.degraded = false
+		g.e.SetLevel(g.saved)
+		if g.sampling > 1 {
+			g.sampling = 1
+			g.e.SetSampling(1)
+		}
+		g.notify(fields, "logging load normal: verbosity restored to %v", g.saved)
+		return true
+	}
+	return false
+}
+
+// notify logs a change made by the guard at WarnLevel, or at
+// the level of the logger if it is less verbose, so that the
+// change is not filtered out by the level the guard set.
+func (g *CostGuard) notify(fields Fields, format string, args ...interface{}) {
+	level := g.e.GetLevel()
+	if level > WarnLevel {
+		level = WarnLevel
+	}
+	g.e.WithFields(fields).Logf(level, format, args...)
+}
+
+// Start checks the load at every interval until Stop is called.
+func (g *CostGuard) Start() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	g.stop = stop
+
+	go func() {
+		ticker := time.NewTicker(g.opts.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case t := <-ticker.C:
+				g.Check(t)
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the guard. If verbosity was reduced, it is
+// left as is.
+func (g *CostGuard) Stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stop != nil {
+		close(g.stop)
+		g.stop = nil
+	}
+}
@@ -0,0 +1,96 @@
+package errorlogger
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCostGuard(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	g := NewCostGuard(e, CostGuardOptions{MaxRate: 10, Floor: WarnLevel, MaxSampling: 4})
+	start := time.Now()
+
+	steps := []struct {
+		name         string
+		log          func()
+		after        time.Duration
+		wantChange   bool
+		wantLevel    Level
+		wantSampling uint32
+		wantDegraded bool
+	}{
+		{"info flood", func() {
+			for i := 0; i < 50; i++ {
+				e.Info("busy")
+			}
+		}, time.Second, true, WarnLevel, 1, true},
+		{"error flood at floor", func() {
+			for i := 0; i < 50; i++ {
+				_ = e.Err(errFake)
+			}
+		}, 2 * time.Second, true, WarnLevel, 2, true},
+		{"sampled error flood", func() {
+			for i := 0; i < 50; i++ {
+				_ = e.Err(errFake)
+			}
+		}, 3 * time.Second, true, WarnLevel, 4, true},
+		{"at max sampling", func() {
+			for i := 0; i < 50; i++ {
+				_ = e.Err(errFake)
+			}
+		}, 4 * time.Second, false, WarnLevel, 4, true},
+		{"idle", func() {}, 14 * time.Second, true, InfoLevel, 1, false},
+	}
+	for _, st := range steps {
+		st.log()
+		if got := g.Check(start.Add(st.after)); got != st.wantChange {
+			t.Errorf("%s: CostGuard.Check() = %v, want %v", st.name, got, st.wantChange)
+		}
+		if got := e.GetLevel(); got != st.wantLevel {
+			t.Errorf("%s: level = %v, want %v", st.name, got, st.wantLevel)
+		}
+		if got := atomic.LoadUint32(&e.sampleN); got != st.wantSampling && (got != 0 || st.wantSampling != 1) {
+			t.Errorf("%s: sampling = %d, want %d", st.name, got, st.wantSampling)
+		}
+		if got := g.Degraded(); got != st.wantDegraded {
+			t.Errorf("%s: CostGuard.Degraded() = %v, want %v", st.name, got, st.wantDegraded)
+		}
+	}
+	for _, msg := range []string{"reducing verbosity", "logging 1 in 4 errors", "verbosity restored"} {
+		if !strings.Contains(buf.String(), msg) {
+			t.Errorf("CostGuard did not log %q", msg)
+		}
+	}
+
+	g.Start()
+	g.Start()
+	g.Stop()
+	g.Stop()
+}
+
+func TestCostGuard_floor(t *testing.T) {
+	e, buf := newBufferLogger(WarnLevel)
+	g := NewCostGuard(e, CostGuardOptions{MaxRate: 10, MaxSampling: 2})
+	start := time.Now()
+
+	for i, want := range []string{
+		"reducing verbosity to error",
+		"logging 1 in 2 errors",
+		"verbosity restored to warning",
+	} {
+		if i < 2 {
+			for j := 0; j < 50; j++ {
+				e.Error("busy")
+			}
+		}
+		buf.Reset()
+		if !g.Check(start.Add(time.Duration(i+1) * time.Second)) {
+			t.Fatalf("CostGuard.Check() %d = false, want a change", i)
+		}
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("CostGuard.Check() %d logged %q, want %q", i, buf.String(), want)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package errorlogger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+type (
+	// CrashReportOptions configures crash reports.
+	CrashReportOptions struct {
+		// Dir is the directory reports are written to. The
+		// default is the current directory.
+		Dir string
+
+		// Recent is the number of recent entries included in
+		// a report. The default is DefaultRingSize.
+		Recent int
+
+		// UploadURL is the endpoint that reports left by
+		// earlier runs are posted to when crash reports are
+		// enabled. Reports are only uploaded if UploadConsent
+		// is also set, which should reflect an explicit
+		// choice of the user.
+		UploadURL     string
+		UploadConsent bool
+	}
+
+	// CrashReport is the structured post-mortem record
+	// written when the process panics or exits fatally,
+	// similar to the hs_err files of the JVM.
+	CrashReport struct {
+		Time      time.Time   `json:"time"`
+		Level     Level       `json:"level"`
+		Message   string      `json:"msg"`
+		PanicType string      `json:"panic_type,omitempty"`
+		Stack     string      `json:"stack"`
+		Recent    []RingEntry `json:"recent"`
+		Build     CrashBuild  `json:"build"`
+		Config    Config      `json:"config"`
+	}
+
+	// CrashBuild describes the crashed process and binary.
+	CrashBuild struct {
+		PID       int               `json:"pid"`
+		Args      []string          `json:"args"`
+		GoVersion string            `json:"go_version"`
+		GOOS      string            `json:"goos"`
+		GOARCH    string            `json:"goarch"`
+		Path      string            `json:"path,omitempty"`
+		Version   string            `json:"version,omitempty"`
+		Settings  map[string]string `json:"settings,omitempty"`
+	}
+
+	// CrashReporter is a logrus hook that writes a
+	// CrashReport file for entries at PanicLevel and
+	// FatalLevel and for panics logged by LogPanic.
+	CrashReporter struct {
+		e    *errorLogger
+		dir  string
+		ring *RingBuffer
+		diag Writer // where report paths are announced
+
+		uploadURL     string
+		uploadConsent bool
+		client        *http.Client
+	}
+)
+
+// EnableCrashReports adds hooks to the logger that keep the
+// recent entries in a RingBuffer and write a crash report
+// file to opts.Dir when the process panics or exits
+// fatally through the logger. It returns the reporter.
+//  log.EnableCrashReports(CrashReportOptions{Dir: "/var/crash/myapp"})
+//  defer log.Recover()
+//
+// If opts.UploadURL and opts.UploadConsent are set, the
+// reports left by earlier runs are uploaded in the
+// background; see CrashReporter.Upload.
+func (e *errorLogger) EnableCrashReports(opts CrashReportOptions) *CrashReporter {
+	c := &CrashReporter{
+		e:             e,
+		dir:           opts.Dir,
+		ring:          NewRingBuffer(opts.Recent),
+		diag:          os.Stderr,
+		uploadURL:     opts.UploadURL,
+		uploadConsent: opts.UploadConsent,
+		client:        &http.Client{Timeout: DefaultCrashUploadTimeout},
+	}
+	e.AddHook(c.ring)
+	e.AddHook(c)
+	if c.uploadURL != "" && c.uploadConsent {
+		go c.Upload(context.Background())
+	}
+	return c
+}
+
+// Levels returns all levels, since panics logged by
+// LogPanic are logged at ErrorLevel.
+func (c *CrashReporter) Levels() []Level { return c.ring.Levels() }
+
+// Fire writes a crash report for fatal entries.
+func (c *CrashReporter) Fire(entry *Entry) error {
+	_, isPanic := entry.Data["panic_type"]
+	if entry.Level > FatalLevel && !isPanic {
+		return nil
+	}
+	path, err := c.Write(entry)
+	if err != nil {
+		fmt.Fprintf(c.diag, "errorlogger: cannot write crash report: %v\n", err)
+		return nil
+	}
+	fmt.Fprintf(c.diag, "errorlogger: crash report written to %s\n", path)
+	return nil
+}
+
+// Write writes a crash report for entry and returns the
+// path of the file.
+func (c *CrashReporter) Write(entry *Entry) (string, error) {
+	r := CrashReport{
+		Time:    entry.Time,
+		Level:   entry.Level,
+		Message: entry.Message,
+		Recent:  c.ring.Entries(),
+		Build:   crashBuild(),
+		Config:  c.e.Config(),
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	if t, ok := entry.Data["panic_type"].(string); ok {
+		r.PanicType = t
+	}
+	if s, ok := entry.Data[StackKey].(string); ok {
+		r.Stack = s
+	} else {
+		r.Stack = string(debug.Stack())
+	}
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if c.dir != "" {
+		if err := os.MkdirAll(c.dir, 0o750); err != nil {
+			return "", err
+		}
+	}
+	name := fmt.Sprintf("crash-%s-%d.json", r.Time.UTC().Format("20060102T150405.000000000Z"), r.Build.PID)
+	path := filepath.Join(c.dir, name)
+	return path, os.WriteFile(path, b, 0o600)
+}
+
+func crashBuild() CrashBuild {
+	b := CrashBuild{
+		PID:       os.Getpid(),
+		Args:      os.Args,
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		b.Path = info.Main.Path
+		b.Version = info.Main.Version
+		if len(info.Settings) > 0 {
+			b.Settings = make(map[string]string, len(info.Settings))
+			for _, s := range info.Settings {
+				b.Settings[s.Key] = s.Value
+			}
+		}
+	}
+	return b
+}
+
+// jsonSafe returns a copy of fields with values that cannot
+// be encoded as JSON replaced by their string form. Errors
+// are replaced by their message.
+func jsonSafe(fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+	safe := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		switch v := v.(type) {
+		case error:
+			safe[k] = v.Error()
+		default:
+			if _, err := json.Marshal(v); err != nil {
+				safe[k] = fmt.Sprint(v)
+			} else {
+				safe[k] = v
+			}
+		}
+	}
+	return safe
+}
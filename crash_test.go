@@ -0,0 +1,80 @@
+package errorlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCrashReporter(t *testing.T) {
+	tests := []struct {
+		name      string
+		crash     func(e *errorLogger)
+		wantLevel Level
+		wantType  string
+	}{
+		{"LogPanic", func(e *errorLogger) { _ = e.LogPanic("boom") }, ErrorLevel, "string"},
+		{"Panic", func(e *errorLogger) {
+			defer func() { recover() }()
+			e.Panic("boom")
+		}, PanicLevel, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newBufferLogger(InfoLevel)
+			dir := filepath.Join(t.TempDir(), "crash")
+			c := e.EnableCrashReports(CrashReportOptions{Dir: dir, Recent: 2})
+			var diag bytes.Buffer
+			c.diag = &diag
+
+			e.Info("starting")
+			e.WithError(errors.New("disk slow")).Warn("degraded")
+			e.Error("not a crash")
+			tt.crash(e)
+
+			files, _ := filepath.Glob(filepath.Join(dir, "crash-*.json"))
+			if len(files) != 1 {
+				t.Fatalf("crash reports = %v, want 1", files)
+			}
+			if !strings.Contains(diag.String(), files[0]) {
+				t.Errorf("diagnostics = %q, want report path", diag.String())
+			}
+
+			b, err := os.ReadFile(files[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var r CrashReport
+			if err := json.Unmarshal(b, &r); err != nil {
+				t.Fatal(err)
+			}
+			if r.Level != tt.wantLevel || r.PanicType != tt.wantType || !strings.Contains(r.Message, "boom") {
+				t.Errorf("report = level %v, panic type %q, msg %q", r.Level, r.PanicType, r.Message)
+			}
+			if r.Stack == "" || r.Build.PID != os.Getpid() || r.Build.GoVersion == "" || r.Config.Level != "info" {
+				t.Errorf("report is missing stack, build info, or config:\n%s", b)
+			}
+			if len(r.Recent) != 2 || r.Recent[0].Message != "not a crash" || !strings.Contains(r.Recent[1].Message, "boom") {
+				t.Errorf("report recent entries = %+v", r.Recent)
+			}
+		})
+	}
+}
+
+func Test_jsonSafe(t *testing.T) {
+	in := map[string]interface{}{"err": errors.New("e"), "ch": make(chan int), "n": 1}
+	got := jsonSafe(in)
+	if _, err := json.Marshal(got); err != nil {
+		t.Errorf("jsonSafe() result does not marshal: %v", err)
+	}
+	if got["err"] != "e" || got["n"] != 1 {
+		t.Errorf("jsonSafe() = %v", got)
+	}
+	if _, ok := in["err"].(error); !ok {
+		t.Error("jsonSafe() modified its argument")
+	}
+}
@@ -0,0 +1,78 @@
+package errorlogger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCrashUploadTimeout is the timeout of each crash
+// report upload.
+const DefaultCrashUploadTimeout = 10 * time.Second
+
+// UploadedSuffix is appended to the name of a crash report
+// file once it has been uploaded. Uploaded reports are kept
+// for local post-mortem analysis but are not uploaded again.
+const UploadedSuffix = ".uploaded"
+
+// Upload posts the crash reports in the report directory
+// that have not been uploaded yet to the upload URL, oldest
+// first, and returns the paths of the uploaded files. Each
+// report is sent as the JSON body of a POST request.
+//
+// Nothing is uploaded unless both an upload URL and the
+// consent of the user were given in CrashReportOptions, so
+// field deployments of CLI tools can report crashes without
+// manual log collection, but only with permission.
+//
+// Upload failures are logged and stop the upload; the
+// remaining reports are retried on the next call.
+func (c *CrashReporter) Upload(ctx context.Context) ([]string, error) {
+	if c.uploadURL == "" || !c.uploadConsent {
+		return nil, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(c.dir, "crash-*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var uploaded []string
+	for _, path := range files {
+		if err := c.upload(ctx, path); err != nil {
+			return uploaded, err
+		}
+		uploaded = append(uploaded, path)
+	}
+	return uploaded, nil
+}
+
+func (c *CrashReporter) upload(ctx context.Context, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return c.e.Err(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.uploadURL, bytes.NewReader(b))
+	if err != nil {
+		return c.e.Err(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err == nil {
+		defer resp.Body.Close()
+	}
+	if err := c.e.CheckResponse(resp, err); err != nil {
+		return fmt.Errorf("upload crash report %s: %w", filepath.Base(path), err)
+	}
+
+	if err := os.Rename(path, path+UploadedSuffix); err != nil {
+		return c.e.Err(err)
+	}
+	c.e.WithField("crash_report", filepath.Base(path)).Info("crash report uploaded")
+	return nil
+}
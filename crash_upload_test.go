@@ -0,0 +1,71 @@
+package errorlogger
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestCrashReporter_Upload(t *testing.T) {
+	tests := []struct {
+		name     string
+		consent  bool
+		status   int
+		wantPost int
+		wantUp   int
+		wantErr  bool
+	}{
+		{"no consent", false, http.StatusOK, 0, 0, false},
+		{"uploaded", true, http.StatusAccepted, 2, 2, false},
+		{"server error", true, http.StatusInternalServerError, 1, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var bodies []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				mu.Lock()
+				bodies = append(bodies, string(b))
+				mu.Unlock()
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("request = %s %s", r.Method, r.Header.Get("Content-Type"))
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			dir := t.TempDir()
+			for _, name := range []string{"crash-1-1.json", "crash-2-1.json", "crash-0-1.json" + UploadedSuffix} {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(`{"msg":"`+name+`"}`), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			e, _ := newBufferLogger(InfoLevel)
+			c := e.EnableCrashReports(CrashReportOptions{Dir: dir})
+			c.uploadURL, c.uploadConsent = srv.URL, tt.consent
+
+			got, err := c.Upload(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Upload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.wantUp || len(bodies) != tt.wantPost {
+				t.Errorf("Upload() = %v with %d requests, want %d uploaded with %d requests", got, len(bodies), tt.wantUp, tt.wantPost)
+			}
+			if tt.wantPost > 0 && bodies[0] != `{"msg":"crash-1-1.json"}` {
+				t.Errorf("first upload body = %s", bodies[0])
+			}
+
+			pending, _ := filepath.Glob(filepath.Join(dir, "crash-*.json"))
+			if len(pending) != 2-tt.wantUp {
+				t.Errorf("pending reports = %v", pending)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package errorlogger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// The fields added to the entries of Err by a logger with a
+// dedup window.
+const (
+	ErrFingerprintKey = "err_fingerprint"
+	OccurrencesKey    = "occurrences"
+)
+
+// ErrFingerprint returns a short, stable identifier for err:
+// a hash of its type and message, so that identical errors
+// share a fingerprint. Unlike Fingerprint, numbers in the
+// message are significant.
+func ErrFingerprint(err error) string {
+	return fingerprint(fmt.Sprintf("%T", err), safeSprint(err))
+}
+
+type (
+	// dedupTable holds the errors logged in the current
+	// dedup window of each fingerprint.
+	dedupTable struct {
+		e      *errorLogger
+		window time.Duration
+
+		mu   sync.Mutex
+		seen map[string]*dedupEntry
+	}
+
+	// dedupEntry is an error logged in a dedup window and the
+	// number of times it occurred.
+	dedupEntry struct {
+		err    error
+		fields Fields
+		count  int
+		timer  *time.Timer
+	}
+)
+
+// SetDedupWindow logs identical errors passed to Err once per
+// window instead of once per call, so that an error repeated
+// thousands of times is logged as two lines:
+//  log.SetDedupWindow(time.Minute)
+//
+// The first occurrence of an error is logged at once. If it
+// occurs again within the window, it is logged again when
+// the window closes with the number of occurrences in the
+// OccurrencesKey field. Errors are identified by
+// ErrFingerprint, which is added as the ErrFingerprintKey
+// field for downstream aggregation. Err returns every error
+// unchanged.
+//
+// A window of zero or less turns deduplication off; pending
+// occurrences are logged first.
+func (e *errorLogger) SetDedupWindow(window time.Duration) {
+	var d *dedupTable
+	if window > 0 {
+		d = &dedupTable{e: e, window: window, seen: make(map[string]*dedupEntry)}
+	}
+	old, _ := e.dedup.Load().(*dedupTable)
+	e.dedup.Store(d)
+	if old != nil {
+		old.close()
+	}
+}
+
+// dedupErr returns the fingerprint of err and whether err
+// should be logged, counting it if it occurred in the
+// current window. A copy of fields is kept for the entry
+// logged when the window closes. It returns "", true
+// without a dedup window.
+func (e *errorLogger) dedupErr(err error, fields Fields) (string, bool) {
+	d, _ := e.dedup.Load().(*dedupTable)
+	if d == nil {
+		return "", true
+	}
+	fp := ErrFingerprint(err)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if de, ok := d.seen[fp]; ok {
+		de.count++
+		return fp, false
+	}
+	de := &dedupEntry{err: err, fields: make(Fields, len(fields)+2), count: 1}
+	for k, v := range fields {
+		de.fields[k] = v
+	}
+	de.timer = time.AfterFunc(d.window, func() { d.flush(fp, de) })
+	d.seen[fp] = de
+	return fp, true
+}
+
+// flush ends the window of de and logs its occurrences.
+func (d *dedupTable) flush(fp string, de *dedupEntry) {
+	d.mu.Lock()
+	if d.seen[fp] != de {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.seen, fp)
+	d.mu.Unlock()
+	d.log(fp, de)
+}
+
+// close ends every window and logs the pending occurrences.
+func (d *dedupTable) close() {
+	d.mu.Lock()
+	seen := d.seen
+	d.seen = make(map[string]*dedupEntry)
+	d.mu.Unlock()
+	for fp, de := range seen {
+		de.timer.Stop()
+		d.log(fp, de)
+	}
+}
+
+// log logs the repeated occurrences of de, if any.
+func (d *dedupTable) log(fp string, de *dedupEntry) {
+	if de.count < 2 {
+		return
+	}
+	de.fields[ErrFingerprintKey] = fp
+	de.fields[OccurrencesKey] = de.count
+	d.e.logErr(nil, de.err, de.fields)
+}
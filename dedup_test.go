@@ -0,0 +1,99 @@
+package errorlogger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrFingerprint(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b error
+		same bool
+	}{
+		{"identical", errors.New("disk full"), errors.New("disk full"), true},
+		{"message", errors.New("disk full"), errors.New("disk empty"), false},
+		{"numbers", errors.New("retry 1"), errors.New("retry 2"), false},
+		{"type", errors.New("disk full"), fmt.Errorf("disk %w", errors.New("full")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrFingerprint(tt.a) == ErrFingerprint(tt.b); got != tt.same {
+				t.Errorf("same fingerprint = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func TestErrorLogger_SetDedupWindow(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.SetDedupWindow(time.Hour)
+
+	errOther := errors.New("other")
+	for i := 0; i < 5; i++ {
+		if got := e.Err(errFake); got != errFake {
+			t.Errorf("Err() = %v, want %v", got, errFake)
+		}
+	}
+	e.ErrWithFields(errOther, Fields{"k": "v"})
+	e.ErrWithFields(errOther, Fields{"k": "v"})
+
+	out := buf.String()
+	if got := strings.Count(out, "\n"); got != 2 {
+		t.Fatalf("logged %d lines, want 2: %q", got, out)
+	}
+	fp := ErrFingerprint(errFake)
+	if !strings.Contains(out, ErrFingerprintKey+"="+fp) {
+		t.Errorf("output = %q, want the fingerprint %s", out, fp)
+	}
+	if strings.Contains(out, OccurrencesKey) {
+		t.Errorf("output = %q, want no occurrences before the window closes", out)
+	}
+
+	buf.Reset()
+	e.SetDedupWindow(0)
+	out = buf.String()
+	for _, want := range []string{OccurrencesKey + "=5", OccurrencesKey + "=2", "k=v"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+	}
+
+	buf.Reset()
+	e.Err(errFake)
+	e.Err(errFake)
+	if got := strings.Count(buf.String(), "\n"); got != 2 {
+		t.Errorf("logged %d lines without a window, want 2", got)
+	}
+	if strings.Contains(buf.String(), ErrFingerprintKey) {
+		t.Errorf("output = %q, want no fingerprint without a window", buf.String())
+	}
+}
+
+func TestErrorLogger_SetDedupWindow_timer(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	buf := &syncBuffer{}
+	e.SetOutput(buf)
+	e.SetDedupWindow(20 * time.Millisecond)
+	defer e.SetDedupWindow(0)
+
+	for i := 0; i < 3; i++ {
+		e.Err(errFake)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(buf.String(), OccurrencesKey+"=3") {
+		if time.Now().After(deadline) {
+			t.Fatalf("output = %q, want the occurrences when the window closes", buf.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// A new window starts after the previous one closed.
+	e.Err(errFake)
+	if got := strings.Count(buf.String(), "\n"); got != 3 {
+		t.Errorf("logged %d lines, want 3: %q", got, buf.String())
+	}
+}
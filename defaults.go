@@ -0,0 +1,83 @@
+package errorlogger
+
+import (
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaults are the package defaults applied to new loggers.
+var defaults struct {
+	mu        sync.RWMutex
+	wrap      error
+	level     Level
+	levelSet  bool
+	formatter Formatter
+}
+
+// SetDefaultWrap sets the error used to wrap the errors of
+// loggers created afterwards without a wrap, as set by
+// SetErrorWrap. A nil wrap disables wrapping, which is the
+// default.
+//  func main() {
+//      errorlogger.SetDefaultWrap(&os.PathError{})
+//      ...
+//  }
+func SetDefaultWrap(wrap error) {
+	defaults.mu.Lock()
+	defaults.wrap = wrap
+	defaults.mu.Unlock()
+}
+
+// DefaultWrap returns the error set with SetDefaultWrap.
+func DefaultWrap() error {
+	defaults.mu.RLock()
+	defer defaults.mu.RUnlock()
+	return defaults.wrap
+}
+
+// SetDefaultLevel sets the level of loggers created
+// afterwards without a *Logger. The default is
+// DefaultLogLevel.
+func SetDefaultLevel(lvl Level) {
+	defaults.mu.Lock()
+	defaults.level, defaults.levelSet = lvl, true
+	defaults.mu.Unlock()
+}
+
+// SetDefaultFormatter sets the formatter of loggers created
+// afterwards without a *Logger. A nil formatter restores
+// DefaultTextFormatter.
+func SetDefaultFormatter(f Formatter) {
+	defaults.mu.Lock()
+	defaults.formatter = f
+	defaults.mu.Unlock()
+}
+
+// defaultLogger returns the *Logger of a logger created
+// without one. Until a default level or formatter is set,
+// such loggers start from a copy of the logrus logger of
+// Log; afterwards from one with the defaults, so that the
+// defaults do not change the loggers created before.
+func defaultLogger() *Logger {
+	defaults.mu.RLock()
+	defer defaults.mu.RUnlock()
+	if !defaults.levelSet && defaults.formatter == nil {
+		return defaultlogger
+	}
+
+	l := &Logger{
+		Out:       os.Stderr,
+		Formatter: DefaultTextFormatter,
+		Hooks:     make(logrus.LevelHooks),
+		Level:     DefaultLogLevel,
+	}
+	if defaults.formatter != nil {
+		l.Formatter = defaults.formatter
+	}
+	if defaults.levelSet {
+		l.Level = defaults.level
+	}
+	return l
+}
@@ -0,0 +1,49 @@
+package errorlogger
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetDefaults(t *testing.T) {
+	defer func() {
+		defaults.mu.Lock()
+		defaults.wrap, defaults.level, defaults.levelSet, defaults.formatter = nil, 0, false, nil
+		defaults.mu.Unlock()
+	}()
+
+	before := New().(*errorLogger)
+	if before.Logger == defaultlogger || before.Logger == New().(*errorLogger).Logger {
+		t.Error("New() shares a logger with another ErrorLogger")
+	}
+	if before.GetLevel() != defaultlogger.GetLevel() {
+		t.Errorf("level = %v, want the level of the default logger", before.GetLevel())
+	}
+
+	SetDefaultWrap(fakeSysCallError)
+	SetDefaultLevel(DebugLevel)
+	f := NewJSONFormatter(false)
+	SetDefaultFormatter(f)
+
+	e := New().(*errorLogger)
+	if e.Logger == defaultlogger {
+		t.Fatal("New() with defaults shares the default logger")
+	}
+	if e.GetLevel() != DebugLevel {
+		t.Errorf("level = %v, want %v", e.GetLevel(), DebugLevel)
+	}
+	if e.formatter() != f {
+		t.Errorf("formatter = %T, want the default formatter", e.formatter())
+	}
+	e.SetOutput(Discard)
+	if err := e.Err(errFake); !errors.Is(err, fakeSysCallError) {
+		t.Errorf("Err() = %v, want it wrapped by the default wrap", err)
+	}
+
+	if before.GetLevel() == DebugLevel || before.formatter() == f || before.wrap != nil {
+		t.Error("defaults changed a logger created before them")
+	}
+	if e := NewWithOptions(true, "", nil, errFake, nil).(*errorLogger); e.wrap != errFake {
+		t.Errorf("wrap = %v, want the wrap passed to NewWithOptions", e.wrap)
+	}
+}
@@ -0,0 +1,206 @@
+package errorlogger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	// MaxDiffLines is the number of differences logged by
+	// Diff. Further differences are only counted.
+	MaxDiffLines = 20
+
+	// maxDiffValue is the number of characters of a value
+	// shown in a difference.
+	maxDiffValue = 60
+)
+
+// Diff compares want and got structurally and reports
+// whether they are equal. If they differ, an ErrorLevel
+// entry lists where, one difference per path, in the "diff"
+// field; otherwise a DebugLevel entry is logged. The list
+// is bounded by MaxDiffLines, so that diffs of large values
+// stay readable:
+//  log.Diff("inventory", expected, actual)
+//  // level=error msg="inventory: values differ" diff=".Items[2].Price: want 10, got 12; .Tags[\"sale\"]: missing" diff_name=inventory differences=2
+//
+// Nil and empty slices and maps are considered equal.
+// Cycles are followed once.
+func (e *errorLogger) Diff(name string, want, got interface{}) bool {
+	lines, n := DiffValues(want, got)
+	if n == 0 {
+		e.WithField("diff_name", name).Debugf("%s: values equal", name)
+		return true
+	}
+	if n > len(lines) {
+		lines = append(lines, fmt.Sprintf("and %d more", n-len(lines)))
+	}
+	e.WithFields(Fields{
+		"diff_name":   name,
+		"diff":        strings.Join(lines, "; "),
+		"differences": n,
+	}).Errorf("%s: values differ", name)
+	return false
+}
+
+// DiffValues returns up to MaxDiffLines differences between
+// want and got, each as "path: want x, got y", and the total
+// number of differences.
+func DiffValues(want, got interface{}) (lines []string, n int) {
+	d := &differ{visited: make(map[[2]uintptr]bool)}
+	d.diff("", reflect.ValueOf(want), reflect.ValueOf(got), 0)
+	return d.lines, d.n
+}
+
+// differ collects the differences of two values.
+type differ struct {
+	lines   []string
+	n       int
+	visited map[[2]uintptr]bool
+}
+
+func (d *differ) add(path, format string, args ...interface{}) {
+	d.n++
+	if len(d.lines) < MaxDiffLines {
+		if path == "" {
+			path = "value"
+		}
+		d.lines = append(d.lines, path+": "+fmt.Sprintf(format, args...))
+	}
+}
+
+func (d *differ) diff(path string, want, got reflect.Value, depth int) {
+	if !want.IsValid() || !got.IsValid() {
+		if want.IsValid() != got.IsValid() {
+			d.add(path, "want %s, got %s", diffString(want), diffString(got))
+		}
+		return
+	}
+	if want.Type() != got.Type() {
+		d.add(path, "want %s of type %v, got %s of type %v", diffString(want), want.Type(), diffString(got), got.Type())
+		return
+	}
+	if depth > MaxFieldDepth {
+		return
+	}
+
+	switch want.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if want.IsNil() || got.IsNil() {
+			if want.IsNil() != got.IsNil() {
+				d.add(path, "want %s, got %s", diffString(want), diffString(got))
+			}
+			return
+		}
+		if want.Kind() == reflect.Ptr {
+			key := [2]uintptr{want.Pointer(), got.Pointer()}
+			if d.visited[key] {
+				return
+			}
+			d.visited[key] = true
+		}
+		d.diff(path, want.Elem(), got.Elem(), depth+1)
+
+	case reflect.Struct:
+		for i := 0; i < want.NumField(); i++ {
+			d.diff(path+"."+want.Type().Field(i).Name, want.Field(i), got.Field(i), depth+1)
+		}
+
+	case reflect.Map:
+		keys := append(want.MapKeys(), got.MapKeys()...)
+		sort.Slice(keys, func(i, j int) bool { return diffString(keys[i]) < diffString(keys[j]) })
+		for i, k := range keys {
+			if i > 0 && diffString(k) == diffString(keys[i-1]) {
+				continue
+			}
+			p := fmt.Sprintf("%s[%s]", path, diffString(k))
+			w, g := want.MapIndex(k), got.MapIndex(k)
+			switch {
+			case !g.IsValid():
+				d.add(p, "missing")
+			case !w.IsValid():
+				d.add(p, "unexpected %s", diffString(g))
+			default:
+				d.diff(p, w, g, depth+1)
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		if want.Kind() == reflect.Slice && want.Len() > 0 && got.Len() > 0 && want.Pointer() == got.Pointer() && want.Len() == got.Len() {
+			return
+		}
+		n := want.Len()
+		if got.Len() < n {
+			n = got.Len()
+		}
+		for i := 0; i < n; i++ {
+			d.diff(fmt.Sprintf("%s[%d]", path, i), want.Index(i), got.Index(i), depth+1)
+		}
+		for i := n; i < want.Len(); i++ {
+			d.add(fmt.Sprintf("%s[%d]", path, i), "missing %s", diffString(want.Index(i)))
+		}
+		for i := n; i < got.Len(); i++ {
+			d.add(fmt.Sprintf("%s[%d]", path, i), "unexpected %s", diffString(got.Index(i)))
+		}
+
+	default:
+		if !scalarEqual(want, got) {
+			d.add(path, "want %s, got %s", diffString(want), diffString(got))
+		}
+	}
+}
+
+// scalarEqual compares values of the same type that cannot
+// nest, without calling Interface, which is not allowed for
+// unexported fields.
+func scalarEqual(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	}
+	return true
+}
+
+// diffString formats v for a difference, quoting strings and
+// truncating long values.
+func diffString(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	var s string
+	switch v.Kind() {
+	case reflect.String:
+		s = fmt.Sprintf("%q", v.String())
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return "nil"
+		}
+		if tooDeep(v, 0) {
+			return fmt.Sprintf("<%v nested deeper than %d>", v.Type(), MaxFieldDepth)
+		}
+		s = fmt.Sprintf("%v", v)
+	default:
+		if tooDeep(v, 0) {
+			return fmt.Sprintf("<%v nested deeper than %d>", v.Type(), MaxFieldDepth)
+		}
+		s = fmt.Sprintf("%v", v)
+	}
+	if r := []rune(s); len(r) > maxDiffValue {
+		s = string(r[:maxDiffValue]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+package errorlogger
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type diffItem struct {
+	Name  string
+	Price int
+	note  string
+}
+
+type diffOrder struct {
+	ID    int
+	Items []diffItem
+	Tags  map[string]bool
+	Next  *diffOrder
+}
+
+func TestDiffValues(t *testing.T) {
+	base := func() diffOrder {
+		return diffOrder{
+			ID:    1,
+			Items: []diffItem{{"pen", 2, "a"}, {"ink", 10, ""}},
+			Tags:  map[string]bool{"sale": true},
+		}
+	}
+	cyclic := base()
+	cyclic.Next = &cyclic
+
+	tests := []struct {
+		name   string
+		change func(o *diffOrder)
+		want   []string
+	}{
+		{"equal", func(o *diffOrder) {}, nil},
+		{"nil and empty", func(o *diffOrder) { o.Tags = map[string]bool{}; o.Items = nil }, []string{`.Items[0]: missing {pen 2 a}`, `.Items[1]: missing {ink 10 }`, `.Tags["sale"]: missing`}},
+		{"field", func(o *diffOrder) { o.Items[1].Price = 12 }, []string{".Items[1].Price: want 10, got 12"}},
+		{"unexported", func(o *diffOrder) { o.Items[0].note = "b" }, []string{`.Items[0].note: want "a", got "b"`}},
+		{"map", func(o *diffOrder) { o.Tags["new"] = true }, []string{`.Tags["new"]: unexpected true`}},
+		{"append", func(o *diffOrder) { o.Items = append(o.Items, diffItem{Name: "cap"}) }, []string{`.Items[2]: unexpected {cap 0 }`}},
+		{"pointer", func(o *diffOrder) { o.Next = &diffOrder{} }, []string{".Next: want nil, got &{0 [] map[] <nil>}"}},
+		{"cycle", func(o *diffOrder) { *o = cyclic }, []string{".Next: want nil, got <*errorlogger.diffOrder nested deeper than"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := base()
+			tt.change(&got)
+			lines, n := DiffValues(base(), got)
+			if n != len(tt.want) {
+				t.Fatalf("DiffValues() = %q (%d), want %q", lines, n, tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(lines[i], want) {
+					t.Errorf("DiffValues()[%d] = %q, want %q", i, lines[i], want)
+				}
+			}
+		})
+	}
+
+	if lines, n := DiffValues(cyclic, cyclic); n != 0 {
+		t.Errorf("DiffValues() of a cyclic value with itself = %q", lines)
+	}
+	if lines, _ := DiffValues(1, "1"); !reflect.DeepEqual(lines, []string{`value: want 1 of type int, got "1" of type string`}) {
+		t.Errorf("DiffValues() of different types = %q", lines)
+	}
+}
+
+func TestErrorLogger_Diff(t *testing.T) {
+	e, buf := newBufferLogger(DebugLevel)
+	want := make([]int, 30)
+	got := make([]int, 30)
+	for i := range got {
+		got[i] = i + 1
+	}
+
+	if e.Diff("counts", want, want) != true {
+		t.Error("Diff() of equal values = false")
+	}
+	if e.Diff("counts", want, got) != false {
+		t.Error("Diff() of different values = true")
+	}
+
+	out := buf.String()
+	for _, s := range []string{"level=debug msg=\"counts: values equal\"", "level=error msg=\"counts: values differ\"", "[0]: want 0, got 1", "and 10 more", "differences=30"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output = %q, want %q", out, s)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package errorlogger
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultDoctorTimeout is the dial timeout used by Doctor
+// for network sinks when DoctorOptions.Timeout is zero.
+const DefaultDoctorTimeout = 2 * time.Second
+
+// minSaneTime is the earliest wall clock time Doctor
+// considers plausible. Clocks before it usually belong to
+// machines without an RTC that have not synced yet.
+var minSaneTime = time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// CheckStatus is the outcome of a single Doctor check.
+type CheckStatus int
+
+const (
+	CheckOK CheckStatus = iota
+	CheckWarn
+	CheckFail
+)
+
+func (s CheckStatus) String() string {
+	switch s {
+	case CheckOK:
+		return "ok"
+	case CheckWarn:
+		return "warn"
+	case CheckFail:
+		return "FAIL"
+	default:
+		return fmt.Sprintf("CheckStatus(%d)", int(s))
+	}
+}
+
+type (
+	// DoctorOptions describes the environment checked by
+	// Doctor.
+	DoctorOptions struct {
+		// Output is the writer the logger writes to. If it
+		// is an *os.File, it is checked for TTY and color
+		// support. The default is os.Stderr.
+		Output io.Writer
+
+		// Files are log files that must be writable. Files
+		// that do not exist yet are checked by creating a
+		// temporary file in their directory.
+		Files []string
+
+		// Addrs are network sinks that must be reachable,
+		// given as host:port (TCP) or network://host:port.
+		Addrs []string
+
+		// Timeout is the dial timeout for each of Addrs.
+		Timeout time.Duration
+
+		// Config are the configuration layers of the logger,
+		// lowest precedence first. If set, the report
+		// explains the source of every field; see Explain.
+		Config []ConfigLayer
+	}
+
+	// Check is the result of a single Doctor check.
+	Check struct {
+		Name   string
+		Status CheckStatus
+		Detail string
+	}
+
+	// DoctorReport is the list of checks run by Doctor.
+	DoctorReport struct {
+		Checks []Check
+
+		// Config explains the configuration layers given in
+		// DoctorOptions, if any.
+		Config Explanation
+	}
+)
+
+// Doctor checks the environment a logger runs in and
+// returns a report: TTY and color support of the output,
+// write permissions for log files, reachability of network
+// sinks, clock sanity, color related environment
+// variables, and, if configuration layers are given, the
+// settings that they override.
+//
+// It is the logging analog of "npm doctor"; see
+// cmd/eldoctor for a command line front end.
+func Doctor(opts DoctorOptions) DoctorReport {
+	if opts.Output == nil {
+		opts.Output = os.Stderr
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultDoctorTimeout
+	}
+
+	var r DoctorReport
+	r.add(checkTTY(opts.Output))
+	r.add(checkColorEnv())
+	r.add(checkClock(time.Now()))
+	for _, name := range opts.Files {
+		r.add(checkFile(name))
+	}
+	for _, addr := range opts.Addrs {
+		r.add(checkAddr(addr, opts.Timeout))
+	}
+	if len(opts.Config) > 0 {
+		r.Config = Explain(opts.Config[0], opts.Config[1:]...)
+		r.add(checkConfig(r.Config))
+	}
+	return r
+}
+
+func (r *DoctorReport) add(c Check) { r.Checks = append(r.Checks, c) }
+
+// OK reports whether no check failed. Warnings do not
+// cause OK to return false.
+func (r DoctorReport) OK() bool {
+	for _, c := range r.Checks {
+		if c.Status == CheckFail {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteTo writes a human readable report to w, followed by
+// the explanation of the configuration, if any.
+func (r DoctorReport) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+	for _, c := range r.Checks {
+		fmt.Fprintf(&sb, "[%-4s] %-12s %s\n", c.Status, c.Name, c.Detail)
+	}
+	if len(r.Config) > 0 {
+		sb.WriteString("\nconfiguration:\n")
+		r.Config.WriteTo(&sb)
+	}
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// checkConfig warns about configuration settings that were
+// overridden by a source of higher precedence, the usual
+// reason a setting seems to be ignored.
+func checkConfig(x Explanation) Check {
+	var over []string
+	for _, fx := range x {
+		if len(fx.Overridden) > 0 {
+			over = append(over, fx.Key)
+		}
+	}
+	if len(over) == 0 {
+		return Check{Name: "config", Status: CheckOK, Detail: "no overridden settings"}
+	}
+	return Check{Name: "config", Status: CheckWarn, Detail: "overridden settings: " + strings.Join(over, ", ")}
+}
+
+func checkTTY(w io.Writer) Check {
+	c := Check{Name: "tty"}
+	f, ok := w.(*os.File)
+	if !ok {
+		c.Detail = fmt.Sprintf("output is %T, not a terminal; colors disabled", w)
+		return c
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		c.Status = CheckFail
+		c.Detail = fmt.Sprintf("cannot stat output %s: %v", f.Name(), err)
+		return c
+	}
+
+	if fi.Mode()&os.ModeCharDevice == 0 {
+		c.Detail = fmt.Sprintf("output %s is not a terminal; colors disabled", f.Name())
+		return c
+	}
+
+	if term := os.Getenv("TERM"); term == "" || term == "dumb" {
+		c.Status = CheckWarn
+		c.Detail = fmt.Sprintf("output %s is a terminal but TERM=%q; colors may not render", f.Name(), term)
+		return c
+	}
+	c.Detail = fmt.Sprintf("output %s is a color capable terminal", f.Name())
+	return c
+}
+
+func checkColorEnv() Check {
+	c := Check{Name: "color env"}
+	var set []string
+	for _, key := range []string{"NO_COLOR", "CLICOLOR", "CLICOLOR_FORCE"} {
+		if v, ok := os.LookupEnv(key); ok {
+			set = append(set, key+"="+v)
+		}
+	}
+
+	_, noColor := os.LookupEnv("NO_COLOR")
+	force := os.Getenv("CLICOLOR_FORCE")
+	switch {
+	case len(set) == 0:
+		c.Detail = "NO_COLOR, CLICOLOR, and CLICOLOR_FORCE are not set"
+		return c
+	case noColor && force != "" && force != "0":
+		c.Status = CheckWarn
+		c.Detail = "conflicting settings: " + strings.Join(set, " ")
+		return c
+	}
+	c.Detail = strings.Join(set, " ")
+	return c
+}
+
+func checkClock(now time.Time) Check {
+	c := Check{Name: "clock"}
+	if now.Before(minSaneTime) {
+		c.Status = CheckFail
+		c.Detail = fmt.Sprintf("wall clock reads %s; timestamps will be wrong", now.Format(time.RFC3339))
+		return c
+	}
+	zone, _ := now.Zone()
+	c.Detail = fmt.Sprintf("%s (zone %s)", now.Format(time.RFC3339), zone)
+	return c
+}
+
+func checkFile(name string) Check {
+	c := Check{Name: "file"}
+
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND, 0)
+	if err == nil {
+		f.Close()
+		c.Detail = name + " is writable"
+		return c
+	}
+	if !os.IsNotExist(err) {
+		c.Status = CheckFail
+		c.Detail = err.Error()
+		return c
+	}
+
+	// The file does not exist yet; it must be creatable.
+	dir := filepath.Dir(name)
+	tmp, err := os.CreateTemp(dir, ".eldoctor-*")
+	if err != nil {
+		c.Status = CheckFail
+		c.Detail = fmt.Sprintf("%s does not exist and cannot be created: %v", name, err)
+		return c
+	}
+	tmp.Close()
+	os.Remove(tmp.Name())
+	c.Detail = name + " does not exist yet; directory is writable"
+	return c
+}
+
+func checkAddr(addr string, timeout time.Duration) Check {
+	c := Check{Name: "sink"}
+	network := "tcp"
+	if i := strings.Index(addr, "://"); i >= 0 {
+		network, addr = addr[:i], addr[i+3:]
+	}
+
+	start := time.Now()
+	conn, err := net.DialTimeout(network, addr, timeout)
+	if err != nil {
+		c.Status = CheckFail
+		c.Detail = err.Error()
+		return c
+	}
+	conn.Close()
+	c.Detail = fmt.Sprintf("%s://%s reachable in %v", network, addr, time.Since(start).Round(time.Millisecond))
+	return c
+}
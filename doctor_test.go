@@ -0,0 +1,127 @@
+package errorlogger
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDoctor(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.log")
+	if err := os.WriteFile(existing, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	r := Doctor(DoctorOptions{
+		Output: &bytes.Buffer{},
+		Files: []string{
+			existing,
+			filepath.Join(dir, "new.log"),
+			filepath.Join(dir, "missing", "dir.log"),
+		},
+		Addrs:   []string{ln.Addr().String(), "tcp://" + closedAddr},
+		Timeout: time.Second,
+	})
+
+	want := map[string][]CheckStatus{
+		"tty":  {CheckOK},
+		"file": {CheckOK, CheckOK, CheckFail},
+		"sink": {CheckOK, CheckFail},
+	}
+	got := map[string][]CheckStatus{}
+	for _, c := range r.Checks {
+		if _, ok := want[c.Name]; ok {
+			got[c.Name] = append(got[c.Name], c.Status)
+		}
+	}
+	for name, statuses := range want {
+		if len(got[name]) != len(statuses) {
+			t.Fatalf("Doctor() %s checks = %v, want %v", name, got[name], statuses)
+		}
+		for i := range statuses {
+			if got[name][i] != statuses[i] {
+				t.Errorf("Doctor() %s check %d = %v, want %v", name, i, got[name][i], statuses[i])
+			}
+		}
+	}
+
+	if r.OK() {
+		t.Error("DoctorReport.OK() = true with failed checks")
+	}
+
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(r.Checks) {
+		t.Errorf("DoctorReport.WriteTo() wrote %d lines, want %d", lines, len(r.Checks))
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "new.log")); !os.IsNotExist(err) {
+		t.Error("Doctor() created a log file that did not exist")
+	}
+}
+
+func TestCheckClock(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want CheckStatus
+	}{
+		{"now", time.Now(), CheckOK},
+		{"epoch", time.Unix(0, 0), CheckFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkClock(tt.now).Status; got != tt.want {
+				t.Errorf("checkClock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckColorEnv(t *testing.T) {
+	tests := []struct {
+		name           string
+		noColor, force string
+		want           CheckStatus
+	}{
+		{"unset", "", "", CheckOK},
+		{"no color", "1", "", CheckOK},
+		{"conflict", "1", "1", CheckWarn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"NO_COLOR", "CLICOLOR", "CLICOLOR_FORCE"} {
+				t.Setenv(key, "") // restored after the test
+				os.Unsetenv(key)
+			}
+			if tt.noColor != "" {
+				t.Setenv("NO_COLOR", tt.noColor)
+			}
+			if tt.force != "" {
+				t.Setenv("CLICOLOR_FORCE", tt.force)
+			}
+			if got := checkColorEnv().Status; got != tt.want {
+				t.Errorf("checkColorEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package errorlogger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DumpKey is the field that holds the rendering of a value
+// logged by Dump.
+const DumpKey = "dump"
+
+// Dump logs v at DebugLevel, rendered with the field names
+// of structs, the keys of maps in sorted order, and the
+// elements of slices, in the DumpKey field of an entry with
+// label as the message. It replaces throwaway uses of
+// fmt.Printf("%#v") or spew while debugging:
+//  log.Dump("request", req)
+//
+// Values that fit in Options().Width are rendered on one
+// line, others over multiple lines indented by
+// Options().Indent. Nesting deeper than Options().MaxDepth
+// is elided and cycles are rendered as <cycle>.
+func (e *errorLogger) Dump(label string, v interface{}) {
+	if !e.IsLevelEnabled(DebugLevel) {
+		return
+	}
+	e.WithField(DumpKey, DumpString(v, e.Options())).Debug(label)
+}
+
+// DumpString returns the rendering of v by Dump with o.
+func DumpString(v interface{}, o Options) string {
+	o = o.withDefaults()
+	d := &dumper{o: o, path: make(map[uintptr]bool)}
+	return d.layout(d.node(reflect.ValueOf(v), 0), 0, 0)
+}
+
+type (
+	// dumpNode is a rendered value: either text, or a
+	// composite value with children between open and close.
+	dumpNode struct {
+		text        string
+		open, close string
+		children    []dumpChild
+	}
+
+	// dumpChild is an element of a composite value, with the
+	// key prefix of struct fields and map entries.
+	dumpChild struct {
+		key  string
+		node *dumpNode
+	}
+
+	// dumper renders values, tracking the pointers on the
+	// path to the current value to detect cycles.
+	dumper struct {
+		o    Options
+		path map[uintptr]bool
+	}
+)
+
+func (d *dumper) node(v reflect.Value, depth int) *dumpNode {
+	if !v.IsValid() {
+		return &dumpNode{text: "nil"}
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return &dumpNode{text: "nil"}
+		}
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return &dumpNode{text: fmt.Sprintf("%s(%q)", v.Type(), v.Bytes())}
+		}
+		p := v.Pointer()
+		if d.path[p] {
+			return &dumpNode{text: fmt.Sprintf("<cycle %s>", v.Type())}
+		}
+		d.path[p] = true
+		defer delete(d.path, p)
+	case reflect.Interface:
+		if v.IsNil() {
+			return &dumpNode{text: "nil"}
+		}
+		return d.node(v.Elem(), depth)
+	}
+
+	if v.CanInterface() {
+		switch v.Interface().(type) {
+		case error, fmt.Stringer:
+			return &dumpNode{text: fmt.Sprintf("%s(%q)", v.Type(), safeSprint(v.Interface()))}
+		}
+	}
+
+	if depth >= d.o.MaxDepth && !scalarKind(v.Kind()) {
+		return &dumpNode{text: fmt.Sprintf("%s{…}", v.Type())}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		n := d.node(v.Elem(), depth+1)
+		if n.text != "" {
+			return &dumpNode{text: "&" + n.text}
+		}
+		n.open = "&" + n.open
+		return n
+	case reflect.Struct:
+		n := &dumpNode{open: v.Type().String() + "{", close: "}"}
+		for i := 0; i < v.NumField(); i++ {
+			n.children = append(n.children, dumpChild{v.Type().Field(i).Name + ": ", d.node(v.Field(i), depth+1)})
+		}
+		return n
+	case reflect.Map:
+		n := &dumpNode{open: v.Type().String() + "{", close: "}"}
+		keys := v.MapKeys()
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = d.node(k, depth+1).flat()
+		}
+		order := make([]int, len(keys))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(i, j int) bool { return names[order[i]] < names[order[j]] })
+		for _, i := range order {
+			n.children = append(n.children, dumpChild{names[i] + ": ", d.node(v.MapIndex(keys[i]), depth+1)})
+		}
+		return n
+	case reflect.Slice, reflect.Array:
+		n := &dumpNode{open: v.Type().String() + "{", close: "}"}
+		for i := 0; i < v.Len(); i++ {
+			n.children = append(n.children, dumpChild{"", d.node(v.Index(i), depth+1)})
+		}
+		return n
+	case reflect.String:
+		return &dumpNode{text: fmt.Sprintf("%q", v.String())}
+	}
+	return &dumpNode{text: fmt.Sprintf("%v", v)}
+}
+
+// flat returns n on a single line.
+func (n *dumpNode) flat() string {
+	if n.text != "" {
+		return n.text
+	}
+	parts := make([]string, len(n.children))
+	for i, c := range n.children {
+		parts[i] = c.key + c.node.flat()
+	}
+	return n.open + strings.Join(parts, ", ") + n.close
+}
+
+// layout renders n at the indentation level after a key of
+// width keyLen, on one line if it fits in the width and over
+// multiple lines otherwise.
+func (d *dumper) layout(n *dumpNode, level, keyLen int) string {
+	indent := strings.Repeat(d.o.Indent, level)
+	if s := n.flat(); n.text != "" || len(n.children) == 0 || len(indent)+keyLen+len(s) <= d.o.Width {
+		return s
+	}
+	var b strings.Builder
+	b.WriteString(n.open + "\n")
+	for _, c := range n.children {
+		b.WriteString(indent + d.o.Indent + c.key + d.layout(c.node, level+1, len(c.key)) + ",\n")
+	}
+	b.WriteString(indent + n.close)
+	return b.String()
+}
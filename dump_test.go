@@ -0,0 +1,84 @@
+package errorlogger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDumpString(t *testing.T) {
+	order := diffOrder{
+		ID:    1,
+		Items: []diffItem{{"pen", 2, "a"}},
+		Tags:  map[string]bool{"sale": true, "new": false},
+	}
+	cyclic := order
+	cyclic.Next = &cyclic
+
+	type deep struct{ Next *deep }
+	chain := &deep{}
+	for i := 0; i < 5; i++ {
+		chain = &deep{chain}
+	}
+
+	tests := []struct {
+		name string
+		v    interface{}
+		o    Options
+		want string
+	}{
+		{"nil", nil, Options{}, "nil"},
+		{"string", "a\tb", Options{}, `"a\tb"`},
+		{"error", errors.New("boom"), Options{}, `*errors.errorString("boom")`},
+		{"bytes", []byte("hi"), Options{}, `[]uint8("hi")`},
+		{"nil slice", []int(nil), Options{}, "nil"},
+		{"compact", order, Options{Width: 200}, `errorlogger.diffOrder{ID: 1, Items: []errorlogger.diffItem{errorlogger.diffItem{Name: "pen", Price: 2, note: "a"}}, Tags: map[string]bool{"new": false, "sale": true}, Next: nil}`},
+		{"multi-line", order, Options{Width: 40, Indent: "\t"}, "errorlogger.diffOrder{\n" +
+			"\tID: 1,\n" +
+			"\tItems: []errorlogger.diffItem{\n" +
+			"\t\terrorlogger.diffItem{\n" +
+			"\t\t\tName: \"pen\",\n" +
+			"\t\t\tPrice: 2,\n" +
+			"\t\t\tnote: \"a\",\n" +
+			"\t\t},\n" +
+			"\t},\n" +
+			"\tTags: map[string]bool{\n" +
+			"\t\t\"new\": false,\n" +
+			"\t\t\"sale\": true,\n" +
+			"\t},\n" +
+			"\tNext: nil,\n" +
+			"}"},
+		{"depth", chain, Options{MaxDepth: 4}, "&errorlogger.deep{Next: &errorlogger.deep{Next: *errorlogger.deep{…}}}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DumpString(tt.v, tt.o); got != tt.want {
+				t.Errorf("DumpString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := DumpString(&cyclic, Options{Width: 1000}); !strings.Contains(got, "Next: <cycle *errorlogger.diffOrder>") {
+		t.Errorf("DumpString() of a cyclic value = %q", got)
+	}
+}
+
+func TestErrorLogger_Dump(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.Dump("order", 42)
+	if buf.Len() != 0 {
+		t.Errorf("Dump() above DebugLevel wrote %q", buf.String())
+	}
+
+	e.SetLevel(DebugLevel)
+	if err := e.SetOptions(Options{Width: 20}); err != nil {
+		t.Fatal(err)
+	}
+	if got := e.Options(); got.Width != 20 || got.Indent != DefaultOptions.Indent {
+		t.Errorf("Options() = %+v", got)
+	}
+	e.Dump("order", []int{1, 2})
+	if out := buf.String(); !strings.Contains(out, `level=debug msg=order dump="[]int{1, 2}"`) {
+		t.Errorf("output = %q", out)
+	}
+}
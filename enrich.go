@@ -0,0 +1,67 @@
+package errorlogger
+
+import (
+	"context"
+	"errors"
+)
+
+// FieldsError is implemented by errors that carry their own
+// structured fields, such as *HTTPError. Err logs the fields
+// of the first FieldsError in the chain of an error.
+type FieldsError interface {
+	error
+	Fields() Fields
+}
+
+// Enricher adds structured fields describing err to fields.
+// Enrichers are run by Err for every logged error, in the
+// order they were added.
+//
+// Enrichers are opt-in:
+//  log.AddEnricher(errorlogger.KindEnricher)
+type Enricher = func(err error, fields Fields)
+
+// AddEnricher adds fn to the enrichers run by Err.
+func (e *errorLogger) AddEnricher(fn Enricher) {
+	if fn == nil {
+		return
+	}
+	e.enrichers = append(e.enrichers, fn)
+}
+
+// errFields returns the fields carried by err and produced
+// by the enrichers for err, or nil if there are none.
+func (e *errorLogger) errFields(err error) Fields {
+	var fe FieldsError
+	hasFields := errors.As(err, &fe)
+	if len(e.enrichers) == 0 && !hasFields {
+		return nil
+	}
+
+	fields := make(Fields, len(e.enrichers))
+	if hasFields {
+		for k, v := range fe.Fields() {
+			fields[k] = v
+		}
+	}
+	for _, fn := range e.enrichers {
+		fn(err, fields)
+	}
+	return fields
+}
+
+// logErr logs err with fields. Without fields or a context,
+// the logger function set by SetLoggerFunc is used.
+// Otherwise, err is logged at ErrorLevel on an entry
+// carrying them.
+func (e *errorLogger) logErr(ctx context.Context, err error, fields Fields) {
+	if ctx != nil {
+		e.WithContext(ctx).WithFields(fields).Error(err)
+		return
+	}
+	if len(fields) == 0 {
+		e.logFunc(err)
+		return
+	}
+	e.WithFields(fields).Error(err)
+}
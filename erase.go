@@ -0,0 +1,243 @@
+package errorlogger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErasureMode selects how entries about a subject are
+// erased by EraseJSON.
+type ErasureMode int
+
+const (
+	// EraseEntries removes matching entries entirely.
+	EraseEntries ErasureMode = iota
+
+	// HashFields replaces the values of the selected fields
+	// in matching entries with their HMAC-SHA256 keyed with
+	// the Salt, which keeps entries countable and
+	// correlatable without identifying the subject to anyone
+	// who does not hold the Salt. This is pseudonymization:
+	// the Salt must be kept secret, and destroyed if the
+	// values must never be confirmed again.
+	HashFields
+)
+
+func (m ErasureMode) String() string {
+	switch m {
+	case EraseEntries:
+		return "remove"
+	case HashFields:
+		return "hash"
+	default:
+		return fmt.Sprintf("ErasureMode(%d)", int(m))
+	}
+}
+
+type (
+	// ErasureOptions selects the entries of a subject and
+	// how they are erased.
+	ErasureOptions struct {
+		// Field and Value identify the subject, e.g.
+		// user_id=42. Values are compared as strings.
+		Field string
+		Value string
+
+		Mode ErasureMode
+
+		// Fields are the fields hashed in matching entries
+		// with HashFields. The default is Field.
+		Fields []string
+
+		// Salt is the key of the HMAC of the values hashed
+		// with HashFields and of the HMAC that identifies the
+		// subject in the manifest. It should be a secret of
+		// at least 32 random bytes, and is required with
+		// HashFields.
+		Salt string
+	}
+
+	// Redaction records the erasure of one entry.
+	Redaction struct {
+		Line   int      `json:"line"`
+		Action string   `json:"action"`
+		Fields []string `json:"fields,omitempty"`
+	}
+
+	// ErasureManifest is the audit record of an erasure. It
+	// identifies the subject by the HMAC-SHA256 of its value
+	// keyed with the Salt of the erasure, so that the subject
+	// can be confirmed by whoever holds the Salt. The hash is
+	// pseudonymous: a subject value such as a user ID is
+	// easily guessed, so without a secret Salt the manifest
+	// identifies the subject and must be kept as personal
+	// data.
+	ErasureManifest struct {
+		File        string      `json:"file,omitempty"`
+		Field       string      `json:"field"`
+		SubjectHash string      `json:"subject_hmac_sha256"`
+		Mode        string      `json:"mode"`
+		Time        time.Time   `json:"time"`
+		Lines       int         `json:"lines"`
+		Skipped     int         `json:"skipped"` // lines that are not JSON objects
+		Redactions  []Redaction `json:"redactions"`
+	}
+)
+
+// EraseJSON copies the JSON lines log read from r to w,
+// erasing the entries that match the subject of opts, and
+// returns an audit manifest of what was redacted. This
+// supports right to erasure requests against file based
+// logs written by the JSON formatter without pretty
+// printing.
+//
+// Lines that are not JSON objects are copied unchanged and
+// counted as skipped. An error wrapping ErrInvalid is
+// returned if opts has no Field, or no Salt with HashFields:
+// an unkeyed hash of a guessable value such as a user ID is
+// easily reversed.
+func EraseJSON(r io.Reader, w io.Writer, opts ErasureOptions) (ErasureManifest, error) {
+	if opts.Field == "" {
+		return ErasureManifest{}, fmt.Errorf("erasure needs a subject field: %w", ErrInvalid)
+	}
+	if opts.Mode == HashFields && opts.Salt == "" {
+		return ErasureManifest{}, fmt.Errorf("hashing erasure needs a secret salt: %w", ErrInvalid)
+	}
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = []string{opts.Field}
+	}
+
+	mac := hmac.New(sha256.New, []byte(opts.Salt))
+	mac.Write([]byte(opts.Value))
+	m := ErasureManifest{
+		Field:       opts.Field,
+		SubjectHash: hex.EncodeToString(mac.Sum(nil)),
+		Mode:        opts.Mode.String(),
+		Time:        time.Now().UTC(),
+		Redactions:  []Redaction{},
+	}
+
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			m.Lines++
+			out, red, skipped := eraseLine(line, opts, fields)
+			if skipped {
+				m.Skipped++
+			}
+			if red != nil {
+				red.Line = m.Lines
+				m.Redactions = append(m.Redactions, *red)
+			}
+			if _, werr := bw.Write(out); werr != nil {
+				return m, werr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return m, err
+		}
+	}
+	return m, bw.Flush()
+}
+
+// eraseLine returns the erased form of one line, the
+// redaction made, if any, and whether the line was skipped.
+func eraseLine(line []byte, opts ErasureOptions, fields []string) ([]byte, *Redaction, bool) {
+	var entry map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&entry); err != nil || entry == nil {
+		return line, nil, true
+	}
+
+	v, ok := entry[opts.Field]
+	if !ok || fmt.Sprint(v) != opts.Value {
+		return line, nil, false
+	}
+
+	if opts.Mode == EraseEntries {
+		return nil, &Redaction{Action: "removed"}, false
+	}
+
+	red := &Redaction{Action: "hashed"}
+	for _, f := range fields {
+		if fv, ok := entry[f]; ok {
+			mac := hmac.New(sha256.New, []byte(opts.Salt))
+			mac.Write([]byte(fmt.Sprint(fv)))
+			entry[f] = "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil))
+			red.Fields = append(red.Fields, f)
+		}
+	}
+	out, err := json.Marshal(entry)
+	if err != nil {
+		// Cannot happen for decoded JSON; remove rather than
+		// leave the subject in place.
+		return nil, &Redaction{Action: "removed"}, false
+	}
+	return append(out, '\n'), red, false
+}
+
+// EraseFile erases the entries of a subject from the JSON
+// lines log file at path in place, as EraseJSON does. The
+// file is replaced atomically and keeps its permissions.
+//
+// The file must not be written to during the erasure: a
+// logger that has it open keeps appending to the replaced
+// file, and those lines are lost. Run it on rotated files,
+// or stop the writers first. If the file changes during the
+// erasure, it is left in place and an error wrapping
+// ErrUnsupported is returned.
+func EraseFile(path string, opts ErasureOptions) (ErasureManifest, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return ErasureManifest{}, err
+	}
+	defer in.Close()
+	fi, err := in.Stat()
+	if err != nil {
+		return ErasureManifest{}, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".erase-*")
+	if err != nil {
+		return ErasureManifest{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	m, err := EraseJSON(in, tmp, opts)
+	m.File = path
+	if err != nil {
+		tmp.Close()
+		return m, err
+	}
+	if err := tmp.Chmod(fi.Mode().Perm()); err != nil {
+		tmp.Close()
+		return m, err
+	}
+	if err := tmp.Close(); err != nil {
+		return m, err
+	}
+	now, err := os.Stat(path)
+	if err != nil {
+		return m, err
+	}
+	if now.Size() != fi.Size() || !now.ModTime().Equal(fi.ModTime()) || !os.SameFile(now, fi) {
+		return m, fmt.Errorf("erase %s: the file was written to during the erasure: %w", path, ErrUnsupported)
+	}
+	return m, os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,140 @@
+package errorlogger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const erasureLog = `{"level":"info","msg":"login","user_id":42,"email":"a@example.com"}
+{"level":"info","msg":"login","user_id":7}
+not json
+{"level":"error","msg":"payment failed","user_id":"42","email":"a@example.com"}
+`
+
+func TestEraseJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        ErasureOptions
+		wantLines   int
+		wantActions []string
+		notWant     []string
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name:        "remove",
+			opts:        ErasureOptions{Field: "user_id", Value: "42"},
+			wantLines:   2,
+			wantActions: []string{"removed", "removed"},
+			notWant:     []string{"a@example.com", "payment failed"},
+			want:        []string{`"user_id":7`, "not json"},
+		},
+		{
+			name:        "hash",
+			opts:        ErasureOptions{Field: "user_id", Value: "42", Mode: HashFields, Fields: []string{"user_id", "email"}, Salt: "secret"},
+			wantLines:   4,
+			wantActions: []string{"hashed", "hashed"},
+			notWant:     []string{"a@example.com", `"user_id":42`, `"user_id":"42"`},
+			want:        []string{"payment failed", `"email":"hmac-sha256:`, `"user_id":7`},
+		},
+		{
+			name:    "no field",
+			opts:    ErasureOptions{Value: "42"},
+			wantErr: true,
+		},
+		{
+			name:    "hash without salt",
+			opts:    ErasureOptions{Field: "user_id", Value: "42", Mode: HashFields},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			m, err := EraseJSON(strings.NewReader(erasureLog), &out, tt.opts)
+			if (err != nil) != tt.wantErr || err != nil && !errors.Is(err, ErrInvalid) {
+				t.Fatalf("EraseJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := strings.Count(out.String(), "\n"); got != tt.wantLines {
+				t.Errorf("EraseJSON() wrote %d lines, want %d:\n%s", got, tt.wantLines, out.String())
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(out.String(), s) {
+					t.Errorf("EraseJSON() output contains %q", s)
+				}
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(out.String(), s) {
+					t.Errorf("EraseJSON() output missing %q", s)
+				}
+			}
+
+			if m.Lines != 4 || m.Skipped != 1 || len(m.Redactions) != len(tt.wantActions) {
+				t.Fatalf("EraseJSON() manifest = %+v", m)
+			}
+			for i, r := range m.Redactions {
+				if r.Action != tt.wantActions[i] || r.Line != []int{1, 4}[i] {
+					t.Errorf("Redactions[%d] = %+v, want %s at line %d", i, r, tt.wantActions[i], []int{1, 4}[i])
+				}
+			}
+			if len(m.SubjectHash) != 64 {
+				t.Errorf("manifest subject = %q, want an HMAC-SHA256", m.SubjectHash)
+			}
+		})
+	}
+}
+
+func TestEraseJSON_subjectHash(t *testing.T) {
+	hash := func(salt string) string {
+		m, err := EraseJSON(strings.NewReader(erasureLog), io.Discard, ErasureOptions{Field: "user_id", Value: "7", Salt: salt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m.SubjectHash
+	}
+	// echo -n 7 | sha256sum
+	if got := hash(""); got == "7902699be42c8a8e46fbbb4501726517e86b22c56a189f7625a6da49081b2451" {
+		t.Errorf("SubjectHash = %q, want an HMAC rather than the plain SHA-256", got)
+	}
+	if hash("k1") == hash("k2") || hash("k1") != hash("k1") {
+		t.Error("SubjectHash does not depend on the salt alone")
+	}
+}
+
+func TestEraseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte(erasureLog), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := EraseFile(path, ErasureOptions{Field: "user_id", Value: "7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.File != path || len(m.Redactions) != 1 || m.Redactions[0].Line != 2 {
+		t.Errorf("EraseFile() manifest = %+v", m)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), `"user_id":7`) || strings.Count(string(b), "\n") != 3 {
+		t.Errorf("EraseFile() left %q", b)
+	}
+	if fi, _ := os.Stat(path); fi.Mode().Perm() != 0o600 {
+		t.Errorf("EraseFile() mode = %v, want 0600", fi.Mode().Perm())
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("EraseFile() left %d files, want 1", len(entries))
+	}
+}
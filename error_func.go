@@ -5,18 +5,21 @@
 package errorlogger
 
 import (
-	"github.com/pkg/errors"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
 )
 
 // Disable disables logging and sets a no-op function for
 // Err() to prevent slowdowns while logging is disabled.
 func (e *errorLogger) Disable() {
-	e.errFunc = e.noErr
+	e.setEnabled(false, nil)
 }
 
 // Enable enables logging and restores the Err() logging functionality.
 func (e *errorLogger) Enable() {
-	e.errFunc = e.yesErr
+	e.setEnabled(true, nil)
 }
 
 // Err logs an error to the provided logger, if it is enabled,
@@ -28,6 +31,49 @@ func (e *errorLogger) Err(err error) error {
 	return e.errFunc(err)
 }
 
+// errFunc calls the ErrorFunc selected by Enable or Disable,
+// which is swapped atomically so that logging can be
+// toggled while other goroutines call Err.
+func (e *errorLogger) errFunc(err error) error {
+	if fn, ok := e.errFn.Load().(ErrorFunc); ok {
+		return fn(err)
+	}
+	return e.yesErr(err)
+}
+
+// enabled reports whether logging is enabled.
+func (e *errorLogger) enabled() bool {
+	return atomic.LoadUint32(&e.disabled) == 0
+}
+
+// Errf formats an error with fmt.Errorf, logs it like Err,
+// and returns it, replacing
+//  return log.Err(fmt.Errorf("open %s: %w", name, err))
+// with
+//  return log.Errf("open %s: %w", name, err)
+//
+// The error is wrapped as set by SetErrorWrap, and only
+// formatted, not logged, while logging is disabled.
+func (e *errorLogger) Errf(format string, args ...interface{}) error {
+	return e.Err(fmt.Errorf(format, args...))
+}
+
+// ErrWithFields logs err like Err, with fields added to its
+// entry, and returns the error unchanged, replacing
+//  log.WithFields(fields).Error(err)
+//  return err
+// with
+//  return log.ErrWithFields(err, Fields{"user": id})
+func (e *errorLogger) ErrWithFields(err error, fields Fields) error {
+	if err == nil {
+		return nil
+	}
+	if len(fields) == 0 || !e.enabled() {
+		return e.Err(err)
+	}
+	return e.errWithFields(err, fields)
+}
+
 // noErr is a no-op errorFunc for disabling logging without
 // constant repetitive flag checks or other hacks.
 // https://en.wikipedia.org/wiki/NOP_(code)
@@ -45,10 +91,58 @@ func (e *errorLogger) yesErr(err error) error {
 	if err == nil {
 		return nil
 	}
+	return e.errWithFields(err, nil)
+}
+
+// errWithFields logs and wraps an error like yesErr, adding
+// extra to the fields of the entry.
+func (e *errorLogger) errWithFields(err error, extra Fields) error {
+	return e.errWithContext(nil, err, extra)
+}
+
+// errWithContext is errWithFields with ctx, if not nil, set
+// as the context of the entry.
+func (e *errorLogger) errWithContext(ctx context.Context, err error, extra Fields) error {
+	start := time.Now()
 	if e.wrap != nil {
-		err = errors.Wrap(err, e.wrap.Error())
+		orig := err
+		err = wrapWith(err, e.wrap)
+		if e.development() {
+			e.auditWrap(orig, err)
+		}
+	}
+	fields := e.errFields(err)
+	if len(extra) > 0 {
+		if fields == nil {
+			fields = make(Fields, len(extra))
+		}
+		for k, v := range extra {
+			fields[k] = v
+		}
+	}
+	fp, ok := e.dedupErr(err, fields)
+	if !ok {
+		return err
+	}
+	if fp != "" {
+		if fields == nil {
+			fields = make(Fields, 1)
+		}
+		fields[ErrFingerprintKey] = fp
+	}
+	var logged error = err
+	if stack := e.captureStack(); stack != "" {
+		if StackFormat(atomic.LoadInt32(&e.stackFormat)) == StackText {
+			logged = stackMessage{error: err, stack: stack}
+		} else {
+			if fields == nil {
+				fields = make(Fields, 1)
+			}
+			fields[StackKey] = stack
+		}
 	}
-	e.logFunc(err)
+	e.logErr(ctx, logged, fields)
+	e.stats.observeErr(time.Since(start))
 
 	return err
 }
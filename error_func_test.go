@@ -5,7 +5,10 @@
 package errorlogger
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -17,7 +20,7 @@ var (
 	nopWriterlogger = NewWithOptions(true, "", nil, nil, nil)
 	lenWriterlogger = NewWithOptions(true, "", nil, nil, nil)
 	logrusonly      = New()
-	nillogger       = &errorLogger{nil, "", nil, nil, nil}
+	nillogger       = &errorLogger{wrap: nil, msg: "", logFunc: nil, Logger: nil}
 
 	fakeOuter error
 )
@@ -170,6 +173,108 @@ func Test_errorLogger_noErr_yesErr(t *testing.T) {
 	}
 }
 
+func Test_errorLogger_Errf(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		wrap     error
+		wantMsg  string
+		wantLogs bool
+	}{
+		{"enabled", true, nil, "open x: fake", true},
+		{"wrapped", true, fakeSysCallError, "fake syscall error: fake syscall error: open x: fake", true},
+		{"disabled", false, nil, "open x: fake", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			e.SetErrorWrap(tt.wrap)
+			if !tt.enabled {
+				e.Disable()
+			}
+
+			err := e.Errf("open %s: %w", "x", errFake)
+			if err == nil || err.Error() != tt.wantMsg {
+				t.Fatalf("Errf() = %v, want %q", err, tt.wantMsg)
+			}
+			if !errors.Is(err, errFake) {
+				t.Error("Errf() does not wrap the error argument")
+			}
+			if got := strings.Contains(buf.String(), "open x: fake"); got != tt.wantLogs {
+				t.Errorf("Errf() logged = %v, want %v: %q", got, tt.wantLogs, buf.String())
+			}
+		})
+	}
+}
+
+func Test_errorLogger_ErrWithFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   error
+		fields  Fields
+		enabled bool
+		want    string
+	}{
+		{"fields", errFake, Fields{"user": 42}, true, "level=error msg=fake user=42"},
+		{"no fields", errFake, nil, true, "level=error msg=fake\n"},
+		{"disabled", errFake, Fields{"user": 42}, false, ""},
+		{"nil", nil, Fields{"user": 42}, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			if !tt.enabled {
+				e.Disable()
+			}
+			if got := e.ErrWithFields(tt.input, tt.fields); got != tt.input {
+				t.Errorf("ErrWithFields() = %v, want %v", got, tt.input)
+			}
+			if out := buf.String(); !strings.Contains(out, tt.want) || (tt.want == "" && out != "") {
+				t.Errorf("output = %q, want %q", out, tt.want)
+			}
+		})
+	}
+}
+
+func Test_errorLogger_Enable_concurrent(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	e.SetOutput(Discard)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := e.Err(errFake); err != errFake {
+					t.Errorf("Err() = %v, want %v", err, errFake)
+					return
+				}
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if (i+j)%2 == 0 {
+					e.Disable()
+				} else {
+					e.Enable()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	e.Disable()
+	if e.enabled() || e.Config().Enabled {
+		t.Error("logger enabled after Disable()")
+	}
+	e.Enable()
+	if !e.enabled() {
+		t.Error("logger disabled after Enable()")
+	}
+}
+
 func Test_nopWriter_Write(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -135,6 +135,12 @@
 //
 // This may be done at any time and as often as desired.
 //
+// Err(nil), and Err while logging is disabled, are guaranteed
+// to cost single-digit nanoseconds and no allocations, with
+// any formatter and hooks configured. Applications can pin
+// this for their own setup with testutil.PerfContract:
+//  testutil.PerfContract(t, log)
+//
 // - SetLoggerFunc allows setting of a custom logger function.
 // The default is log.Error(), which is compatible with
 // the standard library log package and logrus.
@@ -153,7 +159,15 @@
 package errorlogger
 
 import (
-	"github.com/pkg/errors"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/sirupsen/logrus"
 )
 
@@ -181,11 +195,6 @@ var (
 	// of level ErrorLevel or higher.
 	defaultLogFunc LoggerFunc = defaultlogger.Error
 
-	// defaultErrWrap is the default error used to wrap
-	// errors processed with Err. A <nil> value disables
-	// error wrapping.
-	defaultErrWrap error = nil
-
 	// Discard is a Writer on which all Write calls succeed without doing anything.
 	DiscardWriter Writer = Discard
 )
@@ -196,6 +205,8 @@ var (
 	ErrExist      = errors.New("file already exists")
 	ErrNotExist   = errors.New("file does not exist")
 	ErrClosed     = errors.New("file already closed")
+
+	ErrUnsupported = errors.New("operation not supported")
 )
 
 type (
@@ -236,6 +247,13 @@ type (
 		// and returns the error unchanged.
 		Err(err error) error
 
+		// Errf formats, logs, and returns an error in one call.
+		Errf(format string, args ...interface{}) error
+
+		// ErrWithFields logs an error like Err, with fields
+		// added to its entry.
+		ErrWithFields(err error, fields Fields) error
+
 		// SetLoggerFunc allows setting of the logger function.
 		// The default is log.Error(), which is compatible with
 		// the standard library log package and logrus.
@@ -251,22 +269,287 @@ type (
 		// logger.
 		SetCustomMessage(msg string)
 
+		// SetOverride boosts the verbosity of entries that carry
+		// the field key with the given value to lvl for the
+		// duration ttl. A ttl of zero or less never expires.
+		SetOverride(key, value string, lvl Level, ttl time.Duration)
+
+		// ClearOverride removes the override for the field key
+		// with the given value, if any.
+		ClearOverride(key, value string)
+
+		// Stats returns the current self-overhead statistics
+		// of the logger.
+		Stats() Stats
+
+		// LogPanic converts the panic value v into a *PanicError,
+		// logs it, and returns it.
+		LogPanic(v interface{}) error
+
+		// Recover recovers from a panic, if any, and logs it
+		// with LogPanic. Use it with defer.
+		Recover()
+
+		// Assert logs an invariant violation with a stack trace
+		// if cond is false. With a Development preset, it panics.
+		Assert(cond bool, msg string, fields ...Fields)
+
+		// Never records that unreachable code was reached. It
+		// behaves like a failed Assert.
+		Never(msg string)
+
+		// SetPreset applies the settings of a Preset.
+		SetPreset(p Preset)
+
+		// Preset returns the preset most recently applied.
+		Preset() Preset
+
+		// ApplyPreset applies the registered or stored preset
+		// with the given name.
+		ApplyPreset(name string) error
+
+		// SetPresetFromEnv applies the preset selected by the
+		// environment, if any, and reports whether it did.
+		SetPresetFromEnv() bool
+
+		// AddEnricher adds a function that adds structured
+		// fields describing each error logged by Err.
+		AddEnricher(fn Enricher)
+
+		// CheckResponse logs transport errors and non-2xx
+		// responses and returns an error for either.
+		CheckResponse(resp *http.Response, err error) error
+
+		// ExitWith logs err, flushes outputs, and exits with the
+		// sysexits style status of err.
+		ExitWith(err error)
+
+		// Config returns the effective configuration of the
+		// logger.
+		Config() Config
+
+		// ApplyConfig applies c to the logger.
+		ApplyConfig(c Config) error
+
+		// Banner logs a configuration with the source of
+		// each field.
+		Banner(c Config, sources ConfigSources)
+
+		// Event logs a business or operational event with
+		// structured fields.
+		Event(name string, fields Fields)
+
+		// Attach logs err with a reference to a large payload
+		// stored in the attachment directory.
+		Attach(err error, name string, data []byte) error
+
+		// SetAttachmentDir sets the directory used by Attach.
+		SetAttachmentDir(dir string)
+
+		// AttachCauseGraph logs err like Attach with the
+		// graph of its causes as the attachment.
+		AttachCauseGraph(err error) error
+
+		// Msg returns an entry for a message with a stable
+		// identifier that is emitted as a field.
+		Msg(id, text string) *MsgEntry
+
+		// EnableCrashReports writes a crash report file when
+		// the process panics or exits fatally.
+		EnableCrashReports(opts CrashReportOptions) *CrashReporter
+
+		// SwapOutput atomically replaces the output old with
+		// new and drains the entries buffered by old.
+		SwapOutput(old, new Writer) error
+
+		// Writer returns the current output of the logger.
+		Writer() Writer
+
+		// SetAuditOutput enables an audit trail of runtime
+		// configuration changes written to w.
+		SetAuditOutput(w Writer)
+
+		// ChangedBy returns a Changer that records changes in
+		// the audit trail as made via a channel by an actor.
+		ChangedBy(via, actor string) *Changer
+
+		// Reporter returns a handle that can report errors
+		// through the logger but cannot reconfigure it.
+		Reporter() ErrReporter
+
+		// ErrCtx logs err like Err, with fields explaining the
+		// cause and deadline of ctx if err is a context error.
+		ErrCtx(ctx context.Context, err error) error
+
+		// AddContextExtractor adds a function that extracts
+		// fields from the context passed to ErrCtx.
+		AddContextExtractor(fn ContextExtractor)
+
+		// SetContextKeys declares the context keys whose
+		// values ErrCtx logs as fields.
+		SetContextKeys(keys ...interface{})
+
+		// OnFatal and OnPanic add callbacks that run before
+		// the process exits or panics through the logger.
+		OnFatal(fn func(Entry))
+		OnPanic(fn func(Entry))
+
+		// SetCallerSkip sets the number of wrapper frames
+		// skipped when the caller is reported.
+		SetCallerSkip(skip int)
+
+		// SetRateLimit limits the logger to n entries per
+		// window and logs a summary of those dropped.
+		SetRateLimit(n int, per time.Duration)
+
+		// SetSampling logs only the first of every n calls
+		// to Err.
+		SetSampling(n int)
+
+		// SetSchedule applies a level and sampling schedule
+		// by time of day.
+		SetSchedule(spec string) error
+
+		// SetDedupWindow logs identical errors once per
+		// window with the number of occurrences.
+		SetDedupWindow(window time.Duration)
+
+		// DisableLevel and EnableLevel silence and restore
+		// entries of a single level.
+		DisableLevel(level Level)
+		EnableLevel(level Level)
+
+		// SetCaptureStack sets the depth of the stack trace
+		// recorded by Err; zero turns capture off.
+		SetCaptureStack(depth int)
+
+		// SetStackFormat sets how captured stack traces are
+		// logged.
+		SetStackFormat(f StackFormat)
+
+		// AddOutput adds an output that entries are written
+		// to in addition to the current output.
+		AddOutput(out io.Writer)
+
+		// SetDuplicateOutputPolicy sets what AddOutput does
+		// with an output the logger already writes to.
+		SetDuplicateOutputPolicy(p DuplicateOutputPolicy)
+
+		// SetFatalIfMain sets whether Fatal only exits when
+		// called from the main module.
+		SetFatalIfMain(on bool)
+
+		// FatalIfMain logs like Fatal from the main module and
+		// returns ErrFatalInLibrary from any other.
+		FatalIfMain(args ...interface{}) error
+
+		// SetHookPolicy sets the latency budget of hooks.
+		SetHookPolicy(p HookPolicy)
+
+		// AddParallelHook adds hooks that fire concurrently
+		// with each other, with panic isolation.
+		AddParallelHook(hooks ...logrus.Hook)
+
+		// AddFilteredHook adds hook under name, filtered by
+		// the filter configured for name in the configuration.
+		AddFilteredHook(name string, hook logrus.Hook) *FilteredHook
+
+		// EnableHeartbeat logs an InfoLevel entry with the
+		// uptime and statistics of the logger every interval.
+		EnableHeartbeat(interval time.Duration) (stop func())
+
+		// LogShutdown logs the shutdown marker looked for by
+		// CheckShutdown in the next run.
+		LogShutdown()
+
+		// CheckShutdown warns if the previous run did not log
+		// its shutdown marker to the log file.
+		CheckShutdown() (ShutdownCheck, error)
+
+		// Ready and Stopping log a service state transition
+		// and notify the service manager, as with sd_notify.
+		Ready(status string) error
+		Stopping(status string) error
+
+		// AddMutator adds fn to the mutators that transform
+		// every entry before it is formatted.
+		AddMutator(fn Mutator)
+
+		// SetKeyStyle sets the naming convention that field
+		// keys are normalized to.
+		SetKeyStyle(style KeyStyle)
+
+		// SetSchema sets the schema that entries are
+		// validated against.
+		SetSchema(s Schema)
+
+		// Diff logs a structural diff of want and got if they
+		// differ and reports whether they are equal.
+		Diff(name string, want, got interface{}) bool
+
+		// SetOptions and Options set and return the display
+		// options of the logger.
+		SetOptions(o Options) error
+		Options() Options
+
+		// Dump logs a rendering of v at DebugLevel.
+		Dump(label string, v interface{})
+
+		// CommandOutput logs the output of cmd line by line.
+		CommandOutput(cmd *exec.Cmd, stdoutLevel, stderrLevel Level) error
+
 		logrusLogger
 	}
 
 	// errorLogger implements ErrorLogger with logrus or the
 	// standard library log package.
 	errorLogger struct {
-		wrap    error      // `default:"nil"` // nil = disabled
-		msg     string     // `default:""` // the empty string = disabled
-		errFunc ErrorFunc  // `default:"()yesErr"`
-		logFunc LoggerFunc // `default:"defaultLogFunc"`
-		*Logger            // `default:"defaultlogger"`
+		wrap    error        // `default:"nil"` // nil = disabled
+		msg     string       // `default:""` // the empty string = disabled
+		errFn   atomic.Value // `default:"()yesErr"` // ErrorFunc
+		logFunc LoggerFunc   // `default:"defaultLogFunc"`
+		*Logger              // `default:"defaultlogger"`
+
+		overrides atomic.Value   // `default:"nil"` // *overrideTable
+		stats     *loggerStats   // `default:"newLoggerStats()"`
+		preset    atomic.Value   // `default:"Preset{}"` // Preset
+		enrichers []Enricher     // `default:"nil"`
+		mutators  []Mutator      // `default:"nil"`
+		keyStyle  KeyStyle       // `default:"KeepKeys"` // atomic
+		schema    atomic.Value   // `default:"nil"` // *Schema
+		options   atomic.Value   // `default:"DefaultOptions"` // *Options
+		disabled  uint32         // `default:"0"` // atomic
+		enableMu  sync.Mutex     // serializes Enable and Disable
+		blobDir   string         // `default:""` // "" = DefaultAttachmentDir()
+		audit     *auditTrail    // `default:"nil"` // nil = disabled
+		parallel  *parallelHooks // `default:"nil"` // nil = no parallel hooks
+		filters   hookFilters    // `default:"hookFilters{}"`
+
+		gate          *pipelineHook      // hooks added with AddHook
+		ctxExtractors []ContextExtractor // `default:"nil"`
+		ctxKeys       []contextKey       // `default:"nil"`
+		rateLimit     atomic.Value       // `default:"nil"` // *rateLimiter
+		dedup         atomic.Value       // `default:"nil"` // *dedupTable
+		configOut     io.Closer          // `default:"nil"` // file opened by ApplyConfig
+		schedule      loggerSchedule     // SetSchedule
+		callbacks     *levelCallbacks    // `default:"nil"` // OnFatal, OnPanic
+		callbacksOnce sync.Once
+		backend       Backend // `default:"nil"` // nil = logrus output
+		fatalIfMain   uint32  // `default:"0"` // atomic
+		dupPolicy     int32   // `default:"0"` // atomic; DuplicateOutputPolicy
+		levelMask     uint32  // `default:"0"` // atomic; bit per Level
+		sampleN       uint32  // `default:"0"` // atomic; <= 1 = no sampling
+		sampleCount   uint32  // atomic; calls to Err while sampling
+		outputMu      sync.Mutex
+		stackDepth    int32 // `default:"0"` // atomic; 0 = no stack capture
+		stackFormat   int32 // `default:"0"` // atomic; StackFormat
+		callerSkip    int32 // `default:"0"` // atomic
 	}
 )
 
 // New returns a new ErrorLogger with default options and
-// logging enabled.
+// logging enabled. The defaults can be changed with
+// SetDefaultWrap, SetDefaultLevel, and SetDefaultFormatter.
 // Most users will not need to call this, since the default
 // global ErrorLogger 'Log' is provided.
 //
@@ -274,7 +557,7 @@ type (
 // instead of creating a new instance. For example:
 //  var mylogthatwontmessthingsup = errorlogger.Log
 func New() ErrorLogger {
-	return NewWithOptions(defaultEnabled, "", defaultLogFunc, defaultErrWrap, defaultlogger)
+	return NewWithOptions(defaultEnabled, "", defaultLogFunc, nil, nil)
 }
 
 // NewWithOptions returns a new ErrorLogger with options
@@ -287,19 +570,27 @@ func New() ErrorLogger {
 //
 // - wrap: defines a custom error type to wrap all errors in.
 //
-// - logger: defines a custom logger to use.
+// - logger: defines a custom logger to use. If it is
+// already used by another ErrorLogger, a copy of it without
+// its hooks is used, so that the settings of each
+// ErrorLogger apply only to its own entries.
 func NewWithOptions(enabled bool, msg string, fn LoggerFunc, wrap error, logger *Logger) ErrorLogger {
 	return newTestStruct(enabled, msg, wrap, fn, logger)
 }
 
 func (e *errorLogger) Writer() Writer {
-	return e.Out
+	return e.output()
 }
 
 // SetErrorWrap allows ErrorLogger to wrap all errors in a
 // specified custom error type.
 // Example:
 //  log.SetErrorWrap(&os.PathError{})
+// The errors returned satisfy errors.Is and errors.As for
+// both wrap and the original error:
+//  var pathErr *os.PathError
+//  errors.As(log.Err(err), &pathErr) // true
+//  errors.Is(log.Err(err), err)      // true
 // Setting wrap == nil will disable wrapping of errors:
 //  log.SetErrorWrap(nil)
 func (e *errorLogger) SetErrorWrap(wrap error) { e.wrap = wrap }
@@ -324,7 +615,7 @@ func (e *errorLogger) SetCustomMessage(msg string) { e.msg = msg }
 //
 // The default is compact "ugly" json. A "pretty" format can be
 // selected with
-//  Log.SetOptions()
+//  Log.SetJSON(true)
 //
 // Use
 //  Log.SetText()
@@ -356,6 +647,9 @@ func (e *errorLogger) SetCustomMessage(msg string) { e.msg = msg }
 func (e *errorLogger) SetJSON(pretty bool) {
 	// e.SetErrorWrap(&os.PathError{})
 	f := NewJSONFormatter(pretty)
+	if o, ok := e.options.Load().(*Options); ok {
+		f.Options = *o
+	}
 	e.SetFormatter(f)
 }
 
@@ -419,7 +713,7 @@ func (e *errorLogger) SetLogLevel(lvl string) error {
 	if err != nil {
 		return Err(err)
 	}
-	e.Logger.SetLevel(level)
+	e.SetLevel(level)
 	return nil
 }
 
@@ -69,7 +69,7 @@ func Test_errorLogger_SetErrorWrap(t *testing.T) {
 			got := NewWithOptions(true, "", nil, nil, nil)
 			got.SetErrorWrap(tt.wrap)
 
-			if errors.Is(got.Err(errFake), fakeSysCallError) {
+			if errors.Is(got.Err(errFake), fakeSysCallError) != (tt.wrap != nil) {
 				t.Errorf("SetErrorWrap(%s) did not wrap error: got %v, want %v", tt.name, got, tt.wrap)
 			}
 		})
@@ -0,0 +1,28 @@
+package errorlogger
+
+// EventKey is the field that holds the name of an event
+// logged by Event.
+const EventKey = "event"
+
+// Event logs a business or operational event, such as
+// cache_miss or job_completed, at InfoLevel. The name is
+// used as the message and is also stored in the "event"
+// field so that events are easy to filter.
+//
+// Events are not errors: they bypass error wrapping,
+// enrichers, and error statistics, but share the outputs,
+// formatters, level overrides, and hooks of the logger.
+// Like Err, Event does nothing while the logger is
+// disabled.
+//  log.Event("job_completed", Fields{"job": id, "duration": d})
+func (e *errorLogger) Event(name string, fields Fields) {
+	if !e.enabled() || !e.IsLevelEnabled(InfoLevel) {
+		return
+	}
+	f := make(Fields, len(fields)+1)
+	for k, v := range fields {
+		f[k] = v
+	}
+	f[EventKey] = name
+	e.WithFields(f).Info(name)
+}
@@ -0,0 +1,42 @@
+package errorlogger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_Event(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    Level
+		disabled bool
+		want     []string
+	}{
+		{"info", InfoLevel, false, []string{"level=info", "msg=cache_miss", "event=cache_miss", `key="user:42"`}},
+		{"warn", WarnLevel, false, nil},
+		{"disabled", InfoLevel, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(tt.level)
+			if tt.disabled {
+				e.Disable()
+			}
+			fields := Fields{"key": "user:42"}
+			e.Event("cache_miss", fields)
+
+			got := buf.String()
+			if tt.want == nil && got != "" {
+				t.Errorf("Event() = %q, want no output", got)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("Event() = %q, want %q", got, w)
+				}
+			}
+			if _, ok := fields[EventKey]; ok {
+				t.Error("Event() modified the fields argument")
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package errorlogger
+
+import (
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Flusher is implemented by outputs that buffer entries,
+// such as AsyncWriter. Flush blocks until buffered entries
+// have been written.
+type Flusher interface {
+	Flush()
+}
+
+var (
+	flushersMu sync.Mutex
+	flushers   []Flusher
+
+	exitHandlerOnce sync.Once
+)
+
+// RegisterFlusher adds f to the outputs flushed by FlushAll.
+func RegisterFlusher(f Flusher) {
+	if f == nil {
+		return
+	}
+	flushersMu.Lock()
+	defer flushersMu.Unlock()
+	flushers = append(flushers, f)
+}
+
+// FlushAll flushes every registered Flusher.
+func FlushAll() {
+	flushersMu.Lock()
+	list := make([]Flusher, len(flushers))
+	copy(list, flushers)
+	flushersMu.Unlock()
+
+	for _, f := range list {
+		f.Flush()
+	}
+}
+
+// InstallExitHandler registers fs with RegisterFlusher and
+// wires FlushAll into logrus.RegisterExitHandler so that
+// buffered entries are written before Fatal exits the
+// program. It is opt-in and safe to call more than once;
+// the exit handler is only installed once.
+//
+// Calls to os.Exit cannot be intercepted; use Exit instead.
+// Unrecovered panics in main are handled by ExitWrapper.
+func InstallExitHandler(fs ...Flusher) {
+	for _, f := range fs {
+		RegisterFlusher(f)
+	}
+	exitHandlerOnce.Do(func() { logrus.RegisterExitHandler(FlushAll) })
+}
+
+// Exit flushes all registered outputs and then exits the
+// program with the given status code. Use it in place of
+// os.Exit so the last entries are not lost.
+func Exit(code int) {
+	FlushAll()
+	osExit(code)
+}
+
+// osExit is replaced in tests.
+var osExit = os.Exit
+
+// ExitWrapper returns a function that runs fn and flushes
+// all registered outputs when fn returns or panics. A panic
+// is logged to the global logger with LogPanic and then
+// propagated after flushing.
+//
+// Wrap the body of main to guarantee the last error is
+// written:
+//  func main() { errorlogger.ExitWrapper(run)() }
+func ExitWrapper(fn func()) func() {
+	return func() {
+		defer func() {
+			if r := recover(); r != nil {
+				_ = Log.LogPanic(r)
+				FlushAll()
+				panic(r)
+			}
+			FlushAll()
+		}()
+		fn()
+	}
+}
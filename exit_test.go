@@ -0,0 +1,81 @@
+package errorlogger
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+// countingFlusher counts calls to Flush.
+type countingFlusher struct{ n int32 }
+
+func (f *countingFlusher) Flush() { atomic.AddInt32(&f.n, 1) }
+
+func (f *countingFlusher) count() int32 { return atomic.LoadInt32(&f.n) }
+
+func resetFlushers() {
+	flushersMu.Lock()
+	flushers = nil
+	flushersMu.Unlock()
+}
+
+func TestExitWrapper(t *testing.T) {
+	defer resetFlushers()
+	f := &countingFlusher{}
+	RegisterFlusher(f)
+	RegisterFlusher(nil)
+
+	ExitWrapper(func() {})()
+	if got := f.count(); got != 1 {
+		t.Errorf("ExitWrapper() flush count = %d, want 1", got)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("ExitWrapper() recovered %v, want panic to propagate", r)
+			}
+		}()
+		ExitWrapper(func() { panic("boom") })()
+	}()
+	if got := f.count(); got != 2 {
+		t.Errorf("ExitWrapper() flush count after panic = %d, want 2", got)
+	}
+}
+
+func TestExit(t *testing.T) {
+	defer resetFlushers()
+	defer func(fn func(int)) { osExit = fn }(osExit)
+
+	code := -1
+	osExit = func(c int) { code = c }
+
+	f := &countingFlusher{}
+	RegisterFlusher(f)
+	Exit(3)
+
+	if code != 3 {
+		t.Errorf("Exit() code = %d, want 3", code)
+	}
+	if got := f.count(); got != 1 {
+		t.Errorf("Exit() flush count = %d, want 1", got)
+	}
+}
+
+func TestInstallExitHandler(t *testing.T) {
+	defer resetFlushers()
+	f := &countingFlusher{}
+	InstallExitHandler(f)
+	InstallExitHandler()
+
+	e, _ := newBufferLogger(InfoLevel)
+	code := -1
+	e.ExitFunc = func(c int) { code = c }
+	e.Fatal("fatal")
+
+	if code != 1 {
+		t.Errorf("Fatal() exit code = %d, want 1", code)
+	}
+	if got := f.count(); got != 1 {
+		t.Errorf("Fatal() flush count = %d, want 1", got)
+	}
+}
@@ -0,0 +1,102 @@
+package errorlogger
+
+// Exit status codes for CLI tools, as defined by BSD sysexits.h.
+const (
+	ExitOK          = 0  // successful termination
+	ExitFailure     = 1  // general failure
+	ExitUsage       = 64 // command line usage error
+	ExitDataErr     = 65 // data format error
+	ExitNoInput     = 66 // cannot open input
+	ExitNoUser      = 67 // addressee unknown
+	ExitNoHost      = 68 // host name unknown
+	ExitUnavailable = 69 // service unavailable
+	ExitSoftware    = 70 // internal software error
+	ExitOSErr       = 71 // system error (e.g., can't fork)
+	ExitOSFile      = 72 // critical OS file missing
+	ExitCantCreate  = 73 // can't create (user) output file
+	ExitIOErr       = 74 // input/output error
+	ExitTempFail    = 75 // temp failure; user is invited to retry
+	ExitProtocol    = 76 // remote error in protocol
+	ExitNoPerm      = 77 // permission denied
+	ExitConfig      = 78 // configuration error
+)
+
+// ExitCodeKey is the field with the exit status of the
+// error logged by ExitWith.
+const ExitCodeKey = "exit_code"
+
+// ExitCode returns the sysexits style exit status for err.
+//
+// If the chain of err contains a registered error code with
+// an exit status set by SetExitCode, that status is used.
+// Otherwise, the status is derived from the Kind of err, as
+// reported by CodeInfo.ExitCode for codes without a status.
+// A nil error returns ExitOK.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	if code, ok := CodeOf(err); ok {
+		codesMu.RLock()
+		status, ok := exitCodes[code]
+		codesMu.RUnlock()
+		if ok {
+			return status
+		}
+	}
+	return kindExitCode(err)
+}
+
+// kindExitCode returns the exit status for the Kind of err.
+func kindExitCode(err error) int {
+	switch Classify(err) {
+	case KindNotFound:
+		return ExitNoInput
+	case KindAlreadyExists:
+		return ExitCantCreate
+	case KindPermissionDenied:
+		return ExitNoPerm
+	case KindInvalid:
+		return ExitDataErr
+	case KindTimeout, KindCanceled:
+		return ExitTempFail
+	case KindUnavailable:
+		return ExitUnavailable
+	case KindInternal:
+		return ExitSoftware
+	default:
+		return ExitFailure
+	}
+}
+
+// ExitWith logs err at ErrorLevel with the ExitCodeKey
+// field, flushes all registered outputs, and exits the
+// program with the exit status of err as returned by
+// ExitCode. A nil error exits with ExitOK without logging.
+//
+// The error is logged with the fields of the enrichers, but
+// not through Err, so that neither sampling, deduplication,
+// nor the rate limit can suppress the last error before the
+// program exits.
+//
+// It replaces the common misuse of Fatal, which always
+// exits with status 1:
+//  if err := run(); err != nil {
+//  	log.ExitWith(err)
+//  }
+//
+// Like os.Exit, deferred functions are not run.
+func (e *errorLogger) ExitWith(err error) {
+	code := ExitCode(err)
+	if err != nil && e.enabled() {
+		fields := e.errFields(err)
+		if fields == nil {
+			fields = make(Fields, 1)
+		}
+		fields[ExitCodeKey] = code
+		e.WithFields(fields).Error(err)
+	}
+	FlushAll()
+	e.Exit(code)
+}
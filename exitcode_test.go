@@ -0,0 +1,92 @@
+package errorlogger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	errTestExitSentinel  = NewSentinel("ETEST_EXIT", "needs config")
+	errTestPlainSentinel = NewSentinel("ETEST_PLAIN", "no exit code")
+)
+
+func TestExitCode(t *testing.T) {
+	if err := SetExitCode("ETEST_EXIT", ExitConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"registered", fmt.Errorf("load: %w", errTestExitSentinel), ExitConfig},
+		{"registered default", errTestPlainSentinel, ExitSoftware},
+		{"not found", os.ErrNotExist, ExitNoInput},
+		{"permission", os.ErrPermission, ExitNoPerm},
+		{"invalid", ErrInvalid, ExitDataErr},
+		{"timeout", context.DeadlineExceeded, ExitTempFail},
+		{"internal", errFake, ExitSoftware},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	for _, err := range []error{errTestExitSentinel, errTestPlainSentinel} {
+		code, _ := CodeOf(err)
+		if info, _ := LookupCode(code); info.ExitCode != ExitCode(err) {
+			t.Errorf("LookupCode(%s).ExitCode = %d, want ExitCode() = %d", code, info.ExitCode, ExitCode(err))
+		}
+	}
+}
+
+func Test_errorLogger_ExitWith(t *testing.T) {
+	defer resetFlushers()
+	f := &countingFlusher{}
+	RegisterFlusher(f)
+
+	e, buf := newBufferLogger(InfoLevel)
+	code := -1
+	e.ExitFunc = func(c int) { code = c }
+
+	e.ExitWith(os.ErrNotExist)
+	if code != ExitNoInput {
+		t.Errorf("ExitWith() code = %d, want %d", code, ExitNoInput)
+	}
+	if !strings.Contains(buf.String(), os.ErrNotExist.Error()) {
+		t.Errorf("ExitWith() output = %q, want logged error", buf.String())
+	}
+	if f.count() < 1 {
+		t.Errorf("ExitWith() flush count = %d, want at least 1", f.count())
+	}
+
+	// The last error is logged despite sampling, dedup, and
+	// the rate limit.
+	buf.Reset()
+	e.SetSampling(2)
+	e.SetDedupWindow(time.Hour)
+	e.SetRateLimit(1, time.Hour)
+	defer e.SetDedupWindow(0)
+	defer e.SetRateLimit(0, 0)
+	_ = e.Err(errFake)
+	e.ExitWith(errFake)
+	e.ExitWith(errFake)
+	if n := strings.Count(buf.String(), fmt.Sprintf("%s=%d", ExitCodeKey, ExitCode(errFake))); n != 2 {
+		t.Errorf("ExitWith() logged %d errors with the exit code, want 2:\n%s", n, buf.String())
+	}
+
+	buf.Reset()
+	e.ExitWith(nil)
+	if code != ExitOK || buf.Len() != 0 {
+		t.Errorf("ExitWith(nil) code = %d, output = %q", code, buf.String())
+	}
+}
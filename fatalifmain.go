@@ -0,0 +1,147 @@
+package errorlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrFatalInLibrary is returned by FatalIfMain when it is
+// called from outside the main module and the entry was
+// logged at ErrorLevel instead of exiting the program.
+var ErrFatalInLibrary = errors.New("Fatal called from a library")
+
+// FatalCallerKey is the field with the package that called
+// Fatal when a Fatal entry is downgraded to ErrorLevel.
+const FatalCallerKey = "fatal_caller"
+
+var (
+	// mainModule returns the module path of the main
+	// package, or "" if it is not known.
+	mainModule = func() string {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			return info.Main.Path
+		}
+		return ""
+	}
+
+	// fatalDiag is where downgraded Fatal calls are reported.
+	fatalDiag Writer = os.Stderr
+
+	// fatalWarned records the call sites that were already
+	// reported on fatalDiag.
+	fatalWarned sync.Map
+)
+
+// SetFatalIfMain sets whether Fatal, Fatalf, and Fatalln
+// only exit the program when they are called from the main
+// module. Libraries should never call Fatal; with this mode
+// on, a Fatal call from a dependency is logged at ErrorLevel
+// with the calling package in the fatal_caller field, and a
+// warning is written to os.Stderr the first time each call
+// site does so:
+//  log.SetFatalIfMain(true)
+//
+// The main module is read from the build info of the
+// program. If it is not known, Fatal always exits. Entries
+// made Fatal through WithField and friends are not checked.
+func (e *errorLogger) SetFatalIfMain(on bool) {
+	var v uint32
+	if on {
+		v = 1
+	}
+	atomic.StoreUint32(&e.fatalIfMain, v)
+}
+
+// FatalIfMain logs args like Fatal and exits the program if
+// it is called from the main module. Called from any other
+// module, it logs args at ErrorLevel instead and returns
+// ErrFatalInLibrary so that the library can return it to
+// the application:
+//  if err := log.FatalIfMain("config missing"); err != nil {
+//  	return err
+//  }
+//
+// FatalIfMain checks the caller whether or not
+// SetFatalIfMain is on.
+func (e *errorLogger) FatalIfMain(args ...interface{}) error {
+	if e.downgradeFatal(fmt.Sprint(args...)) {
+		return ErrFatalInLibrary
+	}
+	e.Logger.Fatal(args...)
+	return nil
+}
+
+// Fatal logs a message at FatalLevel and exits the program,
+// unless SetFatalIfMain is on and it is called from outside
+// the main module.
+func (e *errorLogger) Fatal(args ...interface{}) {
+	if atomic.LoadUint32(&e.fatalIfMain) == 1 && e.downgradeFatal(fmt.Sprint(args...)) {
+		return
+	}
+	e.Logger.Fatal(args...)
+}
+
+// Fatalf logs a formatted message at FatalLevel and exits
+// the program, unless SetFatalIfMain is on and it is called
+// from outside the main module.
+func (e *errorLogger) Fatalf(format string, args ...interface{}) {
+	if atomic.LoadUint32(&e.fatalIfMain) == 1 && e.downgradeFatal(fmt.Sprintf(format, args...)) {
+		return
+	}
+	e.Logger.Fatalf(format, args...)
+}
+
+// Fatalln logs a message at FatalLevel and exits the
+// program, unless SetFatalIfMain is on and it is called
+// from outside the main module.
+func (e *errorLogger) Fatalln(args ...interface{}) {
+	if atomic.LoadUint32(&e.fatalIfMain) == 1 && e.downgradeFatal(strings.TrimSuffix(fmt.Sprintln(args...), "\n")) {
+		return
+	}
+	e.Logger.Fatalln(args...)
+}
+
+// downgradeFatal logs msg at ErrorLevel and reports true if
+// the caller is outside the main module.
+func (e *errorLogger) downgradeFatal(msg string) bool {
+	f := callerFrame()
+	pkg := funcPackage(f.Function)
+	if !libraryPackage(pkg, mainModule()) {
+		return false
+	}
+
+	site := fmt.Sprintf("%s:%d", f.File, f.Line)
+	if _, loaded := fatalWarned.LoadOrStore(site, struct{}{}); !loaded {
+		fmt.Fprintf(fatalDiag, "errorlogger: Fatal called from library package %s at %s; logged as Error\n", pkg, site)
+	}
+	e.WithFields(Fields{
+		FatalCallerKey: pkg,
+		"call_site":    site,
+	}).Error(msg)
+	return true
+}
+
+// funcPackage returns the package path of a function name
+// as reported by runtime.Frame, e.g. "example.com/lib/db"
+// for "example.com/lib/db.(*Conn).Close".
+func funcPackage(fn string) string {
+	slash := strings.LastIndex(fn, "/") + 1
+	if dot := strings.Index(fn[slash:], "."); dot >= 0 {
+		return fn[:slash+dot]
+	}
+	return fn
+}
+
+// libraryPackage reports whether pkg is outside the main
+// module. If the main module is not known, no package is.
+func libraryPackage(pkg, main string) bool {
+	if main == "" || pkg == "" || pkg == "main" {
+		return false
+	}
+	return pkg != main && !strings.HasPrefix(pkg, main+"/")
+}
@@ -0,0 +1,79 @@
+package errorlogger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFatalIfMain(t *testing.T) {
+	defer func(f func() string, w Writer) { mainModule, fatalDiag = f, w }(mainModule, fatalDiag)
+
+	tests := []struct {
+		name      string
+		main      string
+		mode      bool
+		fatal     func(e *errorLogger) error
+		wantExit  bool
+		wantLevel string
+		wantErr   error
+	}{
+		{"mode off", "example.com/app", false, func(e *errorLogger) error { e.Fatal("boom"); return nil }, true, "level=fatal", nil},
+		{"main module", "github.com/skeptycal/errorlogger", true, func(e *errorLogger) error { e.Fatalf("boom %d", 1); return nil }, true, "level=fatal", nil},
+		{"unknown main module", "", true, func(e *errorLogger) error { e.Fatalln("boom"); return nil }, true, "level=fatal", nil},
+		{"library", "example.com/app", true, func(e *errorLogger) error { e.Fatal("boom"); return nil }, false, "level=error", nil},
+		{"library Fatalf", "example.com/app", true, func(e *errorLogger) error { e.Fatalf("boom %d", 1); return nil }, false, "level=error", nil},
+		{"FatalIfMain library", "example.com/app", false, func(e *errorLogger) error { return e.FatalIfMain("boom") }, false, "level=error", ErrFatalInLibrary},
+		{"FatalIfMain main module", "github.com/skeptycal/errorlogger", false, func(e *errorLogger) error { return e.FatalIfMain("boom") }, true, "level=fatal", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			main := tt.main
+			mainModule = func() string { return main }
+			var diag bytes.Buffer
+			fatalDiag = &diag
+			fatalWarned.Range(func(k, _ interface{}) bool { fatalWarned.Delete(k); return true })
+
+			e, buf := newBufferLogger(InfoLevel)
+			exited := false
+			e.ExitFunc = func(int) { exited = true }
+			e.SetFatalIfMain(tt.mode)
+
+			err := tt.fatal(e)
+			if exited != tt.wantExit {
+				t.Errorf("exited = %v, want %v", exited, tt.wantExit)
+			}
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			out := buf.String()
+			if !strings.Contains(out, tt.wantLevel) || !strings.Contains(out, "boom") {
+				t.Errorf("output = %q, want %q", out, tt.wantLevel)
+			}
+			if downgraded := !tt.wantExit; downgraded != strings.Contains(diag.String(), "called from library package github.com/skeptycal/errorlogger at ") {
+				t.Errorf("diagnostics = %q", diag.String())
+			}
+			if !tt.wantExit && !strings.Contains(out, FatalCallerKey+"=github.com/skeptycal/errorlogger") {
+				t.Errorf("output = %q, want the %s field", out, FatalCallerKey)
+			}
+		})
+	}
+}
+
+func TestFuncPackage(t *testing.T) {
+	tests := []struct {
+		fn   string
+		want string
+	}{
+		{"main.main", "main"},
+		{"example.com/lib/db.(*Conn).Close", "example.com/lib/db"},
+		{"example.com/lib.v2/db.Open.func1", "example.com/lib.v2/db"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := funcPackage(tt.fn); got != tt.want {
+			t.Errorf("funcPackage(%q) = %q, want %q", tt.fn, got, tt.want)
+		}
+	}
+}
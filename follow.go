@@ -0,0 +1,251 @@
+package errorlogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// followPoll is the interval at which Follow polls a file
+// for new entries and rotation.
+var followPoll = 250 * time.Millisecond
+
+// follower tails a log file for Follow.
+type follower struct {
+	path    string
+	f       *os.File
+	offset  int64
+	pending []byte
+	ch      chan Entry
+}
+
+// Follow tails the log file at path and returns a channel of
+// the entries written to it by this package, in either the
+// JSON or the text format, like tail -F:
+//  entries, err := Follow("/var/log/app.log", true)
+//  if err != nil {
+//      return err
+//  }
+//  for entry := range entries {
+//      fmt.Println(entry.Level, entry.Message)
+//  }
+//
+// If fromEnd is true, only entries written after Follow is
+// called are sent; otherwise the file is read from the
+// start. Follow survives rotation: when the file at path is
+// replaced, the rest of the old file is read before the new
+// one, and when it is truncated it is read again from the
+// start. Lines that are not entries are skipped.
+//
+// The channel is never closed; use FollowContext to stop
+// following.
+func Follow(path string, fromEnd bool) (<-chan Entry, error) {
+	return FollowContext(context.Background(), path, fromEnd)
+}
+
+// FollowContext is like Follow, but stops following and
+// closes the channel when ctx is done.
+func FollowContext(ctx context.Context, path string, fromEnd bool) (<-chan Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	fl := &follower{path: path, f: f, ch: make(chan Entry, 64)}
+	if fromEnd {
+		if fl.offset, err = f.Seek(0, io.SeekEnd); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	go fl.run(ctx)
+	return fl.ch, nil
+}
+
+func (fl *follower) run(ctx context.Context) {
+	defer close(fl.ch)
+	defer func() { fl.f.Close() }()
+
+	t := time.NewTicker(followPoll)
+	defer t.Stop()
+	for {
+		if !fl.read(ctx) {
+			return
+		}
+		if fl.rotated(ctx) {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// read sends the entries written to the file since the last
+// read. It returns false if ctx is done.
+func (fl *follower) read(ctx context.Context) bool {
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := fl.f.Read(buf)
+		fl.offset += int64(n)
+		fl.pending = append(fl.pending, buf[:n]...)
+		if !fl.send(ctx, false) {
+			return false
+		}
+		if n == 0 || err != nil {
+			return true
+		}
+	}
+}
+
+// send sends the complete entries in the pending bytes, and
+// the rest too if atEOF is true. It returns false if ctx is
+// done.
+func (fl *follower) send(ctx context.Context, atEOF bool) bool {
+	for len(fl.pending) > 0 {
+		advance, token, _ := SplitJSON(fl.pending, atEOF)
+		if advance == 0 {
+			if len(fl.pending) > MaxJSONEntrySize {
+				fl.pending = nil
+			}
+			break
+		}
+		fl.pending = fl.pending[advance:]
+		entry, ok := parseEntry(token)
+		if !ok {
+			continue
+		}
+		select {
+		case fl.ch <- entry:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	if len(fl.pending) == 0 {
+		fl.pending = nil
+	}
+	return true
+}
+
+// rotated reports whether the file at the path was replaced
+// and, if so, switches to the new file after sending the
+// rest of the old one. A truncated file is read again from
+// the start.
+func (fl *follower) rotated(ctx context.Context) bool {
+	fi, err := os.Stat(fl.path)
+	if err != nil {
+		return false // removed; wait for the new file
+	}
+	cur, err := fl.f.Stat()
+	if err == nil && os.SameFile(fi, cur) {
+		if fi.Size() < fl.offset {
+			if _, err := fl.f.Seek(0, io.SeekStart); err == nil {
+				fl.offset = 0
+				fl.pending = nil
+			}
+		}
+		return false
+	}
+
+	f, err := os.Open(fl.path)
+	if err != nil {
+		return false
+	}
+	if fl.read(ctx) {
+		fl.send(ctx, true)
+	}
+	fl.f.Close()
+	fl.f, fl.offset, fl.pending = f, 0, nil
+	return true
+}
+
+// parseEntry parses an entry formatted by JSONFormatter or by
+// TextFormatter without colors. It reports false if line is
+// not an entry.
+func parseEntry(line []byte) (Entry, bool) {
+	line = bytes.TrimSpace(line)
+	var data map[string]interface{}
+	if len(line) > 0 && line[0] == '{' {
+		if err := json.Unmarshal(line, &data); err != nil {
+			return Entry{}, false
+		}
+	} else {
+		var ok bool
+		if data, ok = parseLogfmt(line); !ok {
+			return Entry{}, false
+		}
+	}
+
+	lvl, ok := data[jsonLevelKey].(string)
+	if !ok {
+		return Entry{}, false
+	}
+	level, err := ParseLevel(lvl)
+	if err != nil {
+		return Entry{}, false
+	}
+	entry := Entry{Level: level, Data: make(Fields, len(data))}
+	if msg, ok := data[jsonMsgKey].(string); ok {
+		entry.Message = msg
+	}
+	if s, ok := data[jsonTimeKey].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			entry.Time = t
+		}
+	}
+	for k, v := range data {
+		if k != jsonLevelKey && k != jsonMsgKey && k != jsonTimeKey {
+			entry.Data[k] = v
+		}
+	}
+	return entry, true
+}
+
+// parseLogfmt parses the key=value pairs of a line formatted
+// by TextFormatter, with quoted values unquoted. It reports
+// false if line is not a sequence of pairs.
+func parseLogfmt(line []byte) (map[string]interface{}, bool) {
+	data := make(map[string]interface{})
+	s := string(line)
+	for s != "" {
+		eq := 0
+		for eq < len(s) && s[eq] != '=' && s[eq] != ' ' && s[eq] != '"' {
+			eq++
+		}
+		if eq == 0 || eq == len(s) || s[eq] != '=' {
+			return nil, false
+		}
+		key := s[:eq]
+		s = s[eq+1:]
+
+		var value string
+		if s != "" && s[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, false
+			}
+			value, _ = strconv.Unquote(quoted)
+			s = s[len(quoted):]
+		} else {
+			end := 0
+			for end < len(s) && s[end] != ' ' {
+				end++
+			}
+			value = s[:end]
+			s = s[end:]
+		}
+		if s != "" && s[0] != ' ' {
+			return nil, false
+		}
+		for s != "" && s[0] == ' ' {
+			s = s[1:]
+		}
+		data[key] = value
+	}
+	return data, len(data) > 0
+}
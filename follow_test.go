@@ -0,0 +1,98 @@
+package errorlogger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseEntry(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   Entry
+		wantOk bool
+	}{
+		{"json", `{"level":"error","msg":"boom","time":"2021-01-02T03:04:05Z","n":2}`,
+			Entry{Level: ErrorLevel, Message: "boom", Time: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), Data: Fields{"n": 2.0}}, true},
+		{"text", `time="2021-01-02T03:04:05Z" level=warning msg="disk \"a\" full" pct=99`,
+			Entry{Level: WarnLevel, Message: `disk "a" full`, Time: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), Data: Fields{"pct": "99"}}, true},
+		{"no level", `{"msg":"boom"}`, Entry{}, false},
+		{"not an entry", `panic: runtime error`, Entry{}, false},
+		{"bad quote", `level=info msg="open`, Entry{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseEntry([]byte(tt.line))
+			if ok != tt.wantOk {
+				t.Fatalf("parseEntry() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEntry() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFollow(t *testing.T) {
+	defer func(d time.Duration) { followPoll = d }(followPoll)
+	followPoll = 5 * time.Millisecond
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	write := func(flag int, s string) {
+		t.Helper()
+		f, err := os.OpenFile(path, flag|os.O_WRONLY|os.O_CREATE, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(os.O_TRUNC, "level=info msg=old\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	entries, err := FollowContext(ctx, path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := func(want string) {
+		t.Helper()
+		select {
+		case entry := <-entries:
+			if entry.Message != want {
+				t.Errorf("entry = %q, want %q", entry.Message, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no entry, want %q", want)
+		}
+	}
+
+	write(os.O_APPEND, "{\"level\":\"info\",\n \"msg\":\"pretty\"}\nnot an entry\nlevel=info msg=one")
+	next("pretty")
+	write(os.O_APPEND, "\n")
+	next("one")
+
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	write(os.O_TRUNC, "level=info msg=rotated\n")
+	next("rotated")
+
+	time.Sleep(10 * followPoll)
+	write(os.O_TRUNC, "level=info msg=x\n")
+	next("x")
+
+	cancel()
+	for range entries {
+	}
+
+	if _, err := Follow(filepath.Join(t.TempDir(), "missing"), false); !os.IsNotExist(err) {
+		t.Errorf("Follow() of a missing file = %v", err)
+	}
+}
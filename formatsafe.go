@@ -0,0 +1,188 @@
+package errorlogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxFieldDepth is the deepest nesting of maps, slices,
+// structs, and pointers in a field value that the
+// formatters of this package encode. Deeper values, which
+// include cyclic ones, are replaced by a placeholder.
+const MaxFieldDepth = 32
+
+// formatPanicKey is the field that holds the panic value if
+// formatting an entry panicked and it was formatted again
+// with its fields as strings.
+const formatPanicKey = "format_panic"
+
+// Format formats entry as text. It never panics: invalid
+// UTF-8 is replaced, deeply nested or cyclic field values
+// are replaced by a placeholder, and field values that
+// panic when formatted are written as strings. Unless
+// DisableSanitize is set, control characters are replaced
+// as by SanitizeControl. Unless DisableHumanize is set,
+// time.Duration values are rounded to three significant
+// digits.
+func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
+	if !f.DisableHumanize {
+		entry = humanizeEntry(entry)
+	}
+	if !f.DisableSanitize {
+		entry = sanitizeControlEntry(entry)
+	}
+	return safeFormat(entry, f.TextFormatter.Format)
+}
+
+// Format formats entry as JSON. It never panics, as
+// TextFormatter.Format. Unless PrettyPrint is set, the entry
+// is written as exactly one line, with newlines in messages
+// and values escaped, so that the output is newline
+// delimited JSON; see SplitJSON.
+func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
+	if f.Options != (Options{}) {
+		return safeFormat(entry, f.formatOptions)
+	}
+	return safeFormat(entry, f.JSONFormatter.Format)
+}
+
+// safeFormat formats a sanitized copy of entry with format,
+// retrying with the field values as strings if it panics.
+func safeFormat(entry *Entry, format func(*Entry) ([]byte, error)) ([]byte, error) {
+	e := sanitizeEntry(entry)
+	b, err, r := tryFormat(e, format)
+	if r == nil {
+		return b, err
+	}
+
+	c := *e
+	c.Data = make(Fields, len(e.Data)+1)
+	for k, v := range e.Data {
+		c.Data[k] = safeSprint(v)
+	}
+	c.Data[formatPanicKey] = safeSprint(r)
+	b, err, r = tryFormat(&c, format)
+	if r != nil {
+		return nil, fmt.Errorf("format entry: panic: %s", safeSprint(r))
+	}
+	return b, err
+}
+
+// tryFormat calls format and returns the value of a panic.
+func tryFormat(entry *Entry, format func(*Entry) ([]byte, error)) (b []byte, err error, r interface{}) {
+	defer func() {
+		r = recover()
+	}()
+	b, err = format(entry)
+	return b, err, nil
+}
+
+// safeSprint returns fmt.Sprint(v) for values that are not
+// nested too deeply. fmt recovers panics in the String and
+// Error methods of v.
+func safeSprint(v interface{}) string {
+	if tooDeep(reflect.ValueOf(v), 0) {
+		return nestedPlaceholder(v)
+	}
+	return strings.ToValidUTF8(fmt.Sprint(v), string(utf8.RuneError))
+}
+
+// sanitizeEntry returns entry, or a copy of it with invalid
+// UTF-8 in the message and string fields replaced and
+// fields nested too deeply replaced by a placeholder.
+func sanitizeEntry(entry *Entry) *Entry {
+	var c *Entry
+	clone := func() {
+		if c == nil {
+			e := *entry
+			e.Data = make(Fields, len(entry.Data))
+			for k, v := range entry.Data {
+				e.Data[k] = v
+			}
+			c = &e
+		}
+	}
+
+	if !utf8.ValidString(entry.Message) {
+		clone()
+		c.Message = strings.ToValidUTF8(entry.Message, string(utf8.RuneError))
+	}
+	for k, v := range entry.Data {
+		var safe interface{}
+		switch v := v.(type) {
+		case string:
+			if utf8.ValidString(v) {
+				continue
+			}
+			safe = strings.ToValidUTF8(v, string(utf8.RuneError))
+		case nil, bool, int, int64, uint64, float64, error, fmt.Stringer, json.Marshaler:
+			continue
+		default:
+			if !tooDeep(reflect.ValueOf(v), 0) {
+				continue
+			}
+			safe = nestedPlaceholder(v)
+		}
+		clone()
+		c.Data[k] = safe
+	}
+	if c == nil {
+		return entry
+	}
+	return c
+}
+
+// nestedPlaceholder replaces a value nested too deeply.
+func nestedPlaceholder(v interface{}) string {
+	return fmt.Sprintf("<%T nested deeper than %d>", v, MaxFieldDepth)
+}
+
+// tooDeep reports whether v nests deeper than MaxFieldDepth
+// below depth.
+func tooDeep(v reflect.Value, depth int) bool {
+	if depth > MaxFieldDepth {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return !v.IsNil() && tooDeep(v.Elem(), depth+1)
+	case reflect.Map:
+		if scalarKind(v.Type().Elem().Kind()) {
+			return false
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			if tooDeep(iter.Value(), depth+1) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if scalarKind(v.Type().Elem().Kind()) {
+			return false
+		}
+		for i := 0; i < v.Len(); i++ {
+			if tooDeep(v.Index(i), depth+1) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if tooDeep(v.Field(i), depth+1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scalarKind reports whether values of kind k cannot nest.
+func scalarKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		return false
+	}
+	return true
+}
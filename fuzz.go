@@ -0,0 +1,74 @@
+package errorlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// FuzzTextFormat is a fuzz harness entry point, in the
+// go-fuzz and OSS-Fuzz convention, that formats an entry
+// built from data with the TextFormatter. It panics if
+// formatting panics or fails, and returns 1 for data that
+// gave a non-empty line.
+func FuzzTextFormat(data []byte) int {
+	f := NewTextFormatter()
+	f.SetDisableColors(true)
+	b, err := f.Format(fuzzEntry(data))
+	if err != nil {
+		panic(fmt.Sprintf("text format error: %v", err))
+	}
+	if len(b) == 0 {
+		return 0
+	}
+	return 1
+}
+
+// FuzzJSONFormat is a fuzz harness entry point like
+// FuzzTextFormat for the JSONFormatter. It also panics if
+// the output is not a single line of valid JSON.
+func FuzzJSONFormat(data []byte) int {
+	b, err := NewJSONFormatter(false).Format(fuzzEntry(data))
+	if err != nil {
+		panic(fmt.Sprintf("json format error: %v", err))
+	}
+	line := bytes.TrimSuffix(b, []byte("\n"))
+	if bytes.IndexByte(line, '\n') >= 0 || !json.Valid(line) {
+		panic(fmt.Sprintf("json format produced invalid output %q", b))
+	}
+	return 1
+}
+
+// fuzzEntry returns an entry with hostile content derived
+// from data: the message, string, byte, and error fields
+// are data itself, and data also selects the level and the
+// depth of a nested field value.
+func fuzzEntry(data []byte) *Entry {
+	s := string(data)
+	entry := &Entry{
+		Message: s,
+		Level:   InfoLevel,
+		Data: Fields{
+			"string": s,
+			"bytes":  data,
+			"error":  errors.New(s),
+			s:        "key",
+		},
+	}
+	if len(data) == 0 {
+		return entry
+	}
+
+	entry.Level = AllLevels[int(data[0])%len(AllLevels)]
+	var nested interface{} = s
+	for i := 0; i < int(data[0]); i++ {
+		nested = []interface{}{nested}
+	}
+	entry.Data["nested"] = nested
+
+	cyclic := map[string]interface{}{}
+	cyclic["self"] = cyclic
+	entry.Data["cyclic"] = cyclic
+	return entry
+}
@@ -0,0 +1,70 @@
+package errorlogger
+
+import (
+	"strings"
+	"testing"
+)
+
+var fuzzSeeds = []string{
+	"",
+	"plain message",
+	"line one\nline two\r\n",
+	"\x1b[2K\x1b[1Afake entry",
+	"\xff\xfe invalid utf-8 \xc3",
+	"\x00\x07\x08 control",
+	strings.Repeat("\xff", 64),
+}
+
+func FuzzTextFormatter(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) { FuzzTextFormat(data) })
+}
+
+func FuzzJSONFormatter(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) { FuzzJSONFormat(data) })
+}
+
+// panicMarshaler panics when formatted as JSON.
+type panicMarshaler struct{}
+
+func (panicMarshaler) MarshalJSON() ([]byte, error) { panic("bad marshaler") }
+
+func TestFormatter_Format_hostile(t *testing.T) {
+	cyclic := map[string]interface{}{}
+	cyclic["self"] = cyclic
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"invalid utf-8", "a\xffb", "a�b"},
+		{"cyclic", cyclic, "nested deeper than"},
+		{"panic", panicMarshaler{}, "format_panic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &Entry{Message: "hostile", Data: Fields{"v": tt.value}}
+			for _, f := range []Formatter{NewJSONFormatter(false), &TextFormatter{}} {
+				b, err := f.Format(entry)
+				if err != nil {
+					t.Fatalf("%T.Format() error = %v", f, err)
+				}
+				if _, ok := f.(*TextFormatter); ok && tt.name == "panic" {
+					continue // fmt does not call MarshalJSON
+				}
+				if !strings.Contains(string(b), tt.want) {
+					t.Errorf("%T.Format() = %q, want %q", f, b, tt.want)
+				}
+			}
+			if entry.Data["v"] == nil || len(entry.Data) != 1 {
+				t.Errorf("Format() changed the entry: %v", entry.Data)
+			}
+		})
+	}
+}
@@ -3,8 +3,19 @@ module github.com/skeptycal/errorlogger
 go 1.18
 
 require (
-	github.com/pkg/errors v0.9.1
 	github.com/sirupsen/logrus v1.8.1
+	go.uber.org/zap v1.24.0
+	golang.org/x/text v0.22.0
+	google.golang.org/grpc v1.50.1
+	google.golang.org/protobuf v1.28.1
 )
 
-require golang.org/x/sys v0.0.0-20220412211240-33da011f77ad // indirect
+require (
+	github.com/golang/protobuf v1.5.2 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	go.uber.org/atomic v1.7.0 // indirect
+	go.uber.org/multierr v1.6.0 // indirect
+	golang.org/x/net v0.35.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
+)
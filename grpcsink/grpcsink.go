@@ -0,0 +1,312 @@
+// Package grpcsink streams the entries of an ErrorLogger to
+// a collector over gRPC:
+//  sink, err := grpcsink.New(grpcsink.Options{Target: "collector:4317", TLS: &tls.Config{}})
+//  log.AddHook(sink)
+//  defer sink.Close(ctx)
+//
+// The package is separate so that programs that do not use
+// gRPC do not link it.
+package grpcsink
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skeptycal/errorlogger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// IngestMethod is the full gRPC method name of the Ingest
+// call of the LogIngest service in proto/errorlogger.proto.
+const IngestMethod = "/errorlogger.v1.LogIngest/Ingest"
+
+// DefaultMaxBackoff is the longest wait between
+// reconnection attempts of a Sink if none is given.
+const DefaultMaxBackoff = 30 * time.Second
+
+type (
+	// Options configures a Sink.
+	Options struct {
+		// Target is the address of the collector, in the
+		// syntax of grpc.Dial.
+		Target string
+
+		// TLS is the TLS configuration of the connection.
+		// A nil TLS connects without transport security,
+		// which should only be used on trusted networks.
+		TLS *tls.Config
+
+		// DialOptions are added to the options of the
+		// connection, e.g. for authentication.
+		DialOptions []grpc.DialOption
+
+		// QueueSize is the number of entries buffered while
+		// the collector is slow or unreachable. The default
+		// is errorlogger.DefaultAsyncQueueSize.
+		QueueSize int
+
+		// Block makes logging calls wait for room in a full
+		// queue instead of dropping the entry.
+		Block bool
+
+		// MaxBackoff is the longest wait between attempts to
+		// reopen a failed stream. The default is
+		// DefaultMaxBackoff.
+		MaxBackoff time.Duration
+	}
+
+	// Sink is a logrus hook that streams entries to a
+	// collector implementing the LogIngest service defined
+	// in proto/errorlogger.proto, for typed ingestion
+	// pipelines instead of parsing JSON. Entries are sent in
+	// the binary format of errorlogger.MarshalBinaryEntry.
+	//
+	// Entries are queued and sent on a background goroutine.
+	// If the stream fails, it is reopened with exponential
+	// backoff and the entry is retried. While the queue is
+	// full, entries are dropped, or logging blocks if
+	// Block is set.
+	Sink struct {
+		conn       *grpc.ClientConn
+		queue      chan entryMessage
+		done       chan struct{}
+		block      bool
+		maxBackoff time.Duration
+		diag       io.Writer // diagnostics on lossy shutdown
+
+		ctx       context.Context
+		cancel    context.CancelFunc
+		closing   chan struct{} // releases blocked calls to Fire
+		closeOnce sync.Once
+
+		mu     sync.RWMutex
+		closed bool
+
+		sent    uint64 // atomic
+		dropped uint64 // atomic
+		retries uint64 // atomic
+	}
+
+	// entryMessage is an encoded Entry message.
+	entryMessage []byte
+
+	// ingestResponse is the IngestResponse message.
+	ingestResponse struct {
+		accepted uint64
+	}
+)
+
+// New returns a new Sink connected to the collector at
+// opts.Target. The connection is established in the
+// background.
+func New(opts Options) (*Sink, error) {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = errorlogger.DefaultAsyncQueueSize
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	creds := insecure.NewCredentials()
+	if opts.TLS != nil {
+		creds = credentials.NewTLS(opts.TLS)
+	}
+	dial := append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, opts.DialOptions...)
+	conn, err := grpc.Dial(opts.Target, dial...)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Sink{
+		conn:       conn,
+		queue:      make(chan entryMessage, opts.QueueSize),
+		done:       make(chan struct{}),
+		block:      opts.Block,
+		maxBackoff: opts.MaxBackoff,
+		diag:       os.Stderr,
+		closing:    make(chan struct{}),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	go s.run()
+	return s, nil
+}
+
+// Levels returns all levels.
+func (s *Sink) Levels() []logrus.Level { return logrus.AllLevels }
+
+// Fire queues entry to be sent. It returns
+// errorlogger.ErrClosed if the sink is closed.
+func (s *Sink) Fire(entry *logrus.Entry) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return errorlogger.ErrClosed
+	}
+
+	r := entryMessage(errorlogger.MarshalBinaryEntry(entry))
+	if s.block {
+		select {
+		case s.queue <- r:
+		case <-s.closing:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+		return nil
+	}
+	select {
+	case s.queue <- r:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+	return nil
+}
+
+func (s *Sink) run() {
+	defer close(s.done)
+	var stream grpc.ClientStream
+	minBackoff := 100 * time.Millisecond
+	if minBackoff > s.maxBackoff {
+		minBackoff = s.maxBackoff
+	}
+	backoff := minBackoff
+	for r := range s.queue {
+		for {
+			if stream == nil {
+				stream, _ = s.conn.NewStream(s.ctx, &grpc.StreamDesc{ClientStreams: true}, IngestMethod, grpc.ForceCodec(binCodec{}))
+			}
+			if stream != nil && stream.SendMsg(&r) == nil {
+				atomic.AddUint64(&s.sent, 1)
+				backoff = minBackoff
+				break
+			}
+
+			// The stream failed: reopen it after a backoff
+			// unless the sink is being abandoned.
+			stream = nil
+			atomic.AddUint64(&s.retries, 1)
+			select {
+			case <-s.ctx.Done():
+				atomic.AddUint64(&s.dropped, 1+uint64(len(s.queue)))
+				for range s.queue {
+				}
+				return
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > s.maxBackoff {
+				backoff = s.maxBackoff
+			}
+		}
+	}
+	if stream != nil {
+		var resp ingestResponse
+		if stream.CloseSend() == nil {
+			_ = stream.RecvMsg(&resp)
+		}
+	}
+}
+
+// Report returns the number of entries sent and dropped so
+// far as a DrainReport. Entries are counted as sent when
+// they are handed to the transport.
+func (s *Sink) Report() errorlogger.DrainReport {
+	return errorlogger.DrainReport{
+		Flushed: atomic.LoadUint64(&s.sent),
+		Dropped: atomic.LoadUint64(&s.dropped),
+	}
+}
+
+// Close stops accepting entries, sends the queued entries
+// until ctx is done, closes the stream and the connection,
+// and returns a report of the entries sent and dropped.
+// Entries still queued when ctx is done are dropped.
+func (s *Sink) Close(ctx context.Context) (errorlogger.DrainReport, error) {
+	// Release calls to Fire blocked on a full queue before
+	// taking the write lock.
+	s.closeOnce.Do(func() { close(s.closing) })
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return s.Report(), errorlogger.ErrClosed
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+
+	var err error
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		s.cancel()
+		<-s.done
+		err = ctx.Err()
+	}
+	s.cancel()
+	s.conn.Close()
+
+	r := s.Report()
+	if r.Dropped > 0 {
+		fmt.Fprintf(s.diag, "errorlogger: grpc sink closed with lost entries: sent=%d dropped=%d\n", r.Flushed, r.Dropped)
+	}
+	return r, err
+}
+
+// binCodec is the gRPC codec of the messages of
+// proto/errorlogger.proto, using the binary format
+// encoding so that no generated code is needed.
+type binCodec struct{}
+
+func (binCodec) Name() string { return "proto" }
+
+func (binCodec) Marshal(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case *entryMessage:
+		return *v, nil
+	case *ingestResponse:
+		var b []byte
+		if v.accepted != 0 {
+			b = protowire.AppendTag(b, 1, protowire.VarintType)
+			b = protowire.AppendVarint(b, v.accepted)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("cannot marshal %T: %w", v, errorlogger.ErrInvalid)
+	}
+}
+
+func (binCodec) Unmarshal(b []byte, v interface{}) error {
+	switch v := v.(type) {
+	case *entryMessage:
+		*v = append((*v)[:0], b...)
+		return nil
+	case *ingestResponse:
+		*v = ingestResponse{}
+		for len(b) > 0 {
+			num, typ, n := protowire.ConsumeTag(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			if num == 1 && typ == protowire.VarintType {
+				v.accepted, n = protowire.ConsumeVarint(b)
+			} else {
+				n = protowire.ConsumeFieldValue(num, typ, b)
+			}
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+		return nil
+	default:
+		return fmt.Errorf("cannot unmarshal %T: %w", v, errorlogger.ErrInvalid)
+	}
+}
@@ -0,0 +1,188 @@
+package grpcsink
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skeptycal/errorlogger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testCollector is a LogIngest server that records the
+// entries it receives.
+type testCollector struct {
+	mu      sync.Mutex
+	entries []*logrus.Entry
+}
+
+func (c *testCollector) ingest(_ interface{}, stream grpc.ServerStream) error {
+	var n uint64
+	for {
+		var m entryMessage
+		err := stream.RecvMsg(&m)
+		if err == io.EOF {
+			return stream.SendMsg(&ingestResponse{accepted: n})
+		}
+		if err != nil {
+			return err
+		}
+		entry, err := errorlogger.UnmarshalBinaryEntry(m)
+		if err != nil {
+			return err
+		}
+		n++
+		c.mu.Lock()
+		c.entries = append(c.entries, entry)
+		c.mu.Unlock()
+	}
+}
+
+func (c *testCollector) messages() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var list []string
+	for _, e := range c.entries {
+		list = append(list, e.Message)
+	}
+	return list
+}
+
+// newTestSink returns a sink connected to a collector
+// through an in-memory listener. The collector is started
+// by calling start.
+func newTestSink(t *testing.T, opts Options) (*Sink, *testCollector, func()) {
+	lis := bufconn.Listen(1 << 20)
+	c := &testCollector{}
+	srv := grpc.NewServer(grpc.ForceServerCodec(binCodec{}), grpc.UnknownServiceHandler(c.ingest))
+	t.Cleanup(srv.Stop)
+
+	var started sync.Once
+	ready := make(chan struct{})
+	start := func() {
+		started.Do(func() {
+			close(ready)
+			go srv.Serve(lis)
+		})
+	}
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) {
+		select {
+		case <-ready:
+			return lis.DialContext(ctx)
+		default:
+			return nil, errors.New("collector down")
+		}
+	}
+
+	opts.Target = "passthrough:///collector"
+	opts.DialOptions = append(opts.DialOptions,
+		grpc.WithContextDialer(dialer),
+		grpc.WithConnectParams(grpc.ConnectParams{Backoff: backoff.Config{BaseDelay: 5 * time.Millisecond, MaxDelay: 10 * time.Millisecond, Multiplier: 1}}),
+	)
+	s, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.diag = io.Discard
+	return s, c, start
+}
+
+func TestSink(t *testing.T) {
+	tests := []struct {
+		name      string
+		startLate bool
+	}{
+		{"connected", false},
+		{"retries until the collector is up", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, c, start := newTestSink(t, Options{MaxBackoff: 10 * time.Millisecond})
+			if !tt.startLate {
+				start()
+			}
+			e := errorlogger.New()
+			e.SetOutput(io.Discard)
+			e.AddHook(s)
+			e.WithField("n", 1).Info("one")
+			e.Warn("two")
+			if tt.startLate {
+				time.Sleep(30 * time.Millisecond)
+				start()
+			}
+			e.Error("three")
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			r, err := s.Close(ctx)
+			if err != nil || r.Flushed != 3 || r.Dropped != 0 {
+				t.Fatalf("Close() = %+v, %v", r, err)
+			}
+			if got := c.messages(); len(got) != 3 || got[0] != "one" || got[2] != "three" {
+				t.Errorf("collector received %v", got)
+			}
+			if c.entries[0].Data["n"] == nil || c.entries[1].Level != logrus.WarnLevel {
+				t.Errorf("collector received %+v", c.entries[:2])
+			}
+			if tt.startLate && s.retries == 0 {
+				t.Error("no retries while the collector was down")
+			}
+			if err := s.Fire(&logrus.Entry{}); err != errorlogger.ErrClosed {
+				t.Errorf("Fire() after Close() = %v, want ErrClosed", err)
+			}
+		})
+	}
+}
+
+func TestSink_backpressure(t *testing.T) {
+	tests := []struct {
+		name  string
+		block bool
+	}{
+		{"drop", false},
+		{"block", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _ := newTestSink(t, Options{QueueSize: 2, Block: tt.block, MaxBackoff: time.Millisecond})
+
+			fired := make(chan struct{})
+			rejected := 0
+			go func() {
+				defer close(fired)
+				for i := 0; i < 10; i++ {
+					if s.Fire(&logrus.Entry{Message: "queued"}) == errorlogger.ErrClosed {
+						rejected++
+					}
+				}
+			}()
+			if tt.block {
+				select {
+				case <-fired:
+					t.Fatal("Fire() did not block on a full queue")
+				case <-time.After(20 * time.Millisecond):
+				}
+			} else {
+				<-fired
+				if s.Report().Dropped < 7 {
+					t.Errorf("Report() = %+v, want at least 7 dropped", s.Report())
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			r, err := s.Close(ctx)
+			<-fired
+			if err != context.DeadlineExceeded || r.Flushed != 0 || r.Dropped+uint64(rejected) != 10 {
+				t.Errorf("Close() = %+v, %v with %d rejected, want 10 lost and a deadline error", r, err, rejected)
+			}
+		})
+	}
+}
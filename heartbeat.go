@@ -0,0 +1,70 @@
+package errorlogger
+
+import (
+	"sync"
+	"time"
+)
+
+// HeartbeatKey is the field that holds the sequence number
+// of a heartbeat entry, starting at 1.
+const HeartbeatKey = "heartbeat"
+
+// processStart is the time the package was initialized,
+// used as the start of the uptime reported by heartbeats.
+var processStart = time.Now()
+
+// EnableHeartbeat logs a compact InfoLevel entry with the
+// uptime of the process and the statistics of the logger
+// every interval until stop is called, so that downstream
+// pipelines can tell a quiet service from broken log
+// shipping. An interval of zero or less defaults to one
+// minute.
+//  stop := log.EnableHeartbeat(30 * time.Second)
+//  defer stop()
+//
+// Heartbeats are only written if InfoLevel is enabled. Stop
+// waits for a heartbeat that is being logged to complete.
+func (e *errorLogger) EnableHeartbeat(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for seq := 1; ; seq++ {
+			select {
+			case t := <-ticker.C:
+				e.heartbeat(seq, t)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		wg.Wait()
+	}
+}
+
+// heartbeat logs heartbeat number seq at time now.
+func (e *errorLogger) heartbeat(seq int, now time.Time) {
+	st := e.Stats()
+	var bytes uint64
+	for _, n := range st.Bytes {
+		bytes += n
+	}
+	e.WithFields(Fields{
+		HeartbeatKey: seq,
+		"uptime":     now.Sub(processStart).Round(time.Second).String(),
+		"errors":     st.Errors,
+		"bytes":      bytes,
+		SessionKey:   sessionID,
+	}).Info("heartbeat")
+}
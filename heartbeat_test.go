@@ -0,0 +1,46 @@
+package errorlogger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorLogger_EnableHeartbeat(t *testing.T) {
+	tests := []struct {
+		name  string
+		level Level
+		want  bool
+	}{
+		{"info", InfoLevel, true},
+		{"warn", WarnLevel, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(tt.level)
+			stop := e.EnableHeartbeat(5 * time.Millisecond)
+			time.Sleep(30 * time.Millisecond)
+			stop()
+			stop()
+
+			out := buf.String()
+			if got := strings.Contains(out, "msg=heartbeat"); got != tt.want {
+				t.Fatalf("heartbeat written = %v, want %v: %q", got, tt.want, out)
+			}
+			if !tt.want {
+				return
+			}
+			for _, want := range []string{"heartbeat=1 ", "heartbeat=2 ", "uptime=", "errors=0", "session=" + SessionID()} {
+				if !strings.Contains(out, want) {
+					t.Errorf("heartbeat = %q, want %q", out, want)
+				}
+			}
+
+			n := strings.Count(out, "\n")
+			time.Sleep(15 * time.Millisecond)
+			if got := strings.Count(buf.String(), "\n"); got != n {
+				t.Errorf("%d heartbeats after stop", got-n)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package errorlogger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	// HookFilter scopes a hook to a subset of entries. A zero
+	// HookFilter passes every entry.
+	HookFilter struct {
+		// Levels are the levels passed to the hook. Nil
+		// passes all levels of the hook.
+		Levels []Level
+
+		// Fields are field values an entry must have to be
+		// passed to the hook, compared in their fmt.Sprint
+		// form.
+		Fields map[string]string
+
+		// Blocklist are the fingerprints of entries that are
+		// not passed to the hook; see Fingerprint.
+		Blocklist []string
+	}
+
+	// FilteredHook is a logrus hook that fires the hook it
+	// wraps only for the entries matching its filter. The
+	// filter may be replaced while the hook is in use.
+	FilteredHook struct {
+		logrus.Hook
+		mu     sync.RWMutex
+		filter HookFilter
+	}
+
+	// hookFilters holds the named filtered hooks of a logger
+	// and the filters configured for them.
+	hookFilters struct {
+		mu      sync.Mutex
+		spec    string
+		filters map[string]HookFilter
+		hooks   map[string][]*FilteredHook
+	}
+)
+
+// FilterHook returns hook wrapped so that it only fires for
+// the entries matching f.
+//  log.AddHook(FilterHook(sentryHook, HookFilter{
+//      Levels: LevelsFrom(ErrorLevel),
+//      Fields: map[string]string{"service": "payments"},
+//  }))
+func FilterHook(hook logrus.Hook, f HookFilter) *FilteredHook {
+	return &FilteredHook{Hook: hook, filter: f}
+}
+
+// Filter returns the filter of the hook.
+func (h *FilteredHook) Filter() HookFilter {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.filter
+}
+
+// SetFilter replaces the filter of the hook.
+func (h *FilteredHook) SetFilter(f HookFilter) {
+	h.mu.Lock()
+	h.filter = f
+	h.mu.Unlock()
+}
+
+// Fire fires the wrapped hook if entry matches the filter.
+func (h *FilteredHook) Fire(entry *Entry) error {
+	if !h.Filter().Match(entry) {
+		return nil
+	}
+	return h.Hook.Fire(entry)
+}
+
+// LevelsFrom returns the levels at least as severe as level,
+// e.g. PanicLevel, FatalLevel, and ErrorLevel for ErrorLevel.
+func LevelsFrom(level Level) []Level {
+	var levels []Level
+	for _, l := range AllLevels {
+		if l <= level {
+			levels = append(levels, l)
+		}
+	}
+	return levels
+}
+
+// Match reports whether entry passes the filter.
+func (f HookFilter) Match(entry *Entry) bool {
+	if f.Levels != nil && !hasLevel(f.Levels, entry.Level) {
+		return false
+	}
+	for k, want := range f.Fields {
+		v, ok := entry.Data[k]
+		if !ok || fmt.Sprint(v) != want {
+			return false
+		}
+	}
+	if len(f.Blocklist) > 0 {
+		fp := Fingerprint(entry)
+		for _, b := range f.Blocklist {
+			if b == fp {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String returns the filter in the form read by
+// ParseHookFilter.
+func (f HookFilter) String() string {
+	var terms []string
+	if f.Levels != nil {
+		terms = append(terms, levelTerm(f.Levels))
+	}
+	keys := make([]string, 0, len(f.Fields))
+	for k := range f.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		terms = append(terms, k+"="+f.Fields[k])
+	}
+	if len(f.Blocklist) > 0 {
+		terms = append(terms, "fingerprint!="+strings.Join(f.Blocklist, ","))
+	}
+	return strings.Join(terms, " ")
+}
+
+// levelTerm returns the level term of a filter string.
+func levelTerm(levels []Level) string {
+	sorted := append([]Level(nil), levels...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	contiguous := len(sorted) > 1 && sorted[0] == PanicLevel
+	for i := 1; i < len(sorted) && contiguous; i++ {
+		contiguous = sorted[i] == sorted[i-1]+1
+	}
+	if contiguous {
+		return "level>=" + sorted[len(sorted)-1].String()
+	}
+	names := make([]string, len(sorted))
+	for i, l := range sorted {
+		names[i] = l.String()
+	}
+	return "level=" + strings.Join(names, ",")
+}
+
+// ParseHookFilter parses a filter of space separated terms:
+//  level>=error          levels at least as severe as error
+//  level=warn,error      only the listed levels
+//  fingerprint!=a1,b2    not the listed fingerprints
+//  service=payments      entries with the field value
+//
+// Field values cannot contain spaces, and the keys level and
+// fingerprint cannot be used as field keys.
+func ParseHookFilter(s string) (HookFilter, error) {
+	var f HookFilter
+	for _, term := range strings.Fields(s) {
+		if strings.HasPrefix(term, "level>=") {
+			level, err := ParseLevel(strings.TrimPrefix(term, "level>="))
+			if err != nil {
+				return HookFilter{}, err
+			}
+			f.Levels = LevelsFrom(level)
+			continue
+		}
+		if strings.HasPrefix(term, "fingerprint!=") {
+			f.Blocklist = append(f.Blocklist, strings.Split(strings.TrimPrefix(term, "fingerprint!="), ",")...)
+			continue
+		}
+
+		k, v, ok := strings.Cut(term, "=")
+		if !ok || k == "" {
+			return HookFilter{}, fmt.Errorf("invalid hook filter term %q: %w", term, ErrInvalid)
+		}
+		if k == "level" {
+			f.Levels = nil
+			for _, name := range strings.Split(v, ",") {
+				level, err := ParseLevel(name)
+				if err != nil {
+					return HookFilter{}, err
+				}
+				f.Levels = append(f.Levels, level)
+			}
+			continue
+		}
+		if f.Fields == nil {
+			f.Fields = make(map[string]string)
+		}
+		f.Fields[k] = v
+	}
+	return f, nil
+}
+
+// ParseHookFilters parses the filters of named hooks, as set
+// by the "hooks" configuration key, separated by semicolons:
+//  sentry: level>=error service=payments; slack: level>=warn fingerprint!=9f3c
+func ParseHookFilters(s string) (map[string]HookFilter, error) {
+	filters := make(map[string]HookFilter)
+	for _, part := range strings.Split(s, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid hook filter %q: want name: filter: %w", strings.TrimSpace(part), ErrInvalid)
+		}
+		f, err := ParseHookFilter(spec)
+		if err != nil {
+			return nil, fmt.Errorf("hook %s: %w", name, err)
+		}
+		filters[name] = f
+	}
+	return filters, nil
+}
+
+// AddFilteredHook adds hook under name, filtered by the
+// filter configured for name by the "hooks" key of the
+// configuration applied with ApplyConfig, so that hooks can
+// be scoped in the configuration file:
+//  hooks: "sentry: level>=error service=payments"
+//
+// Until a filter is configured for name, every entry at the
+// levels of hook is passed. The returned hook may be used to
+// set a filter in code.
+func (e *errorLogger) AddFilteredHook(name string, hook logrus.Hook) *FilteredHook {
+	e.filters.mu.Lock()
+	h := FilterHook(hook, e.filters.filters[name])
+	if e.filters.hooks == nil {
+		e.filters.hooks = make(map[string][]*FilteredHook)
+	}
+	e.filters.hooks[name] = append(e.filters.hooks[name], h)
+	e.filters.mu.Unlock()
+
+	e.AddHook(h)
+	return h
+}
+
+// setHookFilters applies the hook filters in spec, which
+// must be valid, to the named hooks. Hooks without a filter
+// in spec pass every entry.
+func (e *errorLogger) setHookFilters(spec string, src *changeSource) {
+	filters, _ := ParseHookFilters(spec)
+
+	e.filters.mu.Lock()
+	old := e.filters.spec
+	e.filters.spec = spec
+	e.filters.filters = filters
+	for name, hooks := range e.filters.hooks {
+		for _, h := range hooks {
+			h.SetFilter(filters[name])
+		}
+	}
+	e.filters.mu.Unlock()
+
+	e.recordChange(src, "hooks", old, spec)
+}
+
+// hookFilterSpec returns the hook filters last applied.
+func (e *errorLogger) hookFilterSpec() string {
+	e.filters.mu.Lock()
+	defer e.filters.mu.Unlock()
+	return e.filters.spec
+}
+
+var _ logrus.Hook = (*FilteredHook)(nil)
@@ -0,0 +1,118 @@
+package errorlogger
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestHookFilter_Match(t *testing.T) {
+	blocked := &Entry{Level: ErrorLevel, Message: "connection reset 42"}
+	tests := []struct {
+		name   string
+		filter HookFilter
+		entry  *Entry
+		want   bool
+	}{
+		{"zero", HookFilter{}, &Entry{Level: DebugLevel}, true},
+		{"level", HookFilter{Levels: LevelsFrom(ErrorLevel)}, &Entry{Level: ErrorLevel}, true},
+		{"level below", HookFilter{Levels: LevelsFrom(ErrorLevel)}, &Entry{Level: WarnLevel}, false},
+		{"field", HookFilter{Fields: map[string]string{"service": "payments"}}, &Entry{Data: Fields{"service": "payments"}}, true},
+		{"field value", HookFilter{Fields: map[string]string{"code": "42"}}, &Entry{Data: Fields{"code": 42}}, true},
+		{"field other", HookFilter{Fields: map[string]string{"service": "payments"}}, &Entry{Data: Fields{"service": "search"}}, false},
+		{"field missing", HookFilter{Fields: map[string]string{"service": "payments"}}, &Entry{}, false},
+		{"blocklist", HookFilter{Blocklist: []string{Fingerprint(blocked)}}, &Entry{Level: ErrorLevel, Message: "connection reset 7"}, false},
+		{"blocklist other", HookFilter{Blocklist: []string{Fingerprint(blocked)}}, &Entry{Level: ErrorLevel, Message: "disk full"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(tt.entry); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHookFilter(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    HookFilter
+		wantErr bool
+	}{
+		{"", HookFilter{}, false},
+		{"level>=error", HookFilter{Levels: []Level{PanicLevel, FatalLevel, ErrorLevel}}, false},
+		{"level=warn,error", HookFilter{Levels: []Level{WarnLevel, ErrorLevel}}, false},
+		{"level>=error service=payments fingerprint!=a1,b2", HookFilter{
+			Levels:    []Level{PanicLevel, FatalLevel, ErrorLevel},
+			Fields:    map[string]string{"service": "payments"},
+			Blocklist: []string{"a1", "b2"},
+		}, false},
+		{"level>=loud", HookFilter{}, true},
+		{"payments", HookFilter{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseHookFilter(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHookFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseHookFilter() = %+v, want %+v", got, tt.want)
+			}
+			if err == nil {
+				if again, _ := ParseHookFilter(got.String()); again.String() != got.String() {
+					t.Errorf("ParseHookFilter(%q) = %v, want %v", got.String(), again, got)
+				}
+			}
+		})
+	}
+}
+
+func TestParseHookFilters(t *testing.T) {
+	got, err := ParseHookFilters("sentry: level>=error; slack: service=payments;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["sentry"].Levels == nil || got["slack"].Fields["service"] != "payments" {
+		t.Errorf("ParseHookFilters() = %+v", got)
+	}
+	if _, err := ParseHookFilters("level>=error"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("ParseHookFilters() without a name error = %v, want ErrInvalid", err)
+	}
+}
+
+func TestErrorLogger_AddFilteredHook(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	sentry := &countHook{levels: AllLevels}
+	other := &countHook{levels: AllLevels}
+	e.AddFilteredHook("sentry", sentry)
+	e.AddFilteredHook("other", other)
+
+	c := e.Config()
+	c.Hooks = "sentry: level>=error service=payments"
+	if err := e.ApplyConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	if got := e.Config().Hooks; got != c.Hooks {
+		t.Errorf("Config().Hooks = %q, want %q", got, c.Hooks)
+	}
+	if e.output() != buf {
+		t.Errorf("ApplyConfig() replaced the output with %T", e.output())
+	}
+
+	e.WithField("service", "payments").Warn("slow charge")
+	e.WithField("service", "search").Error("index missing")
+	e.WithField("service", "payments").Error("charge failed")
+	if sentry.n != 1 || other.n != 3 {
+		t.Errorf("fires = %d, %d, want 1, 3", sentry.n, other.n)
+	}
+
+	c.Hooks = ""
+	if err := e.ApplyConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	e.Warn("unfiltered")
+	if sentry.n != 2 {
+		t.Errorf("fires after clearing the filter = %d, want 2", sentry.n)
+	}
+}
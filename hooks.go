@@ -0,0 +1,208 @@
+package errorlogger
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultHookSkip is how long a hook that exceeded its
+// latency budget is skipped if HookPolicy.SkipFor is not
+// set.
+const DefaultHookSkip = time.Minute
+
+// slowHookKey marks the diagnostics entries about slow
+// hooks.
+const slowHookKey = "slow_hook"
+
+type (
+	// HookPolicy limits the latency that hooks add to
+	// logging calls, so that one slow Sentry or webhook call
+	// cannot add seconds to every Fatal path.
+	HookPolicy struct {
+		// Budget is the longest a hook may run per entry.
+		// A hook that exceeds it keeps running in the
+		// background, but the logging call continues without
+		// waiting for it. Zero or less disables the policy.
+		Budget time.Duration
+
+		// SkipFor is how long a hook that exceeded the budget
+		// is skipped. The default is DefaultHookSkip.
+		SkipFor time.Duration
+	}
+
+	// HookStats reports the execution time of a hook added
+	// with AddHook.
+	HookStats struct {
+		Name     string // the type of the hook
+		Fires    uint64
+		Total    time.Duration
+		Max      time.Duration
+		Timeouts uint64 // fires that exceeded the budget
+		Skipped  uint64 // entries skipped after a timeout
+	}
+
+	// timedHook measures the execution time of a hook and
+	// enforces the hook policy of the logger.
+	timedHook struct {
+		logrus.Hook
+		name  string
+		e     *errorLogger
+		stats *loggerStats
+
+		fires     uint64 // atomic
+		total     int64  // atomic; nanoseconds
+		max       int64  // atomic; nanoseconds
+		timeouts  uint64 // atomic
+		skipped   uint64 // atomic
+		skipUntil int64  // atomic; Unix nanoseconds
+		stalled   int32  // atomic; timed out fires still running
+		exempt    bool   // not subject to the hook policy
+	}
+)
+
+// The states of a fire within a budget.
+const (
+	fireRunning int32 = iota
+	fireTimedOut
+	fireDone
+)
+
+// timeHook returns hook wrapped in a timedHook whose
+// statistics are reported by Stats.
+func (e *errorLogger) timeHook(hook logrus.Hook) *timedHook {
+	h := &timedHook{Hook: hook, name: fmt.Sprintf("%T", hook), e: e, stats: e.stats}
+	switch hook.(type) {
+	case *CrashReporter, *RingBuffer, *BudgetTracker:
+		// These record the entries that lead to a failure
+		// and must see every one of them.
+		h.exempt = true
+	}
+	if e.stats != nil {
+		e.stats.hooksMu.Lock()
+		e.stats.hooks = append(e.stats.hooks, h)
+		e.stats.hooksMu.Unlock()
+	}
+	return h
+}
+
+// SetHookPolicy sets the latency policy of the hooks added
+// with AddHook.
+//  log.SetHookPolicy(HookPolicy{Budget: 50 * time.Millisecond})
+//
+// A hook that exceeds the budget is skipped for a while and
+// a Warn entry naming it is logged. It is also skipped as
+// long as the fire that exceeded the budget is running.
+// Concurrent fires within the budget are not limited.
+//
+// The hooks added by NewCrashReporter and the RingBuffer
+// and BudgetTracker hooks are exempt from the policy.
+func (e *errorLogger) SetHookPolicy(p HookPolicy) {
+	if e.stats == nil {
+		return
+	}
+	if p.SkipFor <= 0 {
+		p.SkipFor = DefaultHookSkip
+	}
+	atomic.StoreInt64(&e.stats.hookBudget, int64(p.Budget))
+	atomic.StoreInt64(&e.stats.hookSkip, int64(p.SkipFor))
+}
+
+func (h *timedHook) observe(d time.Duration) {
+	atomic.AddUint64(&h.fires, 1)
+	atomic.AddInt64(&h.total, int64(d))
+	for {
+		max := atomic.LoadInt64(&h.max)
+		if int64(d) <= max || atomic.CompareAndSwapInt64(&h.max, max, int64(d)) {
+			return
+		}
+	}
+}
+
+// Fire fires the hook, within the budget of the policy if
+// one is set and the hook is not exempt. Within a budget, the hook fires on a copy of
+// entry whose changes are kept if it completes in time, and
+// a panic in the hook is recovered and returned as an
+// error.
+func (h *timedHook) Fire(entry *Entry) error {
+	var budget time.Duration
+	if h.stats != nil && !h.exempt {
+		budget = time.Duration(atomic.LoadInt64(&h.stats.hookBudget))
+	}
+	start := time.Now()
+	if budget <= 0 {
+		err := h.Hook.Fire(entry)
+		h.observe(time.Since(start))
+		return err
+	}
+
+	if start.UnixNano() < atomic.LoadInt64(&h.skipUntil) || atomic.LoadInt32(&h.stalled) > 0 {
+		atomic.AddUint64(&h.skipped, 1)
+		return nil
+	}
+
+	c := copyEntry(entry)
+	done := make(chan error, 1)
+	state := fireRunning
+	go func() {
+		err := fireSafely(h.Hook, c)
+		h.observe(time.Since(start))
+		if !atomic.CompareAndSwapInt32(&state, fireRunning, fireDone) {
+			atomic.AddInt32(&h.stalled, -1)
+		}
+		done <- err
+	}()
+
+	timer := time.NewTimer(budget)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		*entry = *c
+		return err
+	case <-timer.C:
+	}
+
+	// The fire is stalled unless it completed meanwhile.
+	atomic.AddInt32(&h.stalled, 1)
+	if !atomic.CompareAndSwapInt32(&state, fireRunning, fireTimedOut) {
+		atomic.AddInt32(&h.stalled, -1)
+		*entry = *c
+		return <-done
+	}
+
+	skip := time.Duration(atomic.LoadInt64(&h.stats.hookSkip))
+	atomic.AddUint64(&h.timeouts, 1)
+	atomic.StoreInt64(&h.skipUntil, start.Add(skip).UnixNano())
+	if _, diag := entry.Data[slowHookKey]; !diag {
+		h.e.WithFields(Fields{
+			slowHookKey: h.name,
+			"budget":    budget.String(),
+			"skip_for":  skip.String(),
+		}).Warn("hook exceeded its latency budget and is skipped")
+	}
+	return nil
+}
+
+// hookStats returns the statistics of the hooks added with
+// AddHook.
+func (s *loggerStats) hookStats() []HookStats {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+	if len(s.hooks) == 0 {
+		return nil
+	}
+	list := make([]HookStats, len(s.hooks))
+	for i, h := range s.hooks {
+		list[i] = HookStats{
+			Name:     h.name,
+			Fires:    atomic.LoadUint64(&h.fires),
+			Total:    time.Duration(atomic.LoadInt64(&h.total)),
+			Max:      time.Duration(atomic.LoadInt64(&h.max)),
+			Timeouts: atomic.LoadUint64(&h.timeouts),
+			Skipped:  atomic.LoadUint64(&h.skipped),
+		}
+	}
+	return list
+}
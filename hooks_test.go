@@ -0,0 +1,103 @@
+package errorlogger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sleepHook sleeps for d on every fire and sets a field.
+type sleepHook struct{ d time.Duration }
+
+func (h sleepHook) Levels() []Level { return AllLevels }
+
+func (h sleepHook) Fire(entry *Entry) error {
+	time.Sleep(h.d)
+	entry.Data["hooked"] = true
+	return nil
+}
+
+func TestErrorLogger_AddHook(t *testing.T) {
+	tests := []struct {
+		name         string
+		budget       time.Duration
+		sleep        time.Duration
+		wantTimeouts uint64
+		wantSkipped  uint64
+		wantHooked   bool
+	}{
+		{"no policy", 0, time.Millisecond, 0, 0, true},
+		{"within budget", time.Second, time.Millisecond, 0, 0, true},
+		{"over budget", 10 * time.Millisecond, 200 * time.Millisecond, 1, 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			e.SetHookPolicy(HookPolicy{Budget: tt.budget})
+			e.AddHook(sleepHook{tt.sleep})
+
+			start := time.Now()
+			e.Info("first")
+			e.Info("second")
+			if elapsed := time.Since(start); tt.budget > 0 && elapsed > tt.budget+tt.sleep+100*time.Millisecond {
+				t.Errorf("logging took %v", elapsed)
+			}
+
+			st := e.Stats()
+			if len(st.Hooks) != 1 || st.Hooks[0].Name != "errorlogger.sleepHook" {
+				t.Fatalf("Stats().Hooks = %+v", st.Hooks)
+			}
+			h := st.Hooks[0]
+			if h.Timeouts != tt.wantTimeouts || h.Skipped != tt.wantSkipped {
+				t.Errorf("Stats().Hooks[0] = %+v", h)
+			}
+			if tt.wantTimeouts == 0 && (h.Fires != 2 || h.Max < tt.sleep || h.Total < 2*tt.sleep) {
+				t.Errorf("Stats().Hooks[0] = %+v", h)
+			}
+
+			out := buf.String()
+			if got := strings.Contains(out, "hooked=true"); got != tt.wantHooked {
+				t.Errorf("output = %q, want hook fields %v", out, tt.wantHooked)
+			}
+			if got := strings.Contains(out, "slow_hook=errorlogger.sleepHook"); got != (tt.wantTimeouts > 0) {
+				t.Errorf("output = %q, want slow hook diagnostics %v", out, tt.wantTimeouts > 0)
+			}
+		})
+	}
+}
+
+func TestErrorLogger_AddHook_concurrent(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	e.SetOutput(&syncBuffer{})
+	e.SetHookPolicy(HookPolicy{Budget: time.Second})
+	e.AddHook(sleepHook{20 * time.Millisecond})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.Info("concurrent")
+		}()
+	}
+	wg.Wait()
+
+	h := e.Stats().Hooks[0]
+	if h.Fires != 8 || h.Skipped != 0 || h.Timeouts != 0 {
+		t.Errorf("Stats().Hooks[0] = %+v, want 8 fires and none skipped", h)
+	}
+}
+
+func TestErrorLogger_AddHook_exempt(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	e.SetHookPolicy(HookPolicy{Budget: time.Nanosecond})
+	ring := NewRingBuffer(4)
+	e.AddHook(ring)
+
+	e.Info("first")
+	e.Info("second")
+	if h := e.Stats().Hooks[0]; h.Fires != 2 || h.Skipped != 0 || h.Timeouts != 0 {
+		t.Errorf("Stats().Hooks[0] = %+v, want the ring buffer exempt", h)
+	}
+}
@@ -0,0 +1,115 @@
+package errorlogger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// MaxBodySnippet is the maximum number of bytes of a response
+// body captured by CheckResponse.
+var MaxBodySnippet = 1024
+
+// HTTPError is returned by CheckResponse for responses with
+// a non-2xx status code.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Method     string
+	URL        string
+
+	// Body is a bounded snippet of the response body.
+	Body string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Method, e.URL, e.Status)
+}
+
+// Fields returns the structured fields logged with the error.
+func (e *HTTPError) Fields() Fields {
+	return Fields{
+		"status": e.StatusCode,
+		"method": e.Method,
+		"url":    e.URL,
+		"body":   e.Body,
+	}
+}
+
+// Kind returns the error Kind that corresponds to the status.
+func (e *HTTPError) Kind() Kind {
+	switch e.StatusCode {
+	case http.StatusNotFound, http.StatusGone:
+		return KindNotFound
+	case http.StatusConflict:
+		return KindAlreadyExists
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return KindPermissionDenied
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return KindInvalid
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return KindTimeout
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return KindUnavailable
+	default:
+		return KindInternal
+	}
+}
+
+// CheckResponse standardizes outbound API error handling. It
+// logs transport errors and non-2xx responses and returns:
+//
+// - err, if it is not nil (the transport failed)
+//
+// - an *HTTPError, if the status code is not 2xx
+//
+// - nil, otherwise.
+//
+// The first MaxBodySnippet bytes of the body of a failed
+// response are captured in the error. The body remains
+// readable in full by the caller, who still closes it:
+//  resp, err := client.Do(req)
+//  if err = log.CheckResponse(resp, err); err != nil {
+//  	if resp != nil {
+//  		resp.Body.Close()
+//  	}
+//  	return err
+//  }
+//  defer resp.Body.Close()
+func (e *errorLogger) CheckResponse(resp *http.Response, err error) error {
+	if err != nil {
+		return e.Err(err)
+	}
+	if resp == nil {
+		return e.Err(fmt.Errorf("nil http response: %w", ErrInvalid))
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	httpErr := &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+	if resp.Request != nil {
+		httpErr.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			httpErr.URL = resp.Request.URL.Redacted()
+		}
+	}
+	if resp.Body != nil {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, int64(MaxBodySnippet)))
+		httpErr.Body = strings.TrimSpace(string(snippet))
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(snippet), resp.Body), resp.Body}
+	}
+	return e.Err(httpErr)
+}
+
+// readCloser combines a Reader with the Closer of the
+// original body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
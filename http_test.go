@@ -0,0 +1,59 @@
+package errorlogger
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_errorLogger_CheckResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ok" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, strings.Repeat("x", 2000))
+	}))
+	defer srv.Close()
+
+	e, buf := newBufferLogger(InfoLevel)
+
+	if err := e.CheckResponse(http.Get(srv.URL + "/ok")); err != nil {
+		t.Errorf("CheckResponse() 200 = %v, want nil", err)
+	}
+
+	resp, err := http.Get(srv.URL + "/missing")
+	err = e.CheckResponse(resp, err)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("CheckResponse() 404 = %T, want *HTTPError", err)
+	}
+	if httpErr.StatusCode != http.StatusNotFound || httpErr.Method != http.MethodGet {
+		t.Errorf("CheckResponse() 404 = %+v", httpErr)
+	}
+	if len(httpErr.Body) != MaxBodySnippet {
+		t.Errorf("CheckResponse() body snippet length = %d, want %d", len(httpErr.Body), MaxBodySnippet)
+	}
+	if got := Classify(err); got != KindNotFound {
+		t.Errorf("Classify(HTTPError 404) = %v, want %v", got, KindNotFound)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if len(body) != 2000 {
+		t.Errorf("CheckResponse() remaining body length = %d, want 2000", len(body))
+	}
+	if !strings.Contains(buf.String(), "status=404") {
+		t.Errorf("CheckResponse() output = %q, want status field", buf.String())
+	}
+
+	if err := e.CheckResponse(nil, errFake); err != errFake {
+		t.Errorf("CheckResponse() transport error = %v, want %v", err, errFake)
+	}
+	if err := e.CheckResponse(nil, nil); !errors.Is(err, ErrInvalid) {
+		t.Errorf("CheckResponse(nil, nil) = %v, want ErrInvalid", err)
+	}
+}
@@ -0,0 +1,114 @@
+package errorlogger
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// messages is the catalog of translated operator facing
+// messages, keyed by msgid or error code.
+var messages = catalog.NewBuilder(catalog.Fallback(language.English))
+
+// SetMessage adds the translation text of the message with
+// the given msgid (see Msg) or error code (see NewSentinel)
+// for the language lang, a BCP 47 tag such as "de" or
+// "pt-BR". For msgids, text is a format string for the same
+// arguments as the original message.
+//  errorlogger.SetMessage("de", "cache.miss", "Cache-Fehltreffer für Schlüssel %q")
+func SetMessage(lang, id, text string) error {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return err
+	}
+	return messages.SetString(tag, id, text)
+}
+
+// LocaleFromEnv returns the operator's locale as a BCP 47
+// tag from LC_ALL, LC_MESSAGES, or LANG, in that order,
+// e.g. "de-DE" for LANG=de_DE.UTF-8. It returns "" if none
+// is set or the locale is "C" or "POSIX".
+func LocaleFromEnv() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		if i := strings.IndexAny(v, ".@"); i >= 0 {
+			v = v[:i]
+		}
+		if v == "C" || v == "POSIX" {
+			return ""
+		}
+		return strings.ReplaceAll(v, "_", "-")
+	}
+	return ""
+}
+
+// LocalizedFormatter renders the messages of entries with a
+// registered translation in the operator's locale and
+// formats them with the wrapped formatter, intended for
+// text output read by people:
+//  f, err := NewLocalizedFormatter(NewTextFormatter(), LocaleFromEnv())
+//  log.SetFormatter(f)
+//
+// An entry is translated if it has a "msgid" field (see
+// Msg) or a "code" field (see CodeEnricher) with a message
+// in the catalog. Other entries are unchanged. Machine
+// readable output such as the JSON formatter should not be
+// localized; it keeps the canonical English message and
+// the msgid.
+type LocalizedFormatter struct {
+	Formatter
+	printer *message.Printer
+}
+
+// NewLocalizedFormatter returns a LocalizedFormatter that
+// wraps f and translates into lang. An empty lang selects
+// English, which leaves messages unchanged unless English
+// translations are registered.
+func NewLocalizedFormatter(f Formatter, lang string) (*LocalizedFormatter, error) {
+	tag := language.English
+	if lang != "" {
+		var err error
+		if tag, err = language.Parse(lang); err != nil {
+			return nil, err
+		}
+	}
+	return &LocalizedFormatter{
+		Formatter: f,
+		printer:   message.NewPrinter(tag, message.Catalog(messages)),
+	}, nil
+}
+
+// Format translates the message of entry, if possible, and
+// formats it with the wrapped formatter.
+func (f *LocalizedFormatter) Format(entry *Entry) ([]byte, error) {
+	if msg, ok := f.translate(entry); ok {
+		dup := *entry
+		dup.Message = msg
+		return f.Formatter.Format(&dup)
+	}
+	return f.Formatter.Format(entry)
+}
+
+func (f *LocalizedFormatter) translate(entry *Entry) (string, bool) {
+	for _, key := range []string{MsgIDKey, CodeKey} {
+		id, ok := entry.Data[key].(string)
+		if !ok || id == "" {
+			continue
+		}
+		// A missing message is rendered as its key.
+		if f.printer.Sprintf(id) == id {
+			continue
+		}
+		if key == MsgIDKey {
+			return f.printer.Sprintf(id, msgArgs(entry)...), true
+		}
+		return f.printer.Sprintf(id), true
+	}
+	return "", false
+}
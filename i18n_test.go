@@ -0,0 +1,76 @@
+package errorlogger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLocaleFromEnv(t *testing.T) {
+	tests := []struct {
+		name                string
+		lcAll, lcMsgs, lang string
+		want                string
+	}{
+		{"unset", "", "", "", ""},
+		{"LANG", "", "", "de_DE.UTF-8", "de-DE"},
+		{"LC_MESSAGES", "", "fr_FR", "de_DE.UTF-8", "fr-FR"},
+		{"LC_ALL", "pt_BR.UTF-8@euro", "fr_FR", "de_DE", "pt-BR"},
+		{"POSIX", "C", "", "de_DE", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_MESSAGES", tt.lcMsgs)
+			t.Setenv("LANG", tt.lang)
+			if got := LocaleFromEnv(); got != tt.want {
+				t.Errorf("LocaleFromEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalizedFormatter(t *testing.T) {
+	if err := SetMessage("de", "test.cache.miss", "Cache-Fehltreffer für %q"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetMessage("de", "ETEST_I18N", "Konfiguration fehlt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetMessage("x-invalid-tag-", "id", "text"); err == nil {
+		t.Error("SetMessage() with an invalid tag succeeded")
+	}
+	errNoConfig := NewSentinel("ETEST_I18N", "no configuration")
+
+	tests := []struct {
+		name string
+		lang string
+		json bool
+		log  func(e *errorLogger)
+		want string
+	}{
+		{"msgid de", "de-CH", false, func(e *errorLogger) { e.Msg("test.cache.miss", "cache miss for %q").Warn("k") }, `msg="Cache-Fehltreffer für \"k\""`},
+		{"msgid en", "en", false, func(e *errorLogger) { e.Msg("test.cache.miss", "cache miss for %q").Warn("k") }, `msg="cache miss for \"k\""`},
+		{"code de", "de", false, func(e *errorLogger) { _ = e.Err(errNoConfig) }, `msg="Konfiguration fehlt" code=ETEST_I18N`},
+		{"untranslated", "de", false, func(e *errorLogger) { e.Msg("test.other", "other").Info() }, "msg=other"},
+		{"json stays English", "", true, func(e *errorLogger) { e.Msg("test.cache.miss", "cache miss for %q").Warn("k") }, `"msg":"cache miss for \"k\"","msgid":"test.cache.miss"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			e.AddEnricher(CodeEnricher)
+			if tt.json {
+				e.SetJSON(false)
+			} else {
+				f, err := NewLocalizedFormatter(&TextFormatter{}, tt.lang)
+				if err != nil {
+					t.Fatal(err)
+				}
+				e.SetFormatter(f)
+			}
+			tt.log(e)
+			if got := buf.String(); !strings.Contains(got, tt.want) {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,11 @@
 package errorlogger
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"sync"
 
-	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
@@ -37,12 +37,14 @@ var (
 
 func newTestStruct(enabled bool, msg string, wrap error, _ func(args ...interface{}), logger *Logger) *errorLogger {
 	if logger == nil {
-		logger = defaultlogger
+		logger = defaultLogger()
 	}
+	logger = ownLogger(logger)
 
 	e := errorLogger{
 		msg:    msg,
 		Logger: logger,
+		stats:  newLoggerStats(),
 	}
 
 	if enabled {
@@ -55,12 +57,10 @@ func newTestStruct(enabled bool, msg string, wrap error, _ func(args ...interfac
 	e.logFunc = e.Error
 
 	if wrap == nil {
-		// the defaultErrWrap is actually nil ... so this is not needed.
-		// However, if the default is later changed to a package-wide
-		// wrapper, this will be a valid check
-		wrap = defaultErrWrap
+		wrap = DefaultWrap()
 	}
 	e.wrap = wrap
+	e.installPipeline()
 	return &e
 }
 
@@ -0,0 +1,50 @@
+// Package wraperr implements the error wrapping of
+// SetErrorWrap for the loggers of the tiny and zaplog
+// packages, which import neither logrus nor the errorlogger
+// package.
+package wraperr
+
+import "errors"
+
+// Error is an error wrapped by the error set with
+// SetErrorWrap. It satisfies errors.Is and errors.As for
+// both the wrap and the original error.
+type Error struct {
+	err  error
+	wrap error
+}
+
+// New returns err wrapped by wrap.
+func New(err, wrap error) error {
+	return &Error{err: err, wrap: wrap}
+}
+
+// Error returns the message of the wrap followed by that of
+// the original error. A wrap without a message, such as
+// &os.PathError{}, adds nothing.
+func (w *Error) Error() string {
+	if msg := Message(w.wrap); msg != "" {
+		return msg + ": " + w.err.Error()
+	}
+	return w.err.Error()
+}
+
+// Unwrap returns the original error.
+func (w *Error) Unwrap() error { return w.err }
+
+// Is reports whether the wrap matches target.
+func (w *Error) Is(target error) bool { return errors.Is(w.wrap, target) }
+
+// As sets target to the wrap if it can be assigned to it.
+func (w *Error) As(target interface{}) bool { return errors.As(w.wrap, target) }
+
+// Message returns err.Error(), or "" if it panics, as the
+// Error methods of zero values such as &os.PathError{} do.
+func Message(err error) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = ""
+		}
+	}()
+	return err.Error()
+}
@@ -1,7 +1,10 @@
 package errorlogger
 
 import (
+	"bytes"
+	"encoding/json"
 	"runtime"
+	"sort"
 
 	"github.com/sirupsen/logrus"
 )
@@ -9,14 +12,21 @@ import (
 // JSONFormatter formats logs into parsable json.
 // It is composed of logrus.JSONFormatter with additional
 // formatting methods.
-type JSONFormatter struct{ logrus.JSONFormatter }
+type JSONFormatter struct {
+	logrus.JSONFormatter
+
+	// Options set the indentation, line prefix, and width of
+	// pretty printed entries, and the order of keys. The
+	// zero Options format entries as logrus.JSONFormatter.
+	Options Options
+}
 
 // NewJSONFormatter returns a new Formatter that
 // is initialized and ready to use.
 //
 // For pretty printing, set pretty == true.
 func NewJSONFormatter(pretty bool) *JSONFormatter {
-	f := &JSONFormatter{logrus.JSONFormatter{}}
+	f := &JSONFormatter{}
 	f.SetPrettyPrint(pretty)
 	return f
 }
@@ -77,3 +87,93 @@ func (f *JSONFormatter) SetCallerPrettyfier(fn func(*runtime.Frame) (function st
 func (f *JSONFormatter) SetPrettyPrint(pretty bool) {
 	f.PrettyPrint = pretty
 }
+
+// SetOptions sets the options of the formatter.
+func (f *JSONFormatter) SetOptions(o Options) {
+	f.Options = o
+}
+
+// formatOptions formats entry as compact JSON with
+// logrus.JSONFormatter and then lays it out with the
+// options of the formatter.
+func (f *JSONFormatter) formatOptions(entry *Entry) ([]byte, error) {
+	jf := f.JSONFormatter
+	jf.PrettyPrint = false
+	b, err := jf.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	b = bytes.TrimRight(b, "\n")
+
+	if f.Options.SortKeys {
+		if b, err = f.orderKeys(b); err != nil {
+			return nil, err
+		}
+	}
+	if !f.PrettyPrint {
+		return append(b, '\n'), nil
+	}
+
+	o := f.Options.withDefaults()
+	if len(o.Prefix)+len(b) <= o.Width {
+		return append(append([]byte(o.Prefix), b...), '\n'), nil
+	}
+	buf := bytes.NewBufferString(o.Prefix)
+	if err := json.Indent(buf, b, o.Prefix, o.Indent); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// orderKeys returns the JSON object b with the time, level,
+// and message keys first, followed by the other keys in
+// sorted order.
+func (f *JSONFormatter) orderKeys(b []byte) ([]byte, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+	first := []string{logrus.FieldKeyTime, logrus.FieldKeyLevel, logrus.FieldKeyMsg}
+	if k, ok := f.FieldMap[logrus.FieldKeyTime]; ok {
+		first[0] = k
+	}
+	if k, ok := f.FieldMap[logrus.FieldKeyLevel]; ok {
+		first[1] = k
+	}
+	if k, ok := f.FieldMap[logrus.FieldKeyMsg]; ok {
+		first[2] = k
+	}
+	keys := make([]string, 0, len(obj))
+	for _, k := range first {
+		if _, ok := obj[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	rest := make([]string, 0, len(obj))
+	for k := range obj {
+		if k != first[0] && k != first[1] && k != first[2] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	keys = append(keys, rest...)
+
+	out := bytes.NewBuffer(make([]byte, 0, len(b)))
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(!f.DisableHTMLEscape)
+	out.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		if err := enc.Encode(k); err != nil {
+			return nil, err
+		}
+		out.Truncate(out.Len() - 1) // newline written by Encode
+		out.WriteByte(':')
+		out.Write(obj[k])
+	}
+	out.WriteByte('}')
+	return out.Bytes(), nil
+}
@@ -71,10 +71,10 @@ var (
 		"baz": fmt.Errorf("qux"),
 	}
 
-	sampleTextFormatter        = &TextFormatter{logrus.TextFormatter{DisableColors: true}}
-	sampleColoredTextFormatter = &TextFormatter{logrus.TextFormatter{ForceColors: true}}
-	sampleJSONFormatter        = &JSONFormatter{logrus.JSONFormatter{PrettyPrint: false}}
-	sampleJSONPrettyFormatter  = &JSONFormatter{logrus.JSONFormatter{PrettyPrint: true}}
+	sampleTextFormatter        = &TextFormatter{TextFormatter: logrus.TextFormatter{DisableColors: true}}
+	sampleColoredTextFormatter = &TextFormatter{TextFormatter: logrus.TextFormatter{ForceColors: true}}
+	sampleJSONFormatter        = &JSONFormatter{JSONFormatter: logrus.JSONFormatter{PrettyPrint: false}}
+	sampleJSONPrettyFormatter  = &JSONFormatter{JSONFormatter: logrus.JSONFormatter{PrettyPrint: true}}
 
 	formatterTests = []struct {
 		name   string
@@ -128,7 +128,7 @@ func TestNewJSONFormatter(t *testing.T) {
 		pretty bool
 		want   Formatter
 	}{
-		{"new default JSON formatter", true, &JSONFormatter{logrus.JSONFormatter{PrettyPrint: true}}},
+		{"new default JSON formatter", true, &JSONFormatter{JSONFormatter: logrus.JSONFormatter{PrettyPrint: true}}},
 	}
 	log.SetOutput(Discard)
 	for _, tt := range tests {
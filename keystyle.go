@@ -0,0 +1,212 @@
+package errorlogger
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+)
+
+// KeyStyle is a naming convention for field keys.
+type KeyStyle int32
+
+const (
+	// KeepKeys leaves field keys as they are.
+	KeepKeys KeyStyle = iota
+
+	// SnakeCase keys are lower case words separated by
+	// underscores, e.g. user_id.
+	SnakeCase
+
+	// CamelCase keys are words joined with the first letter
+	// of every word but the first in upper case, e.g. userId.
+	CamelCase
+)
+
+// keyStyleKey marks the warnings logged about keys that do
+// not follow the key style, so that they are not checked
+// themselves.
+const keyStyleKey = "key_style"
+
+// ownKeys are the keys of the fields added by this package
+// that consist of several words. They are neither renamed
+// nor warned about by normalizeKeys, so that the hooks of
+// the package, such as the CrashReporter, still find them
+// and users are not asked to fix keys they do not write.
+var ownKeys = map[string]struct{}{
+	CtxCauseKey:              {},
+	CtxDeadlineKey:           {},
+	CtxExpiredAgoKey:         {},
+	ErrFingerprintKey:        {},
+	ExitCodeKey:              {},
+	FatalCallerKey:           {},
+	RuntimeGCCountKey:        {},
+	RuntimeGCPauseKey:        {},
+	RuntimeMemoryLimitKey:    {},
+	RuntimeMemoryPressureKey: {},
+	SchemaErrorKey:           {},
+	ServiceStateKey:          {},
+	WrapAuditKey:             {},
+	formatPanicKey:           {},
+	keyStyleKey:              {},
+	piiKey:                   {},
+	slowHookKey:              {},
+	"attachment_error":       {},
+	"attachment_path":        {},
+	"attachment_sha256":      {},
+	"attachment_size":        {},
+	"call_site":              {},
+	"crash_report":           {},
+	"diff_name":              {},
+	"docs_url":               {},
+	"entry_rate":             {},
+	"err_latency":            {},
+	"from_type":              {},
+	"last_write":             {},
+	"log_file":               {},
+	"new_path":               {},
+	"notify_error":           {},
+	"old_path":               {},
+	"panic_type":             {},
+	"pii_field":              {},
+	"skip_for":               {},
+	"to_type":                {},
+	"warn_once":              {},
+}
+
+// keyStyleWarned records the call sites and keys that were
+// already warned about.
+var keyStyleWarned sync.Map
+
+func (s KeyStyle) String() string {
+	switch s {
+	case SnakeCase:
+		return "snake_case"
+	case CamelCase:
+		return "camelCase"
+	}
+	return "keep"
+}
+
+// NormalizeKey returns key in style. Words are separated by
+// underscores, hyphens, spaces, and changes of case, so
+// that "userID", "user-id", and "UserId" are all "user_id"
+// in SnakeCase. Dots separate the segments of hierarchical
+// keys such as "http.statusCode", which are normalized
+// separately.
+func NormalizeKey(key string, style KeyStyle) string {
+	if style == KeepKeys {
+		return key
+	}
+	segments := strings.Split(key, ".")
+	for i, seg := range segments {
+		words := keyWords(seg)
+		if len(words) == 0 {
+			continue
+		}
+		if style == SnakeCase {
+			segments[i] = strings.Join(words, "_")
+			continue
+		}
+		for j := 1; j < len(words); j++ {
+			r := []rune(words[j])
+			r[0] = unicode.ToUpper(r[0])
+			words[j] = string(r)
+		}
+		segments[i] = strings.Join(words, "")
+	}
+	return strings.Join(segments, ".")
+}
+
+// keyWords splits s into lower case words.
+func keyWords(s string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	r := []rune(s)
+	for i, c := range r {
+		switch {
+		case c == '_' || c == '-' || c == ' ':
+			flush()
+			continue
+		case unicode.IsUpper(c) && i > 0:
+			prev := r[i-1]
+			nextLower := i+1 < len(r) && unicode.IsLower(r[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextLower {
+				flush()
+			}
+		}
+		word = append(word, c)
+	}
+	flush()
+	return words
+}
+
+// SetKeyStyle sets the naming convention that the keys of
+// all fields are normalized to before entries are
+// formatted, so that code written by different teams
+// produces consistent field names:
+//  log.SetKeyStyle(errorlogger.SnakeCase)
+//
+// The first time a call site logs a key that does not
+// follow the style, a warning with the call site is logged
+// so that the code can be fixed. The keys of the fields
+// added by this package, such as "err_fingerprint", are
+// left as they are. KeepKeys turns normalization off.
+func (e *errorLogger) SetKeyStyle(style KeyStyle) {
+	atomic.StoreInt32((*int32)(&e.keyStyle), int32(style))
+}
+
+// normalizeKeys renames the fields of entry to the key style
+// of the logger, except those in ownKeys. A field is not
+// renamed if the entry already has a field with the
+// normalized key.
+func (e *errorLogger) normalizeKeys(entry *Entry) {
+	style := KeyStyle(atomic.LoadInt32((*int32)(&e.keyStyle)))
+	if style == KeepKeys {
+		return
+	}
+	if _, ok := entry.Data[keyStyleKey]; ok {
+		return
+	}
+
+	var site string
+	var renames map[string]string
+	for k := range entry.Data {
+		if _, ok := ownKeys[k]; ok {
+			continue
+		}
+		norm := NormalizeKey(k, style)
+		if norm == k {
+			continue
+		}
+		if renames == nil {
+			renames = make(map[string]string)
+		}
+		renames[k] = norm
+
+		if site == "" {
+			site = callSite()
+		}
+		if _, loaded := keyStyleWarned.LoadOrStore(site+"\x00"+k, struct{}{}); !loaded {
+			e.WithFields(Fields{
+				keyStyleKey: style.String(),
+				"key":       k,
+				"call_site": site,
+			}).Warnf("field key %q does not follow the key style; use %q", k, norm)
+		}
+	}
+	for k, norm := range renames {
+		if _, exists := entry.Data[norm]; exists {
+			continue
+		}
+		entry.Data[norm] = entry.Data[k]
+		delete(entry.Data, k)
+	}
+}
@@ -0,0 +1,97 @@
+package errorlogger
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		key   string
+		snake string
+		camel string
+	}{
+		{"user_id", "user_id", "userId"},
+		{"userID", "user_id", "userId"},
+		{"UserId", "user_id", "userId"},
+		{"user-id", "user_id", "userId"},
+		{"HTTPServer", "http_server", "httpServer"},
+		{"address2City", "address2_city", "address2City"},
+		{"http.statusCode", "http.status_code", "http.statusCode"},
+		{"msg", "msg", "msg"},
+		{"_", "_", "_"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := NormalizeKey(tt.key, SnakeCase); got != tt.snake {
+				t.Errorf("NormalizeKey(%q, SnakeCase) = %q, want %q", tt.key, got, tt.snake)
+			}
+			if got := NormalizeKey(tt.key, CamelCase); got != tt.camel {
+				t.Errorf("NormalizeKey(%q, CamelCase) = %q, want %q", tt.key, got, tt.camel)
+			}
+			if got := NormalizeKey(tt.key, KeepKeys); got != tt.key {
+				t.Errorf("NormalizeKey(%q, KeepKeys) = %q", tt.key, got)
+			}
+		})
+	}
+}
+
+func TestErrorLogger_SetKeyStyle(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.SetKeyStyle(SnakeCase)
+
+	for i := 0; i < 2; i++ {
+		e.WithFields(Fields{"userID": 1, "request_id": "r1"}).Info("login")
+	}
+	e.WithFields(Fields{"orderID": 2, "order_id": 3}).Info("collision")
+
+	out := buf.String()
+	if n := strings.Count(out, `does not follow the key style`); n != 2 {
+		t.Errorf("%d key style warnings, want 2 (once per call site and key):\n%s", n, out)
+	}
+	if !strings.Contains(out, "call_site=") || !strings.Contains(out, "keystyle_test.go") {
+		t.Errorf("warning does not name the call site:\n%s", out)
+	}
+	if n := strings.Count(out, "user_id=1"); n != 2 {
+		t.Errorf("user_id=1 logged %d times, want 2:\n%s", n, out)
+	}
+	if !strings.Contains(out, "orderID=2 order_id=3") {
+		t.Errorf("a colliding key was overwritten:\n%s", out)
+	}
+
+	buf.Reset()
+	e.SetKeyStyle(KeepKeys)
+	e.WithField("userID", 1).Info("login")
+	if !strings.Contains(buf.String(), "userID=1") || strings.Contains(buf.String(), "key_style") {
+		t.Errorf("KeepKeys output = %q", buf.String())
+	}
+}
+
+func TestErrorLogger_SetKeyStyle_ownKeys(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.SetKeyStyle(CamelCase)
+	c := e.EnableCrashReports(CrashReportOptions{Dir: t.TempDir()})
+	c.diag = io.Discard
+	e.SetDedupWindow(time.Hour)
+	defer e.SetDedupWindow(0)
+
+	_ = e.LogPanic("boom")
+	e.Err(errFake)
+
+	files, _ := filepath.Glob(filepath.Join(c.dir, "crash-*.json"))
+	if len(files) != 1 {
+		t.Errorf("crash reports = %v, want 1", files)
+	}
+	out := buf.String()
+	for _, want := range []string{"panic_type=", ErrFingerprintKey + "="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+	}
+	if strings.Contains(out, "does not follow the key style") {
+		t.Errorf("key style warning for a key of the package:\n%s", out)
+	}
+}
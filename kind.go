@@ -0,0 +1,130 @@
+package errorlogger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// Kind is a coarse classification of an error, used to
+// group errors in logs, map them to HTTP statuses or exit
+// codes, and drive metrics.
+type Kind int
+
+// These are the error kinds returned by Classify.
+const (
+	KindUnknown Kind = iota
+	KindNotFound
+	KindAlreadyExists
+	KindPermissionDenied
+	KindInvalid
+	KindTimeout
+	KindCanceled
+	KindUnavailable
+	KindInternal
+)
+
+var kindNames = [...]string{
+	KindUnknown:          "unknown",
+	KindNotFound:         "not_found",
+	KindAlreadyExists:    "already_exists",
+	KindPermissionDenied: "permission_denied",
+	KindInvalid:          "invalid",
+	KindTimeout:          "timeout",
+	KindCanceled:         "canceled",
+	KindUnavailable:      "unavailable",
+	KindInternal:         "internal",
+}
+
+// String returns the snake_case name of the kind.
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "Kind(" + strconv.Itoa(int(k)) + ")"
+	}
+	return kindNames[k]
+}
+
+// HTTPStatus returns the HTTP status code that corresponds
+// to the kind.
+func (k Kind) HTTPStatus() int {
+	switch k {
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindAlreadyExists:
+		return http.StatusConflict
+	case KindPermissionDenied:
+		return http.StatusForbidden
+	case KindInvalid:
+		return http.StatusBadRequest
+	case KindTimeout:
+		return http.StatusGatewayTimeout
+	case KindCanceled:
+		return 499 // client closed request
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Classify returns the Kind of err by checking the error
+// chain with errors.Is and errors.As against the standard
+// library, net, and os error values and types as well as
+// the copies in this package.
+//
+// An error in the chain with a Kind() Kind method, such as
+// *HTTPError, classifies itself.
+//
+// A nil error is KindUnknown; an error that matches nothing
+// is KindInternal.
+func Classify(err error) Kind {
+	if err == nil {
+		return KindUnknown
+	}
+
+	var kinder interface{ Kind() Kind }
+	if errors.As(err, &kinder) {
+		return kinder.Kind()
+	}
+
+	var timeoutErr interface{ Timeout() bool }
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var numErr *strconv.NumError
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return KindCanceled
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &timeoutErr) && timeoutErr.Timeout():
+		return KindTimeout
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, ErrNotExist):
+		return KindNotFound
+	case errors.Is(err, fs.ErrExist), errors.Is(err, ErrExist):
+		return KindAlreadyExists
+	case errors.Is(err, fs.ErrPermission), errors.Is(err, ErrPermission):
+		return KindPermissionDenied
+	case errors.Is(err, fs.ErrInvalid), errors.Is(err, ErrInvalid),
+		errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.As(err, &numErr):
+		return KindInvalid
+	case errors.Is(err, fs.ErrClosed), errors.Is(err, ErrClosed), errors.Is(err, net.ErrClosed),
+		errors.As(err, &opErr), errors.As(err, &dnsErr):
+		return KindUnavailable
+	default:
+		return KindInternal
+	}
+}
+
+// KindEnricher is an Enricher that adds the Kind of an error
+// as the "kind" field.
+func KindEnricher(err error, fields Fields) {
+	fields["kind"] = Classify(err).String()
+}
@@ -0,0 +1,82 @@
+package errorlogger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	var syntaxErr error = json.Unmarshal([]byte("{"), &struct{}{})
+	tests := []struct {
+		name string
+		err  error
+		want Kind
+	}{
+		{"nil", nil, KindUnknown},
+		{"canceled", context.Canceled, KindCanceled},
+		{"deadline", fmt.Errorf("call: %w", context.DeadlineExceeded), KindTimeout},
+		{"os deadline", os.ErrDeadlineExceeded, KindTimeout},
+		{"not exist", &fs.PathError{Op: "open", Path: "x", Err: fs.ErrNotExist}, KindNotFound},
+		{"package not exist", ErrNotExist, KindNotFound},
+		{"exist", fs.ErrExist, KindAlreadyExists},
+		{"permission", fs.ErrPermission, KindPermissionDenied},
+		{"invalid", ErrInvalid, KindInvalid},
+		{"json syntax", syntaxErr, KindInvalid},
+		{"net closed", net.ErrClosed, KindUnavailable},
+		{"net op", &net.OpError{Op: "dial", Err: errFake}, KindUnavailable},
+		{"other", errFake, KindInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKind_String(t *testing.T) {
+	tests := []struct {
+		k      Kind
+		want   string
+		status int
+	}{
+		{KindNotFound, "not_found", http.StatusNotFound},
+		{KindTimeout, "timeout", http.StatusGatewayTimeout},
+		{KindInternal, "internal", http.StatusInternalServerError},
+		{Kind(99), "Kind(99)", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.k.String(); got != tt.want {
+				t.Errorf("Kind.String() = %q, want %q", got, tt.want)
+			}
+			if got := tt.k.HTTPStatus(); got != tt.status {
+				t.Errorf("Kind.HTTPStatus() = %d, want %d", got, tt.status)
+			}
+		})
+	}
+}
+
+func TestKindEnricher(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.AddEnricher(nil)
+	_ = e.Err(errFake)
+	if strings.Contains(buf.String(), "kind=") {
+		t.Errorf("Err() without enricher added kind field: %q", buf.String())
+	}
+
+	buf.Reset()
+	e.AddEnricher(KindEnricher)
+	_ = e.Err(os.ErrNotExist)
+	if !strings.Contains(buf.String(), "kind=not_found") {
+		t.Errorf("Err() with KindEnricher output = %q, want kind field", buf.String())
+	}
+}
@@ -42,3 +42,10 @@ const (
 	// TraceLevel level. Designates finer-grained informational events than the Debug.
 	TraceLevel
 )
+
+// ParseLevel takes a string level and returns the log level constant.
+//
+// Reference: https://github.com/sirupsen/logrus
+func ParseLevel(lvl string) (Level, error) {
+	return logrus.ParseLevel(lvl)
+}
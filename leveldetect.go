@@ -0,0 +1,88 @@
+package errorlogger
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// PrefixRule classifies the lines written to a level
+// detecting writer. A line matches the rule if it starts
+// with Prefix, ignoring case and leading spaces, or, if
+// Prefix is empty, if it matches Pattern anywhere.
+//
+// The prefix, and the spaces after it, are removed from the
+// logged message; lines matched by Pattern are logged as
+// they are.
+type PrefixRule struct {
+	Prefix  string
+	Pattern *regexp.Regexp
+	Level   Level
+}
+
+// DefaultPrefixRules classify the level markers most often
+// written by third-party SDKs, e.g. "[ERROR]", "WARN:", and
+// "level=debug".
+var DefaultPrefixRules = []PrefixRule{
+	{Prefix: "[ERROR]", Level: ErrorLevel},
+	{Prefix: "[ERR]", Level: ErrorLevel},
+	{Prefix: "[FATAL]", Level: ErrorLevel},
+	{Prefix: "[WARN]", Level: WarnLevel},
+	{Prefix: "[WARNING]", Level: WarnLevel},
+	{Prefix: "[INFO]", Level: InfoLevel},
+	{Prefix: "[DEBUG]", Level: DebugLevel},
+	{Prefix: "[TRACE]", Level: TraceLevel},
+	{Prefix: "ERROR:", Level: ErrorLevel},
+	{Prefix: "FATAL:", Level: ErrorLevel},
+	{Prefix: "WARN:", Level: WarnLevel},
+	{Prefix: "WARNING:", Level: WarnLevel},
+	{Prefix: "INFO:", Level: InfoLevel},
+	{Prefix: "DEBUG:", Level: DebugLevel},
+	{Prefix: "TRACE:", Level: TraceLevel},
+	{Pattern: regexp.MustCompile(`\blevel=(?:error|fatal|panic)\b`), Level: ErrorLevel},
+	{Pattern: regexp.MustCompile(`\blevel=warn(?:ing)?\b`), Level: WarnLevel},
+	{Pattern: regexp.MustCompile(`\blevel=debug\b`), Level: DebugLevel},
+	{Pattern: regexp.MustCompile(`\blevel=trace\b`), Level: TraceLevel},
+}
+
+// NewLevelDetectingWriter returns a writer that logs each
+// line written to it with e, at the level of the first of
+// rules that matches the line, or at InfoLevel if none
+// does. It is meant for SDKs that only accept an io.Writer
+// for their logs:
+//  client := sdk.New(sdk.WithLogOutput(errorlogger.NewLevelDetectingWriter(log, nil)))
+//
+// If rules is nil, DefaultPrefixRules are used. Levels more
+// severe than ErrorLevel are logged at ErrorLevel, so that
+// the output of a dependency cannot exit or panic the
+// program. Blank lines are not logged, and Close logs a
+// last line without a trailing newline.
+func NewLevelDetectingWriter(e ErrorLogger, rules []PrefixRule) io.WriteCloser {
+	if rules == nil {
+		rules = DefaultPrefixRules
+	}
+	return &lineLogger{e: e, level: InfoLevel, rules: rules}
+}
+
+// classify returns the level of line and the message to log
+// for it.
+func (w *lineLogger) classify(line string) (Level, string) {
+	trimmed := strings.TrimLeft(line, " \t")
+	for _, r := range w.rules {
+		level := r.Level
+		if level < ErrorLevel {
+			level = ErrorLevel
+		}
+		switch {
+		case r.Prefix != "":
+			if len(trimmed) >= len(r.Prefix) && strings.EqualFold(trimmed[:len(r.Prefix)], r.Prefix) {
+				return level, strings.TrimLeft(trimmed[len(r.Prefix):], " \t")
+			}
+		case r.Pattern != nil:
+			if r.Pattern.MatchString(line) {
+				return level, line
+			}
+		}
+	}
+	return w.level, line
+}
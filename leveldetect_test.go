@@ -0,0 +1,46 @@
+package errorlogger
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestNewLevelDetectingWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []PrefixRule
+		in    string
+		want  []string
+	}{
+		{"bracket prefix", nil, "[ERROR] disk full\n", []string{`level=error msg="disk full"`}},
+		{"colon prefix", nil, "  warn: retrying\n", []string{"level=warning msg=retrying"}},
+		{"logfmt", nil, "time=now level=debug msg=x\n", []string{`level=debug msg="time=now level=debug msg=x"`}},
+		{"no match", nil, "connected\n", []string{"level=info msg=connected"}},
+		{"fatal is error", nil, "[FATAL] gone\n", []string{"level=error msg=gone"}},
+		{"several lines", nil, "[INFO] a\n\n[WARN] b", []string{"level=info msg=a", "level=warning msg=b"}},
+		{"custom", []PrefixRule{{Pattern: regexp.MustCompile(`^E\d{4}`), Level: ErrorLevel}, {Prefix: "!!", Level: PanicLevel}}, "E0001 bad\n!! worse\n[ERROR] other\n", []string{`level=error msg="E0001 bad"`, "level=error msg=worse", `level=info msg="[ERROR] other"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(TraceLevel)
+			w := NewLevelDetectingWriter(e, tt.rules)
+			if _, err := w.Write([]byte(tt.in)); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("output = %q, want %q", buf.String(), tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(lines[i], want) {
+					t.Errorf("line %d = %q, want %q", i, lines[i], want)
+				}
+			}
+		})
+	}
+}
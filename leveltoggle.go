@@ -0,0 +1,68 @@
+package errorlogger
+
+import "sync/atomic"
+
+// DisableLevel silences entries of level without changing
+// the level of the logger, so that, for example, warnings
+// can be turned off during a hot loop while errors are still
+// recorded:
+//  log.DisableLevel(errorlogger.WarnLevel)
+//  defer log.EnableLevel(errorlogger.WarnLevel)
+//
+// Disabling ErrorLevel sets the same no-op function for Err
+// as Disable. Entries of other levels are dropped before
+// any stage of the logger runs.
+func (e *errorLogger) DisableLevel(level Level) {
+	e.setLevelDisabled(level, true)
+}
+
+// EnableLevel reverses DisableLevel for level. Entries of
+// level are still filtered by the level of the logger.
+func (e *errorLogger) EnableLevel(level Level) {
+	e.setLevelDisabled(level, false)
+}
+
+// IsLevelEnabled reports whether entries of level are
+// logged: level is within the level of the logger and has
+// not been disabled with DisableLevel.
+func (e *errorLogger) IsLevelEnabled(level Level) bool {
+	return e.Logger.IsLevelEnabled(level) && !e.levelDisabled(level)
+}
+
+func (e *errorLogger) setLevelDisabled(level Level, disabled bool) {
+	if level > TraceLevel {
+		return
+	}
+	bit := uint32(1) << level
+	e.enableMu.Lock()
+	defer e.enableMu.Unlock()
+	mask := atomic.LoadUint32(&e.levelMask)
+	if disabled {
+		mask |= bit
+	} else {
+		mask &^= bit
+	}
+	atomic.StoreUint32(&e.levelMask, mask)
+	e.storeErrFn()
+}
+
+// levelDisabled reports whether level was disabled with
+// DisableLevel.
+func (e *errorLogger) levelDisabled(level Level) bool {
+	return atomic.LoadUint32(&e.levelMask)&(uint32(1)<<level) != 0
+}
+
+// storeErrFn sets the ErrorFunc called by Err: the no-op
+// function if logging or ErrorLevel is disabled, and the
+// sampling function if SetSampling is in effect. It is
+// called with enableMu held.
+func (e *errorLogger) storeErrFn() {
+	switch {
+	case atomic.LoadUint32(&e.disabled) != 0 || e.levelDisabled(ErrorLevel):
+		e.errFn.Store(ErrorFunc(e.noErr))
+	case atomic.LoadUint32(&e.sampleN) > 1:
+		e.errFn.Store(ErrorFunc(e.sampleErr))
+	default:
+		e.errFn.Store(ErrorFunc(e.yesErr))
+	}
+}
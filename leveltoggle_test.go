@@ -0,0 +1,94 @@
+package errorlogger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_DisableLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		disable []Level
+		enable  []Level
+		log     func(e *errorLogger)
+		want    bool
+	}{
+		{"warn disabled", []Level{WarnLevel}, nil, func(e *errorLogger) { e.Warn("w") }, false},
+		{"entry warn disabled", []Level{WarnLevel}, nil, func(e *errorLogger) { e.WithField("k", 1).Warn("w") }, false},
+		{"error kept", []Level{WarnLevel}, nil, func(e *errorLogger) { e.Err(errFake) }, true},
+		{"err disabled", []Level{ErrorLevel}, nil, func(e *errorLogger) { e.Err(errFake) }, false},
+		{"err with fields disabled", []Level{ErrorLevel}, nil, func(e *errorLogger) { e.ErrWithFields(errFake, Fields{"k": 1}) }, false},
+		{"reenabled", []Level{WarnLevel}, []Level{WarnLevel}, func(e *errorLogger) { e.Warn("w") }, true},
+		{"err reenabled", []Level{ErrorLevel}, []Level{ErrorLevel}, func(e *errorLogger) { e.Err(errFake) }, true},
+		{"other level", []Level{InfoLevel}, nil, func(e *errorLogger) { e.Warn("w") }, true},
+		{"out of range", []Level{Level(42)}, nil, func(e *errorLogger) { e.Warn("w") }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			for _, l := range tt.disable {
+				e.DisableLevel(l)
+			}
+			for _, l := range tt.enable {
+				e.EnableLevel(l)
+			}
+			tt.log(e)
+			if got := buf.Len() > 0; got != tt.want {
+				t.Errorf("logged = %v, want %v: %q", got, tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestErrorLogger_DisableLevel_enable(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+
+	if got := e.Err(errFake); got != errFake {
+		t.Fatalf("Err() = %v, want %v", got, errFake)
+	}
+
+	// Enable does not restore a disabled ErrorLevel, and
+	// EnableLevel does not restore a disabled logger.
+	e.DisableLevel(ErrorLevel)
+	e.Disable()
+	e.Enable()
+	buf.Reset()
+	if got := e.Err(errFake); got != errFake {
+		t.Errorf("Err() = %v, want %v", got, errFake)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("output = %q, want none with ErrorLevel disabled", buf.String())
+	}
+
+	e.Disable()
+	e.EnableLevel(ErrorLevel)
+	e.Err(errFake)
+	if buf.Len() > 0 {
+		t.Errorf("output = %q, want none while disabled", buf.String())
+	}
+
+	e.Enable()
+	e.Err(errFake)
+	if !strings.Contains(buf.String(), errFake.Error()) {
+		t.Errorf("output = %q, want the error after Enable", buf.String())
+	}
+}
+
+func TestErrorLogger_IsLevelEnabled(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	e.DisableLevel(WarnLevel)
+	tests := []struct {
+		level Level
+		want  bool
+	}{
+		{ErrorLevel, true},
+		{WarnLevel, false},
+		{InfoLevel, true},
+		{DebugLevel, false},
+	}
+	for _, tt := range tests {
+		if got := e.IsLevelEnabled(tt.level); got != tt.want {
+			t.Errorf("IsLevelEnabled(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
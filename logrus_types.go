@@ -168,6 +168,7 @@ type (
 		GetLevel() Level
 		SetFormatter(formatter logrus.Formatter)
 		SetOutput(output io.Writer)
+		AddHook(hook logrus.Hook)
 	}
 
 	// logrusLoggerComplete implements the complete exported
@@ -190,7 +191,6 @@ type (
 		WithTime(t time.Time) *logrus.Entry
 		Exit(code int)
 		SetNoLock()
-		AddHook(hook logrus.Hook)
 		IsLevelEnabled(level Level) bool
 		SetReportCaller(reportCaller bool)
 		ReplaceHooks(hooks logrus.LevelHooks) logrus.LevelHooks
@@ -0,0 +1,7 @@
+//go:build go1.19
+
+package errorlogger
+
+import "runtime/debug"
+
+func memoryLimit() int64 { return debug.SetMemoryLimit(-1) }
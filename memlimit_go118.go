@@ -0,0 +1,7 @@
+//go:build !go1.19
+
+package errorlogger
+
+// memoryLimit returns 0, since memory limits were added to
+// the runtime in Go 1.19.
+func memoryLimit() int64 { return 0 }
@@ -0,0 +1,344 @@
+package errorlogger
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MetricType is the type of metric produced by a MetricRule.
+type MetricType int
+
+const (
+	// CounterMetric sums the field value, or counts entries
+	// if the rule has no field.
+	CounterMetric MetricType = iota
+
+	// GaugeMetric records the most recent field value.
+	GaugeMetric
+
+	// HistogramMetric records the distribution of the field
+	// value in buckets.
+	HistogramMetric
+)
+
+func (t MetricType) String() string {
+	switch t {
+	case CounterMetric:
+		return "counter"
+	case GaugeMetric:
+		return "gauge"
+	case HistogramMetric:
+		return "histogram"
+	default:
+		return "MetricType(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// DefaultMetricBuckets are the histogram bucket upper bounds
+// used when a rule does not specify any. They suit
+// millisecond timings such as an "elapsed_ms" field.
+var DefaultMetricBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+type (
+	// MetricRule extracts a metric from the numeric field of
+	// matching entries.
+	//
+	// Field values may be any integer or floating point type,
+	// a numeric string, or a time.Duration, which is recorded
+	// in seconds. Entries without the field, or with a value
+	// that is not numeric, are skipped; a counter without a
+	// Field counts matching entries instead.
+	MetricRule struct {
+		// Name is the metric name.
+		Name string
+
+		// Help is an optional description of the metric.
+		Help string
+
+		Type MetricType
+
+		// Field is the entry field the value is read from.
+		Field string
+
+		// Labels are entry fields whose values label the
+		// metric. Missing fields label as "".
+		Labels []string
+
+		// Match optionally restricts the rule to entries for
+		// which it returns true.
+		Match func(*Entry) bool
+
+		// Buckets are the histogram bucket upper bounds in
+		// increasing order. The default is
+		// DefaultMetricBuckets.
+		Buckets []float64
+	}
+
+	// MetricSample is a point in time value of one labelled
+	// series of a metric. For counters and gauges, Value is
+	// the current value. For histograms, Value is the sum of
+	// observations, Count the number of observations, and
+	// Buckets the per bucket (not cumulative) counts, the last
+	// one for values greater than every bound.
+	MetricSample struct {
+		Name    string
+		Type    MetricType
+		Labels  map[string]string
+		Value   float64
+		Count   uint64
+		Bounds  []float64
+		Buckets []uint64
+	}
+
+	// FieldMetrics is a logrus hook that turns the numeric
+	// fields of log entries into counters, gauges, and
+	// histograms, bridging logs to metrics without separate
+	// instrumentation.
+	//
+	//  m := NewFieldMetrics(log, MetricRule{
+	//  	Name:   "op_duration_ms",
+	//  	Type:   HistogramMetric,
+	//  	Field:  "elapsed_ms",
+	//  	Labels: []string{"op"},
+	//  })
+	//  ...
+	//  m.WritePrometheus(w, "myapp")
+	FieldMetrics struct {
+		mu     sync.Mutex
+		rules  []MetricRule
+		series map[string]*metricSeries
+	}
+
+	// metricSeries is one labelled series of a rule.
+	metricSeries struct {
+		rule    *MetricRule
+		labels  []string
+		value   float64
+		count   uint64
+		buckets []uint64
+	}
+)
+
+// NewFieldMetrics returns a new FieldMetrics with the given
+// rules that is added as a hook to e.
+func NewFieldMetrics(e ErrorLogger, rules ...MetricRule) *FieldMetrics {
+	m := &FieldMetrics{
+		rules:  make([]MetricRule, len(rules)),
+		series: make(map[string]*metricSeries),
+	}
+	for i, r := range rules {
+		if r.Type == HistogramMetric && len(r.Buckets) == 0 {
+			r.Buckets = DefaultMetricBuckets
+		}
+		m.rules[i] = r
+	}
+	e.AddHook(m)
+	return m
+}
+
+// Levels implements logrus.Hook. Rules apply to entries of
+// every level.
+func (m *FieldMetrics) Levels() []Level { return logrus.AllLevels }
+
+// Fire implements logrus.Hook.
+func (m *FieldMetrics) Fire(entry *Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.rules {
+		r := &m.rules[i]
+		if r.Match != nil && !r.Match(entry) {
+			continue
+		}
+
+		v := 1.0
+		if r.Field != "" || r.Type != CounterMetric {
+			var ok bool
+			if v, ok = metricValue(entry.Data[r.Field]); !ok {
+				continue
+			}
+		}
+
+		labels := make([]string, len(r.Labels))
+		for j, l := range r.Labels {
+			if lv, ok := entry.Data[l]; ok {
+				labels[j] = fmt.Sprint(lv)
+			}
+		}
+		key := strconv.Itoa(i) + "\xff" + strings.Join(labels, "\xff")
+		s, ok := m.series[key]
+		if !ok {
+			s = &metricSeries{rule: r, labels: labels}
+			if r.Type == HistogramMetric {
+				s.buckets = make([]uint64, len(r.Buckets)+1)
+			}
+			m.series[key] = s
+		}
+		s.observe(v)
+	}
+	return nil
+}
+
+func (s *metricSeries) observe(v float64) {
+	switch s.rule.Type {
+	case CounterMetric:
+		s.value += v
+	case GaugeMetric:
+		s.value = v
+	case HistogramMetric:
+		i := sort.SearchFloat64s(s.rule.Buckets, v)
+		s.buckets[i]++
+		s.value += v
+		s.count++
+	}
+}
+
+// metricValue converts a field value to a float64.
+func metricValue(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case time.Duration:
+		return v.Seconds(), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// Samples returns the current value of every series, sorted
+// by metric name and labels.
+func (m *FieldMetrics) Samples() []MetricSample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	samples := make([]MetricSample, 0, len(m.series))
+	for _, s := range m.series {
+		ms := MetricSample{
+			Name:   s.rule.Name,
+			Type:   s.rule.Type,
+			Labels: make(map[string]string, len(s.labels)),
+			Value:  s.value,
+			Count:  s.count,
+		}
+		for i, l := range s.rule.Labels {
+			ms.Labels[l] = s.labels[i]
+		}
+		if s.buckets != nil {
+			ms.Bounds = s.rule.Buckets
+			ms.Buckets = append([]uint64(nil), s.buckets...)
+		}
+		samples = append(samples, ms)
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].Name != samples[j].Name {
+			return samples[i].Name < samples[j].Name
+		}
+		return promLabels(samples[i].Labels, "") < promLabels(samples[j].Labels, "")
+	})
+	return samples
+}
+
+// WritePrometheus writes the metrics to w in the Prometheus
+// text exposition format. Metric names are prefixed with
+// namespace and an underscore unless namespace is empty.
+func (m *FieldMetrics) WritePrometheus(w Writer, namespace string) error {
+	help := make(map[string]string, len(m.rules))
+	for _, r := range m.rules {
+		help[r.Name] = r.Help
+	}
+
+	var last string
+	for _, s := range m.Samples() {
+		name := s.Name
+		if namespace != "" {
+			name = namespace + "_" + name
+		}
+		if s.Name != last {
+			last = s.Name
+			if h := help[s.Name]; h != "" {
+				if _, err := fmt.Fprintf(w, "# HELP %s %s\n", name, h); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(w, "# TYPE %s %s\n", name, s.Type); err != nil {
+				return err
+			}
+		}
+
+		if s.Type != HistogramMetric {
+			if _, err := fmt.Fprintf(w, "%s%s %g\n", name, promLabels(s.Labels, ""), s.Value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var cumulative uint64
+		for i, b := range s.Bounds {
+			cumulative += s.Buckets[i]
+			le := strconv.FormatFloat(b, 'g', -1, 64)
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", name, promLabels(s.Labels, le), cumulative); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %g\n%s_count%s %d\n",
+			name, promLabels(s.Labels, "+Inf"), s.Count,
+			name, promLabels(s.Labels, ""), s.Value,
+			name, promLabels(s.Labels, ""), s.Count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// promLabels formats labels, plus an le label if le is not
+// empty, in the Prometheus text format with sorted names.
+func promLabels(labels map[string]string, le string) string {
+	if len(labels) == 0 && le == "" {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names)+1)
+	for _, k := range names {
+		parts = append(parts, fmt.Sprintf("%s=%q", k, labels[k]))
+	}
+	if le != "" {
+		parts = append(parts, fmt.Sprintf("le=%q", le))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,75 @@
+package errorlogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFieldMetrics(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	m := NewFieldMetrics(e,
+		MetricRule{Name: "op_duration_ms", Help: "Operation duration.", Type: HistogramMetric, Field: "elapsed_ms", Labels: []string{"op"}, Buckets: []float64{10, 100}},
+		MetricRule{Name: "errors_total", Type: CounterMetric, Match: func(e *Entry) bool { return e.Level <= ErrorLevel }},
+		MetricRule{Name: "queue_depth", Type: GaugeMetric, Field: "depth"},
+		MetricRule{Name: "wait_seconds_total", Type: CounterMetric, Field: "wait"},
+	)
+
+	e.WithFields(Fields{"op": "get", "elapsed_ms": 5}).Info("done")
+	e.WithFields(Fields{"op": "get", "elapsed_ms": 50.5}).Info("done")
+	e.WithFields(Fields{"op": "put", "elapsed_ms": "500"}).Info("done")
+	e.WithFields(Fields{"op": "put", "elapsed_ms": "slow"}).Info("not numeric")
+	e.WithField("depth", 3).Info("queued")
+	e.WithField("depth", int64(7)).Info("queued")
+	e.WithField("wait", 1500*time.Millisecond).Error("waited")
+	e.WithField("wait", time.Second).Warn("waited")
+
+	want := []MetricSample{
+		{Name: "errors_total", Value: 1},
+		{Name: "op_duration_ms", Labels: map[string]string{"op": "get"}, Value: 55.5, Count: 2, Buckets: []uint64{1, 1, 0}},
+		{Name: "op_duration_ms", Labels: map[string]string{"op": "put"}, Value: 500, Count: 1, Buckets: []uint64{0, 0, 1}},
+		{Name: "queue_depth", Value: 7},
+		{Name: "wait_seconds_total", Value: 2.5},
+	}
+	got := m.Samples()
+	if len(got) != len(want) {
+		t.Fatalf("Samples() = %+v, want %d samples", got, len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Name != w.Name || g.Value != w.Value || g.Count != w.Count ||
+			promLabels(g.Labels, "") != promLabels(w.Labels, "") || !equalCounts(g.Buckets, w.Buckets) {
+			t.Errorf("Samples()[%d] = %+v, want %+v", i, g, w)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := m.WritePrometheus(&buf, "app"); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"# HELP app_op_duration_ms Operation duration.\n# TYPE app_op_duration_ms histogram\n",
+		`app_op_duration_ms_bucket{op="get",le="100"} 2`,
+		`app_op_duration_ms_bucket{op="put",le="+Inf"} 1`,
+		`app_op_duration_ms_sum{op="get"} 55.5`,
+		"# TYPE app_errors_total counter\napp_errors_total 1\n",
+		"app_queue_depth 7\n",
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("WritePrometheus() missing %q in\n%s", line, buf.String())
+		}
+	}
+}
+
+func equalCounts(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
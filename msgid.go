@@ -0,0 +1,87 @@
+package errorlogger
+
+import (
+	"context"
+	"fmt"
+)
+
+// MsgIDKey is the field that holds the stable identifier of
+// a message logged with Msg.
+const MsgIDKey = "msgid"
+
+// MsgEntry is a log entry with a stable message identifier,
+// created with Msg. Log it with one of the level methods.
+type MsgEntry struct {
+	entry *Entry
+	id    string
+	text  string
+}
+
+// Msg returns an entry for a message with a stable,
+// machine-readable identifier id and the human readable
+// text, which may be a format string for the arguments
+// passed to the level method. The id is emitted in the
+// "msgid" field, so dashboards and alerts keep working when
+// the wording changes:
+//  log.Msg("cache.miss", "cache miss for key %q").With(Fields{"shard": 3}).Warn(key)
+func (e *errorLogger) Msg(id, text string) *MsgEntry {
+	return &MsgEntry{
+		entry: e.WithField(MsgIDKey, id),
+		id:    id,
+		text:  text,
+	}
+}
+
+// With adds fields to the entry.
+func (m *MsgEntry) With(fields Fields) *MsgEntry {
+	m.entry = m.entry.WithFields(fields)
+	return m
+}
+
+// WithError adds err to the entry in the "error" field.
+func (m *MsgEntry) WithError(err error) *MsgEntry {
+	m.entry = m.entry.WithError(err)
+	return m
+}
+
+// ID returns the stable identifier of the message.
+func (m *MsgEntry) ID() string { return m.id }
+
+// Log logs the message at level, formatting the text with
+// args if any are given.
+func (m *MsgEntry) Log(level Level, args ...interface{}) {
+	if !m.entry.Logger.IsLevelEnabled(level) {
+		return
+	}
+	text := m.text
+	entry := m.entry
+	if len(args) > 0 {
+		text = fmt.Sprintf(text, args...)
+		ctx := entry.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		entry = entry.WithContext(context.WithValue(ctx, msgArgsKey{}, args))
+	}
+	entry.Log(level, text)
+}
+
+// msgArgsKey is the context key of the arguments of a
+// message logged with Msg, kept for translation.
+type msgArgsKey struct{}
+
+// msgArgs returns the arguments of a message logged with
+// Msg.
+func msgArgs(entry *Entry) []interface{} {
+	if entry.Context == nil {
+		return nil
+	}
+	args, _ := entry.Context.Value(msgArgsKey{}).([]interface{})
+	return args
+}
+
+func (m *MsgEntry) Trace(args ...interface{}) { m.Log(TraceLevel, args...) }
+func (m *MsgEntry) Debug(args ...interface{}) { m.Log(DebugLevel, args...) }
+func (m *MsgEntry) Info(args ...interface{})  { m.Log(InfoLevel, args...) }
+func (m *MsgEntry) Warn(args ...interface{})  { m.Log(WarnLevel, args...) }
+func (m *MsgEntry) Error(args ...interface{}) { m.Log(ErrorLevel, args...) }
@@ -0,0 +1,37 @@
+package errorlogger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_Msg(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(e *errorLogger)
+		want []string
+	}{
+		{"plain", func(e *errorLogger) { e.Msg("job.done", "job completed").Info() }, []string{"level=info", "msg=\"job completed\"", "msgid=job.done"}},
+		{"format", func(e *errorLogger) { e.Msg("cache.miss", "cache miss for key %q").Warn("user:42") }, []string{"level=warning", `msg="cache miss for key \"user:42\""`, "msgid=cache.miss"}},
+		{"fields", func(e *errorLogger) {
+			e.Msg("db.retry", "retrying").With(Fields{"attempt": 2}).WithError(errors.New("busy")).Error()
+		}, []string{"msgid=db.retry", "attempt=2", "error=busy"}},
+		{"disabled level", func(e *errorLogger) { e.Msg("noisy", "noisy %v").Debug(struct{}{}) }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			tt.log(e)
+			got := buf.String()
+			if tt.want == nil && got != "" {
+				t.Errorf("Msg() = %q, want no output", got)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("Msg() = %q, want %q", got, w)
+				}
+			}
+		})
+	}
+}
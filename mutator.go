@@ -0,0 +1,43 @@
+package errorlogger
+
+// Mutator transforms an entry after its fields are assembled
+// and before the hooks of the logger fire, e.g. to
+// rename legacy fields, add derived fields, or scrub values.
+// Mutators run in the order they were added, and each sees
+// the changes of the ones before it.
+//
+// Mutators may be called concurrently for different
+// entries.
+type Mutator = func(entry *Entry)
+
+// AddMutator adds fn to the mutators run for every entry
+// written by the logger. Mutators should be added before
+// logging starts.
+//  log.AddMutator(errorlogger.RenameFields(map[string]string{"userId": "user_id"}))
+func (e *errorLogger) AddMutator(fn Mutator) {
+	if fn == nil {
+		return
+	}
+	e.mutators = append(e.mutators, fn)
+}
+
+// mutate runs the mutators on entry.
+func (e *errorLogger) mutate(entry *Entry) {
+	for _, fn := range e.mutators {
+		fn(entry)
+	}
+}
+
+// RenameFields returns a Mutator that renames the fields in
+// names from their old key to their new key. A field that
+// already has the new key is overwritten.
+func RenameFields(names map[string]string) Mutator {
+	return func(entry *Entry) {
+		for old, new := range names {
+			if v, ok := entry.Data[old]; ok {
+				delete(entry.Data, old)
+				entry.Data[new] = v
+			}
+		}
+	}
+}
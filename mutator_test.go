@@ -0,0 +1,57 @@
+package errorlogger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_AddMutator(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutators []Mutator
+		want     []string
+		notWant  []string
+	}{
+		{"none", nil, []string{"userId=42"}, nil},
+		{"rename", []Mutator{RenameFields(map[string]string{"userId": "user_id"})}, []string{"user_id=42"}, []string{"userId"}},
+		{"in order", []Mutator{
+			RenameFields(map[string]string{"userId": "user_id"}),
+			func(entry *Entry) { entry.Data["user_tag"] = "u" + entry.Data["user_id"].(string) },
+			func(entry *Entry) { delete(entry.Data, "user_id") },
+		}, []string{"user_tag=u42"}, []string{"user_id", "userId"}},
+		{"message", []Mutator{func(entry *Entry) { entry.Message = strings.ToUpper(entry.Message) }}, []string{"LOGIN"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			for _, fn := range tt.mutators {
+				e.AddMutator(fn)
+			}
+			e.AddMutator(nil)
+			e.WithField("userId", "42").Info("login")
+
+			out := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output = %q, want %q", out, want)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(out, s) {
+					t.Errorf("output = %q, want no %q", out, s)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorLogger_AddMutator_overrides(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.AddMutator(RenameFields(map[string]string{"tenantId": "tenant"}))
+	e.SetOverride("tenant", "acme", DebugLevel, 0)
+
+	e.WithField("tenantId", "acme").Debug("visible")
+	if !strings.Contains(buf.String(), "visible") {
+		t.Errorf("override did not match the renamed field: %q", buf.String())
+	}
+}
@@ -0,0 +1,90 @@
+package errorlogger
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// MaxJSONEntrySize is the largest entry returned by the
+// scanner of NewJSONScanner.
+const MaxJSONEntrySize = 1 << 20
+
+// SplitJSON is a bufio.SplitFunc that splits JSON log output
+// into entries. The JSONFormatter writes exactly one entry
+// per line unless PrettyPrint is set, but SplitJSON also
+// splits pretty printed entries that span lines, by
+// matching the braces of each object. Lines that do not
+// start with an object are returned as they are, without
+// the line ending. A truncated object at the end of the
+// input is returned as the last token.
+func SplitJSON(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	start := 0
+	for start < len(data) && isJSONSpace(data[start]) {
+		start++
+	}
+	if start == len(data) {
+		return len(data), nil, nil
+	}
+
+	if data[start] != '{' {
+		if i := bytes.IndexByte(data[start:], '\n'); i >= 0 {
+			return start + i + 1, bytes.TrimRight(data[start:start+i], "\r"), nil
+		}
+		if atEOF {
+			return len(data), data[start:], nil
+		}
+		return start, nil, nil
+	}
+
+	depth, inString, escaped := 0, false, false
+	for i := start; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch c {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth == 0 {
+				return i + 1, data[start : i+1], nil
+			}
+		}
+	}
+	if atEOF {
+		return len(data), bytes.TrimRight(data[start:], " \t\r\n"), nil
+	}
+	return start, nil, nil
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// NewJSONScanner returns a scanner that reads the entries of
+// JSON log output from r with SplitJSON, one entry per
+// token, for consumers that would otherwise split the output
+// on newlines:
+//  sc := NewJSONScanner(f)
+//  for sc.Scan() {
+//      var entry map[string]interface{}
+//      if err := json.Unmarshal(sc.Bytes(), &entry); err != nil {
+//          ...
+//      }
+//  }
+func NewJSONScanner(r io.Reader) *bufio.Scanner {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), MaxJSONEntrySize)
+	sc.Split(SplitJSON)
+	return sc
+}
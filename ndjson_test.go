@@ -0,0 +1,76 @@
+package errorlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"empty", "", nil},
+		{"lines", "{\"a\":1}\n{\"b\":2}\n", []string{`{"a":1}`, `{"b":2}`}},
+		{"no final newline", "{\"a\":1}\r\n{\"b\":2}", []string{`{"a":1}`, `{"b":2}`}},
+		{"pretty", "{\n  \"a\": {\n    \"b\": [1, 2]\n  }\n}\n{\"c\":3}\n", []string{"{\n  \"a\": {\n    \"b\": [1, 2]\n  }\n}", `{"c":3}`}},
+		{"braces in strings", "{\"msg\":\"} {\\\"\"}\n", []string{`{"msg":"} {\""}`}},
+		{"not json", "plain line\r\n{\"a\":1}\n\n\n", []string{"plain line", `{"a":1}`}},
+		{"truncated", "{\"a\":1}\n{\"b\":", []string{`{"a":1}`, `{"b":`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Read one byte at a time to exercise partial input.
+			sc := NewJSONScanner(&oneByteReader{s: tt.input})
+			var got []string
+			for sc.Scan() {
+				got = append(got, sc.Text())
+			}
+			if err := sc.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitJSON() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// oneByteReader returns s one byte per Read.
+type oneByteReader struct{ s string }
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if r.s == "" {
+		return 0, io.EOF
+	}
+	p[0], r.s = r.s[0], r.s[1:]
+	return 1, nil
+}
+
+func TestJSONFormatter_Format_oneLine(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.SetFormatter(NewJSONFormatter(false))
+	e.WithFields(Fields{
+		"stack": "goroutine 1 [running]:\nmain.main()\n\t/app/main.go:12",
+		"err":   errFake,
+	}).Error("first line\nsecond line")
+	e.Info("next")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("JSON output has %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("invalid JSON line %q", line)
+		}
+	}
+	if sc := NewJSONScanner(bytes.NewReader(buf.Bytes())); !sc.Scan() || !strings.Contains(sc.Text(), `first line\nsecond line`) {
+		t.Errorf("first entry = %q", sc.Text())
+	}
+}
@@ -0,0 +1,68 @@
+package errorlogger
+
+import (
+	"net"
+	"os"
+	"strings"
+)
+
+// ServiceStateKey is the field that holds the service state
+// of the entries logged by Ready and Stopping.
+const ServiceStateKey = "service_state"
+
+// Notify sends state to the service manager with the
+// sd_notify protocol of systemd, e.g. "READY=1". It reports
+// whether a notification was sent, which it is not if the
+// process was not started with NOTIFY_SOCKET set.
+func Notify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	if strings.HasPrefix(socket, "@") {
+		// abstract namespace socket
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Ready logs that the service is ready at InfoLevel and
+// notifies the service manager with READY=1 and the status
+// text, so the transition is visible both to the supervisor
+// and in the logs:
+//  if err := log.Ready("listening on :8080"); err != nil {
+//      log.Warn(err)
+//  }
+func (e *errorLogger) Ready(status string) error {
+	return e.notifyState("ready", "READY=1", status)
+}
+
+// Stopping logs that the service is stopping at InfoLevel
+// and notifies the service manager with STOPPING=1 and the
+// status text.
+func (e *errorLogger) Stopping(status string) error {
+	return e.notifyState("stopping", "STOPPING=1", status)
+}
+
+func (e *errorLogger) notifyState(name, state, status string) error {
+	fields := Fields{ServiceStateKey: name}
+	if status != "" {
+		fields["status"] = status
+		state += "\nSTATUS=" + status
+	}
+	_, err := Notify(state)
+	if err != nil {
+		fields["notify_error"] = err.Error()
+	}
+	e.WithFields(fields).Info("service " + name)
+	return err
+}
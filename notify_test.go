@@ -0,0 +1,69 @@
+package errorlogger
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_Ready(t *testing.T) {
+	dir, err := os.MkdirTemp("", "notify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skip("unixgram sockets not supported:", err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", path)
+
+	tests := []struct {
+		name      string
+		fn        func(e *errorLogger) error
+		wantState string
+		wantLog   string
+	}{
+		{"ready", func(e *errorLogger) error { return e.Ready("listening") }, "READY=1\nSTATUS=listening", "service_state=ready"},
+		{"stopping", func(e *errorLogger) error { return e.Stopping("") }, "STOPPING=1", "service_state=stopping"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			if err := tt.fn(e); err != nil {
+				t.Fatal(err)
+			}
+			b := make([]byte, 256)
+			n, err := conn.Read(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(b[:n]); got != tt.wantState {
+				t.Errorf("notified %q, want %q", got, tt.wantState)
+			}
+			if !strings.Contains(buf.String(), tt.wantLog) {
+				t.Errorf("logged %q, want %q", buf.String(), tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if sent, err := Notify("READY=1"); sent || err != nil {
+		t.Errorf("Notify() without NOTIFY_SOCKET = %v, %v, want false, nil", sent, err)
+	}
+
+	t.Setenv("NOTIFY_SOCKET", filepath.Join(t.TempDir(), "missing"))
+	e, buf := newBufferLogger(InfoLevel)
+	if err := e.Ready(""); err == nil {
+		t.Error("Ready() with a missing socket error = nil")
+	}
+	if !strings.Contains(buf.String(), "notify_error") {
+		t.Errorf("logged %q, want the notify error", buf.String())
+	}
+}
@@ -0,0 +1,106 @@
+package errorlogger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// levelCallbacks is the hook that runs the callbacks added
+// with OnFatal and OnPanic. It is the first hook of its
+// levels, so that it runs even if another hook fails.
+type levelCallbacks struct {
+	mu    sync.RWMutex
+	fatal []func(Entry)
+	panic []func(Entry)
+	diag  Writer // where callback panics are reported
+}
+
+// OnFatal adds fn to the callbacks run for every entry logged
+// at FatalLevel, before the process exits. Callbacks run
+// synchronously, in the order they were added, before any
+// hook; a callback that panics is reported on os.Stderr and
+// does not keep the others from running or the process
+// from exiting.
+//  log.OnFatal(func(entry errorlogger.Entry) {
+//      db.Close()
+//  })
+//
+// Unlike hooks added with AddHook, callbacks are not subject
+// to the hook policy. Callbacks should be added before
+// logging starts.
+func (e *errorLogger) OnFatal(fn func(Entry)) {
+	if fn == nil {
+		return
+	}
+	c := e.levelCallbacks()
+	c.mu.Lock()
+	c.fatal = append(c.fatal, fn)
+	c.mu.Unlock()
+}
+
+// OnPanic adds fn to the callbacks run for every entry
+// logged at PanicLevel, before the panic propagates, as
+// OnFatal does for FatalLevel.
+func (e *errorLogger) OnPanic(fn func(Entry)) {
+	if fn == nil {
+		return
+	}
+	c := e.levelCallbacks()
+	c.mu.Lock()
+	c.panic = append(c.panic, fn)
+	c.mu.Unlock()
+}
+
+// levelCallbacks returns the callback hook of the logger,
+// installing it before the other hooks of its levels on
+// first use.
+func (e *errorLogger) levelCallbacks() *levelCallbacks {
+	e.callbacksOnce.Do(func() {
+		e.callbacks = &levelCallbacks{diag: os.Stderr}
+		hooks := e.Logger.ReplaceHooks(make(logrus.LevelHooks))
+		merged := make(logrus.LevelHooks, len(hooks))
+		for _, l := range e.callbacks.Levels() {
+			merged[l] = []logrus.Hook{e.callbacks}
+		}
+		for l, hs := range hooks {
+			merged[l] = append(merged[l], hs...)
+		}
+		e.Logger.ReplaceHooks(merged)
+	})
+	return e.callbacks
+}
+
+// Levels returns PanicLevel and FatalLevel.
+func (c *levelCallbacks) Levels() []Level {
+	return []Level{PanicLevel, FatalLevel}
+}
+
+// Fire runs the callbacks of the level of entry, each with
+// its own copy of entry.
+func (c *levelCallbacks) Fire(entry *Entry) error {
+	c.mu.RLock()
+	fns, name := c.fatal, "OnFatal"
+	if entry.Level == PanicLevel {
+		fns, name = c.panic, "OnPanic"
+	}
+	c.mu.RUnlock()
+
+	for _, fn := range fns {
+		c.run(name, fn, entry)
+	}
+	return nil
+}
+
+func (c *levelCallbacks) run(name string, fn func(Entry), entry *Entry) {
+	defer func() {
+		if v := recover(); v != nil {
+			fmt.Fprintf(c.diag, "errorlogger: %s callback panicked: %v\n", name, v)
+		}
+	}()
+	fn(*copyEntry(entry))
+}
+
+var _ logrus.Hook = (*levelCallbacks)(nil)
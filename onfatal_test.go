@@ -0,0 +1,45 @@
+package errorlogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_OnFatal(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	e.ExitFunc = func(int) {}
+	e.AddHook(&countHook{levels: AllLevels, fn: func(*Entry) { panic("hook bug") }})
+
+	var calls []string
+	e.OnFatal(func(entry Entry) {
+		entry.Data["changed"] = true
+		panic("callback bug")
+	})
+	e.OnFatal(func(entry Entry) {
+		calls = append(calls, "fatal: "+entry.Message)
+		if _, ok := entry.Data["changed"]; ok {
+			t.Error("callback saw the changes of another callback")
+		}
+	})
+	e.OnPanic(func(entry Entry) { calls = append(calls, "panic: "+entry.Message) })
+	e.OnFatal(nil)
+	diag := &bytes.Buffer{}
+	e.callbacks.diag = diag
+
+	func() {
+		defer func() { _ = recover() }() // the panicking hook
+		e.WithField("db", "main").Fatal("disk gone")
+	}()
+	func() {
+		defer func() { _ = recover() }()
+		e.Panic("bad state")
+	}()
+
+	if want := []string{"fatal: disk gone", "panic: bad state"}; strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("callbacks = %q, want %q", calls, want)
+	}
+	if !strings.Contains(diag.String(), "OnFatal callback panicked: callback bug") {
+		t.Errorf("diagnostics = %q", diag.String())
+	}
+}
@@ -0,0 +1,91 @@
+package errorlogger
+
+import "fmt"
+
+// Options are the display options of a logger. Sets of
+// options can be shared between tools as part of a named
+// Preset saved to a PresetStore.
+type Options struct {
+	// Indent is the indentation of each level of a value
+	// rendered by Dump over multiple lines, and of pretty
+	// printed JSON.
+	Indent string `json:"indent,omitempty"`
+
+	// Prefix begins each line of pretty printed JSON.
+	Prefix string `json:"prefix,omitempty"`
+
+	// Width is the line width up to which Dump renders a
+	// value on a single line. Pretty printed JSON entries
+	// that fit in Width are written on a single line.
+	Width int `json:"width,omitempty"`
+
+	// MaxDepth is the deepest nesting rendered by Dump.
+	MaxDepth int `json:"max_depth,omitempty"`
+
+	// SortKeys orders the keys of JSON entries as
+	// TextFormatter does: the time, level, and message
+	// first, followed by the fields in sorted order.
+	// Otherwise all keys are in sorted order, as written by
+	// encoding/json.
+	SortKeys bool `json:"sort_keys,omitempty"`
+}
+
+// DefaultOptions are the options of a new logger. Zero
+// fields of the options passed to SetOptions are replaced
+// by the fields of DefaultOptions.
+var DefaultOptions = Options{Indent: "  ", Width: 80, MaxDepth: 10}
+
+// withDefaults returns o with zero fields set from
+// DefaultOptions.
+func (o Options) withDefaults() Options {
+	if o.Indent == "" {
+		o.Indent = DefaultOptions.Indent
+	}
+	if o.Width <= 0 {
+		o.Width = DefaultOptions.Width
+	}
+	if o.MaxDepth <= 0 {
+		o.MaxDepth = DefaultOptions.MaxDepth
+	}
+	return o
+}
+
+// check returns an error wrapping ErrInvalid if o has
+// negative sizes.
+func (o Options) check() error {
+	if o.Width < 0 || o.MaxDepth < 0 {
+		return fmt.Errorf("invalid options: width %d, max depth %d: %w", o.Width, o.MaxDepth, ErrInvalid)
+	}
+	return nil
+}
+
+// SetOptions sets the display options of the logger and of
+// its JSON formatter, if it uses one, e.g. to pretty print
+// JSON compactly:
+//  log.SetJSON(true)
+//  err := log.SetOptions(Options{Indent: "\t", Width: 120, SortKeys: true})
+//
+// It returns an error wrapping ErrInvalid, and leaves the
+// options unchanged, if Width or MaxDepth are negative.
+func (e *errorLogger) SetOptions(o Options) error {
+	if err := o.check(); err != nil {
+		return err
+	}
+	o = o.withDefaults()
+	e.options.Store(&o)
+
+	if f, ok := e.formatter().(*JSONFormatter); ok {
+		c := *f
+		c.Options = o
+		e.SetFormatter(&c)
+	}
+	return nil
+}
+
+// Options returns the display options of the logger.
+func (e *errorLogger) Options() Options {
+	if o, ok := e.options.Load().(*Options); ok {
+		return *o
+	}
+	return DefaultOptions
+}
@@ -0,0 +1,71 @@
+package errorlogger
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJSONFormatter_Format_options(t *testing.T) {
+	entry := &Entry{
+		Time:    time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:   InfoLevel,
+		Message: "hi",
+		Data:    Fields{"b": 1, "a": "<x>"},
+	}
+	tests := []struct {
+		name   string
+		pretty bool
+		o      Options
+		want   string
+	}{
+		{"zero", false, Options{}, `{"a":"\u003cx\u003e","b":1,"level":"info","msg":"hi","time":"2021-01-02T03:04:05Z"}` + "\n"},
+		{"sort keys", false, Options{SortKeys: true}, `{"time":"2021-01-02T03:04:05Z","level":"info","msg":"hi","a":"\u003cx\u003e","b":1}` + "\n"},
+		{"pretty fits", true, Options{Width: 200, Prefix: "> "}, `> {"a":"\u003cx\u003e","b":1,"level":"info","msg":"hi","time":"2021-01-02T03:04:05Z"}` + "\n"},
+		{"pretty", true, Options{Width: 40, Prefix: "> ", Indent: "\t", SortKeys: true}, "> {\n" +
+			"> \t\"time\": \"2021-01-02T03:04:05Z\",\n" +
+			"> \t\"level\": \"info\",\n" +
+			"> \t\"msg\": \"hi\",\n" +
+			"> \t\"a\": \"\\u003cx\\u003e\",\n" +
+			"> \t\"b\": 1\n" +
+			"> }\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewJSONFormatter(tt.pretty)
+			f.SetOptions(tt.o)
+			got, err := f.Format(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorLogger_SetOptions(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	if err := e.SetOptions(Options{Width: -1}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("SetOptions() with a negative width = %v, want ErrInvalid", err)
+	}
+	if got := e.Options(); got != DefaultOptions {
+		t.Errorf("Options() = %+v, want %+v", got, DefaultOptions)
+	}
+
+	if err := e.SetOptions(Options{SortKeys: true}); err != nil {
+		t.Fatal(err)
+	}
+	want := DefaultOptions
+	want.SortKeys = true
+	if got := e.Options(); got != want {
+		t.Errorf("Options() = %+v, want %+v", got, want)
+	}
+
+	e.SetJSON(false)
+	e.Info("hi")
+	if got := buf.String(); len(got) < 9 || got[:9] != `{"time":"` {
+		t.Errorf("output = %q, want the time first", got)
+	}
+}
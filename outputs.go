@@ -0,0 +1,135 @@
+package errorlogger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync/atomic"
+)
+
+// DuplicateOutputPolicy selects what AddOutput does with an
+// output that the logger already writes to, which would
+// otherwise log every entry twice.
+type DuplicateOutputPolicy int32
+
+const (
+	// DedupeOutputs ignores duplicate outputs.
+	DedupeOutputs DuplicateOutputPolicy = iota
+
+	// WarnDuplicateOutputs adds duplicate outputs and writes
+	// a diagnostics warning to os.Stderr.
+	WarnDuplicateOutputs
+
+	// AllowDuplicateOutputs adds duplicate outputs silently.
+	AllowDuplicateOutputs
+)
+
+// outputDiag is where duplicate outputs are reported.
+var outputDiag Writer = os.Stderr
+
+// multiOutput writes entries to several outputs. It is not
+// changed after it is created; AddOutput installs a new one.
+type multiOutput struct {
+	outs []Writer
+}
+
+// AddOutput adds out to the outputs of the logger, so that
+// every entry is written to the current output and to out:
+//  log.AddOutput(file)
+//
+// An output that the logger already writes to, either the
+// same writer or an *os.File for the same file, such as
+// os.Stderr and /dev/stderr, is handled as set by
+// SetDuplicateOutputPolicy; by default it is ignored.
+// SetOutput replaces all outputs.
+func (e *errorLogger) AddOutput(out io.Writer) {
+	if out == nil {
+		return
+	}
+	e.outputMu.Lock()
+	defer e.outputMu.Unlock()
+
+	current := []Writer{e.output()}
+	if m, ok := e.output().(*multiOutput); ok {
+		current = m.outs
+	}
+	for _, w := range current {
+		if !sameOutput(w, out) {
+			continue
+		}
+		switch DuplicateOutputPolicy(atomic.LoadInt32(&e.dupPolicy)) {
+		case DedupeOutputs:
+			return
+		case WarnDuplicateOutputs:
+			fmt.Fprintf(outputDiag, "errorlogger: output %s added twice; entries will be written to it twice\n", outputName(out))
+		}
+		break
+	}
+
+	outs := make([]Writer, len(current), len(current)+1)
+	copy(outs, current)
+	e.setOutput(&multiOutput{outs: append(outs, out)}, nil)
+}
+
+// SetDuplicateOutputPolicy sets what AddOutput does with an
+// output that the logger already writes to.
+func (e *errorLogger) SetDuplicateOutputPolicy(p DuplicateOutputPolicy) {
+	atomic.StoreInt32(&e.dupPolicy, int32(p))
+}
+
+// sameOutput reports whether a and b write to the same
+// destination.
+func sameOutput(a, b Writer) bool {
+	if reflect.TypeOf(a) == reflect.TypeOf(b) && reflect.TypeOf(a).Comparable() && a == b {
+		return true
+	}
+	fa, ok := a.(*os.File)
+	if !ok {
+		return false
+	}
+	fb, ok := b.(*os.File)
+	if !ok {
+		return false
+	}
+	sa, err := fa.Stat()
+	if err != nil {
+		return false
+	}
+	sb, err := fb.Stat()
+	if err != nil {
+		return false
+	}
+	return os.SameFile(sa, sb)
+}
+
+// Write writes p to every output. All outputs are written
+// to even if some fail; the first error is returned.
+func (m *multiOutput) Write(p []byte) (int, error) {
+	var first error
+	for _, w := range m.outs {
+		if _, err := w.Write(p); err != nil && first == nil {
+			first = err
+		}
+	}
+	return len(p), first
+}
+
+// Flush flushes the outputs that buffer entries.
+func (m *multiOutput) Flush() {
+	for _, w := range m.outs {
+		FlushSink(w)
+	}
+}
+
+// SinkStats reports the statistics of the outputs that
+// report them.
+func (m *multiOutput) SinkStats() []SinkStats {
+	var stats []SinkStats
+	for _, w := range m.outs {
+		if s, ok := w.(sinkStatser); ok {
+			stats = append(stats, s.SinkStats()...)
+		}
+	}
+	return stats
+}
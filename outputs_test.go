@@ -0,0 +1,77 @@
+package errorlogger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_AddOutput(t *testing.T) {
+	defer func(w Writer) { outputDiag = w }(outputDiag)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	f1, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f1.Close()
+	f2, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f2.Close()
+
+	var extra bytes.Buffer
+	tests := []struct {
+		name      string
+		policy    DuplicateOutputPolicy
+		add       []Writer
+		wantExtra int
+		wantFile  int
+		wantDiag  bool
+	}{
+		{"distinct", DedupeOutputs, []Writer{&extra, f1}, 1, 1, false},
+		{"same writer", DedupeOutputs, []Writer{&extra, &extra}, 1, 0, false},
+		{"same file", DedupeOutputs, []Writer{f1, f2}, 0, 1, false},
+		{"warn", WarnDuplicateOutputs, []Writer{&extra, &extra}, 2, 0, true},
+		{"allow", AllowDuplicateOutputs, []Writer{f1, f2}, 0, 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diag bytes.Buffer
+			outputDiag = &diag
+			extra.Reset()
+			if err := f1.Truncate(0); err != nil {
+				t.Fatal(err)
+			}
+
+			e, buf := newBufferLogger(InfoLevel)
+			e.SetDuplicateOutputPolicy(tt.policy)
+			for _, w := range tt.add {
+				e.AddOutput(w)
+			}
+			e.AddOutput(buf) // the current output
+			e.Info("hello")
+
+			data, _ := os.ReadFile(path)
+			if got := strings.Count(extra.String(), "hello"); got != tt.wantExtra {
+				t.Errorf("buffer has %d entries, want %d", got, tt.wantExtra)
+			}
+			if got := strings.Count(string(data), "hello"); got != tt.wantFile {
+				t.Errorf("file has %d entries, want %d", got, tt.wantFile)
+			}
+			wantOrig := 1
+			if tt.policy != DedupeOutputs {
+				wantOrig = 2
+			}
+			if got := strings.Count(buf.String(), "hello"); got != wantOrig {
+				t.Errorf("original output has %d entries, want %d", got, wantOrig)
+			}
+			if got := strings.Contains(diag.String(), "added twice"); got != tt.wantDiag {
+				t.Errorf("diagnostics = %q, want warning %v", diag.String(), tt.wantDiag)
+			}
+		})
+	}
+}
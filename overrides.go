@@ -0,0 +1,197 @@
+package errorlogger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type (
+	// overrideKey identifies an override by a field key and
+	// the value it must have in an entry.
+	overrideKey struct{ key, value string }
+
+	// levelOverride is the boosted level for an override and
+	// the time it expires. A zero expires never expires.
+	levelOverride struct {
+		level   Level
+		expires time.Time
+	}
+
+	// overrideTable holds the per-field verbosity overrides
+	// for an errorLogger along with the base level that
+	// applies to entries without a matching override.
+	overrideTable struct {
+		mu      sync.Mutex
+		logger  *Logger
+		base    Level
+		entries map[overrideKey]levelOverride
+	}
+)
+
+func newOverrideTable(logger *Logger) *overrideTable {
+	return &overrideTable{
+		logger:  logger,
+		base:    logger.GetLevel(),
+		entries: make(map[overrideKey]levelOverride),
+	}
+}
+
+// update sets the level of the underlying logger to the
+// effective level. The caller must hold t.mu.
+func (t *overrideTable) update() {
+	t.logger.SetLevel(t.effective(time.Now()))
+}
+
+// effective returns the most verbose level of the base level
+// and all unexpired overrides. Expired overrides are removed.
+// The caller must hold t.mu.
+func (t *overrideTable) effective(now time.Time) Level {
+	level := t.base
+	for k, o := range t.entries {
+		if !o.expires.IsZero() && now.After(o.expires) {
+			delete(t.entries, k)
+			continue
+		}
+		if o.level > level {
+			level = o.level
+		}
+	}
+	return level
+}
+
+// allows reports whether entry should be logged, either
+// because it is within the base level or because it carries
+// a field matching an unexpired override of sufficient level.
+func (t *overrideTable) allows(entry *Entry) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if entry.Level <= t.base {
+		return true
+	}
+
+	now := time.Now()
+	allowed, expired := false, false
+	for k, o := range t.entries {
+		if !o.expires.IsZero() && now.After(o.expires) {
+			expired = true
+			continue
+		}
+		if entry.Level > o.level {
+			continue
+		}
+		if v, ok := entry.Data[k.key]; ok && fmt.Sprint(v) == k.value {
+			allowed = true
+		}
+	}
+
+	if expired {
+		t.update()
+	}
+	return allowed
+}
+
+// SetOverride boosts the verbosity of entries that carry the
+// field key with the given value to lvl for the duration ttl.
+// A ttl of zero or less never expires.
+//
+// This enables targeted debugging in production without
+// changing the level for every entry:
+//  log.SetOverride("tenant", "acme", DebugLevel, 30*time.Minute)
+//  log.WithField("tenant", "acme").Debug("logged")
+//  log.WithField("tenant", "other").Debug("not logged")
+//
+// Entries filtered out by the overrides do not reach the
+// hooks of the logger.
+func (e *errorLogger) SetOverride(key, value string, lvl Level, ttl time.Duration) {
+	e.setOverride(key, value, &lvl, ttl, nil)
+}
+
+// ClearOverride removes the override for the field key
+// with the given value, if any.
+func (e *errorLogger) ClearOverride(key, value string) {
+	e.setOverride(key, value, nil, 0, nil)
+}
+
+// setOverride sets the override for the field key with the
+// given value to lvl, or removes it if lvl is nil, and
+// records the change in the audit trail.
+func (e *errorLogger) setOverride(key, value string, lvl *Level, ttl time.Duration, src *changeSource) {
+	t := e.overrideTable()
+	if t == nil {
+		if lvl == nil {
+			return
+		}
+		e.overrides.CompareAndSwap(nil, newOverrideTable(e.Logger))
+		t = e.overrideTable()
+	}
+
+	k := overrideKey{key, value}
+	t.mu.Lock()
+	old, existed := t.entries[k]
+	if lvl == nil {
+		delete(t.entries, k)
+	} else {
+		o := levelOverride{level: *lvl}
+		if ttl > 0 {
+			o.expires = time.Now().Add(ttl)
+		}
+		t.entries[k] = o
+	}
+	t.update()
+	t.mu.Unlock()
+
+	if e.audit != nil {
+		oldName, newName := "none", "none"
+		if existed {
+			oldName = old.level.String()
+		}
+		if lvl != nil {
+			newName = lvl.String()
+		}
+		e.recordChange(src, "override "+key+"="+value, oldName, newName)
+	}
+}
+
+// SetLevel sets the base logger level. Overrides set with
+// SetOverride continue to apply on top of the new level.
+func (e *errorLogger) SetLevel(level Level) {
+	e.setLevel(level, nil)
+}
+
+func (e *errorLogger) setLevel(level Level, src *changeSource) {
+	old := e.GetLevel()
+	if t := e.overrideTable(); t == nil {
+		e.Logger.SetLevel(level)
+	} else {
+		t.mu.Lock()
+		t.base = level
+		t.update()
+		t.mu.Unlock()
+	}
+	if e.audit != nil {
+		e.recordChange(src, "level", old.String(), level.String())
+	}
+}
+
+// GetLevel returns the base logger level, without regard
+// to any overrides.
+func (e *errorLogger) GetLevel() Level {
+	t := e.overrideTable()
+	if t == nil {
+		return e.Logger.GetLevel()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.base
+}
+
+// overrideTable returns the overrides of the logger, or nil
+// if none was ever set. The table is created on first use
+// and stored atomically, since every entry reads it.
+func (e *errorLogger) overrideTable() *overrideTable {
+	t, _ := e.overrides.Load().(*overrideTable)
+	return t
+}
@@ -0,0 +1,159 @@
+package errorlogger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newBufferLogger(level Level) (*errorLogger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	logger := &Logger{
+		Out:       buf,
+		Formatter: &TextFormatter{},
+		Hooks:     make(logrus.LevelHooks),
+		Level:     level,
+	}
+	return newTestStruct(true, "", nil, nil, logger), buf
+}
+
+func Test_errorLogger_SetOverride(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.SetOverride("tenant", "acme", DebugLevel, time.Hour)
+
+	tests := []struct {
+		name   string
+		tenant string
+		msg    string
+		want   bool
+	}{
+		{"matching field", "acme", "acme debug", true},
+		{"other field", "other", "other debug", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			e.WithField("tenant", tt.tenant).Debug(tt.msg)
+			if got := strings.Contains(buf.String(), tt.msg); got != tt.want {
+				t.Errorf("SetOverride() logged %q = %v, want %v", tt.msg, got, tt.want)
+			}
+		})
+	}
+
+	if got := e.GetLevel(); got != InfoLevel {
+		t.Errorf("GetLevel() with override = %v, want %v", got, InfoLevel)
+	}
+
+	buf.Reset()
+	e.Debug("plain debug")
+	if buf.Len() != 0 {
+		t.Errorf("SetOverride() logged entry without field: %q", buf.String())
+	}
+
+	e.SetLevel(WarnLevel)
+	buf.Reset()
+	e.WithField("tenant", "other").Info("other info")
+	if buf.Len() != 0 {
+		t.Errorf("SetLevel() with override logged entry below base level: %q", buf.String())
+	}
+
+	e.SetJSON(false)
+	if _, ok := e.Logger.Formatter.(*pipelineFormatter); !ok {
+		t.Errorf("SetJSON() removed pipeline formatter: %T", e.Logger.Formatter)
+	}
+
+	e.ClearOverride("tenant", "acme")
+	if got := e.Logger.GetLevel(); got != WarnLevel {
+		t.Errorf("ClearOverride() level = %v, want %v", got, WarnLevel)
+	}
+}
+
+func Test_errorLogger_SetOverride_expired(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.SetOverride("user", "42", TraceLevel, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	e.WithField("user", "42").Trace("expired trace")
+	if buf.Len() != 0 {
+		t.Errorf("expired override logged entry: %q", buf.String())
+	}
+	if got := e.Logger.GetLevel(); got != InfoLevel {
+		t.Errorf("expired override level = %v, want %v", got, InfoLevel)
+	}
+}
+
+func Test_errorLogger_SetOverride_concurrent(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			e.SetOverride("tenant", "acme", DebugLevel, 0)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			e.WithField("tenant", "acme").Error("x")
+		}
+	}()
+	wg.Wait()
+}
+
+func TestPipeline_ownLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := &Logger{Out: buf, Formatter: &TextFormatter{}, Hooks: make(logrus.LevelHooks), Level: InfoLevel}
+	a := NewWithOptions(true, "", nil, nil, logger).(*errorLogger)
+	b := NewWithOptions(true, "", nil, nil, logger).(*errorLogger)
+	if a.Logger == b.Logger {
+		t.Fatal("ErrorLoggers share a Logger")
+	}
+
+	// The settings of b apply to b, not to a.
+	b.SetRateLimit(1, time.Hour)
+	defer b.SetRateLimit(0, 0)
+	b.DisableLevel(WarnLevel)
+	for i := 0; i < 3; i++ {
+		b.Info("b")
+		b.Warn("b")
+		a.Info("a")
+		a.Warn("a")
+	}
+	if got := strings.Count(buf.String(), "msg=b"); got != 1 {
+		t.Errorf("b logged %d entries, want 1 with its rate limit", got)
+	}
+	if got := strings.Count(buf.String(), "msg=a"); got != 6 {
+		t.Errorf("a logged %d entries, want 6", got)
+	}
+
+	b.SetFormatter(NewJSONFormatter(false))
+	if _, ok := a.formatter().(*TextFormatter); !ok {
+		t.Errorf("SetFormatter() of b changed the formatter of a to %T", a.formatter())
+	}
+}
+
+func TestPipeline_hooks(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	var fired []string
+	e.AddHook(&countHook{levels: AllLevels, fn: func(entry *Entry) { fired = append(fired, entry.Message) }})
+
+	e.DisableLevel(WarnLevel)
+	e.SetOverride("tenant", "acme", DebugLevel, 0)
+	e.Warn("disabled")
+	e.Debug("filtered")
+	e.WithField("tenant", "acme").Debug("boosted")
+	e.Info("logged")
+
+	want := []string{"boosted", "logged"}
+	if strings.Join(fired, ",") != strings.Join(want, ",") {
+		t.Errorf("hooks fired for %v, want %v", fired, want)
+	}
+	if strings.Contains(buf.String(), droppedKey) {
+		t.Errorf("output = %q, want no internal fields", buf.String())
+	}
+}
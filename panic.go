@@ -0,0 +1,76 @@
+package errorlogger
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is a structured error created from a recovered
+// panic value. Panic values that are not errors (strings,
+// custom structs, ...) are preserved in Value along with
+// their type and the stack at the time of recovery.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// NewPanicError returns a new PanicError for the panic value v,
+// capturing the current stack. If v is already a *PanicError,
+// it is returned unchanged.
+func NewPanicError(v interface{}) *PanicError {
+	if pe, ok := v.(*PanicError); ok {
+		return pe
+	}
+	return &PanicError{Value: v, Stack: debug.Stack()}
+}
+
+func (p *PanicError) Error() string { return fmt.Sprintf("panic: %v", p.Value) }
+
+// Type returns the Go type of the panic value.
+func (p *PanicError) Type() string { return fmt.Sprintf("%T", p.Value) }
+
+// Unwrap returns the panic value if it is an error.
+func (p *PanicError) Unwrap() error {
+	if err, ok := p.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// LogPanic converts the panic value v into a *PanicError,
+// logs it with the panic type and stack as fields, and
+// returns it. A nil v returns nil. If the stack passes
+// through cgo, the cgo field is set to true and its C
+// frames are cleaned up with CleanCgoStack.
+//
+// Use it in custom recover blocks:
+//  defer func() {
+//  	if r := recover(); r != nil {
+//  		err = log.LogPanic(r)
+//  	}
+//  }()
+func (e *errorLogger) LogPanic(v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	pe := NewPanicError(v)
+	stack, cgo := CleanCgoStack(pe.Stack)
+	fields := Fields{
+		"panic_type": pe.Type(),
+		StackKey:     string(stack),
+	}
+	if cgo {
+		fields[CgoKey] = true
+	}
+	e.WithFields(fields).Error(pe)
+	return pe
+}
+
+// Recover recovers from a panic, if any, and logs it with
+// LogPanic. The panic is not propagated. Use it with defer:
+//  defer log.Recover()
+func (e *errorLogger) Recover() {
+	if r := recover(); r != nil {
+		_ = e.LogPanic(r)
+	}
+}
@@ -0,0 +1,64 @@
+package errorlogger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type customPanic struct{ code int }
+
+func Test_errorLogger_LogPanic(t *testing.T) {
+	tests := []struct {
+		name     string
+		v        interface{}
+		wantType string
+		wantErr  error
+	}{
+		{"string", "boom", "string", nil},
+		{"struct", customPanic{42}, "errorlogger.customPanic", nil},
+		{"error", errFake, "*errors.errorString", errFake},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			err := e.LogPanic(tt.v)
+
+			var pe *PanicError
+			if !errors.As(err, &pe) {
+				t.Fatalf("LogPanic() = %T, want *PanicError", err)
+			}
+			if got := pe.Type(); got != tt.wantType {
+				t.Errorf("PanicError.Type() = %q, want %q", got, tt.wantType)
+			}
+			if len(pe.Stack) == 0 {
+				t.Errorf("PanicError.Stack is empty")
+			}
+			if got := errors.Unwrap(err); got != tt.wantErr {
+				t.Errorf("PanicError.Unwrap() = %v, want %v", got, tt.wantErr)
+			}
+			if !strings.Contains(buf.String(), "panic_type=") {
+				t.Errorf("LogPanic() output missing panic_type field: %q", buf.String())
+			}
+			if NewPanicError(pe) != pe {
+				t.Errorf("NewPanicError(*PanicError) did not return the same value")
+			}
+		})
+	}
+
+	e, _ := newBufferLogger(InfoLevel)
+	if err := e.LogPanic(nil); err != nil {
+		t.Errorf("LogPanic(nil) = %v, want nil", err)
+	}
+}
+
+func Test_errorLogger_Recover(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	func() {
+		defer e.Recover()
+		panic("recovered")
+	}()
+	if !strings.Contains(buf.String(), "panic: recovered") {
+		t.Errorf("Recover() output = %q, want logged panic", buf.String())
+	}
+}
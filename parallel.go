@@ -0,0 +1,138 @@
+package errorlogger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	// parallelHooks is a hook that fires a group of hooks
+	// concurrently for each entry and waits for all of them.
+	parallelHooks struct {
+		mu    sync.RWMutex
+		hooks []logrus.Hook
+	}
+
+	// hookErrors are the errors returned by the hooks of a
+	// parallel group for one entry.
+	hookErrors []error
+)
+
+// AddParallelHook adds hooks that fire concurrently with
+// each other for every entry, instead of one after another,
+// so that independent destinations such as Sentry, Slack,
+// and metrics do not add up their latencies. The logging
+// call waits until every parallel hook has returned.
+//
+//  log.AddParallelHook(sentryHook, slackHook, metricsHook)
+//
+// Each parallel hook fires on its own copy of the entry, so
+// changes a hook makes to the entry are not seen by other
+// hooks or written to the output. A panic in a parallel
+// hook is recovered and reported as an error of that hook
+// without affecting the others. Parallel hooks are measured
+// and subject to the hook policy like hooks added with
+// AddHook.
+func (e *errorLogger) AddParallelHook(hooks ...logrus.Hook) {
+	if e.parallel == nil {
+		e.parallel = &parallelHooks{}
+		e.gate.add(e.parallel)
+	}
+	for _, hook := range hooks {
+		h := e.timeHook(hook)
+		e.parallel.mu.Lock()
+		e.parallel.hooks = append(e.parallel.hooks, h)
+		e.parallel.mu.Unlock()
+	}
+}
+
+// Levels returns all levels; each hook of the group is only
+// fired for its own levels.
+func (p *parallelHooks) Levels() []Level { return logrus.AllLevels }
+
+// Fire fires the hooks of the group for the level of entry
+// concurrently and returns their errors.
+func (p *parallelHooks) Fire(entry *Entry) error {
+	p.mu.RLock()
+	hooks := p.hooks
+	p.mu.RUnlock()
+
+	errs := make(hookErrors, len(hooks))
+	var wg sync.WaitGroup
+	for i, h := range hooks {
+		if !hasLevel(h.Levels(), entry.Level) {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, h logrus.Hook, entry *Entry) {
+			defer wg.Done()
+			errs[i] = fireSafely(h, entry)
+		}(i, h, copyEntry(entry))
+	}
+	wg.Wait()
+
+	n := 0
+	for _, err := range errs {
+		if err != nil {
+			errs[n] = err
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	return errs[:n]
+}
+
+func (e hookErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// fireSafely fires h, converting a panic into a
+// *PanicError. Errors are prefixed with the hook type.
+func fireSafely(h logrus.Hook, entry *Entry) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = NewPanicError(v)
+		}
+		if err != nil {
+			err = fmt.Errorf("hook %s: %w", hookName(h), err)
+		}
+	}()
+	return h.Fire(entry)
+}
+
+// hookName returns the name of the type of h, or of the
+// hook measured by h.
+func hookName(h logrus.Hook) string {
+	if t, ok := h.(*timedHook); ok {
+		return t.name
+	}
+	return fmt.Sprintf("%T", h)
+}
+
+func hasLevel(levels []Level, level Level) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// copyEntry returns a copy of entry with its own Data.
+func copyEntry(entry *Entry) *Entry {
+	c := *entry
+	c.Data = make(Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		c.Data[k] = v
+	}
+	return &c
+}
@@ -0,0 +1,87 @@
+package errorlogger
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countHook counts fires at its levels and then runs fn.
+type countHook struct {
+	levels []Level
+	n      int32
+	fn     func(entry *Entry)
+}
+
+func (h *countHook) Levels() []Level { return h.levels }
+
+func (h *countHook) Fire(entry *Entry) error {
+	atomic.AddInt32(&h.n, 1)
+	if h.fn != nil {
+		h.fn(entry)
+	}
+	return nil
+}
+
+func TestErrorLogger_AddParallelHook(t *testing.T) {
+	sleep := func(*Entry) { time.Sleep(50 * time.Millisecond) }
+	slow := []*countHook{{levels: AllLevels, fn: sleep}, {levels: AllLevels, fn: sleep}, {levels: AllLevels, fn: sleep}}
+	panicky := &countHook{levels: AllLevels, fn: func(*Entry) { panic("hook bug") }}
+	mutating := &countHook{levels: AllLevels, fn: func(e *Entry) { e.Data["mutated"] = true }}
+	errorsOnly := &countHook{levels: []Level{ErrorLevel}}
+
+	e, buf := newBufferLogger(InfoLevel)
+	e.AddParallelHook(slow[0], slow[1], slow[2], panicky)
+	e.AddParallelHook(mutating, errorsOnly)
+
+	start := time.Now()
+	e.Info("fanned out")
+	if elapsed := time.Since(start); elapsed > 140*time.Millisecond {
+		t.Errorf("parallel hooks took %v, want about 50ms", elapsed)
+	}
+	for i, h := range append(slow, panicky, mutating) {
+		if h.n != 1 {
+			t.Errorf("hook %d fired %d times, want 1", i, h.n)
+		}
+	}
+	if errorsOnly.n != 0 {
+		t.Errorf("error level hook fired %d times for an Info entry", errorsOnly.n)
+	}
+	if strings.Contains(buf.String(), "mutated") {
+		t.Error("a change made by a parallel hook reached the output")
+	}
+	if st := e.Stats(); len(st.Hooks) != 6 || st.Hooks[0].Fires != 1 {
+		t.Errorf("Stats().Hooks = %+v", st.Hooks)
+	}
+}
+
+func Test_parallelHooks_Fire(t *testing.T) {
+	errHook := errors.New("hook failed")
+	p := &parallelHooks{}
+	p.hooks = append(p.hooks,
+		&countHook{levels: AllLevels, fn: func(*Entry) { panic("boom") }},
+		&countHook{levels: AllLevels},
+		hookFunc(func(*Entry) error { return errHook }),
+	)
+
+	err := p.Fire(&Entry{Level: InfoLevel})
+	var errs hookErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("Fire() = %v, want 2 errors", err)
+	}
+	var pe *PanicError
+	if !errors.As(errs[0], &pe) || !errors.Is(errs[1], errHook) {
+		t.Errorf("Fire() = %v, want a panic and the hook error", err)
+	}
+	if !strings.Contains(err.Error(), "hook *errorlogger.countHook: panic: boom") {
+		t.Errorf("Fire() = %q", err)
+	}
+}
+
+// hookFunc is a hook for all levels.
+type hookFunc func(*Entry) error
+
+func (f hookFunc) Levels() []Level         { return AllLevels }
+func (f hookFunc) Fire(entry *Entry) error { return f(entry) }
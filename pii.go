@@ -0,0 +1,156 @@
+package errorlogger
+
+import (
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// piiKey marks the warnings logged by the PII scanner so
+// that they are not scanned themselves.
+const piiKey = "pii_kind"
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?\(?\b\d{3}\)?[\s.-]\d{3}[\s.-]\d{4}\b|\+\d{10,15}\b`)
+	ibanPattern  = regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`)
+
+	// piiWarned records the call sites, fields, and kinds
+	// that were already warned about.
+	piiWarned sync.Map
+
+	// packageDir is the directory of this package, used to
+	// skip its frames when looking for the call site.
+	packageDir = func() string {
+		_, file, _, _ := runtime.Caller(0)
+		return filepath.Dir(file)
+	}()
+)
+
+// DetectPII reports whether s contains a common kind of
+// personally identifiable information and returns the
+// kind: "email", "phone", or "iban". IBANs are confirmed
+// with their check digits to avoid false positives.
+func DetectPII(s string) (kind string, ok bool) {
+	switch {
+	case emailPattern.MatchString(s):
+		return "email", true
+	case phonePattern.MatchString(s):
+		return "phone", true
+	}
+	for _, m := range ibanPattern.FindAllString(s, -1) {
+		if validIBAN(m) {
+			return "iban", true
+		}
+	}
+	return "", false
+}
+
+// validIBAN checks the ISO 13616 mod 97 check digits.
+func validIBAN(s string) bool {
+	s = strings.ReplaceAll(s, " ", "")
+	if len(s) < 15 || len(s) > 34 {
+		return false
+	}
+	var digits strings.Builder
+	for _, r := range s[4:] + s[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			fmt.Fprintf(&digits, "%d", r-'A'+10)
+		default:
+			return false
+		}
+	}
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// scanPII warns once per call site, field, and kind about
+// field values of entry that look like personal data. It
+// runs in the pipeline when a Development preset is set, so
+// that leaks are caught before production.
+func (e *errorLogger) scanPII(entry *Entry) {
+	if _, ok := entry.Data[piiKey]; ok {
+		return
+	}
+
+	var site string
+	for k, v := range entry.Data {
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = v
+		case error:
+			s = v.Error()
+		case fmt.Stringer:
+			s = v.String()
+		default:
+			continue
+		}
+
+		kind, ok := DetectPII(s)
+		if !ok {
+			continue
+		}
+		if site == "" {
+			site = callSite()
+		}
+		if _, loaded := piiWarned.LoadOrStore(site+"\x00"+k+"\x00"+kind, struct{}{}); loaded {
+			continue
+		}
+		e.WithFields(Fields{
+			piiKey:      kind,
+			"pii_field": k,
+			"call_site": site,
+		}).Warn("possible personal data in log field")
+	}
+}
+
+// callSite returns the file:line of the first caller
+// outside of logrus and the non-test files of this package.
+func callSite() string {
+	f := callerFrame()
+	if f.File == "" {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s:%d", f.File, f.Line)
+}
+
+// callerFrame returns the frame of the first caller outside
+// of logrus and the non-test files of this package, or a
+// zero frame if there is none.
+func callerFrame() runtime.Frame {
+	return callerFrameSkip(0)
+}
+
+// callerFrameSkip returns the frame skip frames above the
+// first caller outside of logrus and the non-test files of
+// this package, or a zero frame if there is none.
+func callerFrameSkip(skip int) runtime.Frame {
+	pcs := make([]uintptr, 32+skip)
+	n := runtime.Callers(4, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	found := false
+	for {
+		f, more := frames.Next()
+		if !found {
+			found = !strings.Contains(f.Function, "github.com/sirupsen/logrus.") &&
+				(filepath.Dir(f.File) != packageDir || strings.HasSuffix(f.File, "_test.go"))
+		}
+		if found {
+			if skip == 0 {
+				return f
+			}
+			skip--
+		}
+		if !more {
+			return runtime.Frame{}
+		}
+	}
+}
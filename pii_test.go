@@ -0,0 +1,57 @@
+package errorlogger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDetectPII(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOk bool
+	}{
+		{"contact jane.doe@example.com", "email", true},
+		{"call (555) 123-4567", "phone", true},
+		{"+14155552671", "phone", true},
+		{"GB82 WEST 1234 5698 7654 32", "iban", true},
+		{"DE89370400440532013000", "iban", true},
+		{"DE89370400440532013001", "", false}, // bad check digits
+		{"request 12345 took 30ms", "", false},
+		{"2022-04-01T12:00:00Z", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := DetectPII(tt.in)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("DetectPII(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestErrorLogger_scanPII(t *testing.T) {
+	e, buf := newBufferLogger(DebugLevel)
+
+	e.WithField("user", "jane@example.com").Info("not scanned without a development preset")
+	if strings.Contains(buf.String(), "personal data") {
+		t.Fatalf("PII scanned without development preset: %q", buf.String())
+	}
+
+	e.SetPreset(DevelopmentPreset)
+	for i := 0; i < 3; i++ {
+		e.WithField("user", "jane@example.com").Info("signup") // same call site
+	}
+	e.WithError(errors.New("iban DE89370400440532013000 rejected")).Info("payment")
+
+	out := buf.String()
+	if got := strings.Count(out, "possible personal data"); got != 2 {
+		t.Errorf("logged %d PII warnings, want 2:\n%s", got, out)
+	}
+	for _, want := range []string{"pii_field=user", "pii_kind=email", "pii_kind=iban", "call_site=", "pii_test.go:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("PII warning missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package errorlogger
+
+import (
+	"io"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// droppedKey marks an entry dropped by the pipeline so that
+// pipelineFormatter does not write it.
+const droppedKey = "\x00errorlogger_dropped"
+
+type (
+	// pipelineHook runs the entry processing stages of an
+	// errorLogger and then fires the hooks added to it. It is
+	// the only hook of the underlying Logger for every level,
+	// so that entries dropped by the stages, such as those of
+	// a disabled level or beyond the rate limit, never reach
+	// the hooks.
+	//
+	// Hooks added with AddHook are held by the pipelineHook;
+	// hooks added to the Logger directly fire for every entry.
+	pipelineHook struct {
+		e     *errorLogger
+		mu    sync.RWMutex
+		hooks logrus.LevelHooks
+	}
+
+	// pipelineFormatter wraps the formatter selected by the
+	// user. It skips the entries dropped by the pipelineHook
+	// and passes the others to the Backend of the logger or
+	// formats them with the wrapped formatter.
+	pipelineFormatter struct {
+		Formatter
+		e *errorLogger
+	}
+
+	// pipelineOutput wraps the output selected by the user.
+	// logrus writes every entry to the output, even one that
+	// pipelineFormatter formatted as nothing, so pipelineOutput
+	// skips empty writes to keep the entries dropped by the
+	// pipeline and those passed to a Backend out of the sinks.
+	pipelineOutput struct {
+		io.Writer
+	}
+)
+
+// Levels returns all levels.
+func (h *pipelineHook) Levels() []Level { return logrus.AllLevels }
+
+// Fire runs entry through the pipeline stages and then fires
+// the hooks of its level. The reported caller is corrected
+// first and mutators run next, so that the later stages and
+// the hooks see the final caller and fields.
+func (h *pipelineHook) Fire(entry *Entry) error {
+	if !h.e.process(entry) {
+		entry.Data[droppedKey] = struct{}{}
+		return nil
+	}
+	h.mu.RLock()
+	hooks := h.hooks[entry.Level]
+	h.mu.RUnlock()
+	for _, hook := range hooks {
+		if err := hook.Fire(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add adds hook for its levels.
+func (h *pipelineHook) add(hook logrus.Hook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, level := range hook.Levels() {
+		h.hooks[level] = append(h.hooks[level], hook)
+	}
+}
+
+// replace replaces the hooks and returns the old ones.
+func (h *pipelineHook) replace(hooks logrus.LevelHooks) logrus.LevelHooks {
+	if hooks == nil {
+		hooks = make(logrus.LevelHooks)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	old := h.hooks
+	h.hooks = hooks
+	return old
+}
+
+// process runs the pipeline stages on entry and reports
+// whether it is logged.
+func (e *errorLogger) process(entry *Entry) bool {
+	if e.levelDisabled(entry.Level) {
+		return false
+	}
+	e.fixCaller(entry)
+	e.mutate(entry)
+	e.normalizeKeys(entry)
+	if o := e.overrideTable(); o != nil && !o.allows(entry) {
+		return false
+	}
+	if !e.validate(entry) {
+		return false
+	}
+	if !e.rateAllows(entry) {
+		return false
+	}
+	if e.development() {
+		e.scanPII(entry)
+	}
+	return true
+}
+
+// Format formats entry using the wrapped formatter, or
+// passes it to the Backend of the logger, unless the
+// pipelineHook dropped it. Returning a nil slice drops the
+// entry.
+func (f *pipelineFormatter) Format(entry *Entry) ([]byte, error) {
+	if _, ok := entry.Data[droppedKey]; ok {
+		return nil, nil
+	}
+	f.e.stats.observeEntry(entry.Level)
+	if f.e.backend != nil {
+		f.e.backend.Log(entry.Level, entry.Message, entry.Data)
+		return nil, nil
+	}
+	b, err := f.Formatter.Format(entry)
+	f.e.stats.observeBytes(entry.Level, len(b))
+	return b, err
+}
+
+// Write writes p to the wrapped output unless p is empty.
+func (o pipelineOutput) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return o.Writer.Write(p)
+}
+
+// output returns the output selected by the user, without
+// the pipeline.
+func (e *errorLogger) output() io.Writer {
+	return outputOf(e.Logger)
+}
+
+// outputOf returns the output of l selected by the user,
+// without the pipeline.
+func outputOf(l *Logger) io.Writer {
+	if o, ok := l.Out.(pipelineOutput); ok {
+		return o.Writer
+	}
+	return l.Out
+}
+
+// formatter returns the formatter selected by the user,
+// without the pipeline.
+func (e *errorLogger) formatter() Formatter {
+	return formatterOf(e.Logger)
+}
+
+// formatterOf returns the formatter of l selected by the
+// user, without the pipeline.
+func formatterOf(l *Logger) Formatter {
+	if p, ok := l.Formatter.(*pipelineFormatter); ok {
+		return p.Formatter
+	}
+	return l.Formatter
+}
+
+// installPipeline wraps the current formatter with the
+// pipelineFormatter and the current output with the
+// pipelineOutput, and moves the hooks of the Logger to the
+// pipelineHook. The Logger must not be used by another
+// errorLogger; see ownLogger.
+func (e *errorLogger) installPipeline() {
+	e.Logger.SetFormatter(&pipelineFormatter{Formatter: e.Logger.Formatter, e: e})
+	e.Logger.SetOutput(pipelineOutput{e.Logger.Out})
+
+	e.gate = &pipelineHook{e: e, hooks: make(logrus.LevelHooks)}
+	gate := make(logrus.LevelHooks, len(logrus.AllLevels))
+	gate.Add(e.gate)
+	e.gate.replace(e.Logger.ReplaceHooks(gate))
+}
+
+// ownLogger returns l, or a copy of l without its hooks if
+// l is used by another errorLogger, so that the pipeline of
+// every errorLogger has a Logger of its own.
+func ownLogger(l *Logger) *Logger {
+	if _, ok := l.Formatter.(*pipelineFormatter); !ok {
+		return l
+	}
+	return &Logger{
+		Out:          outputOf(l),
+		Formatter:    formatterOf(l),
+		Hooks:        make(logrus.LevelHooks),
+		Level:        l.GetLevel(),
+		ReportCaller: l.ReportCaller,
+		ExitFunc:     l.ExitFunc,
+	}
+}
+
+// AddHook adds a hook to the logger. The hook fires only for
+// entries that pass the pipeline stages of the logger. Its
+// execution time is measured and reported in Stats, and the
+// hook is subject to the policy set with SetHookPolicy.
+func (e *errorLogger) AddHook(hook logrus.Hook) {
+	e.gate.add(e.timeHook(hook))
+}
+
+// ReplaceHooks replaces the hooks added with AddHook and
+// returns the old ones.
+func (e *errorLogger) ReplaceHooks(hooks logrus.LevelHooks) logrus.LevelHooks {
+	return e.gate.replace(hooks)
+}
+
+// SetFormatter sets the logger formatter. The pipeline of
+// the logger is preserved and runs before formatter. The
+// formatter is passed on to the Backend of a logger created
+// with NewWithBackend.
+func (e *errorLogger) SetFormatter(formatter logrus.Formatter) {
+	if e.backend != nil {
+		e.backend.SetFormatter(formatter)
+	}
+	e.Logger.SetFormatter(&pipelineFormatter{Formatter: formatter, e: e})
+}
@@ -0,0 +1,59 @@
+package errorlogger
+
+import (
+	"testing"
+	"time"
+)
+
+// writeCounter counts the writes to it.
+type writeCounter struct{ n, empty int }
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	w.n++
+	if len(p) == 0 {
+		w.empty++
+	}
+	return len(p), nil
+}
+
+func TestErrorLogger_pipelineOutput(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(e *errorLogger)
+		log   func(e *errorLogger)
+		want  int
+	}{
+		{"logged", func(e *errorLogger) {}, func(e *errorLogger) {
+			e.Warn("w")
+			e.Err(errFake)
+		}, 2},
+		{"disabled level", func(e *errorLogger) { e.DisableLevel(WarnLevel) }, func(e *errorLogger) {
+			for i := 0; i < 5; i++ {
+				e.Warn("w")
+			}
+		}, 0},
+		{"rate limit", func(e *errorLogger) { e.SetRateLimit(1, time.Hour) }, func(e *errorLogger) {
+			for i := 0; i < 3; i++ {
+				e.Err(errFake)
+			}
+		}, 1},
+		{"below level", func(e *errorLogger) {}, func(e *errorLogger) { e.Debug("d") }, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newBufferLogger(InfoLevel)
+			w := &writeCounter{}
+			e.SetOutput(w)
+			tt.setup(e)
+			defer e.SetRateLimit(0, 0)
+
+			tt.log(e)
+			if w.n != tt.want || w.empty != 0 {
+				t.Errorf("output got %d writes, %d empty, want %d writes", w.n, w.empty, tt.want)
+			}
+			if e.Writer() != Writer(w) {
+				t.Errorf("Writer() = %T, want the output set with SetOutput", e.Writer())
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package errorlogger
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// Preset is a named bundle of logger settings suited to an
+// environment, such as development or production.
+type Preset struct {
+	// Name identifies the preset.
+	Name string `json:"name"`
+
+	// Level is the log level applied by the preset.
+	Level Level `json:"level"`
+
+	// JSON selects the JSON formatter instead of the
+	// default text formatter.
+	JSON bool `json:"json,omitempty"`
+
+	// Development enables checks that are too strict or
+	// too expensive for production, e.g. failed assertions
+	// panic instead of only being logged, field values
+	// are scanned for personal data, and errors wrapped as
+	// set by SetErrorWrap are audited for changes in how
+	// they match.
+	Development bool `json:"development,omitempty"`
+
+	// Options are the display options applied by the
+	// preset. Zero options leave those of the logger
+	// unchanged.
+	Options Options `json:"options"`
+}
+
+var (
+	// DevelopmentPreset logs colorized text at DebugLevel
+	// and enables development checks.
+	DevelopmentPreset = Preset{Name: "development", Level: DebugLevel, Development: true}
+
+	// StagingPreset logs JSON at DebugLevel without
+	// development checks.
+	StagingPreset = Preset{Name: "staging", Level: DebugLevel, JSON: true}
+
+	// ProductionPreset logs JSON at InfoLevel.
+	ProductionPreset = Preset{Name: "production", Level: InfoLevel, JSON: true}
+
+	// PresetEnvVars are the environment variables checked,
+	// in order, by PresetFromEnv.
+	PresetEnvVars = []string{"APP_ENV", "GO_ENV"}
+)
+
+var (
+	presetsMu sync.RWMutex
+	presets   = map[string]Preset{
+		DevelopmentPreset.Name: DevelopmentPreset,
+		StagingPreset.Name:     StagingPreset,
+		ProductionPreset.Name:  ProductionPreset,
+	}
+	presetAliases = map[string]string{
+		"dev":   DevelopmentPreset.Name,
+		"stage": StagingPreset.Name,
+		"prod":  ProductionPreset.Name,
+	}
+)
+
+// RegisterPreset adds a custom preset, or replaces the
+// preset with the same name. Names are case insensitive.
+//
+// Registered presets can be selected by the environment,
+// e.g. APP_ENV=qa for a preset named "qa", and applied with
+// SetPresetFromEnv without code branching:
+//  func init() {
+//  	errorlogger.RegisterPreset(errorlogger.Preset{Name: "qa", Level: errorlogger.DebugLevel, JSON: true})
+//  }
+//
+//  func main() {
+//  	log.SetPresetFromEnv()
+//  	...
+//  }
+func RegisterPreset(p Preset) {
+	presetsMu.Lock()
+	presets[strings.ToLower(p.Name)] = p
+	presetsMu.Unlock()
+}
+
+// LookupPreset returns the registered preset with the given
+// name. The aliases dev, stage, and prod are accepted for
+// the built in presets.
+func LookupPreset(name string) (Preset, bool) {
+	name = strings.ToLower(name)
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	if p, ok := presets[name]; ok {
+		return p, true
+	}
+	p, ok := presets[presetAliases[name]]
+	return p, ok
+}
+
+// PresetFromEnv returns the preset named by the first of
+// PresetEnvVars that is set, e.g. APP_ENV=production. It
+// returns false if none is set or the named preset is not
+// registered.
+func PresetFromEnv() (Preset, bool) {
+	name := envPresetName()
+	if name == "" {
+		return Preset{}, false
+	}
+	return LookupPreset(name)
+}
+
+// envPresetName returns the lower case preset name selected
+// by the environment, resolving aliases.
+func envPresetName() string {
+	for _, key := range PresetEnvVars {
+		if v := strings.ToLower(strings.TrimSpace(os.Getenv(key))); v != "" {
+			if alias, ok := presetAliases[v]; ok {
+				return alias
+			}
+			return v
+		}
+	}
+	return ""
+}
+
+// SetPreset applies the settings of p to the logger.
+func (e *errorLogger) SetPreset(p Preset) {
+	e.preset.Store(p)
+	e.SetLevel(p.Level)
+	if p.JSON {
+		e.SetJSON(false)
+	} else {
+		e.SetText()
+	}
+	if p.Options != (Options{}) {
+		// Invalid options leave those of the logger unchanged.
+		_ = e.SetOptions(p.Options)
+	}
+}
+
+// SetPresetFromEnv applies the preset returned by
+// PresetFromEnv, if any, and reports whether it did. No
+// logger, not even the global one, uses the preset selected
+// by the environment unless this is called.
+func (e *errorLogger) SetPresetFromEnv() bool {
+	p, ok := PresetFromEnv()
+	if ok {
+		e.SetPreset(p)
+	}
+	return ok
+}
+
+// Preset returns the preset most recently applied to the
+// logger. The zero value is returned if none was applied.
+func (e *errorLogger) Preset() Preset {
+	p, _ := e.preset.Load().(Preset)
+	return p
+}
+
+// development reports whether the preset of the logger
+// enables development checks.
+func (e *errorLogger) development() bool { return e.Preset().Development }
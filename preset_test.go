@@ -0,0 +1,104 @@
+package errorlogger
+
+import (
+	"io"
+	"testing"
+)
+
+func TestLookupPreset(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{"production", "production", true},
+		{"PROD", "production", true},
+		{"dev", "development", true},
+		{"stage", "staging", true},
+		{"qa", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := LookupPreset(tt.name)
+			if ok != tt.wantOk || got.Name != tt.want {
+				t.Errorf("LookupPreset(%q) = %q, %v, want %q, %v", tt.name, got.Name, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestPresetFromEnv(t *testing.T) {
+	tests := []struct {
+		name          string
+		appEnv, goEnv string
+		want          string
+		wantOk        bool
+	}{
+		{"unset", "", "", "", false},
+		{"APP_ENV", "production", "", "production", true},
+		{"GO_ENV", "", "staging", "staging", true},
+		{"APP_ENV first", "dev", "production", "development", true},
+		{"unknown", "qa", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_ENV", tt.appEnv)
+			t.Setenv("GO_ENV", tt.goEnv)
+			got, ok := PresetFromEnv()
+			if ok != tt.wantOk || got.Name != tt.want {
+				t.Errorf("PresetFromEnv() = %q, %v, want %q, %v", got.Name, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestRegisterPreset(t *testing.T) {
+	defer func() {
+		presetsMu.Lock()
+		delete(presets, "qa")
+		presetsMu.Unlock()
+	}()
+
+	t.Setenv("APP_ENV", "qa")
+	qa := Preset{Name: "QA", Level: TraceLevel}
+	RegisterPreset(qa)
+
+	if got, ok := PresetFromEnv(); !ok || got != qa {
+		t.Errorf("PresetFromEnv() = %+v, %v, want %+v", got, ok, qa)
+	}
+	if got := Log.Preset(); got == qa {
+		t.Errorf("Log.Preset() = %+v, want the preset of the environment applied only by SetPresetFromEnv", got)
+	}
+
+	e, _ := newBufferLogger(InfoLevel)
+	if !e.SetPresetFromEnv() {
+		t.Fatal("SetPresetFromEnv() = false, want true")
+	}
+	if got := e.Preset(); got != qa {
+		t.Errorf("Preset() = %+v, want %+v", got, qa)
+	}
+	if got := e.GetLevel(); got != TraceLevel {
+		t.Errorf("GetLevel() = %v, want %v", got, TraceLevel)
+	}
+
+	t.Setenv("APP_ENV", "")
+	if e.SetPresetFromEnv() {
+		t.Error("SetPresetFromEnv() without APP_ENV = true, want false")
+	}
+}
+
+func TestErrorLogger_SetPreset_concurrent(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	e.SetOutput(io.Discard)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			e.SetPreset(Preset{Level: InfoLevel, Development: i%2 == 0})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_ = e.Err(errFake)
+	}
+	<-done
+}
@@ -0,0 +1,257 @@
+package errorlogger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PresetEnvPrefix is the prefix of the environment variables
+// holding presets in an EnvPresetStore, e.g.
+//  ERRORLOGGER_PRESET_QA={"name":"qa","level":"debug","json":true}
+const PresetEnvPrefix = "ERRORLOGGER_PRESET_"
+
+type (
+	// PresetStore persists named presets so that they can be
+	// shared between tools. Implementations must be safe for
+	// concurrent use.
+	PresetStore interface {
+		// Load returns the preset with the given name. It
+		// returns an error wrapping fs.ErrNotExist if there
+		// is none.
+		Load(name string) (Preset, error)
+
+		// Save stores p under p.Name, replacing a preset
+		// with the same name.
+		Save(p Preset) error
+
+		// List returns the names of the stored presets.
+		List() ([]string, error)
+	}
+
+	// FilePresetStore stores each preset as a JSON file named
+	// after the preset in a directory.
+	FilePresetStore string
+
+	// EnvPresetStore reads presets as JSON from environment
+	// variables named PresetEnvPrefix followed by the upper
+	// case preset name. Save sets the variable, which is
+	// inherited by child processes.
+	EnvPresetStore struct{}
+)
+
+// presetStore is the store set with SetPresetStore.
+var presetStore PresetStore
+
+// SetPresetStore sets the store consulted by ListPresets and
+// ApplyPreset after the registered presets, and written by
+// SavePreset. A nil store disables persistence.
+//  errorlogger.SetPresetStore(errorlogger.FilePresetStore("/etc/myteam/presets"))
+func SetPresetStore(s PresetStore) {
+	presetsMu.Lock()
+	presetStore = s
+	presetsMu.Unlock()
+}
+
+func getPresetStore() PresetStore {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	return presetStore
+}
+
+// SavePreset registers p and saves it to the store set with
+// SetPresetStore, if any.
+func SavePreset(p Preset) error {
+	if err := checkPresetName(p.Name); err != nil {
+		return err
+	}
+	RegisterPreset(p)
+	if s := getPresetStore(); s != nil {
+		return s.Save(p)
+	}
+	return nil
+}
+
+// ListPresets returns the sorted, lower case names of the
+// registered presets and of the presets in the store set
+// with SetPresetStore.
+func ListPresets() ([]string, error) {
+	seen := make(map[string]bool)
+	presetsMu.RLock()
+	for name := range presets {
+		seen[name] = true
+	}
+	presetsMu.RUnlock()
+
+	var err error
+	if s := getPresetStore(); s != nil {
+		var stored []string
+		stored, err = s.List()
+		for _, name := range stored {
+			seen[strings.ToLower(name)] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, err
+}
+
+// ApplyPreset applies the preset with the given name, looked
+// up with LookupPreset or else loaded from the store set with
+// SetPresetStore. It returns an error wrapping fs.ErrNotExist
+// if there is no such preset.
+func (e *errorLogger) ApplyPreset(name string) error {
+	if p, ok := LookupPreset(name); ok {
+		e.SetPreset(p)
+		return nil
+	}
+	s := getPresetStore()
+	if s == nil {
+		return fmt.Errorf("preset %q: %w", name, fs.ErrNotExist)
+	}
+	p, err := s.Load(name)
+	if err != nil {
+		return err
+	}
+	e.SetPreset(p)
+	return nil
+}
+
+// checkPresetName returns an error wrapping ErrInvalid if
+// name cannot be stored.
+func checkPresetName(name string) error {
+	if name == "" || strings.ContainsAny(name, `/\=`) || name == "." || name == ".." {
+		return fmt.Errorf("invalid preset name %q: %w", name, ErrInvalid)
+	}
+	return nil
+}
+
+func (d FilePresetStore) path(name string) string {
+	return filepath.Join(string(d), strings.ToLower(name)+".json")
+}
+
+// Load reads the preset from the file name.json.
+func (d FilePresetStore) Load(name string) (Preset, error) {
+	if err := checkPresetName(name); err != nil {
+		return Preset{}, err
+	}
+	b, err := os.ReadFile(d.path(name))
+	if err != nil {
+		return Preset{}, err
+	}
+	return decodePreset(name, b)
+}
+
+// Save writes the preset to the file name.json, creating
+// the directory if needed. The file is replaced atomically.
+func (d FilePresetStore) Save(p Preset) error {
+	if err := checkPresetName(p.Name); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(string(d), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(string(d), ".preset-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path(p.Name))
+}
+
+// List returns the names of the .json files in the
+// directory. A missing directory holds no presets.
+func (d FilePresetStore) List() ([]string, error) {
+	entries, err := os.ReadDir(string(d))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, de := range entries {
+		if name := de.Name(); !de.IsDir() && !strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".json") {
+			names = append(names, strings.TrimSuffix(name, ".json"))
+		}
+	}
+	return names, nil
+}
+
+// Load reads the preset from the environment.
+func (EnvPresetStore) Load(name string) (Preset, error) {
+	if err := checkPresetName(name); err != nil {
+		return Preset{}, err
+	}
+	key := PresetEnvPrefix + strings.ToUpper(name)
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return Preset{}, fmt.Errorf("preset %q: %s not set: %w", name, key, fs.ErrNotExist)
+	}
+	return decodePreset(name, []byte(v))
+}
+
+// Save sets the environment variable of the preset.
+func (EnvPresetStore) Save(p Preset) error {
+	if err := checkPresetName(p.Name); err != nil {
+		return err
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return os.Setenv(PresetEnvPrefix+strings.ToUpper(p.Name), string(b))
+}
+
+// List returns the names of the presets in the environment.
+func (EnvPresetStore) List() ([]string, error) {
+	var names []string
+	for _, kv := range os.Environ() {
+		if k, _, _ := strings.Cut(kv, "="); strings.HasPrefix(k, PresetEnvPrefix) && len(k) > len(PresetEnvPrefix) {
+			names = append(names, strings.ToLower(strings.TrimPrefix(k, PresetEnvPrefix)))
+		}
+	}
+	return names, nil
+}
+
+// decodePreset decodes a stored preset, named name if the
+// stored preset has no name and at DefaultLogLevel if it has
+// no level.
+func decodePreset(name string, b []byte) (Preset, error) {
+	p := Preset{Level: DefaultLogLevel}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return Preset{}, fmt.Errorf("preset %q: %v: %w", name, err, ErrInvalid)
+	}
+	if err := p.Options.check(); err != nil {
+		return Preset{}, fmt.Errorf("preset %q: %w", name, err)
+	}
+	if p.Name == "" {
+		p.Name = name
+	}
+	return p, nil
+}
+
+var (
+	_ PresetStore = FilePresetStore("")
+	_ PresetStore = EnvPresetStore{}
+)
@@ -0,0 +1,72 @@
+package errorlogger
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+)
+
+func TestPresetStore(t *testing.T) {
+	qa := Preset{Name: "qa", Level: DebugLevel, JSON: true, Options: Options{Indent: "\t", Width: 120}}
+	stores := []struct {
+		name  string
+		store PresetStore
+	}{
+		{"file", FilePresetStore(t.TempDir() + "/presets")},
+		{"env", EnvPresetStore{}},
+	}
+	for _, tt := range stores {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(PresetEnvPrefix+"QA", "")
+			if names, err := tt.store.List(); err != nil || (tt.name == "file" && len(names) != 0) {
+				t.Errorf("List() of an empty store = %q, %v", names, err)
+			}
+			if _, err := tt.store.Load("qa"); tt.name == "file" && !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("Load() of a missing preset = %v, want fs.ErrNotExist", err)
+			}
+
+			if err := tt.store.Save(qa); err != nil {
+				t.Fatal(err)
+			}
+			got, err := tt.store.Load("QA")
+			if err != nil || !reflect.DeepEqual(got, qa) {
+				t.Errorf("Load() = %+v, %v, want %+v", got, err, qa)
+			}
+			if names, err := tt.store.List(); err != nil || !reflect.DeepEqual(names, []string{"qa"}) {
+				t.Errorf("List() = %q, %v", names, err)
+			}
+			if err := tt.store.Save(Preset{Name: "../qa"}); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Save() with an invalid name = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
+
+func TestErrorLogger_ApplyPreset(t *testing.T) {
+	defer SetPresetStore(nil)
+	t.Setenv(PresetEnvPrefix+"CI", `{"json":true,"options":{"width":100}}`)
+	SetPresetStore(EnvPresetStore{})
+
+	names, err := ListPresets()
+	if err != nil || !reflect.DeepEqual(names, []string{"ci", "development", "production", "staging"}) {
+		t.Errorf("ListPresets() = %q, %v", names, err)
+	}
+
+	e, _ := newBufferLogger(InfoLevel)
+	if err := e.ApplyPreset("ci"); err != nil {
+		t.Fatal(err)
+	}
+	if p := e.Preset(); p.Name != "ci" || p.Level != DefaultLogLevel || !p.JSON {
+		t.Errorf("Preset() = %+v", p)
+	}
+	if o := e.Options(); o.Width != 100 || o.Indent != DefaultOptions.Indent {
+		t.Errorf("Options() = %+v", o)
+	}
+	if err := e.ApplyPreset("prod"); err != nil || e.Preset().Name != "production" {
+		t.Errorf("ApplyPreset(prod) = %v, preset %q", err, e.Preset().Name)
+	}
+	if err := e.ApplyPreset("nope"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ApplyPreset() of a missing preset = %v, want fs.ErrNotExist", err)
+	}
+}
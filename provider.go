@@ -0,0 +1,108 @@
+package errorlogger
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// ProviderSettings are the logging settings supplied by a
+	// LevelProvider. The zero values of Disabled and Sampling
+	// leave logging by Err on and unsampled, so that a
+	// provider that only knows the level may set only Level.
+	ProviderSettings struct {
+		Level Level
+
+		// Disabled turns logging by Err off; see Disable.
+		Disabled bool
+
+		// Sampling is the n of SetSampling. One or less logs
+		// every error.
+		Sampling int
+	}
+
+	// LevelProvider is the extension point for external
+	// feature-flag or configuration systems (LaunchDarkly,
+	// Consul, etcd, ...) that drive logging settings at
+	// runtime. The provider is polled by UseProvider.
+	//
+	// Adapters for these systems live in the application,
+	// not in the logging calls.
+	LevelProvider interface {
+		Settings() (ProviderSettings, error)
+	}
+
+	// LevelPublisher is an optional interface implemented by
+	// a LevelProvider that pushes changes instead of being
+	// polled. Subscribe registers fn to be called with new
+	// settings and returns a function that cancels the
+	// subscription.
+	LevelPublisher interface {
+		Subscribe(fn func(ProviderSettings)) (cancel func())
+	}
+
+	// LevelProviderFunc is an adapter that allows the use of an
+	// ordinary function as a LevelProvider.
+	LevelProviderFunc func() (ProviderSettings, error)
+)
+
+// Settings calls f().
+func (f LevelProviderFunc) Settings() (ProviderSettings, error) { return f() }
+
+// ApplySettings applies the provider settings s to e.
+func ApplySettings(e ErrorLogger, s ProviderSettings) {
+	e.SetLevel(s.Level)
+	e.SetSampling(s.Sampling)
+	if s.Disabled {
+		e.Disable()
+	} else {
+		e.Enable()
+	}
+}
+
+// UseProvider applies the current settings of p to e and
+// keeps them in sync until the returned stop function is
+// called.
+//
+// If p implements LevelPublisher, changes are pushed by the
+// provider. Otherwise, p is polled at every interval. An
+// interval of zero or less defaults to one minute. Errors
+// from polling are logged at WarnLevel and the previous
+// settings are kept.
+func UseProvider(e ErrorLogger, p LevelProvider, interval time.Duration) (stop func(), err error) {
+	s, err := p.Settings()
+	if err != nil {
+		return nil, err
+	}
+	ApplySettings(e, s)
+
+	if pub, ok := p.(LevelPublisher); ok {
+		return pub.Subscribe(func(s ProviderSettings) { ApplySettings(e, s) }), nil
+	}
+
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s, err := p.Settings()
+				if err != nil {
+					e.Warnf("level provider: %v", err)
+					continue
+				}
+				ApplySettings(e, s)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }, nil
+}
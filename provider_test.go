@@ -0,0 +1,109 @@
+package errorlogger
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakePublisher is a LevelProvider that pushes settings.
+type fakePublisher struct {
+	mu  sync.Mutex
+	s   ProviderSettings
+	fns []func(ProviderSettings)
+}
+
+func (p *fakePublisher) Settings() (ProviderSettings, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.s, nil
+}
+
+func (p *fakePublisher) Subscribe(fn func(ProviderSettings)) func() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fns = append(p.fns, fn)
+	return func() {}
+}
+
+func (p *fakePublisher) publish(s ProviderSettings) {
+	p.mu.Lock()
+	p.s = s
+	fns := p.fns
+	p.mu.Unlock()
+	for _, fn := range fns {
+		fn(s)
+	}
+}
+
+func TestUseProvider(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+
+	p := &fakePublisher{s: ProviderSettings{Level: DebugLevel}}
+	stop, err := UseProvider(e, p, 0)
+	if err != nil {
+		t.Fatalf("UseProvider() error = %v", err)
+	}
+	defer stop()
+
+	if got := e.GetLevel(); got != DebugLevel || !e.Config().Enabled {
+		t.Errorf("UseProvider() initial level = %v, enabled %v, want %v and enabled", got, e.Config().Enabled, DebugLevel)
+	}
+
+	p.publish(ProviderSettings{Level: WarnLevel, Sampling: 3})
+	if got := atomic.LoadUint32(&e.sampleN); got != 3 {
+		t.Errorf("UseProvider() pushed sampling = %d, want 3", got)
+	}
+
+	p.publish(ProviderSettings{Level: ErrorLevel, Disabled: true})
+	if got := e.GetLevel(); got != ErrorLevel {
+		t.Errorf("UseProvider() pushed level = %v, want %v", got, ErrorLevel)
+	}
+	if got := e.Err(errFake); got != errFake {
+		t.Errorf("UseProvider() disabled Err() = %v, want %v", got, errFake)
+	}
+}
+
+func TestUseProvider_polled(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+
+	var mu sync.Mutex
+	level := WarnLevel
+	p := LevelProviderFunc(func() (ProviderSettings, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return ProviderSettings{Level: level}, nil
+	})
+
+	stop, err := UseProvider(e, p, time.Millisecond)
+	if err != nil {
+		t.Fatalf("UseProvider() error = %v", err)
+	}
+	defer stop()
+
+	mu.Lock()
+	level = TraceLevel
+	mu.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	for e.GetLevel() != TraceLevel && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := e.GetLevel(); got != TraceLevel {
+		t.Errorf("UseProvider() polled level = %v, want %v", got, TraceLevel)
+	}
+	stop()
+	stop()
+}
+
+func TestUseProvider_error(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	p := LevelProviderFunc(func() (ProviderSettings, error) {
+		return ProviderSettings{}, errors.New("unavailable")
+	})
+	if _, err := UseProvider(e, p, 0); err == nil {
+		t.Errorf("UseProvider() with failing provider should produce an error")
+	}
+}
@@ -0,0 +1,137 @@
+package errorlogger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+type (
+	// QuotaOptions configures the daily quota of a
+	// MeteredWriter. The zero value has no quota.
+	QuotaOptions struct {
+		// DailyBytes is the number of bytes that may be
+		// written per calendar day (local time). Zero or
+		// less means no quota.
+		DailyBytes int64
+
+		// SampleEvery keeps one in SampleEvery entries once
+		// the quota is exceeded. Zero or less drops every
+		// entry until the next day.
+		SampleEvery int
+	}
+
+	// SinkStats reports the bytes and entries written to a
+	// sink and the consumption of its daily quota.
+	SinkStats struct {
+		Name       string
+		Bytes      uint64 // written since creation
+		Entries    uint64 // written since creation
+		Dropped    uint64 // entries dropped by the quota since creation
+		DayBytes   int64  // written today
+		QuotaBytes int64  // daily quota; zero if none
+		OverQuota  bool
+	}
+
+	// MeteredWriter is a Writer that counts the bytes and
+	// entries written to a sink and optionally enforces a
+	// daily byte quota, for teams billed by log volume.
+	//
+	//  w := NewMeteredWriter("datadog", conn, QuotaOptions{DailyBytes: 5 << 30, SampleEvery: 100})
+	//  log.SetOutput(w)
+	//
+	// When the quota is exceeded, a diagnostics record is
+	// written to os.Stderr once per day and further entries
+	// are sampled or dropped. Dropped writes report success
+	// so that the logger does not report write errors.
+	//
+	// The statistics of the output of a logger are included
+	// in its Stats.
+	MeteredWriter struct {
+		name string
+		out  Writer
+		opts QuotaOptions
+		diag Writer // quota diagnostics
+		now  func() time.Time
+
+		mu        sync.Mutex
+		day       time.Time
+		dayBytes  int64
+		overCount int
+		bytes     uint64
+		entries   uint64
+		dropped   uint64
+	}
+)
+
+// NewMeteredWriter returns a new MeteredWriter named name
+// that writes to w with the given quota.
+func NewMeteredWriter(name string, w Writer, opts QuotaOptions) *MeteredWriter {
+	m := &MeteredWriter{
+		name: name,
+		out:  w,
+		opts: opts,
+		diag: os.Stderr,
+		now:  time.Now,
+	}
+	m.day = startOfDay(m.now())
+	return m
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, t.Location())
+}
+
+// Write writes p, which is expected to be one log entry, to
+// the sink unless the daily quota drops it.
+func (m *MeteredWriter) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	if day := startOfDay(m.now()); !day.Equal(m.day) {
+		m.day, m.dayBytes, m.overCount = day, 0, 0
+	}
+
+	if q := m.opts.DailyBytes; q > 0 && m.dayBytes+int64(len(p)) > q {
+		if m.overCount == 0 {
+			fmt.Fprintf(m.diag, "errorlogger: sink %q exceeded its daily quota of %d bytes; %s until midnight\n", m.name, q, m.quotaAction())
+		}
+		m.overCount++
+		if m.opts.SampleEvery <= 0 || (m.overCount-1)%m.opts.SampleEvery != 0 {
+			m.dropped++
+			m.mu.Unlock()
+			return len(p), nil
+		}
+	}
+
+	// Hold the lock across the write so that counts match
+	// the order of the output.
+	defer m.mu.Unlock()
+	n, err := m.out.Write(p)
+	m.dayBytes += int64(n)
+	m.bytes += uint64(n)
+	m.entries++
+	return n, err
+}
+
+func (m *MeteredWriter) quotaAction() string {
+	if m.opts.SampleEvery <= 0 {
+		return "dropping entries"
+	}
+	return fmt.Sprintf("sampling 1 in %d entries", m.opts.SampleEvery)
+}
+
+// SinkStats returns the statistics of the sink.
+func (m *MeteredWriter) SinkStats() []SinkStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return []SinkStats{{
+		Name:       m.name,
+		Bytes:      m.bytes,
+		Entries:    m.entries,
+		Dropped:    m.dropped,
+		DayBytes:   m.dayBytes,
+		QuotaBytes: m.opts.DailyBytes,
+		OverQuota:  m.overCount > 0,
+	}}
+}
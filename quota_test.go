@@ -0,0 +1,91 @@
+package errorlogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMeteredWriter(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        QuotaOptions
+		wantEntries uint64
+		wantDropped uint64
+		wantDiag    string
+	}{
+		{"no quota", QuotaOptions{}, 10, 0, ""},
+		{"drop", QuotaOptions{DailyBytes: 30}, 3, 7, "dropping entries"},
+		{"sample", QuotaOptions{DailyBytes: 30, SampleEvery: 3}, 6, 4, "sampling 1 in 3 entries"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, diag bytes.Buffer
+			m := NewMeteredWriter("test", &out, tt.opts)
+			m.diag = &diag
+			now := time.Date(2022, 4, 1, 23, 0, 0, 0, time.UTC)
+			m.now = func() time.Time { return now }
+			m.day = startOfDay(now)
+
+			for i := 0; i < 10; i++ {
+				if n, err := m.Write([]byte("123456789\n")); n != 10 || err != nil {
+					t.Fatalf("Write() = %d, %v, want 10, nil", n, err)
+				}
+			}
+
+			s := m.SinkStats()[0]
+			if s.Entries != tt.wantEntries || s.Dropped != tt.wantDropped || s.Bytes != 10*tt.wantEntries {
+				t.Errorf("SinkStats() = %+v, want %d entries and %d dropped", s, tt.wantEntries, tt.wantDropped)
+			}
+			if got := uint64(out.Len()); got != s.Bytes {
+				t.Errorf("wrote %d bytes, SinkStats().Bytes = %d", got, s.Bytes)
+			}
+			wantLines := 1
+			if tt.wantDiag == "" {
+				wantLines = 0
+			}
+			if got := diag.String(); strings.Count(got, "\n") != wantLines || !strings.Contains(got, tt.wantDiag) {
+				t.Errorf("diagnostics = %q, want %q once", diag.String(), tt.wantDiag)
+			}
+
+			// The quota resets at midnight.
+			now = now.Add(2 * time.Hour)
+			m.Write([]byte("123456789\n"))
+			if s := m.SinkStats()[0]; s.DayBytes != 10 || s.OverQuota {
+				t.Errorf("SinkStats() after midnight = %+v, want quota reset", s)
+			}
+		})
+	}
+}
+
+func TestErrorLogger_Stats_Bytes(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	m := NewMeteredWriter("buffer", buf, QuotaOptions{})
+	e.SetOutput(m)
+
+	e.Info("info")
+	e.Warn("warning")
+	e.Debug("not logged")
+
+	s := e.Stats()
+	if got := s.Bytes[InfoLevel] + s.Bytes[WarnLevel]; got != uint64(buf.Len()) || s.Bytes[InfoLevel] == 0 || s.Bytes[WarnLevel] == 0 {
+		t.Errorf("Stats().Bytes = %v, want %d bytes at info and warn", s.Bytes, buf.Len())
+	}
+	if s.Bytes[DebugLevel] != 0 {
+		t.Errorf("Stats().Bytes[debug] = %d, want 0", s.Bytes[DebugLevel])
+	}
+	if len(s.Sinks) != 1 || s.Sinks[0].Name != "buffer" || s.Sinks[0].Bytes != uint64(buf.Len()) {
+		t.Errorf("Stats().Sinks = %+v, want buffer sink with %d bytes", s.Sinks, buf.Len())
+	}
+
+	var prom bytes.Buffer
+	if err := s.WritePrometheus(&prom, ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`errorlogger_bytes_total{level="info"}`, `errorlogger_sink_bytes_total{sink="buffer"}`, `errorlogger_sink_dropped_total{sink="buffer"} 0`} {
+		if !strings.Contains(prom.String(), want) {
+			t.Errorf("WritePrometheus() missing %q", want)
+		}
+	}
+}
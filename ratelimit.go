@@ -0,0 +1,141 @@
+package errorlogger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SuppressedKey is the field of the summary entry logged by a
+// rate limited logger with the number of entries it dropped.
+const SuppressedKey = "suppressed"
+
+// rateLimiter drops entries beyond n per window of length
+// per and logs a summary of the dropped entries when the
+// window closes.
+type rateLimiter struct {
+	e   *errorLogger
+	n   int
+	per time.Duration
+	now func() time.Time
+
+	mu         sync.Mutex
+	start      time.Time // start of the current window
+	count      int       // entries allowed in the window
+	suppressed int       // entries dropped in the window
+	level      Level     // most severe level dropped
+	timer      *time.Timer
+}
+
+// SetRateLimit limits the logger to n entries per window of
+// length per, so that tight retry loops do not flood the
+// output:
+//  log.SetRateLimit(100, time.Second)
+//
+// Entries beyond the limit are dropped. When the window
+// closes, a summary such as "suppressed 4312 similar errors"
+// is logged with the count in the SuppressedKey field, at
+// the most severe level dropped. Fatal and panic entries,
+// and the error logged by ExitWith, are never dropped.
+//
+// A limit or window of zero or less removes the rate limit;
+// a pending summary is logged first.
+func (e *errorLogger) SetRateLimit(n int, per time.Duration) {
+	var r *rateLimiter
+	if n > 0 && per > 0 {
+		r = &rateLimiter{e: e, n: n, per: per, now: time.Now}
+	}
+	old, _ := e.rateLimit.Load().(*rateLimiter)
+	e.rateLimit.Store(r)
+	if old != nil {
+		old.close()
+	}
+}
+
+// rateAllows reports whether entry is within the rate limit
+// of the logger, if any.
+func (e *errorLogger) rateAllows(entry *Entry) bool {
+	r, _ := e.rateLimit.Load().(*rateLimiter)
+	if r == nil || entry.Level <= FatalLevel {
+		return true
+	}
+	if _, ok := entry.Data[SuppressedKey]; ok {
+		return true
+	}
+	if _, ok := entry.Data[ExitCodeKey]; ok {
+		return true
+	}
+	return r.allow(entry.Level)
+}
+
+func (r *rateLimiter) allow(level Level) bool {
+	r.mu.Lock()
+	now := r.now()
+	if now.Sub(r.start) >= r.per {
+		n, lvl := r.reset(now)
+		r.mu.Unlock()
+		r.summarize(n, lvl)
+		r.mu.Lock()
+	}
+	if r.count < r.n {
+		r.count++
+		r.mu.Unlock()
+		return true
+	}
+	if r.suppressed == 0 || level < r.level {
+		r.level = level
+	}
+	r.suppressed++
+	if r.timer == nil {
+		r.timer = time.AfterFunc(r.per-now.Sub(r.start), r.flush)
+	}
+	r.mu.Unlock()
+	return false
+}
+
+// reset starts a new window at now and returns the number of
+// entries dropped in the previous one and their level. It
+// is called with r.mu held.
+func (r *rateLimiter) reset(now time.Time) (int, Level) {
+	n, lvl := r.suppressed, r.level
+	r.start, r.count, r.suppressed = now, 0, 0
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	return n, lvl
+}
+
+// flush logs the summary of the current window, if it has
+// closed.
+func (r *rateLimiter) flush() {
+	r.mu.Lock()
+	r.timer = nil
+	now := r.now()
+	if now.Sub(r.start) < r.per {
+		r.mu.Unlock()
+		return
+	}
+	n, lvl := r.reset(now)
+	r.mu.Unlock()
+	r.summarize(n, lvl)
+}
+
+// close logs the summary of the current window, whether or
+// not it has closed.
+func (r *rateLimiter) close() {
+	r.mu.Lock()
+	n, lvl := r.reset(r.now())
+	r.mu.Unlock()
+	r.summarize(n, lvl)
+}
+
+func (r *rateLimiter) summarize(n int, level Level) {
+	if n == 0 {
+		return
+	}
+	if level < ErrorLevel {
+		level = ErrorLevel
+	}
+	r.e.WithField(SuppressedKey, n).Log(level, fmt.Sprintf("suppressed %d similar errors", n))
+}
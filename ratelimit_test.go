@@ -0,0 +1,109 @@
+package errorlogger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorLogger_SetRateLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		n           int
+		log         func(e *errorLogger)
+		wantLines   int
+		wantSummary string
+	}{
+		{"under limit", 3, func(e *errorLogger) {
+			e.Err(errFake)
+			e.Err(errFake)
+		}, 2, ""},
+		{"over limit", 2, func(e *errorLogger) {
+			for i := 0; i < 5; i++ {
+				e.Err(errFake)
+			}
+		}, 2, "level=error msg=\"suppressed 3 similar errors\" suppressed=3"},
+		{"summary at dropped level", 1, func(e *errorLogger) {
+			e.Warn("w")
+			e.Warn("w")
+		}, 1, "level=warning msg=\"suppressed 1 similar errors\" suppressed=1"},
+		{"disabled", 0, func(e *errorLogger) {
+			for i := 0; i < 5; i++ {
+				e.Err(errFake)
+			}
+		}, 5, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			now := time.Unix(1000, 0)
+			e.SetRateLimit(tt.n, time.Minute)
+			if r, ok := e.rateLimit.Load().(*rateLimiter); ok && r != nil {
+				r.now = func() time.Time { return now }
+			}
+			defer e.SetRateLimit(0, 0)
+
+			tt.log(e)
+			if got := strings.Count(buf.String(), "\n"); got != tt.wantLines {
+				t.Fatalf("logged %d lines, want %d: %q", got, tt.wantLines, buf.String())
+			}
+
+			// The summary is logged by the first entry of
+			// the next window.
+			buf.Reset()
+			now = now.Add(time.Minute)
+			e.Info("next")
+			out := buf.String()
+			if tt.wantSummary == "" {
+				if strings.Contains(out, "suppressed") {
+					t.Errorf("output = %q, want no summary", out)
+				}
+			} else if !strings.Contains(out, tt.wantSummary) {
+				t.Errorf("output = %q, want %q", out, tt.wantSummary)
+			}
+			if !strings.Contains(out, "msg=next") {
+				t.Errorf("output = %q, want the entry of the next window", out)
+			}
+		})
+	}
+}
+
+func TestErrorLogger_SetRateLimit_timer(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	buf := &syncBuffer{}
+	e.SetOutput(buf)
+	e.SetRateLimit(1, 20*time.Millisecond)
+	defer e.SetRateLimit(0, 0)
+
+	for i := 0; i < 4; i++ {
+		e.Err(errFake)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(buf.String(), "suppressed=3") {
+		if time.Now().After(deadline) {
+			t.Fatalf("output = %q, want a summary when the window closes", buf.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestErrorLogger_SetRateLimit_never(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.SetRateLimit(1, time.Hour)
+	e.Err(errFake)
+	e.Err(errFake)
+
+	func() {
+		defer func() { recover() }()
+		e.Panic("p")
+	}()
+	if !strings.Contains(buf.String(), "level=panic") {
+		t.Errorf("output = %q, want the panic entry", buf.String())
+	}
+
+	// Removing the limit logs the pending summary.
+	e.SetRateLimit(0, 0)
+	if !strings.Contains(buf.String(), "suppressed=1") {
+		t.Errorf("output = %q, want the pending summary", buf.String())
+	}
+}
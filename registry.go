@@ -0,0 +1,128 @@
+package errorlogger
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type (
+	// FormatterFactory returns a formatter configured by c,
+	// e.g. from its Pretty and TimestampFormat fields.
+	FormatterFactory = func(c Config) (Formatter, error)
+
+	// SinkFactory returns an output for target, the part of
+	// the Output config field after the sink name and a
+	// colon, e.g. "local0" for "syslog:local0".
+	SinkFactory = func(target string) (Writer, error)
+)
+
+var (
+	registryMu sync.RWMutex
+	formatters = map[string]FormatterFactory{}
+	sinks      = map[string]SinkFactory{}
+)
+
+// RegisterFormatter makes a formatter available by name as
+// the Format of a Config, so that external modules can plug
+// in formats without this package importing them. Like
+// database/sql drivers, it is meant to be called from the
+// init function of the package providing the formatter:
+//  func init() {
+//  	errorlogger.RegisterFormatter("logfmt", newLogfmtFormatter)
+//  }
+//
+// The importing program then selects it in its config file:
+//  format: logfmt
+//
+// RegisterFormatter panics if factory is nil, if name is
+// empty or one of the built in formats "text" and "json",
+// or if it is called twice with the same name.
+func RegisterFormatter(name string, factory FormatterFactory) {
+	if factory == nil {
+		panic("errorlogger: RegisterFormatter factory is nil")
+	}
+	if name == "" || name == "text" || name == "json" {
+		panic("errorlogger: RegisterFormatter with reserved name " + strconv.Quote(name))
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := formatters[name]; dup {
+		panic("errorlogger: RegisterFormatter called twice for formatter " + name)
+	}
+	formatters[name] = factory
+}
+
+// RegisterSink makes an output available by name in the
+// Output of a Config. An Output of "name" or "name:target"
+// calls the factory with target:
+//  func init() {
+//  	errorlogger.RegisterSink("syslog", newSyslogWriter)
+//  }
+//
+//  output: syslog:local0
+//
+// RegisterSink panics if factory is nil, if name is shorter
+// than two characters, so that Windows drive letters are
+// still read as file paths, if name contains a colon or is
+// one of the built in outputs, or if it is called twice
+// with the same name.
+func RegisterSink(name string, factory SinkFactory) {
+	if factory == nil {
+		panic("errorlogger: RegisterSink factory is nil")
+	}
+	switch {
+	case len(name) < 2, strings.Contains(name, ":"), name == "stderr", name == "stdout", name == "discard":
+		panic("errorlogger: RegisterSink with reserved name " + strconv.Quote(name))
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := sinks[name]; dup {
+		panic("errorlogger: RegisterSink called twice for sink " + name)
+	}
+	sinks[name] = factory
+}
+
+// Formatters returns the sorted names of the registered
+// formatters.
+func Formatters() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Sinks returns the sorted names of the registered sinks.
+func Sinks() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(sinks))
+	for name := range sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupFormatter returns the factory registered as name.
+func lookupFormatter(name string) (FormatterFactory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := formatters[name]
+	return f, ok
+}
+
+// lookupSink returns the factory of the sink addressed by
+// output and its target.
+func lookupSink(output string) (factory SinkFactory, target string, ok bool) {
+	name, target, _ := strings.Cut(output, ":")
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	factory, ok = sinks[name]
+	return factory, target, ok
+}
@@ -0,0 +1,95 @@
+package errorlogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type prefixFormatter struct{ prefix string }
+
+func (f *prefixFormatter) Format(entry *Entry) ([]byte, error) {
+	return []byte(f.prefix + entry.Message + "\n"), nil
+}
+
+func TestRegistry(t *testing.T) {
+	var sinkBuf bytes.Buffer
+	var gotTarget string
+	RegisterFormatter("test-prefix", func(c Config) (Formatter, error) {
+		return &prefixFormatter{prefix: c.TimestampFormat}, nil
+	})
+	RegisterFormatter("test-broken", func(Config) (Formatter, error) { return nil, errFake })
+	RegisterSink("test-mem", func(target string) (Writer, error) {
+		gotTarget = target
+		return &sinkBuf, nil
+	})
+
+	tests := []struct {
+		name    string
+		format  string
+		output  string
+		wantErr string
+		want    string
+		target  string
+	}{
+		{"registered formatter and sink", "test-prefix", "test-mem:chan1", "", "> hello\n", "chan1"},
+		{"sink without target", "test-prefix", "test-mem", "", "> hello\n", ""},
+		{"unknown format", "test-unknown", "discard", "invalid format", "", ""},
+		{"factory error", "test-broken", "discard", "format test-broken: fake", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sinkBuf.Reset()
+			gotTarget = "unset"
+			e, _ := newBufferLogger(InfoLevel)
+			c := DefaultConfig()
+			c.Format, c.Output, c.TimestampFormat = tt.format, tt.output, "> "
+
+			err := e.ApplyConfig(c)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ApplyConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			e.Info("hello")
+			if got := sinkBuf.String(); got != tt.want || gotTarget != tt.target {
+				t.Errorf("sink = %q with target %q, want %q with target %q", got, gotTarget, tt.want, tt.target)
+			}
+		})
+	}
+
+	if got := strings.Join(Formatters(), ","); !strings.Contains(got, "test-broken,test-prefix") {
+		t.Errorf("Formatters() = %s", got)
+	}
+	if got := strings.Join(Sinks(), ","); !strings.Contains(got, "test-mem") {
+		t.Errorf("Sinks() = %s", got)
+	}
+}
+
+func TestRegistry_panics(t *testing.T) {
+	RegisterSink("test-dup", func(string) (Writer, error) { return Discard, nil })
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"nil formatter", func() { RegisterFormatter("test-nil", nil) }},
+		{"builtin format", func() { RegisterFormatter("json", func(Config) (Formatter, error) { return nil, nil }) }},
+		{"drive letter", func() { RegisterSink("C", func(string) (Writer, error) { return nil, nil }) }},
+		{"builtin output", func() { RegisterSink("stdout", func(string) (Writer, error) { return nil, nil }) }},
+		{"duplicate sink", func() { RegisterSink("test-dup", func(string) (Writer, error) { return nil, nil }) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("no panic")
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package errorlogger
+
+import "fmt"
+
+// ErrReporter is a restricted handle on a logger that can
+// only report errors. Libraries should accept an
+// ErrReporter rather than an ErrorLogger, so that they can
+// log through the logger of the application but cannot
+// reconfigure or disable it.
+//  func NewClient(log errorlogger.ErrReporter) *Client
+//
+//  client := NewClient(errorlogger.Log.Reporter())
+type ErrReporter interface {
+	// Err logs err, if logging is enabled, and returns it
+	// as Err of ErrorLogger does.
+	Err(err error) error
+
+	// Errf formats an error with fmt.Errorf and reports it
+	// with Err.
+	Errf(format string, args ...interface{}) error
+
+	// WithFields returns a reporter that adds fields to
+	// every reported error.
+	WithFields(fields Fields) ErrReporter
+}
+
+// DiscardReporter is an ErrReporter that logs nothing and
+// returns errors unchanged, for libraries used without a
+// logger.
+var DiscardReporter ErrReporter = discardReporter{}
+
+// reporter is the ErrReporter of an errorLogger.
+type reporter struct {
+	e      *errorLogger
+	fields Fields
+}
+
+// Reporter returns an ErrReporter that logs through e.
+func (e *errorLogger) Reporter() ErrReporter {
+	return reporter{e: e}
+}
+
+func (r reporter) Err(err error) error {
+	if err == nil {
+		return nil
+	}
+	if len(r.fields) == 0 || !r.e.enabled() {
+		return r.e.Err(err)
+	}
+	return r.e.errWithFields(err, r.fields)
+}
+
+func (r reporter) Errf(format string, args ...interface{}) error {
+	return r.Err(fmt.Errorf(format, args...))
+}
+
+func (r reporter) WithFields(fields Fields) ErrReporter {
+	merged := make(Fields, len(r.fields)+len(fields))
+	for k, v := range r.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return reporter{e: r.e, fields: merged}
+}
+
+type discardReporter struct{}
+
+func (discardReporter) Err(err error) error { return err }
+
+func (discardReporter) Errf(format string, args ...interface{}) error {
+	return fmt.Errorf(format, args...)
+}
+
+func (d discardReporter) WithFields(Fields) ErrReporter { return d }
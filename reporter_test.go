@@ -0,0 +1,68 @@
+package errorlogger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_Reporter(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		report  func(r ErrReporter) error
+		wantErr string
+		want    []string
+	}{
+		{"Err", true, func(r ErrReporter) error { return r.Err(errors.New("plain")) }, "plain", []string{"plain"}},
+		{"Errf", true, func(r ErrReporter) error { return r.Errf("code %d", 42) }, "code 42", []string{"code 42"}},
+		{"WithFields", true, func(r ErrReporter) error {
+			return r.WithFields(Fields{"lib": "db"}).WithFields(Fields{"op": "query"}).Err(errors.New("failed"))
+		}, "failed", []string{"failed", "lib=db", "op=query"}},
+		{"disabled", false, func(r ErrReporter) error {
+			return r.WithFields(Fields{"lib": "db"}).Errf("quiet")
+		}, "quiet", nil},
+		{"nil", true, func(r ErrReporter) error { return r.Err(nil) }, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			if !tt.enabled {
+				e.Disable()
+			}
+			err := tt.report(e.Reporter())
+			if (err == nil) != (tt.wantErr == "") || err != nil && err.Error() != tt.wantErr {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output = %q, want %q", buf.String(), want)
+				}
+			}
+			if tt.want == nil && buf.Len() != 0 {
+				t.Errorf("output = %q, want none", buf.String())
+			}
+		})
+	}
+}
+
+func TestErrorLogger_Reporter_restricted(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	var r interface{} = e.Reporter()
+	if _, ok := r.(ErrorLogger); ok {
+		t.Error("Reporter() can be converted to an ErrorLogger")
+	}
+	if _, ok := r.(interface{ SetLevel(Level) }); ok {
+		t.Error("Reporter() can change the level")
+	}
+}
+
+func TestDiscardReporter(t *testing.T) {
+	err := errors.New("kept")
+	if got := DiscardReporter.WithFields(Fields{"k": 1}).Err(err); got != err {
+		t.Errorf("Err() = %v, want %v", got, err)
+	}
+	if got := DiscardReporter.Errf("n=%d", 1); got == nil || got.Error() != "n=1" {
+		t.Errorf("Errf() = %v", got)
+	}
+}
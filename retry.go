@@ -0,0 +1,32 @@
+package errorlogger
+
+import "errors"
+
+// IsTimeout reports whether any error in the chain of err
+// reports itself as a timeout with a Timeout() bool method,
+// as net.Error and the os and io/fs error types do.
+func IsTimeout(err error) bool {
+	var t interface{ Timeout() bool }
+	return errors.As(err, &t) && t.Timeout()
+}
+
+// IsTemporary reports whether any error in the chain of err
+// reports itself as temporary with a Temporary() bool method.
+func IsTemporary(err error) bool {
+	var t interface{ Temporary() bool }
+	return errors.As(err, &t) && t.Temporary()
+}
+
+// IsRetryable reports whether err is a transient failure
+// worth retrying: a temporary error or a timeout.
+func IsRetryable(err error) bool {
+	return IsTemporary(err) || IsTimeout(err)
+}
+
+// RetryEnricher is an Enricher that adds the "retryable" and
+// "timeout" fields, so dashboards can separate transient
+// network blips from real failures.
+func RetryEnricher(err error, fields Fields) {
+	fields["retryable"] = IsRetryable(err)
+	fields["timeout"] = IsTimeout(err)
+}
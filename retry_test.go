@@ -0,0 +1,54 @@
+package errorlogger
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeNetError implements net.Error.
+type fakeNetError struct{ timeout, temporary bool }
+
+func (e fakeNetError) Error() string   { return "fake net error" }
+func (e fakeNetError) Timeout() bool   { return e.timeout }
+func (e fakeNetError) Temporary() bool { return e.temporary }
+
+var _ net.Error = fakeNetError{}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		timeout   bool
+		retryable bool
+	}{
+		{"nil", nil, false, false},
+		{"plain", errFake, false, false},
+		{"timeout", fakeNetError{timeout: true}, true, true},
+		{"temporary", fmt.Errorf("dial: %w", fakeNetError{temporary: true}), false, true},
+		{"permanent net error", fakeNetError{}, false, false},
+		{"syscall wrapper", &SyscallError{"read", fakeNetError{timeout: true}}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTimeout(tt.err); got != tt.timeout {
+				t.Errorf("IsTimeout() = %v, want %v", got, tt.timeout)
+			}
+			if got := IsRetryable(tt.err); got != tt.retryable {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestRetryEnricher(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.AddEnricher(RetryEnricher)
+	_ = e.Err(fakeNetError{timeout: true})
+	for _, want := range []string{"retryable=true", "timeout=true"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Err() with RetryEnricher output = %q, want %q", buf.String(), want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package errorlogger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRingSize is the number of entries kept by a
+// RingBuffer if no size is given.
+const DefaultRingSize = 100
+
+type (
+	// RingEntry is a copy of a log entry kept by a
+	// RingBuffer.
+	RingEntry struct {
+		Time    time.Time              `json:"time"`
+		Level   Level                  `json:"level"`
+		Message string                 `json:"msg"`
+		Fields  map[string]interface{} `json:"fields,omitempty"`
+
+		frame []byte // JSON encoding, made when recorded
+	}
+
+	// ringEntryJSON is a RingEntry without its MarshalJSON
+	// method.
+	ringEntryJSON RingEntry
+
+	// RingBuffer is a logrus hook that keeps the most recent
+	// entries of every level in memory, regardless of
+	// whether they are written to the output, so that the
+	// context leading up to a failure is available for
+	// crash reports and debugging.
+	//
+	//  ring := NewRingBuffer(200)
+	//  log.AddHook(ring)
+	//
+	// Entries are encoded as JSON when they are recorded, so
+	// that crash reports and the debug handler of ServeHTTP
+	// read them back with Range and WriteTo without encoding
+	// or copying them when the process is failing.
+	RingBuffer struct {
+		mu      sync.Mutex
+		entries []RingEntry
+		next    int
+		full    bool
+	}
+)
+
+// NewRingBuffer returns a new RingBuffer that keeps the
+// last size entries. A size of zero or less uses
+// DefaultRingSize.
+func NewRingBuffer(size int) *RingBuffer {
+	if size <= 0 {
+		size = DefaultRingSize
+	}
+	return &RingBuffer{entries: make([]RingEntry, size)}
+}
+
+// Levels returns all levels. Note that logrus only fires
+// hooks for entries at or above the level of the logger.
+func (r *RingBuffer) Levels() []Level { return logrus.AllLevels }
+
+// Fire records a copy of entry.
+func (r *RingBuffer) Fire(entry *Entry) error {
+	var fields map[string]interface{}
+	if len(entry.Data) > 0 {
+		fields = make(map[string]interface{}, len(entry.Data))
+		for k, v := range entry.Data {
+			fields[k] = v
+		}
+	}
+
+	re := RingEntry{
+		Time:    entry.Time,
+		Level:   entry.Level,
+		Message: entry.Message,
+		Fields:  fields,
+	}
+	safe := re
+	safe.Fields = jsonSafe(fields)
+	re.frame, _ = json.Marshal(ringEntryJSON(safe))
+
+	r.mu.Lock()
+	r.entries[r.next] = re
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// Entries returns the recorded entries, oldest first.
+func (r *RingBuffer) Entries() []RingEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]RingEntry(nil), r.entries[:r.next]...)
+	}
+	list := make([]RingEntry, 0, len(r.entries))
+	list = append(list, r.entries[r.next:]...)
+	return append(list, r.entries[:r.next]...)
+}
+
+// frames returns the JSON encodings of the recorded entries,
+// oldest first. The encodings are shared, not copied.
+func (r *RingBuffer) frames() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := r.next
+	if r.full {
+		n = len(r.entries)
+	}
+	list := make([][]byte, 0, n)
+	if r.full {
+		for _, e := range r.entries[r.next:] {
+			list = append(list, e.frame)
+		}
+	}
+	for _, e := range r.entries[:r.next] {
+		list = append(list, e.frame)
+	}
+	return list
+}
+
+// Range calls fn with the JSON encoding of each recorded
+// entry, oldest first, until fn returns false. The frames
+// must not be modified or retained. The buffer is not locked
+// while fn runs, so fn may log, e.g. from a crash handler:
+//  ring.Range(func(frame []byte) bool {
+//      _, err := crashFile.Write(frame)
+//      return err == nil
+//  })
+func (r *RingBuffer) Range(fn func(frame []byte) bool) {
+	for _, frame := range r.frames() {
+		if !fn(frame) {
+			return
+		}
+	}
+}
+
+// WriteTo writes the recorded entries to w as newline
+// delimited JSON, oldest first.
+func (r *RingBuffer) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	var err error
+	nl := []byte{'\n'}
+	r.Range(func(frame []byte) bool {
+		var n int
+		if n, err = w.Write(frame); err == nil {
+			total += int64(n)
+			n, err = w.Write(nl)
+		}
+		total += int64(n)
+		return err == nil
+	})
+	return total, err
+}
+
+// ServeHTTP serves the recorded entries as newline delimited
+// JSON, so that the ring can be mounted as a debug handler:
+//  http.Handle("/debug/log", ring)
+func (r *RingBuffer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	_, _ = r.WriteTo(w)
+}
+
+// MarshalJSON returns the encoding of the entry made when it
+// was recorded, with values that cannot be encoded as JSON
+// replaced by their string form.
+func (e RingEntry) MarshalJSON() ([]byte, error) {
+	if e.frame != nil {
+		return e.frame, nil
+	}
+	safe := e
+	safe.Fields = jsonSafe(e.Fields)
+	return json.Marshal(ringEntryJSON(safe))
+}
+
+var (
+	_ logrus.Hook    = (*RingBuffer)(nil)
+	_ io.WriterTo    = (*RingBuffer)(nil)
+	_ http.Handler   = (*RingBuffer)(nil)
+	_ json.Marshaler = RingEntry{}
+)
@@ -0,0 +1,79 @@
+package errorlogger
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		logs int
+		want []string
+	}{
+		{"partial", 3, 2, []string{"0", "1"}},
+		{"full", 3, 3, []string{"0", "1", "2"}},
+		{"wrapped", 3, 5, []string{"2", "3", "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newBufferLogger(DebugLevel)
+			r := NewRingBuffer(tt.size)
+			e.AddHook(r)
+			for i := 0; i < tt.logs; i++ {
+				e.WithField("i", i).Debug(i)
+			}
+
+			got := r.Entries()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Entries() = %d entries, want %d", len(got), len(tt.want))
+			}
+			for i, w := range tt.want {
+				if got[i].Message != w || got[i].Level != DebugLevel || got[i].Fields["i"] == nil {
+					t.Errorf("Entries()[%d] = %+v, want message %s", i, got[i], w)
+				}
+			}
+		})
+	}
+}
+
+func TestRingBuffer_Range(t *testing.T) {
+	e, _ := newBufferLogger(DebugLevel)
+	r := NewRingBuffer(3)
+	e.AddHook(r)
+	for i := 0; i < 4; i++ {
+		e.WithField("err", errFake).Info(i)
+	}
+
+	var got []string
+	r.Range(func(frame []byte) bool {
+		got = append(got, string(frame))
+		e.Info("logging while ranging")
+		return len(got) < 2
+	})
+	if len(got) != 2 || !strings.Contains(got[0], `"msg":"1"`) || !strings.Contains(got[0], `"fields":{"err":"fake"}`) {
+		t.Errorf("Range() frames = %q", got)
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/log", nil))
+	lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+	if len(lines) != 3 || rec.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("ServeHTTP() = %q", rec.Body.String())
+	}
+	for i, want := range []string{"3", "logging while ranging", "logging while ranging"} {
+		var entry RingEntry
+		if err := json.Unmarshal([]byte(lines[i]), &entry); err != nil || entry.Message != want {
+			t.Errorf("line %d = %q, %v, want message %q", i, lines[i], err, want)
+		}
+	}
+
+	entries := r.Entries()
+	b, err := json.Marshal(entries[0])
+	if err != nil || string(b) != lines[0] {
+		t.Errorf("MarshalJSON() = %s, %v, want the recorded frame %s", b, err, lines[0])
+	}
+}
@@ -0,0 +1,243 @@
+package errorlogger
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RingFile layout: a header followed by the data area.
+//
+//  magic [8]byte  "ELRING1\x00"
+//  size  uint64   size of the data area
+//  head  uint64   logical offset of the next write
+//  tail  uint64   logical offset of the oldest record
+//
+// Records are a uint32 payload length followed by the
+// payload, all little endian. Logical offsets only grow;
+// the physical offset is the logical offset modulo size.
+// Records never wrap: if a record does not fit before the
+// end of the data area, a wrap marker is written (if there
+// is room for one) and the record starts at the beginning.
+const (
+	ringFileMagic      = "ELRING1\x00"
+	ringFileHeaderSize = 32
+	ringFileWrap       = 0xFFFFFFFF
+)
+
+// DefaultRingFileSize is the size of the data area of a new
+// RingFile if no size is given.
+const DefaultRingFileSize = 16 << 20
+
+// RingFile is a Writer that keeps the most recent entries
+// in a preallocated, memory mapped file of fixed size, like
+// a persistent RingBuffer. Every write is a copy into
+// memory shared with the operating system, so entries
+// survive a crash of the process without the cost of a
+// sync per write; call Sync to also survive a crash of the
+// system.
+//
+//  r, err := OpenRingFile("/var/log/myapp.ring", 0)
+//  log.SetOutput(io.MultiWriter(os.Stderr, r))
+//
+// Entries are read back, oldest first, with Entries or
+// ReadRingFile. A RingFile must not be opened for writing
+// by more than one process at a time.
+type RingFile struct {
+	mu   sync.Mutex
+	f    *os.File
+	buf  []byte // the mapping: header and data area
+	data []byte
+	size uint64
+}
+
+// OpenRingFile opens the ring file at path, creating and
+// preallocating it with a data area of size bytes if it
+// does not exist. A size of zero or less uses
+// DefaultRingFileSize. The size of an existing file is
+// kept and its entries are preserved.
+func OpenRingFile(path string, size int) (*RingFile, error) {
+	if size <= 0 {
+		size = DefaultRingFileSize
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	fresh := fi.Size() == 0
+	total := int64(ringFileHeaderSize + size)
+	if fresh {
+		if err := f.Truncate(total); err != nil {
+			f.Close()
+			return nil, err
+		}
+	} else {
+		total = fi.Size()
+	}
+
+	buf, err := mmapFile(f, int(total))
+	if err != nil {
+		f.Close()
+		return nil, &PathError{Op: "mmap", Path: path, Err: err}
+	}
+	r := &RingFile{f: f, buf: buf, data: buf[ringFileHeaderSize:], size: uint64(total - ringFileHeaderSize)}
+	if fresh {
+		copy(buf, ringFileMagic)
+		binary.LittleEndian.PutUint64(buf[8:], r.size)
+	} else if err := checkRingHeader(buf); err != nil {
+		r.Close()
+		return nil, &PathError{Op: "open", Path: path, Err: err}
+	}
+	return r, nil
+}
+
+func checkRingHeader(buf []byte) error {
+	if len(buf) < ringFileHeaderSize || string(buf[:8]) != ringFileMagic {
+		return fmt.Errorf("not a ring file: %w", ErrInvalid)
+	}
+	size := binary.LittleEndian.Uint64(buf[8:])
+	head := binary.LittleEndian.Uint64(buf[16:])
+	tail := binary.LittleEndian.Uint64(buf[24:])
+	if size != uint64(len(buf)-ringFileHeaderSize) || tail > head || head-tail > size {
+		return fmt.Errorf("corrupt ring file header: %w", ErrInvalid)
+	}
+	return nil
+}
+
+func (r *RingFile) head() uint64     { return binary.LittleEndian.Uint64(r.buf[16:]) }
+func (r *RingFile) tail() uint64     { return binary.LittleEndian.Uint64(r.buf[24:]) }
+func (r *RingFile) setHead(v uint64) { binary.LittleEndian.PutUint64(r.buf[16:], v) }
+func (r *RingFile) setTail(v uint64) { binary.LittleEndian.PutUint64(r.buf[24:], v) }
+
+// Write appends p as one entry, overwriting the oldest
+// entries as needed. Entries larger than the data area
+// are rejected.
+func (r *RingFile) Write(p []byte) (int, error) {
+	n := uint64(4 + len(p))
+	if n > r.size {
+		return 0, fmt.Errorf("entry of %d bytes exceeds ring file size %d: %w", len(p), r.size, ErrInvalid)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.data == nil {
+		return 0, ErrClosed
+	}
+
+	head := r.head()
+	start := head
+	if off := head % r.size; off+n > r.size {
+		start = head - off + r.size // the start of the next lap
+	}
+
+	// Evict first, so that a crash never leaves the tail
+	// pointing into overwritten data.
+	tail := r.tail()
+	for start+n-tail > r.size {
+		_, tail = ringRecord(r.data, tail, r.size)
+	}
+	r.setTail(tail)
+
+	if start != head && r.size-head%r.size >= 4 {
+		binary.LittleEndian.PutUint32(r.data[head%r.size:], ringFileWrap)
+	}
+	off := start % r.size
+	binary.LittleEndian.PutUint32(r.data[off:], uint32(len(p)))
+	copy(r.data[off+4:], p)
+	r.setHead(start + n)
+	return len(p), nil
+}
+
+// ringRecord returns the payload of the record at logical
+// offset pos, or nil at a wrap, and the offset of the next
+// record.
+func ringRecord(data []byte, pos, size uint64) ([]byte, uint64) {
+	off := pos % size
+	lap := pos - off + size
+	if size-off < 4 {
+		return nil, lap
+	}
+	l := uint64(binary.LittleEndian.Uint32(data[off:]))
+	if l == ringFileWrap || off+4+l > size {
+		return nil, lap
+	}
+	return data[off+4 : off+4+l], pos + 4 + l
+}
+
+// Entries returns copies of the entries in the ring file,
+// oldest first.
+func (r *RingFile) Entries() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.data == nil {
+		return nil
+	}
+	list, _ := ringEntries(r.buf)
+	return list
+}
+
+// Sync flushes the mapped file to stable storage.
+func (r *RingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Sync()
+}
+
+// Close unmaps and closes the file.
+func (r *RingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.data == nil {
+		return ErrClosed
+	}
+	err := munmapFile(r.buf)
+	r.buf, r.data = nil, nil
+	if cerr := r.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// ReadRingFile returns the entries of the ring file at
+// path, oldest first. It does not require memory mapping
+// and may be used while the file is open for writing.
+func ReadRingFile(path string) ([][]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	list, err := ringEntries(b)
+	if err != nil {
+		return nil, &PathError{Op: "read", Path: path, Err: err}
+	}
+	return list, nil
+}
+
+// ringEntries returns copies of the records of the ring
+// file contents buf.
+func ringEntries(buf []byte) ([][]byte, error) {
+	if err := checkRingHeader(buf); err != nil {
+		return nil, err
+	}
+	data := buf[ringFileHeaderSize:]
+	size := uint64(len(data))
+	head := binary.LittleEndian.Uint64(buf[16:])
+	tail := binary.LittleEndian.Uint64(buf[24:])
+
+	var list [][]byte
+	for pos := tail; pos < head; {
+		var p []byte
+		p, pos = ringRecord(data, pos, size)
+		if p != nil {
+			list = append(list, append([]byte(nil), p...))
+		}
+	}
+	return list, nil
+}
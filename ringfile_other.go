@@ -0,0 +1,10 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package errorlogger
+
+import "os"
+
+func mmapFile(f *os.File, size int) ([]byte, error) { return nil, ErrUnsupported }
+func munmapFile(b []byte) error                     { return ErrUnsupported }
+
+const canMmapFile = false
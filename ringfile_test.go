@@ -0,0 +1,92 @@
+package errorlogger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRingFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		writes   int
+		wantLast int // number of most recent entries kept
+	}{
+		{"empty", 64, 0, 0},
+		{"partial", 64, 3, 3},
+		{"wrapped", 64, 10, 4},
+		{"many laps", 100, 1000, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !canMmapFile {
+				t.Skip("memory mapping is not supported")
+			}
+			path := filepath.Join(t.TempDir(), "log.ring")
+			r, err := OpenRingFile(path, tt.size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < tt.writes; i++ {
+				if _, err := fmt.Fprintf(r, "entry %04d\n", i); err != nil {
+					t.Fatal(err)
+				}
+			}
+			check := func(how string, got [][]byte) {
+				t.Helper()
+				if len(got) != tt.wantLast {
+					t.Fatalf("%s = %d entries, want %d", how, len(got), tt.wantLast)
+				}
+				for i, b := range got {
+					if want := fmt.Sprintf("entry %04d\n", tt.writes-tt.wantLast+i); string(b) != want {
+						t.Errorf("%s[%d] = %q, want %q", how, i, b, want)
+					}
+				}
+			}
+			check("Entries()", r.Entries())
+
+			// Entries survive without a close or sync.
+			got, err := ReadRingFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			check("ReadRingFile()", got)
+
+			r.Close()
+			r, err = OpenRingFile(path, 1<<20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			check("reopened Entries()", r.Entries())
+		})
+	}
+}
+
+func TestRingFile_errors(t *testing.T) {
+	if !canMmapFile {
+		t.Skip("memory mapping is not supported")
+	}
+	path := filepath.Join(t.TempDir(), "log.ring")
+	r, err := OpenRingFile(path, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Write(make([]byte, 13)); err == nil {
+		t.Error("Write() of an oversized entry succeeded")
+	}
+	r.Close()
+	if _, err := r.Write([]byte("x")); err != ErrClosed {
+		t.Errorf("Write() after Close() = %v, want ErrClosed", err)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.ring")
+	if err := os.WriteFile(bad, []byte("not a ring file, but long enough"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenRingFile(bad, 0); err == nil {
+		t.Error("OpenRingFile() of a non ring file succeeded")
+	}
+}
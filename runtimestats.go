@@ -0,0 +1,84 @@
+package errorlogger
+
+import (
+	"math"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+)
+
+// Fields added by RuntimeFields.
+const (
+	RuntimeGoroutinesKey     = "runtime.goroutines"
+	RuntimeGCCountKey        = "runtime.gc_count"
+	RuntimeGCPauseKey        = "runtime.gc_pause_total"
+	RuntimeMemoryKey         = "runtime.memory"
+	RuntimeMemoryLimitKey    = "runtime.memory_limit"
+	RuntimeMemoryPressureKey = "runtime.memory_pressure"
+)
+
+// runtimeMemoryMetrics are the runtime/metrics samples that
+// make up the memory counted against GOMEMLIMIT.
+var runtimeMemoryMetrics = []string{
+	"/memory/classes/total:bytes",
+	"/memory/classes/heap/released:bytes",
+}
+
+// RuntimeFields returns a Mutator that adds the state of
+// the Go runtime to entries at level or more severe, since
+// a spike of errors often comes with runtime pressure:
+//  log.AddMutator(errorlogger.RuntimeFields(errorlogger.ErrorLevel, time.Minute))
+//
+// The fields are the number of goroutines, the number of
+// completed GC cycles and their total pause time, and the
+// memory in use by the runtime. If a memory limit is set
+// with GOMEMLIMIT, the limit and the fraction of it in use
+// are added as well.
+//
+// If every is greater than zero, the fields are also added
+// to the first entry of any level logged after every has
+// passed, as a sampled record of the runtime over time.
+// Reading the runtime state does not stop the world.
+func RuntimeFields(level Level, every time.Duration) Mutator {
+	var next int64 // atomic; UnixNano of the next sample
+	return func(entry *Entry) {
+		if entry.Level > level {
+			if every <= 0 {
+				return
+			}
+			now := time.Now().UnixNano()
+			n := atomic.LoadInt64(&next)
+			if now < n || !atomic.CompareAndSwapInt64(&next, n, now+int64(every)) {
+				return
+			}
+		}
+		addRuntimeFields(entry.Data)
+	}
+}
+
+// addRuntimeFields adds the current runtime state to fields.
+func addRuntimeFields(fields Fields) {
+	var gc debug.GCStats
+	debug.ReadGCStats(&gc)
+	fields[RuntimeGoroutinesKey] = runtime.NumGoroutine()
+	fields[RuntimeGCCountKey] = gc.NumGC
+	fields[RuntimeGCPauseKey] = gc.PauseTotal.String()
+
+	samples := make([]metrics.Sample, len(runtimeMemoryMetrics))
+	for i, name := range runtimeMemoryMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() != metrics.KindUint64 || samples[1].Value.Kind() != metrics.KindUint64 {
+		return
+	}
+	used := samples[0].Value.Uint64() - samples[1].Value.Uint64()
+	fields[RuntimeMemoryKey] = used
+
+	if limit := memoryLimit(); limit > 0 && limit < math.MaxInt64 {
+		fields[RuntimeMemoryLimitKey] = limit
+		fields[RuntimeMemoryPressureKey] = math.Round(float64(used)/float64(limit)*100) / 100
+	}
+}
@@ -0,0 +1,46 @@
+//go:build go1.19
+
+package errorlogger
+
+import (
+	"runtime/debug"
+	"testing"
+	"time"
+)
+
+func TestRuntimeFields(t *testing.T) {
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(1 << 40))
+
+	tests := []struct {
+		name  string
+		every time.Duration
+		logs  []Level
+		want  []bool
+	}{
+		{"error only", 0, []Level{InfoLevel, ErrorLevel, FatalLevel, WarnLevel}, []bool{false, true, true, false}},
+		{"sampled", time.Hour, []Level{InfoLevel, DebugLevel, ErrorLevel, InfoLevel}, []bool{true, false, true, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := RuntimeFields(ErrorLevel, tt.every)
+			for i, level := range tt.logs {
+				entry := &Entry{Level: level, Data: Fields{}}
+				fn(entry)
+				if _, got := entry.Data[RuntimeGoroutinesKey]; got != tt.want[i] {
+					t.Fatalf("entry %d at %v has runtime fields = %v, want %v", i, level, got, tt.want[i])
+				}
+				if !tt.want[i] {
+					continue
+				}
+				for _, k := range []string{RuntimeGCCountKey, RuntimeGCPauseKey, RuntimeMemoryKey, RuntimeMemoryLimitKey, RuntimeMemoryPressureKey} {
+					if _, ok := entry.Data[k]; !ok {
+						t.Errorf("entry %d has no %s field: %v", i, k, entry.Data)
+					}
+				}
+				if n, _ := entry.Data[RuntimeGoroutinesKey].(int); n < 1 {
+					t.Errorf("%s = %v", RuntimeGoroutinesKey, entry.Data[RuntimeGoroutinesKey])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package errorlogger
+
+import "sync/atomic"
+
+// SetSampling logs only the first of every n calls to Err,
+// so that the logging overhead of high QPS services stays
+// bounded without disabling logging:
+//  log.SetSampling(100) // log 1 in 100 errors
+//
+// Err returns every error wrapped as set by SetErrorWrap,
+// logged or not, so that sampling only affects what is
+// logged. Like
+// Disable, sampling swaps the function called by Err, so
+// that it costs nothing while it is off. An n of one or less
+// turns sampling off.
+func (e *errorLogger) SetSampling(n int) {
+	if n < 1 {
+		n = 1
+	}
+	e.enableMu.Lock()
+	defer e.enableMu.Unlock()
+	atomic.StoreUint32(&e.sampleN, uint32(n))
+	atomic.StoreUint32(&e.sampleCount, 0)
+	e.storeErrFn()
+}
+
+// sampleErr is an errorFunc that logs the first of every
+// sampleN errors like yesErr and returns the others wrapped
+// without logging them.
+func (e *errorLogger) sampleErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	n := atomic.LoadUint32(&e.sampleN)
+	if c := atomic.AddUint32(&e.sampleCount, 1); n > 1 && (c-1)%n != 0 {
+		if e.wrap != nil {
+			return wrapWith(err, e.wrap)
+		}
+		return err
+	}
+	return e.yesErr(err)
+}
@@ -0,0 +1,80 @@
+package errorlogger
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_SetSampling(t *testing.T) {
+	tests := []struct {
+		name  string
+		n     int
+		calls int
+		want  int
+	}{
+		{"off", 0, 5, 5},
+		{"one", 1, 5, 5},
+		{"1 in 3", 3, 7, 3},
+		{"1 in 10", 10, 10, 1},
+		{"negative", -2, 4, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			e.SetSampling(tt.n)
+			for i := 0; i < tt.calls; i++ {
+				if got := e.Err(errFake); got != errFake {
+					t.Errorf("Err() = %v, want %v", got, errFake)
+				}
+			}
+			if got := strings.Count(buf.String(), "\n"); got != tt.want {
+				t.Errorf("logged %d of %d errors, want %d", got, tt.calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorLogger_SetSampling_disable(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.SetSampling(2)
+
+	// Disable takes precedence, and Enable restores sampling.
+	e.Disable()
+	e.Err(errFake)
+	if buf.Len() > 0 {
+		t.Errorf("output = %q, want none while disabled", buf.String())
+	}
+	e.Enable()
+	for i := 0; i < 4; i++ {
+		e.Err(errFake)
+	}
+	if got := strings.Count(buf.String(), "\n"); got != 2 {
+		t.Errorf("logged %d of 4 errors, want 2", got)
+	}
+
+	buf.Reset()
+	e.SetSampling(0)
+	e.Err(errFake)
+	e.Err(errFake)
+	if got := strings.Count(buf.String(), "\n"); got != 2 {
+		t.Errorf("logged %d of 2 errors after sampling is off, want 2", got)
+	}
+}
+
+func TestErrorLogger_SetSampling_wrap(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.SetErrorWrap(&os.PathError{})
+	e.SetSampling(3)
+
+	for i := 0; i < 4; i++ {
+		var pathErr *os.PathError
+		if err := e.Err(errFake); !errors.As(err, &pathErr) || !errors.Is(err, errFake) {
+			t.Errorf("call %d: Err() = %v, want errFake wrapped by *os.PathError", i, err)
+		}
+	}
+	if got := strings.Count(buf.String(), "\n"); got != 2 {
+		t.Errorf("logged %d of 4 errors, want 2", got)
+	}
+}
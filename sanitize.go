@@ -0,0 +1,101 @@
+package errorlogger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SanitizeControl returns s with control characters, which
+// include the escape character that starts ANSI terminal
+// sequences, replaced by their visible symbols from the
+// Unicode Control Pictures block, e.g. "\n" by "␊" and
+// ESC by "␛". Tabs are kept. C1 control characters are
+// replaced by U+FFFD.
+//
+// This prevents log injection through user controlled
+// strings, such as embedded newlines that forge entries or
+// escape sequences that rewrite terminal lines, while
+// keeping the content readable.
+func SanitizeControl(s string) string {
+	if strings.IndexFunc(s, isControl) < 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	for _, r := range s {
+		switch {
+		case !isControl(r):
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteRune(0x2400 + r)
+		case r == 0x7f:
+			b.WriteRune(0x2421)
+		default:
+			b.WriteRune('�')
+		}
+	}
+	return b.String()
+}
+
+// isControl reports whether r is a control character other
+// than tab.
+func isControl(r rune) bool {
+	return r != '\t' && (r < 0x20 || r >= 0x7f && r <= 0x9f)
+}
+
+// sanitizeControlEntry returns entry, or a copy of it with
+// the control characters of the message, field keys, and
+// string, error, and Stringer field values replaced by
+// SanitizeControl.
+func sanitizeControlEntry(entry *Entry) *Entry {
+	var c *Entry
+	clone := func() {
+		if c == nil {
+			e := *entry
+			e.Data = make(Fields, len(entry.Data))
+			for k, v := range entry.Data {
+				e.Data[k] = v
+			}
+			c = &e
+		}
+	}
+
+	if msg := SanitizeControl(entry.Message); msg != entry.Message {
+		clone()
+		c.Message = msg
+	}
+	for k, v := range entry.Data {
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = v
+		case error:
+			s = safeSprint(v)
+		case fmt.Stringer:
+			s = safeSprint(v)
+		default:
+			if key := SanitizeControl(k); key != k {
+				clone()
+				delete(c.Data, k)
+				c.Data[key] = v
+			}
+			continue
+		}
+
+		key, safe := SanitizeControl(k), SanitizeControl(s)
+		if key == k && safe == s {
+			continue
+		}
+		clone()
+		delete(c.Data, k)
+		if _, isString := v.(string); isString || safe != s {
+			c.Data[key] = safe
+		} else {
+			c.Data[key] = v
+		}
+	}
+	if c == nil {
+		return entry
+	}
+	return c
+}
@@ -0,0 +1,69 @@
+package errorlogger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestSanitizeControl(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"plain", "plain text", "plain text"},
+		{"tab", "a\tb", "a\tb"},
+		{"newline", "ok\nlevel=error msg=forged", "ok␊level=error msg=forged"},
+		{"carriage return", "a\r\nb", "a␍␊b"},
+		{"ansi", "\x1b[2K\x1b[1Afake", "␛[2K␛[1Afake"},
+		{"del and nul", "a\x7f\x00", "a␡␀"},
+		{"c1", "a\u009bb", "a�b"},
+		{"unicode", "héllo ✓", "héllo ✓"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeControl(tt.s); got != tt.want {
+				t.Errorf("SanitizeControl(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextFormatter_Format_sanitize(t *testing.T) {
+	entry := &Entry{
+		Message: "user input\nlevel=error msg=forged",
+		Data: Fields{
+			"name":        "\x1b[31mroot",
+			"err":         errors.New("bad\rline"),
+			"key\nforged": 1,
+		},
+	}
+	tests := []struct {
+		name    string
+		disable bool
+		wantRaw bool
+	}{
+		{"sanitized", false, false},
+		{"disabled", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &TextFormatter{TextFormatter: logrus.TextFormatter{DisableColors: true, DisableQuote: true}}
+			f.SetDisableSanitize(tt.disable)
+			b, err := f.Format(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			line := strings.TrimSuffix(string(b), "\n")
+			if raw := strings.ContainsAny(line, "\n\r\x1b"); raw != tt.wantRaw {
+				t.Errorf("Format() = %q, control characters present = %v, want %v", b, raw, tt.wantRaw)
+			}
+		})
+	}
+	if _, ok := entry.Data["key\nforged"]; !ok || entry.Message != "user input\nlevel=error msg=forged" {
+		t.Error("Format() changed the entry")
+	}
+}
@@ -0,0 +1,305 @@
+package errorlogger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Window is a recurring daily time window during which a
+// Scheduler applies a specific log level and sampling. For
+// example, Trace logging during a nightly batch window or
+// Warn-only logging with one in ten errors during peak
+// traffic.
+//
+// Start and End are wall clock times of day, as returned by
+// Clock, in the location of the times passed to the
+// Scheduler. If End is before Start, the window wraps past
+// midnight.
+//
+// Sampling is the n of SetSampling during the window; one
+// or less logs every error.
+type Window struct {
+	Name     string
+	Start    time.Duration
+	End      time.Duration
+	Level    Level
+	Sampling int
+}
+
+// Clock returns the time of day of the given hour and
+// minute. It is a convenience for defining Windows:
+//  Window{Name: "batch", Start: Clock(1, 0), End: Clock(5, 0), Level: TraceLevel}
+func Clock(hour, min int) time.Duration {
+	return time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute
+}
+
+// ParseWindow returns a new Window from a span in the format
+// "HH:MM-HH:MM" and a level name. This is the format used
+// when reading schedules from text configuration.
+//  w, err := ParseWindow("peak", "09:00-17:00", "warn")
+func ParseWindow(name, span, level string) (Window, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return Window{}, err
+	}
+
+	parts := strings.Split(span, "-")
+	if len(parts) != 2 {
+		return Window{}, fmt.Errorf("window %q: span must be HH:MM-HH:MM: %w", name, ErrInvalid)
+	}
+
+	start, err := parseClock(parts[0])
+	if err != nil {
+		return Window{}, fmt.Errorf("window %q: %w", name, err)
+	}
+	end, err := parseClock(parts[1])
+	if err != nil {
+		return Window{}, fmt.Errorf("window %q: %w", name, err)
+	}
+
+	return Window{Name: name, Start: start, End: end, Level: lvl}, nil
+}
+
+// ParseSchedule returns the windows of a schedule spec, as
+// used by the "schedule" key of the configuration: windows
+// separated by semicolons, each a name, a colon, a span in
+// the format of ParseWindow, a level name, and an optional
+// sampling of Err as "sample=N":
+//  batch: 01:00-05:00 trace; peak: 09:00-17:00 warn sample=10
+func ParseSchedule(spec string) ([]Window, error) {
+	var windows []Window
+	for _, part := range strings.Split(spec, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		name, rest, ok := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("schedule %q: window needs a name: %w", part, ErrInvalid)
+		}
+		words := strings.Fields(rest)
+		if len(words) < 2 || len(words) > 3 {
+			return nil, fmt.Errorf("window %q: want span, level, and optional sample=N: %w", name, ErrInvalid)
+		}
+		w, err := ParseWindow(name, words[0], words[1])
+		if err != nil {
+			return nil, err
+		}
+		if len(words) == 3 {
+			n, err := strconv.Atoi(strings.TrimPrefix(words[2], "sample="))
+			if err != nil || !strings.HasPrefix(words[2], "sample=") || n < 1 {
+				return nil, fmt.Errorf("window %q: invalid sampling %q: %w", name, words[2], ErrInvalid)
+			}
+			w.Sampling = n
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid clock value %q: %w", s, ErrInvalid)
+	}
+	return Clock(t.Hour(), t.Minute()), nil
+}
+
+// Contains reports whether the wall clock time of t falls
+// within the window. On days with a daylight saving time
+// change, the window still starts and ends at the times on
+// the clock.
+func (w Window) Contains(t time.Time) bool {
+	offset := Clock(t.Hour(), t.Minute()) + time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+
+	if w.Start <= w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	// window wraps past midnight
+	return offset >= w.Start || offset < w.End
+}
+
+// Scheduler applies different log levels and sampling to an
+// ErrorLogger based on the time of day. Outside of all
+// windows, the base level is used and every error is
+// logged. If windows overlap, the first one listed wins.
+//
+// The level and sampling are only set when the schedule
+// moves to another window, so that changes made in between,
+// e.g. by an operator, last until the next window boundary.
+type Scheduler struct {
+	mu      sync.Mutex
+	logger  ErrorLogger
+	base    Level
+	windows []Window
+	active  string
+	current int // index of the applied window, -1 for base
+	applied bool
+	stop    chan struct{}
+}
+
+// NewScheduler returns a new Scheduler for e that uses base
+// as the log level outside of the given windows.
+//
+// The schedule is not applied until Apply or Start is called.
+func NewScheduler(e ErrorLogger, base Level, windows ...Window) *Scheduler {
+	return &Scheduler{
+		logger:  e,
+		base:    base,
+		windows: windows,
+		current: -1,
+	}
+}
+
+// Apply sets the log level and sampling that are scheduled
+// for time t, if the scheduled window differs from the one
+// last applied, and returns the name of the active window.
+// The empty string is returned if the base level is in
+// effect. The first call always applies the schedule.
+func (s *Scheduler) Apply(t time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.apply(t)
+}
+
+func (s *Scheduler) apply(t time.Time) string {
+	current := -1
+	for i, w := range s.windows {
+		if w.Contains(t) {
+			current = i
+			break
+		}
+	}
+	if s.applied && current == s.current {
+		return s.active
+	}
+
+	level, sampling, name := s.base, 1, ""
+	if current >= 0 {
+		w := s.windows[current]
+		level, sampling, name = w.Level, w.Sampling, w.Name
+	}
+	s.logger.SetLevel(level)
+	s.logger.SetSampling(sampling)
+	s.active, s.current, s.applied = name, current, true
+	return name
+}
+
+// Active returns the name of the window currently applied.
+// The empty string is returned if the base level is in effect.
+func (s *Scheduler) Active() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active
+}
+
+// Start applies the schedule immediately and then checks it
+// again at every interval until Stop is called. An interval
+// of zero or less defaults to one minute.
+func (s *Scheduler) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	s.stop = stop
+	s.mu.Unlock()
+
+	s.Apply(time.Now())
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case t := <-ticker.C:
+				s.mu.Lock()
+				if s.stop == stop {
+					s.apply(t)
+				}
+				s.mu.Unlock()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the scheduler. The current log level is left
+// unchanged.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+// loggerSchedule is the schedule of a logger set with
+// SetSchedule.
+type loggerSchedule struct {
+	mu   sync.Mutex
+	spec string
+	s    *Scheduler
+}
+
+// SetSchedule applies the level and sampling schedule in
+// spec, in the format of ParseSchedule, with the current
+// level of the logger as the base level outside of its
+// windows. The schedule is checked every minute. It replaces
+// the previous schedule; an empty spec removes it and
+// restores the base level with sampling turned off.
+//
+// The schedule is also set by the "schedule" key of the
+// configuration applied with ApplyConfig.
+func (e *errorLogger) SetSchedule(spec string) error {
+	if _, err := ParseSchedule(spec); err != nil {
+		return err
+	}
+	base, _ := e.scheduleBase()
+	e.setSchedule(spec, base, nil)
+	return nil
+}
+
+// setSchedule replaces the schedule of the logger with spec,
+// which must be valid, using base outside of its windows.
+func (e *errorLogger) setSchedule(spec string, base Level, src *changeSource) {
+	windows, _ := ParseSchedule(spec)
+
+	e.schedule.mu.Lock()
+	old := e.schedule.spec
+	if e.schedule.s != nil {
+		e.schedule.s.Stop()
+		e.schedule.s = nil
+		e.SetLevel(base)
+		e.SetSampling(1)
+	}
+	e.schedule.spec = spec
+	if len(windows) > 0 {
+		e.schedule.s = NewScheduler(e, base, windows...)
+		e.schedule.s.Start(time.Minute)
+	}
+	e.schedule.mu.Unlock()
+
+	e.recordChange(src, "schedule", old, spec)
+}
+
+// scheduleBase returns the base level and the spec of the
+// schedule of the logger, or its level and "" without one.
+func (e *errorLogger) scheduleBase() (Level, string) {
+	e.schedule.mu.Lock()
+	defer e.schedule.mu.Unlock()
+	if e.schedule.s == nil {
+		return e.GetLevel(), e.schedule.spec
+	}
+	return e.schedule.s.base, e.schedule.spec
+}
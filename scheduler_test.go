@@ -0,0 +1,205 @@
+package errorlogger
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWindow_Contains(t *testing.T) {
+	day := time.Date(2022, 4, 1, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		w    Window
+		at   time.Duration
+		want bool
+	}{
+		{"inside", Window{Name: "peak", Start: Clock(9, 0), End: Clock(17, 0), Level: WarnLevel}, Clock(12, 0), true},
+		{"start inclusive", Window{Name: "peak", Start: Clock(9, 0), End: Clock(17, 0), Level: WarnLevel}, Clock(9, 0), true},
+		{"end exclusive", Window{Name: "peak", Start: Clock(9, 0), End: Clock(17, 0), Level: WarnLevel}, Clock(17, 0), false},
+		{"wrap before midnight", Window{Name: "batch", Start: Clock(22, 0), End: Clock(2, 0), Level: TraceLevel}, Clock(23, 30), true},
+		{"wrap after midnight", Window{Name: "batch", Start: Clock(22, 0), End: Clock(2, 0), Level: TraceLevel}, Clock(1, 0), true},
+		{"wrap outside", Window{Name: "batch", Start: Clock(22, 0), End: Clock(2, 0), Level: TraceLevel}, Clock(12, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.w.Contains(day.Add(tt.at)); got != tt.want {
+				t.Errorf("Window.Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindow_Contains_dst(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	w := Window{Name: "early", Start: Clock(3, 0), End: Clock(4, 0), Level: DebugLevel}
+	// Clocks moved from 02:00 to 03:00 on 2022-03-13 and
+	// from 02:00 back to 01:00 on 2022-11-06.
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2022, 3, 13, 3, 30, 0, 0, loc), true},
+		{time.Date(2022, 3, 13, 4, 30, 0, 0, loc), false},
+		{time.Date(2022, 11, 6, 3, 30, 0, 0, loc), true},
+		{time.Date(2022, 11, 6, 2, 30, 0, 0, loc), false},
+	}
+	for _, tt := range tests {
+		if got := w.Contains(tt.at); got != tt.want {
+			t.Errorf("Window.Contains(%v) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []Window
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"windows", "batch: 01:00-05:00 trace; peak: 09:00-17:00 warn sample=10;", []Window{
+			{Name: "batch", Start: Clock(1, 0), End: Clock(5, 0), Level: TraceLevel},
+			{Name: "peak", Start: Clock(9, 0), End: Clock(17, 0), Level: WarnLevel, Sampling: 10},
+		}, false},
+		{"no name", "01:00-05:00 trace", nil, true},
+		{"no level", "batch: 01:00-05:00", nil, true},
+		{"bad sampling", "peak: 09:00-17:00 warn sample=0", nil, true},
+		{"bad option", "peak: 09:00-17:00 warn every=10", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSchedule(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseSchedule() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseSchedule()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		span    string
+		level   string
+		want    Window
+		wantErr bool
+	}{
+		{"peak", "09:00-17:30", "warn", Window{Name: "peak", Start: Clock(9, 0), End: Clock(17, 30), Level: WarnLevel}, false},
+		{"bad level", "09:00-17:00", "loud", Window{}, true},
+		{"bad span", "09:00", "warn", Window{}, true},
+		{"bad clock", "09:00-25:00", "warn", Window{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWindow(tt.name, tt.span, tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduler_Apply(t *testing.T) {
+	logger := NewWithOptions(true, "", nil, nil, &Logger{Out: Discard, Formatter: DefaultTextFormatter, Level: InfoLevel})
+	s := NewScheduler(logger, InfoLevel,
+		Window{Name: "batch", Start: Clock(1, 0), End: Clock(5, 0), Level: TraceLevel},
+		Window{Name: "peak", Start: Clock(9, 0), End: Clock(17, 0), Level: WarnLevel},
+	)
+
+	day := time.Date(2022, 4, 1, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name   string
+		at     time.Duration
+		window string
+		level  Level
+	}{
+		{"batch", Clock(2, 0), "batch", TraceLevel},
+		{"base", Clock(7, 0), "", InfoLevel},
+		{"peak", Clock(10, 0), "peak", WarnLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Apply(day.Add(tt.at)); got != tt.window {
+				t.Errorf("Scheduler.Apply() = %q, want %q", got, tt.window)
+			}
+			if got := logger.GetLevel(); got != tt.level {
+				t.Errorf("Scheduler.Apply() level = %v, want %v", got, tt.level)
+			}
+			if got := s.Active(); got != tt.window {
+				t.Errorf("Scheduler.Active() = %q, want %q", got, tt.window)
+			}
+		})
+	}
+
+	s.Start(time.Hour)
+	s.Start(time.Hour) // second call is a no-op
+	s.Stop()
+	s.Stop()
+}
+
+func TestScheduler_Apply_boundary(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	s := NewScheduler(e, InfoLevel, Window{Name: "peak", Start: Clock(9, 0), End: Clock(17, 0), Level: WarnLevel, Sampling: 10})
+	day := time.Date(2022, 4, 1, 0, 0, 0, 0, time.Local)
+
+	s.Apply(day.Add(Clock(10, 0)))
+	if e.GetLevel() != WarnLevel || atomic.LoadUint32(&e.sampleN) != 10 {
+		t.Fatalf("level = %v, sampling = %d, want warning and 10", e.GetLevel(), e.sampleN)
+	}
+
+	// A change within the window lasts until its end.
+	e.SetLevel(DebugLevel)
+	s.Apply(day.Add(Clock(11, 0)))
+	if e.GetLevel() != DebugLevel {
+		t.Errorf("level = %v within the window, want the change kept", e.GetLevel())
+	}
+	s.Apply(day.Add(Clock(17, 0)))
+	if e.GetLevel() != InfoLevel || atomic.LoadUint32(&e.sampleN) > 1 {
+		t.Errorf("level = %v, sampling = %d after the window, want info and none", e.GetLevel(), e.sampleN)
+	}
+}
+
+func TestErrorLogger_SetSchedule(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	defer e.SetSchedule("")
+
+	// The window covers all but the last minute of the day.
+	c := e.Config()
+	c.Schedule = "day: 00:00-23:59 error"
+	if err := e.ApplyConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	if got := e.Config(); got.Schedule != c.Schedule || got.Level != "info" {
+		t.Errorf("Config() = %+v, want the schedule with the base level", got)
+	}
+	if now := time.Now(); (now.Hour() != 23 || now.Minute() != 59) && e.GetLevel() != ErrorLevel {
+		t.Errorf("level = %v, want the level of the window", e.GetLevel())
+	}
+
+	if err := e.SetSchedule("bad"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("SetSchedule(bad) error = %v, want ErrInvalid", err)
+	}
+	if err := e.SetSchedule(""); err != nil {
+		t.Fatal(err)
+	}
+	if e.GetLevel() != InfoLevel || e.Config().Schedule != "" {
+		t.Errorf("level = %v after removing the schedule, want info", e.GetLevel())
+	}
+}
@@ -0,0 +1,132 @@
+package errorlogger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// SchemaErrorKey is the field added to entries that violate
+// the schema of the logger. It describes the violations.
+const SchemaErrorKey = "schema_error"
+
+type (
+	// Schema declares the fields that entries must carry so
+	// that structured logs stay queryable.
+	//  log.SetSchema(errorlogger.Schema{
+	//      Types: map[string]reflect.Type{"user_id": reflect.TypeOf("")},
+	//      Events: map[string]errorlogger.EventSchema{
+	//          "payment_captured": {Required: []string{"order_id", "amount"}},
+	//      },
+	//  })
+	Schema struct {
+		// Types are the expected types of fields in all
+		// entries. A value must be assignable to its type.
+		Types map[string]reflect.Type
+
+		// Events are the schemas of entries logged by Event,
+		// by event name.
+		Events map[string]EventSchema
+
+		// Strict drops entries that violate the schema
+		// instead of marking them.
+		Strict bool
+	}
+
+	// EventSchema declares the fields of the entries of one
+	// event.
+	EventSchema struct {
+		// Required are the fields every entry must have.
+		Required []string
+
+		// Types are the expected types of fields, in addition
+		// to the Types of the Schema.
+		Types map[string]reflect.Type
+	}
+)
+
+// SetSchema sets the schema that entries are validated
+// against before they are formatted. Entries that violate
+// it get a SchemaErrorKey field describing the violations,
+// or are dropped if s.Strict is set, and are counted in
+// Stats().SchemaViolations. A zero Schema turns validation
+// off.
+func (e *errorLogger) SetSchema(s Schema) {
+	if len(s.Types) == 0 && len(s.Events) == 0 {
+		e.schema.Store((*Schema)(nil))
+		return
+	}
+	e.schema.Store(&s)
+}
+
+// validate checks entry against the schema of the logger
+// and reports whether it should be written.
+func (e *errorLogger) validate(entry *Entry) bool {
+	s, _ := e.schema.Load().(*Schema)
+	if s == nil {
+		return true
+	}
+	problems := s.Check(entry)
+	if len(problems) == 0 {
+		return true
+	}
+	if e.stats != nil {
+		atomic.AddUint64(&e.stats.schemaViolations, 1)
+	}
+	if s.Strict {
+		return false
+	}
+	entry.Data[SchemaErrorKey] = strings.Join(problems, "; ")
+	return true
+}
+
+// Check returns the violations of the schema by entry, or
+// nil if it conforms.
+func (s *Schema) Check(entry *Entry) []string {
+	var problems []string
+	event, _ := entry.Data[EventKey].(string)
+	es, isEvent := s.Events[event]
+	if isEvent {
+		for _, k := range es.Required {
+			if _, ok := entry.Data[k]; !ok {
+				problems = append(problems, "missing "+k)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		want, ok := es.Types[k]
+		if !ok {
+			if want, ok = s.Types[k]; !ok {
+				continue
+			}
+		}
+		v := entry.Data[k]
+		if v == nil {
+			if !canBeNil(want) {
+				problems = append(problems, fmt.Sprintf("%s: want %v, got nil", k, want))
+			}
+			continue
+		}
+		if got := reflect.TypeOf(v); !got.AssignableTo(want) {
+			problems = append(problems, fmt.Sprintf("%s: want %v, got %v", k, want, got))
+		}
+	}
+	return problems
+}
+
+// canBeNil reports whether nil is a valid value of type t.
+func canBeNil(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package errorlogger
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchema_Check(t *testing.T) {
+	s := &Schema{
+		Types: map[string]reflect.Type{
+			"user_id": reflect.TypeOf(""),
+			"err":     reflect.TypeOf((*error)(nil)).Elem(),
+		},
+		Events: map[string]EventSchema{
+			"payment_captured": {
+				Required: []string{"order_id", "amount"},
+				Types:    map[string]reflect.Type{"amount": reflect.TypeOf(0.0)},
+			},
+		},
+	}
+	tests := []struct {
+		name string
+		data Fields
+		want []string
+	}{
+		{"no fields", Fields{}, nil},
+		{"typed", Fields{"user_id": "u1", "err": errFake}, nil},
+		{"wrong type", Fields{"user_id": 42}, []string{"user_id: want string, got int"}},
+		{"nil interface", Fields{"err": nil}, nil},
+		{"nil string", Fields{"user_id": nil}, []string{"user_id: want string, got nil"}},
+		{"event", Fields{EventKey: "payment_captured", "order_id": 7, "amount": 9.5}, nil},
+		{"event missing", Fields{EventKey: "payment_captured", "amount": 9}, []string{"missing order_id", "amount: want float64, got int"}},
+		{"other event", Fields{EventKey: "cache_miss"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Check(&Entry{Data: tt.data}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorLogger_SetSchema(t *testing.T) {
+	schema := Schema{Events: map[string]EventSchema{"job_completed": {Required: []string{"job"}}}}
+	tests := []struct {
+		name        string
+		strict      bool
+		wantOutput  []string
+		wantEntries int
+	}{
+		{"mark", false, []string{`schema_error="missing job"`}, 2},
+		{"strict", true, nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			schema.Strict = tt.strict
+			e.SetSchema(schema)
+
+			e.Event("job_completed", nil)
+			e.Event("job_completed", Fields{"job": "j1"})
+
+			out := buf.String()
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(out, want) {
+					t.Errorf("output = %q, want %q", out, want)
+				}
+			}
+			if n := strings.Count(out, "msg=job_completed"); n != tt.wantEntries {
+				t.Errorf("%d entries written, want %d:\n%s", n, tt.wantEntries, out)
+			}
+			if got := e.Stats().SchemaViolations; got != 1 {
+				t.Errorf("SchemaViolations = %d, want 1", got)
+			}
+			if got := strings.Count(out, "job=j1"); got != 1 {
+				t.Errorf("valid entry written %d times, want 1", got)
+			}
+		})
+	}
+
+	e, buf := newBufferLogger(InfoLevel)
+	e.SetSchema(schema)
+	e.SetSchema(Schema{})
+	e.Event("job_completed", nil)
+	if strings.Contains(buf.String(), SchemaErrorKey) {
+		t.Errorf("zero Schema still validates: %q", buf.String())
+	}
+}
@@ -0,0 +1,70 @@
+package errorlogger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SessionKey is the field that holds the session ID.
+const SessionKey = "session"
+
+// sessionID is generated once per process.
+var sessionID = newSessionID()
+
+// newSessionID returns 8 random bytes in hex, or the start
+// time in hex if no randomness is available.
+func newSessionID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// SessionID returns the ID of the current run of the
+// process. It is generated at startup and is the same for
+// every logger in the process, so that the entries of
+// restarts interleaved in the same file can be separated.
+func SessionID() string { return sessionID }
+
+// SessionFields returns the fields that identify the
+// current session.
+//  log.WithFields(errorlogger.SessionFields()).Info("started")
+func SessionFields() Fields { return Fields{SessionKey: sessionID} }
+
+// SessionHook is a logrus hook that adds the session ID to
+// every entry that does not already have one.
+//  log.AddHook(errorlogger.SessionHook{})
+type SessionHook struct{}
+
+// Levels returns all levels.
+func (SessionHook) Levels() []Level { return AllLevels }
+
+// Fire adds the session field to entry.
+func (SessionHook) Fire(entry *Entry) error {
+	if _, ok := entry.Data[SessionKey]; !ok {
+		entry.Data[SessionKey] = sessionID
+	}
+	return nil
+}
+
+// SessionFilter selects the entries of one session from
+// decoded JSON log entries. ID may be a prefix of the full
+// session ID, as with abbreviated commit hashes.
+type SessionFilter struct {
+	ID string
+}
+
+// Match reports whether fields belong to the session. An
+// empty ID matches every entry.
+func (f SessionFilter) Match(fields map[string]interface{}) bool {
+	if f.ID == "" {
+		return true
+	}
+	v, ok := fields[SessionKey]
+	return ok && strings.HasPrefix(fmt.Sprint(v), f.ID)
+}
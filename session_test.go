@@ -0,0 +1,51 @@
+package errorlogger
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSessionHook(t *testing.T) {
+	if len(SessionID()) != 16 || SessionID() != SessionFields()[SessionKey] {
+		t.Fatalf("SessionID() = %q, SessionFields() = %v", SessionID(), SessionFields())
+	}
+
+	e, buf := newBufferLogger(InfoLevel)
+	e.SetFormatter(&JSONFormatter{})
+	e.AddHook(SessionHook{})
+	e.Info("started")
+	e.WithField(SessionKey, "other").Info("forwarded")
+
+	dec := json.NewDecoder(buf)
+	for _, want := range []string{SessionID(), "other"} {
+		var entry map[string]interface{}
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry[SessionKey] != want {
+			t.Errorf("session = %v, want %v", entry[SessionKey], want)
+		}
+	}
+}
+
+func TestSessionFilter_Match(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		fields map[string]interface{}
+		want   bool
+	}{
+		{"empty matches all", "", map[string]interface{}{}, true},
+		{"full id", "0123abcd", map[string]interface{}{SessionKey: "0123abcd"}, true},
+		{"prefix", "0123", map[string]interface{}{SessionKey: "0123abcd"}, true},
+		{"other session", "4567", map[string]interface{}{SessionKey: "0123abcd"}, false},
+		{"no session", "0123", map[string]interface{}{"msg": "x"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (SessionFilter{ID: tt.id}).Match(tt.fields); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package errorlogger
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultMaxEntrySize is the size above which entries are
+// truncated by a SharedFile if no size is given.
+const DefaultMaxEntrySize = 64 << 10
+
+type (
+	// SharedFileOptions configures a SharedFile.
+	SharedFileOptions struct {
+		// Lock serializes writes between processes with an
+		// advisory lock (flock) on the file. It is
+		// unsupported on some platforms, where
+		// OpenSharedFile returns an error.
+		Lock bool
+
+		// MaxEntrySize is the maximum size of an entry in
+		// bytes. Longer entries are truncated so that each
+		// entry is written with a single append. Zero uses
+		// DefaultMaxEntrySize; less than zero disables it.
+		MaxEntrySize int
+
+		// Perm is the permission of a new file. The default
+		// is 0o644.
+		Perm os.FileMode
+	}
+
+	// SharedFile is a file Writer that can be shared by
+	// several processes without interleaving partial lines.
+	//
+	// Every entry is written with a single write to a file
+	// opened with O_APPEND, which the operating system
+	// appends atomically to the end of the file. Entries
+	// longer than the maximum entry size are truncated
+	// rather than split. With Lock, writes are additionally
+	// serialized with an advisory lock, for file systems
+	// that do not guarantee atomic appends.
+	//
+	//  f, err := OpenSharedFile("/var/log/myapp.log", SharedFileOptions{Lock: true})
+	//  log.SetOutput(f)
+	SharedFile struct {
+		f         *os.File
+		lock      bool
+		max       int
+		mu        sync.Mutex
+		truncated uint64 // atomic
+	}
+)
+
+// OpenSharedFile opens or creates the file at path for
+// appending by several processes.
+func OpenSharedFile(path string, opts SharedFileOptions) (*SharedFile, error) {
+	if opts.Perm == 0 {
+		opts.Perm = 0o644
+	}
+	switch {
+	case opts.MaxEntrySize == 0:
+		opts.MaxEntrySize = DefaultMaxEntrySize
+	case opts.MaxEntrySize < 0:
+		opts.MaxEntrySize = 0
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, opts.Perm)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Lock && !canLockFile {
+		f.Close()
+		return nil, &PathError{Op: "lock", Path: path, Err: ErrUnsupported}
+	}
+	return &SharedFile{f: f, lock: opts.Lock, max: opts.MaxEntrySize}, nil
+}
+
+// Write appends p, which is expected to be one log entry,
+// to the file with a single write.
+func (s *SharedFile) Write(p []byte) (int, error) {
+	n := len(p)
+	if s.max > 0 && len(p) > s.max {
+		atomic.AddUint64(&s.truncated, 1)
+		q := make([]byte, s.max)
+		copy(q, p[:s.max-1])
+		q[s.max-1] = '\n'
+		p = q
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lock {
+		if err := lockFile(s.f); err != nil {
+			return 0, err
+		}
+		defer unlockFile(s.f)
+	}
+	if _, err := s.f.Write(p); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Truncated returns the number of entries that were
+// truncated to the maximum entry size.
+func (s *SharedFile) Truncated() uint64 {
+	return atomic.LoadUint64(&s.truncated)
+}
+
+// Close closes the file.
+func (s *SharedFile) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
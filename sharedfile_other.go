@@ -0,0 +1,10 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package errorlogger
+
+import "os"
+
+const canLockFile = false
+
+func lockFile(f *os.File) error   { return ErrUnsupported }
+func unlockFile(f *os.File) error { return ErrUnsupported }
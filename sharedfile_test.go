@@ -0,0 +1,129 @@
+package errorlogger
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	sharedFileEnv     = "ERRORLOGGER_SHARED_FILE"
+	sharedFileWriters = 4
+	sharedFileLines   = 500
+)
+
+// TestSharedFileHelper is run as a separate process by
+// TestSharedFile to write to a shared file.
+func TestSharedFileHelper(t *testing.T) {
+	path := os.Getenv(sharedFileEnv)
+	if path == "" {
+		t.Skip("helper process")
+	}
+	lock := os.Getenv(sharedFileEnv+"_LOCK") == "1"
+	writer := os.Getenv(sharedFileEnv + "_WRITER")
+
+	f, err := OpenSharedFile(path, SharedFileOptions{Lock: lock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	e, _ := newBufferLogger(InfoLevel)
+	e.SetFormatter(&JSONFormatter{})
+	e.SetOutput(f)
+	pad := strings.Repeat("x", 2000)
+	for i := 0; i < sharedFileLines; i++ {
+		e.WithFields(Fields{"writer": writer, "n": i, "pad": pad}).Info("shared")
+	}
+}
+
+func TestSharedFile(t *testing.T) {
+	if os.Getenv(sharedFileEnv) != "" {
+		t.Skip("helper process")
+	}
+	tests := []struct {
+		name string
+		lock bool
+	}{
+		{"append", false},
+		{"flock", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.lock && !canLockFile {
+				t.Skip("file locking is not supported")
+			}
+			path := filepath.Join(t.TempDir(), "shared.log")
+			cmds := make([]*exec.Cmd, sharedFileWriters)
+			for i := range cmds {
+				cmd := exec.Command(os.Args[0], "-test.run=^TestSharedFileHelper$")
+				cmd.Env = append(os.Environ(),
+					sharedFileEnv+"="+path,
+					sharedFileEnv+"_WRITER="+strconv.Itoa(i),
+					sharedFileEnv+"_LOCK="+map[bool]string{true: "1"}[tt.lock],
+				)
+				if err := cmd.Start(); err != nil {
+					t.Fatal(err)
+				}
+				cmds[i] = cmd
+			}
+			for _, cmd := range cmds {
+				if err := cmd.Wait(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			next := make([]int, sharedFileWriters)
+			sc := bufio.NewScanner(f)
+			sc.Buffer(nil, 1<<20)
+			lines := 0
+			for sc.Scan() {
+				lines++
+				var entry struct {
+					Writer string
+					N      int
+				}
+				if err := json.Unmarshal(sc.Bytes(), &entry); err != nil {
+					t.Fatalf("line %d is corrupt: %v", lines, err)
+				}
+				w, _ := strconv.Atoi(entry.Writer)
+				if entry.N != next[w] {
+					t.Fatalf("line %d: writer %d entry %d, want %d", lines, w, entry.N, next[w])
+				}
+				next[w]++
+			}
+			if lines != sharedFileWriters*sharedFileLines {
+				t.Errorf("lines = %d, want %d", lines, sharedFileWriters*sharedFileLines)
+			}
+		})
+	}
+}
+
+func TestSharedFile_truncate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared.log")
+	f, err := OpenSharedFile(path, SharedFileOptions{MaxEntrySize: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"short\n", "a long entry\n"} {
+		if n, err := f.Write([]byte(s)); n != len(s) || err != nil {
+			t.Errorf("Write(%q) = %d, %v", s, n, err)
+		}
+	}
+	f.Close()
+
+	b, _ := os.ReadFile(path)
+	if string(b) != "short\na long \n" || f.Truncated() != 1 {
+		t.Errorf("file = %q, truncated %d", b, f.Truncated())
+	}
+}
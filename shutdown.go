@@ -0,0 +1,119 @@
+package errorlogger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// ShutdownKey is the field of the entry logged by
+	// LogShutdown. Its value is "clean".
+	ShutdownKey = "shutdown"
+
+	// shutdownTail is the number of bytes at the end of a
+	// log file searched for the shutdown marker.
+	shutdownTail = 4 << 10
+)
+
+// ShutdownCheck is the result of looking for the shutdown
+// marker of the previous run at the end of a log file.
+type ShutdownCheck struct {
+	Path string
+
+	// Clean reports whether the last entry of the file is a
+	// shutdown marker. A missing or empty file is clean.
+	Clean bool
+
+	// LastEntry is the last line of the file and LastWrite
+	// the time the file was last modified.
+	LastEntry string
+	LastWrite time.Time
+}
+
+// LogShutdown logs the shutdown marker that CheckShutdown
+// looks for in the next run. Call it as the last entry
+// before the process exits:
+//  defer log.LogShutdown()
+//
+// The marker is logged at InfoLevel, or at the level of the
+// logger if it is less verbose, down to ErrorLevel.
+func (e *errorLogger) LogShutdown() {
+	level := InfoLevel
+	if l := e.GetLevel(); l < level {
+		level = l
+	}
+	if level < ErrorLevel {
+		return
+	}
+	e.WithFields(Fields{ShutdownKey: "clean", SessionKey: sessionID}).Log(level, "logger shut down")
+}
+
+// CheckShutdown looks for the shutdown marker of the
+// previous run at the end of the log file the logger writes
+// to and logs a warning with the age of the last entry if
+// it is missing, as a cheap way of detecting crashes using
+// only the log itself. Call it on startup before logging:
+//  log.ApplyConfig(c)
+//  log.CheckShutdown()
+//
+// ErrUnsupported is returned if the output is not a file.
+func (e *errorLogger) CheckShutdown() (ShutdownCheck, error) {
+	f, ok := e.output().(*os.File)
+	if !ok || f == os.Stderr || f == os.Stdout {
+		return ShutdownCheck{}, fmt.Errorf("check shutdown of %s: %w", outputName(e.output()), ErrUnsupported)
+	}
+	c, err := LastShutdown(f.Name())
+	if err != nil || c.Clean {
+		return c, err
+	}
+	e.WithFields(Fields{
+		"log_file":   c.Path,
+		"last_write": c.LastWrite.Format(time.RFC3339),
+		"stale":      time.Since(c.LastWrite).Round(time.Second).String(),
+	}).Warn("previous run did not shut down cleanly")
+	return c, nil
+}
+
+// LastShutdown reads the end of the log file at path and
+// reports whether the previous run logged its shutdown
+// marker. Text and JSON log files are supported.
+func LastShutdown(path string) (ShutdownCheck, error) {
+	c := ShutdownCheck{Path: path, Clean: true}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return c, err
+	}
+	c.LastWrite = fi.ModTime()
+	offset := fi.Size() - shutdownTail
+	if offset < 0 {
+		offset = 0
+	}
+	tail := make([]byte, fi.Size()-offset)
+	if _, err := f.ReadAt(tail, offset); err != nil && err != io.EOF {
+		return c, &PathError{Op: "read", Path: path, Err: err}
+	}
+
+	tail = bytes.TrimRight(tail, "\r\n")
+	if len(tail) == 0 {
+		return c, nil
+	}
+	if i := bytes.LastIndexByte(tail, '\n'); i >= 0 {
+		tail = tail[i+1:]
+	}
+	c.LastEntry = string(tail)
+	c.Clean = bytes.Contains(tail, []byte(ShutdownKey+"=clean")) ||
+		bytes.Contains(tail, []byte(`"`+ShutdownKey+`":"clean"`))
+	return c, nil
+}
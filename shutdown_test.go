@@ -0,0 +1,75 @@
+package errorlogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_CheckShutdown(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		shutdown  bool
+		wantClean bool
+	}{
+		{"text clean", "text", true, true},
+		{"text crashed", "text", false, false},
+		{"json clean", "json", true, true},
+		{"json crashed", "json", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			c := Config{Level: "info", Format: tt.format, Enabled: true, Output: path}
+
+			prev, _ := newBufferLogger(InfoLevel)
+			if err := prev.ApplyConfig(c); err != nil {
+				t.Fatal(err)
+			}
+			prev.Info("serving")
+			if tt.shutdown {
+				prev.LogShutdown()
+			}
+			prev.output().(*os.File).Close()
+
+			next, _ := newBufferLogger(InfoLevel)
+			if err := next.ApplyConfig(c); err != nil {
+				t.Fatal(err)
+			}
+			got, err := next.CheckShutdown()
+			if err != nil {
+				t.Fatal(err)
+			}
+			next.output().(*os.File).Close()
+			if got.Clean != tt.wantClean {
+				t.Errorf("CheckShutdown() = %+v, want clean %v", got, tt.wantClean)
+			}
+
+			b, _ := os.ReadFile(path)
+			if warned := strings.Contains(string(b), "did not shut down cleanly"); warned == tt.wantClean {
+				t.Errorf("warned = %v, want %v:\n%s", warned, !tt.wantClean, b)
+			}
+		})
+	}
+}
+
+func TestLastShutdown(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.log")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{filepath.Join(dir, "missing.log"), empty} {
+		if c, err := LastShutdown(path); err != nil || !c.Clean {
+			t.Errorf("LastShutdown(%s) = %+v, %v, want clean", filepath.Base(path), c, err)
+		}
+	}
+
+	e, _ := newBufferLogger(InfoLevel)
+	if _, err := e.CheckShutdown(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("CheckShutdown() of a buffer error = %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,202 @@
+package errorlogger
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Sink is an output of a logger. Each Write is expected
+	// to be one formatted entry.
+	Sink = Writer
+
+	// SinkMiddleware wraps a sink with a feature such as
+	// compression, encryption, batching, rate limiting, or
+	// metrics. Middleware sinks should implement Flusher
+	// and io.Closer by finishing their own work and then
+	// calling FlushSink or CloseSink on the sink they wrap,
+	// so that a chain can be flushed and closed from the
+	// outside.
+	SinkMiddleware func(next Sink) Sink
+)
+
+// WrapSink wraps s with the middleware mw. The first
+// middleware is the outermost: it receives the entries
+// first.
+//  out := WrapSink(file, Batch(64<<10, time.Second), Compress(gzip.BestSpeed))
+//  log.SetOutput(out)
+//  InstallExitHandler(out.(Flusher))
+//  defer CloseSink(out)
+func WrapSink(s Sink, mw ...SinkMiddleware) Sink {
+	for i := len(mw) - 1; i >= 0; i-- {
+		s = mw[i](s)
+	}
+	return s
+}
+
+// FlushSink writes the entries buffered by s, if it is a
+// Flusher.
+func FlushSink(s Sink) {
+	if f, ok := s.(Flusher); ok {
+		f.Flush()
+	}
+}
+
+// CloseSink flushes and closes s, if it is an io.Closer.
+func CloseSink(s Sink) error {
+	if c, ok := s.(io.Closer); ok {
+		return c.Close()
+	}
+	FlushSink(s)
+	return nil
+}
+
+// Batch returns a middleware that collects entries and
+// writes them to the sink in one write when size bytes are
+// buffered or interval has passed since the first buffered
+// entry, reducing the number of writes to slow sinks. An
+// interval of zero or less only writes full batches.
+//
+// The entries of a batch that cannot be written are lost.
+// If the batch was written by the timer or by Flush, the
+// error is returned by the next Write, which still buffers
+// its entry.
+func Batch(size int, interval time.Duration) SinkMiddleware {
+	return func(next Sink) Sink {
+		return &batchSink{next: next, size: size, interval: interval}
+	}
+}
+
+type batchSink struct {
+	next     Sink
+	size     int
+	interval time.Duration
+
+	mu    sync.Mutex
+	buf   []byte
+	timer *time.Timer
+	err   error // the last error of a flush, returned by the next Write
+}
+
+func (b *batchSink) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	err := b.err
+	b.err = nil
+	b.buf = append(b.buf, p...)
+	if len(b.buf) >= b.size {
+		if ferr := b.flush(); ferr != nil {
+			err = ferr
+		}
+	} else if b.timer == nil && b.interval > 0 {
+		b.timer = time.AfterFunc(b.interval, b.timedFlush)
+	}
+	return len(p), err
+}
+
+func (b *batchSink) timedFlush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.timer = nil
+	if err := b.flush(); err != nil {
+		b.err = err
+	}
+}
+
+// flush writes the batch. b.mu must be held.
+func (b *batchSink) flush() error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.buf) == 0 {
+		return nil
+	}
+	_, err := b.next.Write(b.buf)
+	b.buf = b.buf[:0]
+	return err
+}
+
+func (b *batchSink) Flush() {
+	b.mu.Lock()
+	if err := b.flush(); err != nil {
+		b.err = err
+	}
+	b.mu.Unlock()
+	FlushSink(b.next)
+}
+
+func (b *batchSink) Close() error {
+	b.mu.Lock()
+	err := b.flush()
+	b.mu.Unlock()
+	if cerr := CloseSink(b.next); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// RateLimit returns a middleware that passes at most
+// perSecond entries per second to the sink, with bursts of
+// up to burst entries, and drops the rest. Dropped writes
+// report success. The number of dropped entries is
+// reported by the Dropped method of the sink.
+func RateLimit(perSecond float64, burst int) SinkMiddleware {
+	return func(next Sink) Sink {
+		return &rateSink{next: next, rate: perSecond, burst: float64(burst), tokens: float64(burst), now: time.Now}
+	}
+}
+
+type rateSink struct {
+	next        Sink
+	rate, burst float64
+	now         func() time.Time
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	dropped uint64 // atomic
+}
+
+func (r *rateSink) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	now := r.now()
+	if !r.last.IsZero() {
+		r.tokens += now.Sub(r.last).Seconds() * r.rate
+		if r.tokens > r.burst {
+			r.tokens = r.burst
+		}
+	}
+	r.last = now
+	if r.tokens < 1 {
+		r.mu.Unlock()
+		atomic.AddUint64(&r.dropped, 1)
+		return len(p), nil
+	}
+	r.tokens--
+	r.mu.Unlock()
+	return r.next.Write(p)
+}
+
+// Dropped returns the number of entries dropped by the
+// rate limit.
+func (r *rateSink) Dropped() uint64 { return atomic.LoadUint64(&r.dropped) }
+
+func (r *rateSink) Flush()       { FlushSink(r.next) }
+func (r *rateSink) Close() error { return CloseSink(r.next) }
+
+// Meter returns a middleware that counts the bytes and
+// entries written to the sink and enforces the daily
+// quota of opts, as a MeteredWriter named name.
+func Meter(name string, opts QuotaOptions) SinkMiddleware {
+	return func(next Sink) Sink {
+		return &meterSink{NewMeteredWriter(name, next, opts)}
+	}
+}
+
+type meterSink struct{ *MeteredWriter }
+
+func (m *meterSink) Flush()       { FlushSink(m.out) }
+func (m *meterSink) Close() error { return CloseSink(m.out) }
@@ -0,0 +1,149 @@
+package errorlogger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Compress returns a middleware that gzip compresses the
+// stream written to the sink at the given compression
+// level, e.g. gzip.BestSpeed. Entries are compressed as
+// they are written and reach the sink as the compressor
+// fills its buffer, on Flush, and on Close, which must be
+// called to write a complete gzip stream.
+//
+// If the sink fails, the compressed data not yet written
+// is lost and a new gzip member is started, so that later
+// entries can still be read by a reader of multistream
+// gzip such as gzip.Reader. The error of a failed Flush is
+// returned by the next Write, which still writes its entry.
+func Compress(level int) SinkMiddleware {
+	return func(next Sink) Sink {
+		zw, err := gzip.NewWriterLevel(next, level)
+		if err != nil {
+			zw = gzip.NewWriter(next)
+		}
+		return &gzipSink{next: next, zw: zw}
+	}
+}
+
+type gzipSink struct {
+	next Sink
+	mu   sync.Mutex
+	zw   *gzip.Writer
+	err  error // the last error of a flush, returned by the next Write
+}
+
+func (g *gzipSink) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	n, err := g.zw.Write(p)
+	if err != nil {
+		g.zw.Reset(g.next)
+	}
+	if ferr := g.err; ferr != nil {
+		g.err = nil
+		if err == nil {
+			err = ferr
+		}
+	}
+	return n, err
+}
+
+func (g *gzipSink) Flush() {
+	g.mu.Lock()
+	if err := g.zw.Flush(); err != nil {
+		g.err = err
+		g.zw.Reset(g.next)
+	}
+	g.mu.Unlock()
+	FlushSink(g.next)
+}
+
+func (g *gzipSink) Close() error {
+	g.mu.Lock()
+	err := g.zw.Close()
+	g.mu.Unlock()
+	if cerr := CloseSink(g.next); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Encrypt returns a middleware that encrypts every write to
+// the sink with aead, e.g. AES-GCM, so that logs at rest or
+// in transit cannot be read without the key. Each write is
+// sealed with a random nonce and written as one frame:
+//
+//  length uint32 (big endian) of nonce and ciphertext
+//  nonce
+//  ciphertext
+//
+// Use DecryptFrames to read the entries back.
+func Encrypt(aead cipher.AEAD) SinkMiddleware {
+	return func(next Sink) Sink {
+		return &cryptSink{next: next, aead: aead}
+	}
+}
+
+type cryptSink struct {
+	next Sink
+	aead cipher.AEAD
+}
+
+func (c *cryptSink) Write(p []byte) (int, error) {
+	ns := c.aead.NonceSize()
+	frame := make([]byte, 4+ns, 4+ns+len(p)+c.aead.Overhead())
+	if _, err := rand.Read(frame[4:]); err != nil {
+		return 0, err
+	}
+	frame = c.aead.Seal(frame, frame[4:], p, nil)
+	binary.BigEndian.PutUint32(frame, uint32(len(frame)-4))
+	if _, err := c.next.Write(frame); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *cryptSink) Flush()       { FlushSink(c.next) }
+func (c *cryptSink) Close() error { return CloseSink(c.next) }
+
+// DecryptFrames reads the frames written by an Encrypt
+// middleware from r and writes the decrypted entries to w.
+func DecryptFrames(r io.Reader, w io.Writer, aead cipher.AEAD) error {
+	br := bufio.NewReader(r)
+	ns := aead.NonceSize()
+	var hdr [4]byte
+	for {
+		if _, err := io.ReadFull(br, hdr[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		n := binary.BigEndian.Uint32(hdr[:])
+		if n < uint32(ns+aead.Overhead()) || n > uint32(MaxBinaryEntrySize) {
+			return fmt.Errorf("invalid encrypted frame of %d bytes: %w", n, ErrInvalid)
+		}
+		frame := make([]byte, n)
+		if _, err := io.ReadFull(br, frame); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		p, err := aead.Open(nil, frame[:ns], frame[ns:], nil)
+		if err != nil {
+			return fmt.Errorf("cannot decrypt frame: %v: %w", err, ErrInvalid)
+		}
+		if _, err := w.Write(p); err != nil {
+			return err
+		}
+	}
+}
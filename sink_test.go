@@ -0,0 +1,197 @@
+package errorlogger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// prefixSink is a middleware that prefixes every write.
+func prefixSink(prefix string) SinkMiddleware {
+	return func(next Sink) Sink {
+		return writerFunc(func(p []byte) (int, error) {
+			_, err := next.Write(append([]byte(prefix), p...))
+			return len(p), err
+		})
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+func TestWrapSink(t *testing.T) {
+	var buf bytes.Buffer
+	s := WrapSink(&buf, prefixSink("a"), prefixSink("b"))
+	s.Write([]byte("x"))
+	if buf.String() != "bax" {
+		t.Errorf("WrapSink() wrote %q, want %q", buf.String(), "bax")
+	}
+	if s := WrapSink(&buf); s != &buf {
+		t.Error("WrapSink() without middleware did not return the sink")
+	}
+}
+
+// countingWriter counts writes.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestBatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       int
+		interval   time.Duration
+		entries    int
+		wait       time.Duration
+		wantWrites int
+	}{
+		{"size", 10, 0, 7, 0, 3},
+		{"interval", 1 << 20, 5 * time.Millisecond, 3, 50 * time.Millisecond, 1},
+		{"pending", 1 << 20, time.Hour, 3, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &countingWriter{}
+			s := WrapSink(out, Batch(tt.size, tt.interval))
+			for i := 0; i < tt.entries; i++ {
+				s.Write([]byte("abcd\n"))
+			}
+			time.Sleep(tt.wait)
+			s.(*batchSink).mu.Lock()
+			writes := out.writes
+			s.(*batchSink).mu.Unlock()
+			if writes != tt.wantWrites {
+				t.Errorf("writes = %d, want %d", writes, tt.wantWrites)
+			}
+
+			if err := CloseSink(s); err != nil {
+				t.Fatal(err)
+			}
+			if out.Len() != 5*tt.entries {
+				t.Errorf("after Close() wrote %d bytes, want %d", out.Len(), 5*tt.entries)
+			}
+		})
+	}
+}
+
+func TestSink_error(t *testing.T) {
+	errWrite := errors.New("write failed")
+	tests := []struct {
+		name string
+		mw   SinkMiddleware
+		read func(b []byte) string
+	}{
+		{"batch", Batch(1<<20, 0), func(b []byte) string { return string(b) }},
+		{"compress", Compress(gzip.BestSpeed), func(b []byte) string {
+			zr, err := gzip.NewReader(bytes.NewReader(b))
+			if err != nil {
+				return err.Error()
+			}
+			plain, _ := io.ReadAll(zr)
+			return string(plain)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			fail := true
+			s := WrapSink(writerFunc(func(p []byte) (int, error) {
+				if fail {
+					return 0, errWrite
+				}
+				return out.Write(p)
+			}), tt.mw)
+
+			s.Write([]byte("a\n"))
+			FlushSink(s)
+			fail = false
+			if n, err := s.Write([]byte("b\n")); n != 2 || err != errWrite {
+				t.Errorf("Write() after a failed flush = %d, %v, want 2, %v", n, err, errWrite)
+			}
+			if err := CloseSink(s); err != nil {
+				t.Fatal(err)
+			}
+			if got := tt.read(out.Bytes()); got != "b\n" {
+				t.Errorf("delivered %q, want the entry written after the failed flush", got)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	now := time.Unix(0, 0)
+	var buf countingWriter
+	s := WrapSink(&buf, RateLimit(2, 3))
+	s.(*rateSink).now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		s.Write([]byte("x"))
+	}
+	now = now.Add(time.Second)
+	for i := 0; i < 5; i++ {
+		s.Write([]byte("x"))
+	}
+	if buf.writes != 5 || s.(*rateSink).Dropped() != 5 {
+		t.Errorf("writes = %d, dropped = %d, want 5 and 5", buf.writes, s.(*rateSink).Dropped())
+	}
+}
+
+func TestMeter(t *testing.T) {
+	var buf bytes.Buffer
+	s := WrapSink(&buf, Meter("test", QuotaOptions{DailyBytes: 8}))
+	s.Write([]byte("12345\n"))
+	s.Write([]byte("12345\n"))
+	st := s.(*meterSink).SinkStats()[0]
+	if st.Name != "test" || st.Bytes != 6 || st.Dropped != 1 {
+		t.Errorf("SinkStats() = %+v", st)
+	}
+}
+
+func TestCompressEncrypt(t *testing.T) {
+	block, _ := aes.NewCipher(bytes.Repeat([]byte{7}, 32))
+	aead, _ := cipher.NewGCM(block)
+
+	var stored bytes.Buffer
+	s := WrapSink(&stored, Compress(gzip.BestSpeed), Encrypt(aead))
+	e, _ := newBufferLogger(InfoLevel)
+	e.SetOutput(s)
+	e.Info("secret one")
+	e.Info("secret two")
+	if err := CloseSink(s); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(stored.String(), "secret") {
+		t.Fatal("stored output is not encrypted")
+	}
+
+	var compressed bytes.Buffer
+	if err := DecryptFrames(bytes.NewReader(stored.Bytes()), &compressed, aead); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(&compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, _ := io.ReadAll(zr)
+	if !strings.Contains(string(plain), "secret one") || !strings.Contains(string(plain), "secret two") {
+		t.Errorf("decrypted output = %q", plain)
+	}
+
+	stored.Bytes()[10] ^= 1
+	if err := DecryptFrames(&stored, io.Discard, aead); !errors.Is(err, ErrInvalid) {
+		t.Errorf("DecryptFrames() of tampered data = %v, want ErrInvalid", err)
+	}
+}
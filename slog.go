@@ -0,0 +1,108 @@
+//go:build go1.21
+
+package errorlogger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// SlogBackend is a Backend that writes to a log/slog
+// Handler, so that an ErrorLogger can be used by services
+// that have moved to slog. Fields become attributes, in
+// sorted order, and levels are mapped with SlogLevel.
+//
+// The output and format are those of the handler, so
+// SetOutput and SetFormatter do nothing.
+type SlogBackend struct {
+	h     slog.Handler
+	level uint32 // atomic
+}
+
+// NewWithSlog returns a new ErrorLogger, with logging
+// enabled, that writes its entries to h:
+//  log := errorlogger.NewWithSlog(slog.NewJSONHandler(os.Stderr, nil))
+//  log.Err(err) // handled by h at slog.LevelError
+//
+// It is the same as NewWithBackend(NewSlogBackend(h)).
+func NewWithSlog(h slog.Handler) ErrorLogger {
+	return NewWithBackend(NewSlogBackend(h))
+}
+
+// NewSlogBackend returns a SlogBackend writing to h. If h is
+// nil, the handler of slog.Default() is used.
+func NewSlogBackend(h slog.Handler) *SlogBackend {
+	if h == nil {
+		h = slog.Default().Handler()
+	}
+	return &SlogBackend{h: h, level: uint32(DefaultLogLevel)}
+}
+
+// SlogLevel returns the slog level of level. Trace is one
+// step of 4 below slog.LevelDebug and Fatal and Panic are
+// steps above slog.LevelError.
+func SlogLevel(level Level) slog.Level {
+	return slog.LevelError + slog.Level(4*(int(ErrorLevel)-int(level)))
+}
+
+// LevelFromSlog returns the level of the slog level l,
+// rounding down to the next less severe level.
+func LevelFromSlog(l slog.Level) Level {
+	switch {
+	case l >= SlogLevel(PanicLevel):
+		return PanicLevel
+	case l >= SlogLevel(FatalLevel):
+		return FatalLevel
+	case l >= slog.LevelError:
+		return ErrorLevel
+	case l >= slog.LevelWarn:
+		return WarnLevel
+	case l >= slog.LevelInfo:
+		return InfoLevel
+	case l >= slog.LevelDebug:
+		return DebugLevel
+	}
+	return TraceLevel
+}
+
+// Log passes an entry to the slog Handler if the level is
+// enabled by both the backend and the handler.
+func (b *SlogBackend) Log(level Level, msg string, fields Fields) {
+	if uint32(level) > atomic.LoadUint32(&b.level) {
+		return
+	}
+	ctx := context.Background()
+	sl := SlogLevel(level)
+	if !b.h.Enabled(ctx, sl) {
+		return
+	}
+
+	r := slog.NewRecord(time.Now(), sl, msg, 0)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		r.AddAttrs(slog.Any(k, fields[k]))
+	}
+	_ = b.h.Handle(ctx, r)
+}
+
+// SetLevel sets the least severe level written.
+func (b *SlogBackend) SetLevel(level Level) {
+	atomic.StoreUint32(&b.level, uint32(level))
+}
+
+// SetOutput does nothing; the output is set by the handler.
+func (b *SlogBackend) SetOutput(io.Writer) {}
+
+// SetFormatter does nothing; the format is set by the
+// handler.
+func (b *SlogBackend) SetFormatter(Formatter) {}
+
+var _ Backend = (*SlogBackend)(nil)
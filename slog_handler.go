@@ -0,0 +1,127 @@
+//go:build go1.21
+
+package errorlogger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slogHandler is the slog.Handler returned by Handler.
+type slogHandler struct {
+	e      *errorLogger
+	attrs  Fields
+	prefix string // group names, each followed by a dot
+}
+
+// Handler returns a slog.Handler that writes records to the
+// logger, so that code that only speaks slog can log
+// through it:
+//  slog.SetDefault(slog.New(log.Handler()))
+//
+// Records are dropped while logging is disabled and below
+// the level of the logger, as mapped by LevelFromSlog.
+// Attributes become fields, with the names of groups and
+// the key joined by dots, e.g. "req.id". Attributes holding
+// an error are wrapped as set by SetErrorWrap. Records are
+// formatted and written like any other entry; records above
+// slog.LevelError are logged at FatalLevel without exiting
+// the program.
+//
+// Handler is not part of the ErrorLogger interface, which
+// supports Go versions without log/slog; use a type
+// assertion:
+//  h := log.(interface{ Handler() slog.Handler }).Handler()
+func (e *errorLogger) Handler() slog.Handler {
+	return &slogHandler{e: e}
+}
+
+// Enabled reports whether the logger is enabled at level.
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.e.enabled() && h.e.IsLevelEnabled(LevelFromSlog(level))
+}
+
+// Handle logs r.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	level := LevelFromSlog(r.Level)
+	if !h.e.enabled() || !h.e.IsLevelEnabled(level) {
+		return nil
+	}
+	if level < FatalLevel {
+		level = FatalLevel
+	}
+
+	fields := make(Fields, len(h.attrs)+r.NumAttrs())
+	for k, v := range h.attrs {
+		fields[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addSlogAttr(fields, h.prefix, a)
+		return true
+	})
+	if h.e.wrap != nil {
+		for k, v := range fields {
+			if err, ok := v.(error); ok {
+				fields[k] = wrapWith(err, h.e.wrap)
+			}
+		}
+	}
+
+	entry := h.e.WithFields(fields)
+	if ctx != nil {
+		entry = entry.WithContext(ctx)
+	}
+	if !r.Time.IsZero() {
+		entry = entry.WithTime(r.Time)
+	}
+	entry.Log(level, r.Message)
+	return nil
+}
+
+// WithAttrs returns a handler that adds attrs to every
+// record.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = make(Fields, len(h.attrs)+len(attrs))
+	for k, v := range h.attrs {
+		h2.attrs[k] = v
+	}
+	for _, a := range attrs {
+		addSlogAttr(h2.attrs, h.prefix, a)
+	}
+	return &h2
+}
+
+// WithGroup returns a handler that qualifies the keys of
+// later attributes with name.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// addSlogAttr adds a to fields, flattening groups into keys
+// joined by dots. Empty attributes are ignored, as slog
+// handlers should.
+func addSlogAttr(fields Fields, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			addSlogAttr(fields, prefix, ga)
+		}
+		return
+	}
+	fields[prefix+a.Key] = a.Value.Any()
+}
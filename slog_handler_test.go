@@ -0,0 +1,52 @@
+//go:build go1.21
+
+package errorlogger
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_Handler(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *slog.Logger)
+		want []string
+	}{
+		{"message", func(l *slog.Logger) { l.Info("started", "port", 80) }, []string{"level=info", "msg=started", "port=80"}},
+		{"groups", func(l *slog.Logger) {
+			l.With("svc", "api").WithGroup("req").Warn("slow", "id", 7, slog.Group("user", "name", "ann"))
+		}, []string{"level=warning", "svc=api", "req.id=7", "req.user.name=ann"}},
+		{"below level", func(l *slog.Logger) { l.Debug("hidden") }, nil},
+		{"above error", func(l *slog.Logger) { l.Log(nil, slog.LevelError+8, "bad") }, []string{"level=fatal", "msg=bad"}},
+		{"error wrap", func(l *slog.Logger) { l.Error("failed", "err", errFake) }, []string{"level=error", "err=\"wrapped: fake\""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			e.ExitFunc = func(int) { t.Error("Handler exited the program") }
+			e.SetErrorWrap(errors.New("wrapped"))
+			tt.log(slog.New(e.Handler()))
+
+			out := buf.String()
+			if tt.want == nil && out != "" {
+				t.Errorf("output = %q, want none", out)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("output = %q, want %q", out, w)
+				}
+			}
+		})
+	}
+
+	e, buf := newBufferLogger(InfoLevel)
+	e.Disable()
+	h := e.Handler()
+	slog.New(h).Error("dropped")
+	if h.Enabled(nil, slog.LevelError) || buf.Len() != 0 {
+		t.Errorf("disabled handler wrote %q", buf.String())
+	}
+}
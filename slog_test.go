@@ -0,0 +1,71 @@
+//go:build go1.21
+
+package errorlogger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewWithSlog(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(e ErrorLogger)
+		want string
+	}{
+		{"error", func(e ErrorLogger) { e.Err(errFake) }, `level=ERROR msg=fake`},
+		{"fields", func(e ErrorLogger) { e.WithFields(Fields{"b": 2, "a": "x"}).Warn("slow") }, `level=WARN msg=slow a=x b=2`},
+		{"logger level", func(e ErrorLogger) { e.Debug("hidden") }, ``},
+		{"handler level", func(e ErrorLogger) { e.SetLevel(TraceLevel); e.WithFields(nil).Trace("hidden"); e.Debug("shown") }, `level=DEBUG msg=shown`},
+		{"disabled", func(e ErrorLogger) { e.Disable(); e.Err(errFake) }, ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				Level:       slog.LevelDebug,
+				ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr { return dropTime(a) },
+			})
+			e := NewWithSlog(h)
+			tt.log(e)
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func dropTime(a slog.Attr) slog.Attr {
+	if a.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return a
+}
+
+func TestSlogLevel(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  slog.Level
+	}{
+		{PanicLevel, slog.LevelError + 8},
+		{FatalLevel, slog.LevelError + 4},
+		{ErrorLevel, slog.LevelError},
+		{WarnLevel, slog.LevelWarn},
+		{InfoLevel, slog.LevelInfo},
+		{DebugLevel, slog.LevelDebug},
+		{TraceLevel, slog.LevelDebug - 4},
+	}
+	for _, tt := range tests {
+		if got := SlogLevel(tt.level); got != tt.want {
+			t.Errorf("SlogLevel(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+		if got := LevelFromSlog(tt.want); got != tt.level {
+			t.Errorf("LevelFromSlog(%v) = %v, want %v", tt.want, got, tt.level)
+		}
+	}
+	if got := LevelFromSlog(slog.LevelWarn + 1); got != WarnLevel {
+		t.Errorf("LevelFromSlog(WARN+1) = %v, want %v", got, WarnLevel)
+	}
+}
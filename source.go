@@ -0,0 +1,194 @@
+package errorlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxExcerptWidth is the maximum number of characters of a
+// source line shown on either side of an error position.
+const maxExcerptWidth = 40
+
+// SourceError is a decoding or parsing error combined with
+// the source it was read from. Its fields pinpoint the file,
+// line, column, and a short excerpt of the source, turning
+// "invalid character at offset 91742" into something
+// actionable.
+type SourceError struct {
+	Err    error
+	File   string
+	Line   int
+	Column int // 0 if unknown
+
+	// Excerpt is the source line around the error position
+	// with a caret marking the column, if known.
+	Excerpt string
+}
+
+// JSONError returns err combined with the position of the
+// error in src if err is a *json.SyntaxError or a
+// *json.UnmarshalTypeError. Other errors are returned
+// unchanged.
+//  if err := json.Unmarshal(data, &v); err != nil {
+//  	return log.Err(errorlogger.JSONError(err, data))
+//  }
+func JSONError(err error, src []byte) error {
+	offset, ok := jsonOffset(err)
+	if !ok || offset > int64(len(src)) {
+		return err
+	}
+
+	line, col := position(src, int(offset))
+	return &SourceError{
+		Err:     err,
+		Line:    line,
+		Column:  col,
+		Excerpt: excerpt(src, line, col),
+	}
+}
+
+// ConfigError returns err combined with the file name and the
+// position of the error in src, so configuration errors can
+// be located quickly. The position is taken from:
+//
+// - text/template and html/template errors ("template: name:12:3: ...")
+//
+// - YAML errors ("yaml: line 12: ...")
+//
+// - JSON decoding errors (see JSONError)
+//
+// If no position is found, err is returned with only the
+// file name attached.
+//  t, err := template.New("page").Parse(string(src))
+//  if err != nil {
+//  	return log.Err(errorlogger.ConfigError(err, "page.tmpl", src))
+//  }
+func ConfigError(err error, file string, src []byte) error {
+	if err == nil {
+		return nil
+	}
+
+	if se, ok := JSONError(err, src).(*SourceError); ok {
+		se.File = file
+		return se
+	}
+
+	se := &SourceError{Err: err, File: file}
+	msg := err.Error()
+	for _, re := range []*regexp.Regexp{templatePosition, yamlPosition} {
+		if m := re.FindStringSubmatch(msg); m != nil {
+			se.Line, _ = strconv.Atoi(m[1])
+			if m[2] != "" {
+				se.Column, _ = strconv.Atoi(m[2])
+			}
+			se.Excerpt = excerpt(src, se.Line, se.Column)
+			break
+		}
+	}
+	return se
+}
+
+var (
+	templatePosition = regexp.MustCompile(`template: [^:]*:(\d+)(?::(\d+))?:`)
+	yamlPosition     = regexp.MustCompile(`line (\d+)(?::(\d+))?:`)
+)
+
+func (e *SourceError) Error() string { return e.Err.Error() }
+
+func (e *SourceError) Unwrap() error { return e.Err }
+
+// Fields returns the structured fields logged with the error.
+// Unknown values are omitted.
+func (e *SourceError) Fields() Fields {
+	f := Fields{}
+	if e.File != "" {
+		f["file"] = e.File
+	}
+	if e.Line > 0 {
+		f["line"] = e.Line
+	}
+	if e.Column > 0 {
+		f["column"] = e.Column
+	}
+	if e.Excerpt != "" {
+		f["excerpt"] = e.Excerpt
+	}
+	return f
+}
+
+// JSONEnricher is an Enricher that adds the byte offset of
+// JSON decoding errors as the "offset" field. Use JSONError
+// to also log the line, column, and a source excerpt.
+func JSONEnricher(err error, fields Fields) {
+	if offset, ok := jsonOffset(err); ok {
+		fields["offset"] = offset
+	}
+}
+
+// jsonOffset returns the offset of a JSON decoding error
+// in the chain of err.
+func jsonOffset(err error) (int64, bool) {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return syntaxErr.Offset, true
+	case errors.As(err, &typeErr):
+		return typeErr.Offset, true
+	}
+	return 0, false
+}
+
+// position returns the 1-based line and column of the
+// character at byte offset in src. Columns count characters,
+// not bytes. An offset at the end of a line refers to the
+// last character of that line.
+func position(src []byte, offset int) (line, col int) {
+	if offset > 0 && offset <= len(src) && src[offset-1] != '\n' {
+		offset-- // JSON offsets point after the offending byte
+	}
+	for offset > 0 && offset < len(src) && !utf8.RuneStart(src[offset]) {
+		offset--
+	}
+	before := src[:offset]
+	line = bytes.Count(before, []byte{'\n'}) + 1
+	col = utf8.RuneCount(before[bytes.LastIndexByte(before, '\n')+1:]) + 1
+	return line, col
+}
+
+// excerpt returns the given 1-based line of src, shortened to
+// maxExcerptWidth characters around the character at col,
+// followed by a line with a caret under it. If col is 0,
+// only the line is returned.
+func excerpt(src []byte, line, col int) string {
+	lines := strings.Split(string(src), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	text := []rune(strings.ReplaceAll(strings.TrimRight(lines[line-1], "\r"), "\t", " "))
+	if col < 1 {
+		return string(text)
+	}
+
+	start, end := 0, len(text)
+	if col-1 > maxExcerptWidth {
+		start = col - 1 - maxExcerptWidth
+	}
+	if end-(col-1) > maxExcerptWidth {
+		end = col - 1 + maxExcerptWidth
+	}
+	if start > end {
+		start = end
+	}
+
+	caret := col - 1 - start
+	if caret < 0 {
+		caret = 0
+	}
+	return string(text[start:end]) + "\n" + strings.Repeat(" ", caret) + "^"
+}
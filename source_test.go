@@ -0,0 +1,127 @@
+package errorlogger
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestJSONError(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		v       interface{}
+		line    int
+		column  int
+		excerpt string
+	}{
+		{"syntax", "{\n  \"a\": 1,\n  \"b\": x\n}", &map[string]int{}, 3, 8, "  \"b\": x\n       ^"},
+		{"type", "{\"a\": \"one\"}", &struct{ A int }{}, 1, 11, "{\"a\": \"one\"}\n          ^"},
+		{"multibyte", "{\"名前\": \"é\", x}", &map[string]string{}, 1, 13, "{\"名前\": \"é\", x}\n            ^"},
+		{"multibyte type", "{\"a\": \"日本\"}", &struct{ A int }{}, 1, 10, "{\"a\": \"日本\"}\n         ^"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := JSONError(json.Unmarshal([]byte(tt.src), tt.v), []byte(tt.src))
+
+			var se *SourceError
+			if !errors.As(err, &se) {
+				t.Fatalf("JSONError() = %T, want *SourceError", err)
+			}
+			if se.Line != tt.line || se.Column != tt.column {
+				t.Errorf("JSONError() position = %d:%d, want %d:%d", se.Line, se.Column, tt.line, tt.column)
+			}
+			if se.Excerpt != tt.excerpt {
+				t.Errorf("JSONError() excerpt = %q, want %q", se.Excerpt, tt.excerpt)
+			}
+			if errors.Unwrap(err) == nil || err.Error() != errors.Unwrap(err).Error() {
+				t.Errorf("JSONError() does not preserve the original error: %v", err)
+			}
+		})
+	}
+
+	if got := JSONError(errFake, nil); got != errFake {
+		t.Errorf("JSONError() non-JSON error = %v, want unchanged", got)
+	}
+}
+
+func Test_excerpt_long(t *testing.T) {
+	src := []byte(strings.Repeat("a", 100) + "X" + strings.Repeat("b", 100))
+	got := excerpt(src, 1, 101)
+	lines := strings.Split(got, "\n")
+	if len(lines[0]) != 2*maxExcerptWidth || lines[0][maxExcerptWidth] != 'X' {
+		t.Errorf("excerpt() = %q, want window centered on X", lines[0])
+	}
+	if len(lines[1]) != maxExcerptWidth+1 {
+		t.Errorf("excerpt() caret line = %q", lines[1])
+	}
+	if excerpt(src, 5, 1) != "" {
+		t.Errorf("excerpt() out of range line should be empty")
+	}
+
+	src = []byte(strings.Repeat("é", 100) + "X" + strings.Repeat("ü", 100))
+	lines = strings.Split(excerpt(src, 1, 101), "\n")
+	if r := []rune(lines[0]); len(r) != 2*maxExcerptWidth || r[maxExcerptWidth] != 'X' {
+		t.Errorf("excerpt() = %q, want window of characters centered on X", lines[0])
+	}
+	if len(lines[1]) != maxExcerptWidth+1 {
+		t.Errorf("excerpt() caret line = %q", lines[1])
+	}
+}
+
+func TestJSONEnricher(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	e.AddEnricher(JSONEnricher)
+
+	src := []byte(`{"a": x}`)
+	_ = e.Err(json.Unmarshal(src, &struct{}{}))
+	if !strings.Contains(buf.String(), "offset=7") {
+		t.Errorf("Err() with JSONEnricher output = %q, want offset field", buf.String())
+	}
+
+	buf.Reset()
+	_ = e.Err(JSONError(json.Unmarshal(src, &struct{}{}), src))
+	for _, want := range []string{"line=1", "column=7"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Err(JSONError()) output = %q, want %q", buf.String(), want)
+		}
+	}
+}
+
+func TestConfigError(t *testing.T) {
+	tmplSrc := "line one\n{{ .Name }\n"
+	_, tmplErr := template.New("page").Parse(tmplSrc)
+
+	tests := []struct {
+		name string
+		err  error
+		src  string
+		want Fields
+	}{
+		{"template", tmplErr, tmplSrc, Fields{"file": "page.tmpl", "line": 2, "excerpt": "{{ .Name }"}},
+		{"yaml", errors.New("yaml: line 3: mapping values are not allowed in this context"), "a: 1\nb: 2\nc: d: e\n",
+			Fields{"file": "page.tmpl", "line": 3, "excerpt": "c: d: e"}},
+		{"json", json.Unmarshal([]byte(`{"a" 1}`), &struct{}{}), `{"a" 1}`,
+			Fields{"file": "page.tmpl", "line": 1, "column": 6, "excerpt": "{\"a\" 1}\n     ^"}},
+		{"unknown", errFake, "", Fields{"file": "page.tmpl"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ConfigError(tt.err, "page.tmpl", []byte(tt.src))
+			var se *SourceError
+			if !errors.As(err, &se) {
+				t.Fatalf("ConfigError() = %T, want *SourceError", err)
+			}
+			if got := se.Fields(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConfigError().Fields() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	if ConfigError(nil, "x", nil) != nil {
+		t.Errorf("ConfigError(nil) should be nil")
+	}
+}
@@ -0,0 +1,90 @@
+package errorlogger
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// StackKey is the field that holds a stack trace.
+const StackKey = "stack"
+
+// StackFormat selects how a stack trace captured by Err is
+// logged.
+type StackFormat int32
+
+const (
+	// StackField logs the stack trace in the StackKey field.
+	StackField StackFormat = iota
+
+	// StackText appends the stack trace to the message, on
+	// the lines after the error.
+	StackText
+)
+
+// stackMessage is an error logged with its stack trace
+// appended to the message.
+type stackMessage struct {
+	error
+	stack string
+}
+
+func (m stackMessage) Error() string { return m.error.Error() + "\n" + m.stack }
+
+// SetCaptureStack sets the number of frames of the stack
+// trace recorded at the call site of Err, starting with the
+// code that called it. Zero, the default, turns capture
+// off:
+//  log.SetCaptureStack(32)
+//
+// The stack is only captured when an error is logged, so
+// Err(nil) and Err while logging is disabled do not pay
+// for it.
+func (e *errorLogger) SetCaptureStack(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+	atomic.StoreInt32(&e.stackDepth, int32(depth))
+}
+
+// SetStackFormat sets how the stack traces captured by Err
+// are logged. The default is StackField.
+func (e *errorLogger) SetStackFormat(f StackFormat) {
+	atomic.StoreInt32(&e.stackFormat, int32(f))
+}
+
+// captureStack returns the stack trace of the caller of
+// the logger, depth frames deep, or "" if capture is off.
+func (e *errorLogger) captureStack() string {
+	depth := int(atomic.LoadInt32(&e.stackDepth))
+	if depth == 0 {
+		return ""
+	}
+
+	pcs := make([]uintptr, depth+16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var b strings.Builder
+	inCaller := false
+	for depth > 0 {
+		f, more := frames.Next()
+		if !inCaller {
+			inCaller = filepath.Dir(f.File) != packageDir || strings.HasSuffix(f.File, "_test.go")
+		}
+		if inCaller {
+			b.WriteString(f.Function)
+			b.WriteString("\n\t")
+			b.WriteString(f.File)
+			b.WriteByte(':')
+			b.WriteString(strconv.Itoa(f.Line))
+			b.WriteByte('\n')
+			depth--
+		}
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
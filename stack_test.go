@@ -0,0 +1,60 @@
+package errorlogger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrorLogger_SetCaptureStack(t *testing.T) {
+	tests := []struct {
+		name      string
+		depth     int
+		format    StackFormat
+		wantField bool
+		wantText  bool
+	}{
+		{"off", 0, StackField, false, false},
+		{"field", 2, StackField, true, false},
+		{"text", 2, StackText, false, true},
+		{"negative", -1, StackField, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newBufferLogger(InfoLevel)
+			var got *Entry
+			e.AddHook(&countHook{levels: AllLevels, fn: func(entry *Entry) { got = entry }})
+			e.SetCaptureStack(tt.depth)
+			e.SetStackFormat(tt.format)
+
+			if err := e.Err(errFake); err != errFake {
+				t.Errorf("Err() = %v, want the error unchanged", err)
+			}
+			stack, hasField := got.Data[StackKey].(string)
+			if hasField != tt.wantField {
+				t.Fatalf("stack field = %v, want %v", hasField, tt.wantField)
+			}
+			if hasText := strings.Contains(got.Message, "\n"); hasText != tt.wantText {
+				t.Fatalf("message = %q, want stack text %v", got.Message, tt.wantText)
+			}
+			if tt.wantText {
+				stack = strings.TrimPrefix(got.Message, "fake\n")
+			}
+			if !tt.wantField && !tt.wantText {
+				return
+			}
+			lines := strings.Split(strings.TrimSuffix(stack, "\n"), "\n")
+			if len(lines) != 2*tt.depth || !strings.Contains(lines[0], "TestErrorLogger_SetCaptureStack") || !strings.Contains(lines[1], "stack_test.go:") {
+				t.Errorf("stack = %q, want %d frames starting at the caller of Err", stack, tt.depth)
+			}
+		})
+	}
+}
+
+func TestErrorLogger_SetCaptureStack_disabled(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	e.SetCaptureStack(32)
+	e.Disable()
+	if n := testing.AllocsPerRun(100, func() { _ = e.Err(errFake) }); n != 0 {
+		t.Errorf("Err() while disabled allocates %v times, want 0", n)
+	}
+}
@@ -0,0 +1,340 @@
+package errorlogger
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultLatencyBuckets are the upper bounds of the buckets
+// used to record the time spent inside Err.
+var DefaultLatencyBuckets = []time.Duration{
+	time.Microsecond,
+	5 * time.Microsecond,
+	10 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+}
+
+type (
+	// Histogram is a fixed bucket histogram of durations that
+	// is safe for concurrent use.
+	Histogram struct {
+		bounds []time.Duration
+		counts []uint64 // atomic; the last bucket is +Inf
+		count  uint64   // atomic
+		sum    int64    // atomic; nanoseconds
+	}
+
+	// HistogramSnapshot is a point in time copy of a Histogram.
+	// Counts are per bucket (not cumulative) and the last
+	// count is for values greater than every bound.
+	HistogramSnapshot struct {
+		Bounds []time.Duration
+		Counts []uint64
+		Count  uint64
+		Sum    time.Duration
+	}
+
+	// Stats reports the self-overhead of an ErrorLogger so
+	// that logging costs can be quantified in production.
+	//
+	// Errors is the number of errors logged by Err and
+	// ErrLatency is the time spent inside Err (wrapping,
+	// formatting, and writing) for each of them.
+	//
+	// Entries is the number of entries written per level,
+	// after filtering, sampling, and rate limiting.
+	//
+	// Bytes is the number of formatted bytes per level. Sinks
+	// reports the bytes written to each sink if the output of
+	// the logger reports them, as a *MeteredWriter does.
+	//
+	// Hooks reports the execution time of each hook added
+	// with AddHook.
+	//
+	// SchemaViolations is the number of entries that did not
+	// conform to the schema set with SetSchema.
+	Stats struct {
+		Errors           uint64
+		ErrLatency       HistogramSnapshot
+		Entries          map[Level]uint64
+		Bytes            map[Level]uint64
+		Sinks            []SinkStats
+		Hooks            []HookStats
+		SchemaViolations uint64
+	}
+
+	// loggerStats holds the live counters of an errorLogger.
+	loggerStats struct {
+		errLatency *Histogram
+		entries    [TraceLevel + 1]uint64 // atomic; by level
+		bytes      [TraceLevel + 1]uint64 // atomic; by level
+
+		hooksMu    sync.Mutex
+		hooks      []*timedHook
+		hookBudget int64 // atomic; HookPolicy.Budget
+		hookSkip   int64 // atomic; HookPolicy.SkipFor
+
+		schemaViolations uint64 // atomic
+	}
+
+	// sinkStatser is implemented by outputs that report
+	// per sink statistics.
+	sinkStatser interface {
+		SinkStats() []SinkStats
+	}
+)
+
+// NewHistogram returns a new Histogram with the given bucket
+// upper bounds, which must be sorted in increasing order. If
+// no bounds are given, DefaultLatencyBuckets are used.
+func NewHistogram(bounds ...time.Duration) *Histogram {
+	if len(bounds) == 0 {
+		bounds = DefaultLatencyBuckets
+	}
+	return &Histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+// Observe records d in the histogram.
+func (h *Histogram) Observe(d time.Duration) {
+	i := 0
+	for i < len(h.bounds) && d > h.bounds[i] {
+		i++
+	}
+	atomic.AddUint64(&h.counts[i], 1)
+	atomic.AddUint64(&h.count, 1)
+	atomic.AddInt64(&h.sum, int64(d))
+}
+
+// Snapshot returns a copy of the current histogram values.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	s := HistogramSnapshot{
+		Bounds: h.bounds,
+		Counts: make([]uint64, len(h.counts)),
+		Count:  atomic.LoadUint64(&h.count),
+		Sum:    time.Duration(atomic.LoadInt64(&h.sum)),
+	}
+	for i := range h.counts {
+		s.Counts[i] = atomic.LoadUint64(&h.counts[i])
+	}
+	return s
+}
+
+// Mean returns the average of the recorded values.
+func (s HistogramSnapshot) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+// writePrometheus writes s in the Prometheus text exposition
+// format as a histogram of seconds.
+func (s HistogramSnapshot) writePrometheus(w Writer, name, help string) error {
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name); err != nil {
+		return err
+	}
+	var cumulative uint64
+	for i, b := range s.Bounds {
+		cumulative += s.Counts[i]
+		le := strconv.FormatFloat(b.Seconds(), 'g', -1, 64)
+		if _, err := fmt.Fprintf(w, "%s_bucket{le=%q} %d\n", name, le, cumulative); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n%s_sum %g\n%s_count %d\n",
+		name, s.Count, name, s.Sum.Seconds(), name, s.Count)
+	return err
+}
+
+// WritePrometheus writes the stats to w in the Prometheus text
+// exposition format. Metric names are prefixed with namespace;
+// if namespace is empty, "errorlogger" is used.
+//
+// The metrics are the errors and their latency, entries and
+// bytes by level, schema violations, hook timings labeled
+// with the type of the hook, and the bytes, entries, and
+// quota drops of each sink. The daily quota state of sinks
+// is not exported.
+//
+// This avoids a dependency on the Prometheus client library;
+// the output can be served directly from a /metrics handler.
+func (s Stats) WritePrometheus(w Writer, namespace string) error {
+	if namespace == "" {
+		namespace = "errorlogger"
+	}
+	if _, err := fmt.Fprintf(w, "# HELP %[1]s_errors_total Number of errors logged by Err.\n# TYPE %[1]s_errors_total counter\n%[1]s_errors_total %[2]d\n", namespace, s.Errors); err != nil {
+		return err
+	}
+	if err := s.ErrLatency.writePrometheus(w, namespace+"_err_duration_seconds", "Time spent inside Err."); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "# HELP %[1]s_entries_total Number of entries written by level.\n# TYPE %[1]s_entries_total counter\n", namespace); err != nil {
+		return err
+	}
+	for _, lvl := range logrus.AllLevels {
+		if _, err := fmt.Fprintf(w, "%s_entries_total{level=%q} %d\n", namespace, lvl, s.Entries[lvl]); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "# HELP %[1]s_bytes_total Number of formatted bytes by level.\n# TYPE %[1]s_bytes_total counter\n", namespace); err != nil {
+		return err
+	}
+	for _, lvl := range logrus.AllLevels {
+		if _, err := fmt.Fprintf(w, "%s_bytes_total{level=%q} %d\n", namespace, lvl, s.Bytes[lvl]); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "# HELP %[1]s_schema_violations_total Number of entries that failed schema validation.\n# TYPE %[1]s_schema_violations_total counter\n%[1]s_schema_violations_total %[2]d\n", namespace, s.SchemaViolations); err != nil {
+		return err
+	}
+	if err := writeHookPrometheus(w, namespace, s.Hooks); err != nil {
+		return err
+	}
+
+	if len(s.Sinks) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "# HELP %[1]s_sink_bytes_total Number of bytes written by sink.\n# TYPE %[1]s_sink_bytes_total counter\n", namespace); err != nil {
+		return err
+	}
+	for _, sink := range s.Sinks {
+		if _, err := fmt.Fprintf(w, "%s_sink_bytes_total{sink=%q} %d\n", namespace, sink.Name, sink.Bytes); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "# HELP %[1]s_sink_entries_total Number of entries written by sink.\n# TYPE %[1]s_sink_entries_total counter\n", namespace); err != nil {
+		return err
+	}
+	for _, sink := range s.Sinks {
+		if _, err := fmt.Fprintf(w, "%s_sink_entries_total{sink=%q} %d\n", namespace, sink.Name, sink.Entries); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "# HELP %[1]s_sink_dropped_total Number of entries dropped by sink quotas.\n# TYPE %[1]s_sink_dropped_total counter\n", namespace); err != nil {
+		return err
+	}
+	for _, sink := range s.Sinks {
+		if _, err := fmt.Fprintf(w, "%s_sink_dropped_total{sink=%q} %d\n", namespace, sink.Name, sink.Dropped); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeHookPrometheus writes the statistics of hooks to w.
+// Hooks of the same type are added up into one series, as
+// they share the same label.
+func writeHookPrometheus(w Writer, namespace string, hooks []HookStats) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+	var names []string
+	byName := make(map[string]HookStats, len(hooks))
+	for _, h := range hooks {
+		sum, ok := byName[h.Name]
+		if !ok {
+			names = append(names, h.Name)
+		}
+		sum.Fires += h.Fires
+		sum.Total += h.Total
+		sum.Timeouts += h.Timeouts
+		sum.Skipped += h.Skipped
+		if h.Max > sum.Max {
+			sum.Max = h.Max
+		}
+		byName[h.Name] = sum
+	}
+
+	metrics := []struct {
+		name, help, kind string
+		value            func(h HookStats) string
+	}{
+		{"hook_fires_total", "Number of times each hook fired.", "counter", func(h HookStats) string { return strconv.FormatUint(h.Fires, 10) }},
+		{"hook_duration_seconds_total", "Time spent in each hook.", "counter", func(h HookStats) string { return strconv.FormatFloat(h.Total.Seconds(), 'g', -1, 64) }},
+		{"hook_duration_seconds_max", "Longest time spent in one fire of each hook.", "gauge", func(h HookStats) string { return strconv.FormatFloat(h.Max.Seconds(), 'g', -1, 64) }},
+		{"hook_timeouts_total", "Number of fires of each hook that exceeded the hook budget.", "counter", func(h HookStats) string { return strconv.FormatUint(h.Timeouts, 10) }},
+		{"hook_skipped_total", "Number of entries each hook skipped after a timeout.", "counter", func(h HookStats) string { return strconv.FormatUint(h.Skipped, 10) }},
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %[1]s_%[2]s %[3]s\n# TYPE %[1]s_%[2]s %[4]s\n", namespace, m.name, m.help, m.kind); err != nil {
+			return err
+		}
+		for _, name := range names {
+			if _, err := fmt.Fprintf(w, "%s_%s{hook=%q} %s\n", namespace, m.name, name, m.value(byName[name])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newLoggerStats() *loggerStats {
+	return &loggerStats{errLatency: NewHistogram()}
+}
+
+// observeErr records the time spent logging an error. It is
+// safe to call on a nil *loggerStats.
+func (s *loggerStats) observeErr(d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.errLatency.Observe(d)
+}
+
+// observeEntry records an entry written at level.
+func (s *loggerStats) observeEntry(level Level) {
+	if s == nil || level > TraceLevel {
+		return
+	}
+	atomic.AddUint64(&s.entries[level], 1)
+}
+
+// observeBytes records n formatted bytes at level. It is
+// safe to call on a nil *loggerStats.
+func (s *loggerStats) observeBytes(level Level, n int) {
+	if s == nil || level > TraceLevel {
+		return
+	}
+	atomic.AddUint64(&s.bytes[level], uint64(n))
+}
+
+// Stats returns the current self-overhead statistics of
+// the logger.
+func (e *errorLogger) Stats() Stats {
+	if e.stats == nil {
+		return Stats{}
+	}
+	h := e.stats.errLatency.Snapshot()
+	st := Stats{
+		Errors:     h.Count,
+		ErrLatency: h,
+		Entries:    make(map[Level]uint64, len(e.stats.entries)),
+		Bytes:      make(map[Level]uint64, len(e.stats.bytes)),
+	}
+	for lvl := range e.stats.bytes {
+		st.Entries[Level(lvl)] = atomic.LoadUint64(&e.stats.entries[lvl])
+		st.Bytes[Level(lvl)] = atomic.LoadUint64(&e.stats.bytes[lvl])
+	}
+	if s, ok := e.output().(sinkStatser); ok {
+		st.Sinks = s.SinkStats()
+	}
+	st.Hooks = e.stats.hookStats()
+	st.SchemaViolations = atomic.LoadUint64(&e.stats.schemaViolations)
+	return st
+}
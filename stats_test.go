@@ -0,0 +1,96 @@
+package errorlogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram(time.Millisecond, 10*time.Millisecond)
+	h.Observe(500 * time.Microsecond)
+	h.Observe(time.Millisecond)
+	h.Observe(5 * time.Millisecond)
+	h.Observe(time.Second)
+
+	s := h.Snapshot()
+	want := []uint64{2, 1, 1}
+	for i := range want {
+		if s.Counts[i] != want[i] {
+			t.Errorf("Histogram.Snapshot().Counts = %v, want %v", s.Counts, want)
+			break
+		}
+	}
+	if s.Count != 4 {
+		t.Errorf("Histogram.Snapshot().Count = %d, want 4", s.Count)
+	}
+	if got, want := s.Mean(), (500*time.Microsecond+6*time.Millisecond+time.Second)/4; got != want {
+		t.Errorf("HistogramSnapshot.Mean() = %v, want %v", got, want)
+	}
+	if got := (HistogramSnapshot{}).Mean(); got != 0 {
+		t.Errorf("empty HistogramSnapshot.Mean() = %v, want 0", got)
+	}
+}
+
+func Test_errorLogger_Stats(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	for i := 0; i < 3; i++ {
+		_ = e.Err(errFake)
+	}
+	_ = e.Err(nil)
+
+	s := e.Stats()
+	if s.Errors != 3 {
+		t.Errorf("Stats().Errors = %d, want 3", s.Errors)
+	}
+	if s.ErrLatency.Sum <= 0 {
+		t.Errorf("Stats().ErrLatency.Sum = %v, want > 0", s.ErrLatency.Sum)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := s.WritePrometheus(buf, ""); err != nil {
+		t.Fatalf("Stats.WritePrometheus() error = %v", err)
+	}
+	for _, want := range []string{
+		"errorlogger_errors_total 3",
+		`errorlogger_err_duration_seconds_bucket{le="1e-06"}`,
+		`errorlogger_err_duration_seconds_bucket{le="+Inf"} 3`,
+		"errorlogger_err_duration_seconds_count 3",
+		`errorlogger_entries_total{level="error"} 3`,
+		"errorlogger_schema_violations_total 0",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Stats.WritePrometheus() missing %q in:\n%s", want, buf.String())
+		}
+	}
+
+	if got := (&errorLogger{}).Stats(); got.Errors != 0 {
+		t.Errorf("Stats() without counters = %+v, want zero value", got)
+	}
+}
+
+func TestStats_WritePrometheus_hooks(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	e.AddHook(&countHook{levels: []Level{ErrorLevel}})
+	e.AddHook(&countHook{levels: []Level{ErrorLevel}})
+	_ = e.Err(errFake)
+
+	buf := &bytes.Buffer{}
+	if err := e.Stats().WritePrometheus(buf, "app"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# TYPE app_hook_fires_total counter",
+		`app_hook_fires_total{hook="*errorlogger.countHook"} 2`,
+		`app_hook_timeouts_total{hook="*errorlogger.countHook"} 0`,
+		"# TYPE app_hook_duration_seconds_max gauge",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Stats.WritePrometheus() missing %q in:\n%s", want, buf.String())
+		}
+	}
+	if n := strings.Count(buf.String(), "app_hook_skipped_total{"); n != 1 {
+		t.Errorf("Stats.WritePrometheus() wrote %d series per hook type, want 1", n)
+	}
+}
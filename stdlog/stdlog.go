@@ -0,0 +1,51 @@
+// Package stdlog is an error logger for small tools that
+// want plain output and a minimal dependency graph. It
+// imports only the standard library and writes through the
+// log package, with the standard flags:
+//  log := stdlog.NewStdlib(os.Stderr)
+//  return log.Err(err)
+//  // 2009/11/10 23:00:00 ERROR open config: file does not exist
+//
+// The loggers of this package implement tiny.ErrorLogger,
+// the Err-centric subset of the errorlogger ErrorLogger
+// interface, so that a library accepting it can be used
+// with either.
+package stdlog
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/skeptycal/errorlogger/tiny"
+)
+
+// logWriter writes each line to a *log.Logger, which adds
+// its prefix and flags.
+type logWriter struct{ l *log.Logger }
+
+func (w logWriter) Write(p []byte) (int, error) {
+	return len(p), w.l.Output(2, string(p))
+}
+
+// NewStdlib returns a new logger, with logging enabled, that
+// writes its entries to w through the standard library log
+// package, with the standard flags. If w is nil, os.Stderr
+// is used. It is the same as
+//  NewWithLogger(log.New(w, "", log.LstdFlags))
+func NewStdlib(w io.Writer) *tiny.Logger {
+	if w == nil {
+		w = os.Stderr
+	}
+	return NewWithLogger(log.New(w, "", log.LstdFlags))
+}
+
+// NewWithLogger returns a new logger, with logging enabled,
+// that writes its entries to l, whose prefix and flags
+// apply. If l is nil, log.Default() is used.
+func NewWithLogger(l *log.Logger) *tiny.Logger {
+	if l == nil {
+		l = log.Default()
+	}
+	return tiny.New(logWriter{l})
+}
@@ -0,0 +1,41 @@
+package stdlog
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+)
+
+func TestNewStdlib(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(w *bytes.Buffer) error
+		want string
+	}{
+		{"err", func(w *bytes.Buffer) error { return NewWithLogger(log.New(w, "", 0)).Err(errors.New("fake")) }, "ERROR fake\n"},
+		{"prefix", func(w *bytes.Buffer) error {
+			NewWithLogger(log.New(w, "app: ", 0)).Warn("slow")
+			return nil
+		}, "app: WARN slow\n"},
+		{"level", func(w *bytes.Buffer) error {
+			NewWithLogger(log.New(w, "", 0)).Debug("hidden")
+			return nil
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(&buf)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	NewStdlib(&buf).Err(errors.New("fake"))
+	if out := buf.String(); !bytes.HasSuffix(buf.Bytes(), []byte(" ERROR fake\n")) || len(out) != len("2009/11/10 23:00:00 ERROR fake\n") {
+		t.Errorf("output = %q, want a timestamped line", out)
+	}
+}
@@ -0,0 +1,56 @@
+package errorlogger
+
+import (
+	"fmt"
+	"sync"
+)
+
+// swapMu serializes calls to SwapOutput.
+var swapMu sync.Mutex
+
+// SwapOutput atomically replaces the output old with new,
+// for live migration from file to network logging or to
+// rotate the credentials of a network sink without losing
+// entries.
+//
+// The swap waits for a write in progress to complete, so
+// every entry is written to either old or new. Afterwards,
+// entries still buffered by old, such as those queued by an
+// AsyncWriter or a Batch middleware, are drained to its
+// destination with FlushSink before SwapOutput returns.
+// Closing old is left to the caller.
+//
+// It returns an error, and changes nothing, if old is not
+// the current output or new is nil.
+//  if err := log.SwapOutput(file, WrapSink(conn, Batch(64<<10, time.Second))); err == nil {
+//      file.Close()
+//  }
+func (e *errorLogger) SwapOutput(old, new Writer) error {
+	if new == nil {
+		return Err(ErrInvalidWriter)
+	}
+
+	swapMu.Lock()
+	defer swapMu.Unlock()
+	if !sameWriter(e.output(), old) {
+		return Err(fmt.Errorf("swap output: old is not the current output: %w", ErrInvalid))
+	}
+
+	// SetOutput takes the lock that logrus holds while
+	// writing an entry.
+	e.SetOutput(new)
+	FlushSink(old)
+	return nil
+}
+
+// sameWriter reports whether a and b are the same writer.
+// Writers of types that are not comparable, such as
+// functions, are never the same.
+func sameWriter(a, b Writer) (same bool) {
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return a == b
+}
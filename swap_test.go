@@ -0,0 +1,87 @@
+package errorlogger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestErrorLogger_SwapOutput(t *testing.T) {
+	e, _ := newBufferLogger(InfoLevel)
+	var oldBuf, newBuf syncBuffer
+	old := WrapSink(&oldBuf, Batch(1<<20, time.Hour))
+	e.SetOutput(old)
+
+	const writers, entries = 4, 200
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for i := 0; i < entries; i++ {
+				e.Info("entry")
+			}
+		}()
+	}
+	close(start)
+	time.Sleep(time.Millisecond)
+	if err := e.SwapOutput(old, &newBuf); err != nil {
+		t.Fatal(err)
+	}
+	// Everything buffered by old was drained by the swap.
+	drained := oldBuf.String()
+	wg.Wait()
+
+	if oldBuf.String() != drained {
+		t.Error("entries were written to the old output after the swap")
+	}
+	got := strings.Count(drained, "\n") + strings.Count(newBuf.String(), "\n")
+	if got != writers*entries {
+		t.Errorf("entries written = %d, want %d", got, writers*entries)
+	}
+}
+
+func TestErrorLogger_SwapOutput_errors(t *testing.T) {
+	e, buf := newBufferLogger(InfoLevel)
+	other := writerFunc(func(p []byte) (int, error) { return len(p), nil })
+	tests := []struct {
+		name     string
+		old, new Writer
+	}{
+		{"nil new", buf, nil},
+		{"not current", &bytes.Buffer{}, &bytes.Buffer{}},
+		{"not comparable", other, &bytes.Buffer{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := e.SwapOutput(tt.old, tt.new); err == nil {
+				t.Error("SwapOutput() succeeded")
+			}
+			if e.output() != Writer(buf) {
+				t.Error("SwapOutput() changed the output")
+			}
+		})
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
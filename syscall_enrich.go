@@ -0,0 +1,48 @@
+package errorlogger
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// SyscallEnricher is an Enricher that extracts the system
+// call name, operation, paths, and errno from the chain of
+// err into dedicated fields rather than leaving them mashed
+// into the message string.
+//
+// It recognizes *os.SyscallError, *fs.PathError (os.PathError),
+// *os.LinkError, the SyscallError and PathError types of this
+// package, and syscall.Errno, except on Plan 9, which has no
+// error numbers.
+func SyscallEnricher(err error, fields Fields) {
+	var (
+		osSyscallErr *os.SyscallError
+		syscallErr   *SyscallError
+		fsPathErr    *fs.PathError
+		pathErr      *PathError
+		linkErr      *os.LinkError
+	)
+
+	switch {
+	case errors.As(err, &osSyscallErr):
+		fields["syscall"] = osSyscallErr.Syscall
+	case errors.As(err, &syscallErr):
+		fields["syscall"] = syscallErr.Syscall
+	}
+
+	switch {
+	case errors.As(err, &fsPathErr):
+		fields["op"] = fsPathErr.Op
+		fields["path"] = fsPathErr.Path
+	case errors.As(err, &pathErr):
+		fields["op"] = pathErr.Op
+		fields["path"] = pathErr.Path
+	case errors.As(err, &linkErr):
+		fields["op"] = linkErr.Op
+		fields["old_path"] = linkErr.Old
+		fields["new_path"] = linkErr.New
+	}
+
+	errnoEnricher(err, fields)
+}
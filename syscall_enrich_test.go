@@ -0,0 +1,40 @@
+//go:build !plan9
+
+package errorlogger
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+func TestSyscallEnricher(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Fields
+	}{
+		{"plain", errFake, Fields{}},
+		{"package syscall error", fakeSysCallError, Fields{"syscall": "fake syscall error"}},
+		{"os syscall error", fmt.Errorf("wrapped: %w", os.NewSyscallError("connect", syscall.ECONNREFUSED)),
+			Fields{"syscall": "connect", "errno": int(syscall.ECONNREFUSED)}},
+		{"path error", &fs.PathError{Op: "open", Path: "/etc/shadow", Err: syscall.EACCES},
+			Fields{"op": "open", "path": "/etc/shadow", "errno": int(syscall.EACCES)}},
+		{"package path error", &PathError{Op: "stat", Path: "x", Err: errFake},
+			Fields{"op": "stat", "path": "x"}},
+		{"link error", &os.LinkError{Op: "rename", Old: "a", New: "b", Err: syscall.EXDEV},
+			Fields{"op": "rename", "old_path": "a", "new_path": "b", "errno": int(syscall.EXDEV)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Fields{}
+			SyscallEnricher(tt.err, got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SyscallEnricher() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
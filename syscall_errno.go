@@ -0,0 +1,17 @@
+//go:build !plan9
+
+package errorlogger
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errnoEnricher adds the syscall.Errno in the chain of err
+// as the "errno" field.
+func errnoEnricher(err error, fields Fields) {
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		fields["errno"] = int(errno)
+	}
+}
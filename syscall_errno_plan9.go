@@ -0,0 +1,7 @@
+//go:build plan9
+
+package errorlogger
+
+// errnoEnricher does nothing: Plan 9 reports errors as
+// strings rather than error numbers.
+func errnoEnricher(err error, fields Fields) {}
@@ -0,0 +1,70 @@
+package testutil
+
+import (
+	"io"
+	"testing"
+
+	"github.com/skeptycal/errorlogger"
+)
+
+// allocRuns is the number of calls averaged by AssertAllocs.
+const allocRuns = 100
+
+// AssertAllocs reports an error if fn allocates more than
+// maxAllocs times per call on average, and returns the
+// average.
+//  testutil.AssertAllocs(t, 0, func() { log.Err(nil) })
+func AssertAllocs(t testing.TB, maxAllocs float64, fn func()) float64 {
+	t.Helper()
+	allocs := testing.AllocsPerRun(allocRuns, fn)
+	if allocs > maxAllocs {
+		t.Errorf("%v allocs per call, want at most %v", allocs, maxAllocs)
+	}
+	return allocs
+}
+
+// AssertErrNilAllocs checks that Err(nil) does not allocate.
+func AssertErrNilAllocs(t testing.TB, log errorlogger.ErrorLogger) {
+	t.Helper()
+	AssertAllocs(t, 0, func() { sink = log.Err(nil) })
+}
+
+// AssertDisabledAllocs checks that Err does not allocate
+// while log is disabled. The logger is disabled during the
+// check and enabled again afterwards if it was enabled.
+func AssertDisabledAllocs(t testing.TB, log errorlogger.ErrorLogger) {
+	t.Helper()
+	if log.Config().Enabled {
+		defer log.Enable()
+	}
+	log.Disable()
+	AssertAllocs(t, 0, func() { sink = log.Err(errPerf) })
+}
+
+// AssertJSONAllocs checks that logging an error with Err
+// allocates at most maxAllocs times with log configured for
+// JSON output, so that hooks or fields added later cannot
+// silently make every entry more expensive:
+//  log.SetJSON(false)
+//  log.AddHook(sentryHook)
+//  testutil.AssertJSONAllocs(t, log, 30)
+//
+// Entries are discarded during the check and logging is
+// enabled; the output and enabled state are restored
+// afterwards.
+func AssertJSONAllocs(t testing.TB, log errorlogger.ErrorLogger, maxAllocs float64) {
+	t.Helper()
+	c := log.Config()
+	if c.Format != "json" {
+		t.Errorf("AssertJSONAllocs: logger format is %q, want json", c.Format)
+		return
+	}
+	if !c.Enabled {
+		defer log.Disable()
+	}
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(io.Discard)
+	log.Enable()
+
+	AssertAllocs(t, maxAllocs, func() { sink = log.Err(errPerf) })
+}
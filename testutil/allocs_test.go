@@ -0,0 +1,64 @@
+package testutil
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skeptycal/errorlogger"
+)
+
+// recordTB records the errors reported by a helper.
+type recordTB struct {
+	testing.TB
+	errs []string
+}
+
+func (r *recordTB) Helper() {}
+
+func (r *recordTB) Errorf(format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func TestAssertAllocs(t *testing.T) {
+	var keep []byte
+	tests := []struct {
+		name      string
+		max       float64
+		fn        func()
+		wantFails int
+	}{
+		{"none", 0, func() {}, 0},
+		{"within", 1, func() { keep = make([]byte, 64) }, 0},
+		{"over", 0, func() { keep = make([]byte, 64) }, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recordTB{TB: t}
+			AssertAllocs(r, tt.max, tt.fn)
+			if len(r.errs) != tt.wantFails {
+				t.Errorf("AssertAllocs() errors = %q, want %d", r.errs, tt.wantFails)
+			}
+		})
+	}
+	_ = keep
+}
+
+func TestAssertJSONAllocs(t *testing.T) {
+	log := errorlogger.NewWithOptions(false, "", nil, nil, logrus.New())
+	log.AddHook(nopHook{})
+
+	r := &recordTB{TB: t}
+	AssertJSONAllocs(r, log, 1000)
+	if len(r.errs) != 1 {
+		t.Errorf("AssertJSONAllocs() of a text logger errors = %q, want 1", r.errs)
+	}
+
+	log.SetJSON(false)
+	AssertErrNilAllocs(t, log)
+	AssertDisabledAllocs(t, log)
+	AssertJSONAllocs(t, log, 30)
+	if c := log.Config(); c.Enabled || c.Output != "stderr" {
+		t.Errorf("Config() after AssertJSONAllocs = %+v, want disabled stderr", c)
+	}
+}
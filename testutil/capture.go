@@ -0,0 +1,54 @@
+package testutil
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/skeptycal/errorlogger"
+)
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// CaptureOutput runs fn with the output of log swapped for a
+// buffer and returns what was written. The previous output
+// is restored afterwards, even if fn panics. Entries that
+// the previous output buffered before the swap are drained
+// to it first; see SwapOutput.
+//
+// Together with a formatter without timestamps, it makes
+// Example tests of logging practical:
+//  log.SetFormatter(&errorlogger.TextFormatter{TextFormatter: logrus.TextFormatter{DisableTimestamp: true}})
+//  out, _ := testutil.CaptureOutput(log, func() { log.Info("ready") })
+//  fmt.Print(out)
+//  // Output: level=info msg=ready
+func CaptureOutput(log errorlogger.ErrorLogger, fn func()) (out string, err error) {
+	var buf lockedBuffer
+	old := log.Writer()
+	if err := log.SwapOutput(old, &buf); err != nil {
+		return "", err
+	}
+	defer func() {
+		if restoreErr := log.SwapOutput(&buf, old); err == nil {
+			err = restoreErr
+		}
+		out = buf.String()
+	}()
+	fn()
+	return "", nil
+}
@@ -0,0 +1,59 @@
+package testutil
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skeptycal/errorlogger"
+)
+
+func newExampleLogger() errorlogger.ErrorLogger {
+	log := errorlogger.NewWithOptions(true, "", nil, nil, logrus.New())
+	log.SetFormatter(&errorlogger.TextFormatter{TextFormatter: logrus.TextFormatter{DisableTimestamp: true}})
+	return log
+}
+
+func ExampleCaptureOutput() {
+	log := newExampleLogger()
+	out, err := CaptureOutput(log, func() {
+		log.WithField("port", 8080).Info("listening")
+		_ = log.Err(io.ErrUnexpectedEOF)
+	})
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Print(out)
+	// Output:
+	// level=info msg=listening port=8080
+	// level=error msg="unexpected EOF"
+}
+
+func TestCaptureOutput(t *testing.T) {
+	log := newExampleLogger()
+	var orig strings.Builder
+	log.SetOutput(&orig)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("CaptureOutput() did not propagate the panic")
+			}
+		}()
+		CaptureOutput(log, func() { panic("boom") })
+	}()
+	if w := log.Writer(); w != &orig {
+		t.Fatalf("output after a panic = %T, want the original", w)
+	}
+
+	out, err := CaptureOutput(log, func() { log.Info("captured") })
+	if err != nil || !strings.Contains(out, "captured") {
+		t.Errorf("CaptureOutput() = %q, %v", out, err)
+	}
+	log.Info("after")
+	if got := orig.String(); strings.Contains(got, "captured") || !strings.Contains(got, "after") {
+		t.Errorf("original output = %q", got)
+	}
+}
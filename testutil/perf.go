@@ -0,0 +1,135 @@
+// Package testutil provides test helpers that verify the
+// performance guarantees of errorlogger against a logger
+// configured the way an application uses it.
+package testutil
+
+import (
+	"errors"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/skeptycal/errorlogger"
+)
+
+// PerfTimingEnv is the environment variable that turns on
+// the timing checks of PerfContract, e.g.
+//  ERRORLOGGER_PERF_TIMING=1 go test ./...
+//
+// Wall time depends on the machine and its load, so timing
+// is only checked on request, such as on a dedicated CI
+// runner; allocations are always checked.
+const PerfTimingEnv = "ERRORLOGGER_PERF_TIMING"
+
+// PerfBudget is the cost allowed per call on the fast paths
+// of a logger. MaxNsPerOp is only checked if PerfTimingEnv
+// is set.
+type PerfBudget struct {
+	MaxNsPerOp float64
+	MaxAllocs  float64
+}
+
+// DefaultPerfBudget is the guarantee of errorlogger for the
+// fast paths: Err(nil), and Err on a disabled logger, cost
+// single-digit nanoseconds and do not allocate, whatever
+// formatters, wrappers, and hooks are configured.
+var DefaultPerfBudget = PerfBudget{MaxNsPerOp: 10, MaxAllocs: 0}
+
+// perfRuns is the number of calls timed per measurement.
+const perfRuns = 1 << 20
+
+var (
+	errPerf = errors.New("testutil: perf contract")
+
+	// sink keeps the calls from being optimized away.
+	sink error
+)
+
+// PerfContract verifies that log meets DefaultPerfBudget.
+// Run it against the logger as configured by the
+// application, with its hooks attached, to check that the
+// setup did not break the fast path:
+//  func TestLoggerPerf(t *testing.T) {
+//      testutil.PerfContract(t, newAppLogger())
+//  }
+//
+// The logger is disabled during the check and enabled again
+// afterwards if it was enabled. Timing is checked only if
+// PerfTimingEnv is set and the race detector is off; use
+// BenchmarkFastPath to compare timings on one machine.
+func PerfContract(t testing.TB, log errorlogger.ErrorLogger) {
+	t.Helper()
+	PerfContractBudget(t, log, DefaultPerfBudget)
+}
+
+// PerfContractBudget is PerfContract with a custom budget.
+func PerfContractBudget(t testing.TB, log errorlogger.ErrorLogger, b PerfBudget) {
+	t.Helper()
+	if log.Config().Enabled {
+		defer log.Enable()
+	}
+
+	checks := []struct {
+		name string
+		fn   func()
+	}{
+		{"Err(nil)", func() { sink = log.Err(nil) }},
+		{"disabled Err", func() { sink = log.Err(errPerf) }},
+	}
+	for i, c := range checks {
+		if i == 1 {
+			log.Disable()
+		}
+		if allocs := testing.AllocsPerRun(allocRuns, c.fn); allocs > b.MaxAllocs {
+			t.Errorf("%s: %v allocs per call, budget %v", c.name, allocs, b.MaxAllocs)
+		}
+		if raceEnabled || os.Getenv(PerfTimingEnv) == "" {
+			continue
+		}
+		if ns := nsPerOp(c.fn); ns > b.MaxNsPerOp {
+			t.Errorf("%s: %.1f ns per call, budget %v", c.name, ns, b.MaxNsPerOp)
+		}
+	}
+}
+
+// BenchmarkFastPath benchmarks the fast paths checked by
+// PerfContract, for comparison with a baseline measured on
+// the same machine, e.g. with benchstat:
+//  func BenchmarkLogger(b *testing.B) {
+//      testutil.BenchmarkFastPath(b, newAppLogger())
+//  }
+func BenchmarkFastPath(b *testing.B, log errorlogger.ErrorLogger) {
+	if log.Config().Enabled {
+		defer log.Enable()
+	}
+	b.Run("Err(nil)", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sink = log.Err(nil)
+		}
+	})
+	log.Disable()
+	b.Run("disabled Err", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sink = log.Err(errPerf)
+		}
+	})
+}
+
+// nsPerOp returns the best of three timings of fn, in
+// nanoseconds per call.
+func nsPerOp(fn func()) float64 {
+	best := math.Inf(1)
+	for r := 0; r < 3; r++ {
+		start := time.Now()
+		for i := 0; i < perfRuns; i++ {
+			fn()
+		}
+		if ns := float64(time.Since(start).Nanoseconds()) / perfRuns; ns < best {
+			best = ns
+		}
+	}
+	return best
+}
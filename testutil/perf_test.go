@@ -0,0 +1,47 @@
+package testutil
+
+import (
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/skeptycal/errorlogger"
+)
+
+// nopHook is attached to check that hooks do not affect
+// the fast path.
+type nopHook struct{}
+
+func (nopHook) Levels() []logrus.Level   { return logrus.AllLevels }
+func (nopHook) Fire(*logrus.Entry) error { return nil }
+
+func TestPerfContract(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+	}{
+		{"enabled", true},
+		{"disabled", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := errorlogger.NewWithOptions(tt.enabled, "", nil, nil, logrus.New())
+			log.SetOutput(io.Discard)
+			log.SetJSON(false)
+			log.AddHook(nopHook{})
+			log.AddParallelHook(nopHook{})
+
+			PerfContract(t, log)
+			if got := log.Config().Enabled; got != tt.enabled {
+				t.Errorf("Enabled after PerfContract = %v, want %v", got, tt.enabled)
+			}
+		})
+	}
+}
+
+func BenchmarkPerfContract(b *testing.B) {
+	log := errorlogger.NewWithOptions(true, "", nil, nil, logrus.New())
+	log.SetOutput(io.Discard)
+	log.AddHook(nopHook{})
+	BenchmarkFastPath(b, log)
+}
@@ -99,6 +99,16 @@ var DefaultTextFormatter Formatter = NewTextFormatter()
 */
 type TextFormatter struct {
 	logrus.TextFormatter
+
+	// DisableSanitize keeps control characters and ANSI
+	// escape sequences in messages, keys, and values; see
+	// SanitizeControl.
+	DisableSanitize bool
+
+	// DisableHumanize writes time.Duration values with full
+	// precision instead of rounding them to three
+	// significant digits.
+	DisableHumanize bool
 }
 
 // NewTextFormatter returns a new TextFormatter that
@@ -206,6 +216,21 @@ func (f *TextFormatter) SetFieldMap(m logrus.FieldMap) {
 	f.FieldMap = m
 }
 
+// SetDisableSanitize allows users to disable the replacement of
+// control characters and ANSI escape sequences in messages, field
+// keys, and field values. Sanitizing prevents log injection through
+// user controlled strings and is enabled by default.
+func (f *TextFormatter) SetDisableSanitize(yesno bool) {
+	f.DisableSanitize = yesno
+}
+
+// SetDisableHumanize allows users to disable the rounding of
+// time.Duration field values to three significant digits,
+// e.g. 1.24s. Values are never rounded by JSONFormatter.
+func (f *TextFormatter) SetDisableHumanize(yesno bool) {
+	f.DisableHumanize = yesno
+}
+
 // SetCallerPrettyfier sets the user option to modify the content
 // of the function and file keys in the data when ReportCaller is
 // activated. If any of the returned values is the empty string the
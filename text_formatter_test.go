@@ -29,7 +29,7 @@ func TestNewTextFormatter(t *testing.T) {
 		name string
 		want Formatter
 	}{
-		{"new default JSON formatter", &TextFormatter{logrus.TextFormatter{}}},
+		{"new default JSON formatter", &TextFormatter{TextFormatter: logrus.TextFormatter{}}},
 	}
 	for _, tt := range tests {
 
@@ -0,0 +1,10 @@
+//go:build !tinygo
+
+package tiny
+
+import (
+	"io"
+	"os"
+)
+
+func defaultOutput() io.Writer { return os.Stderr }
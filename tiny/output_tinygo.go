@@ -0,0 +1,12 @@
+//go:build tinygo
+
+package tiny
+
+import (
+	"io"
+	"os"
+)
+
+// defaultOutput returns os.Stdout, which TinyGo connects to
+// the serial console of a microcontroller.
+func defaultOutput() io.Writer { return os.Stdout }
@@ -0,0 +1,234 @@
+// Package tiny is a minimal error logger for TinyGo and
+// other embedded targets. It imports neither logrus nor fmt
+// and writes fixed text lines:
+//  ERROR open config: file does not exist
+//
+// The full ErrorLogger interface of the errorlogger package
+// returns *logrus.Entry from WithField and friends, so it
+// cannot be implemented without logrus. Instead, ErrorLogger
+// in this package is the Err-centric subset that both
+// loggers implement, so a shared library can accept it and
+// log on microcontrollers and servers with one API:
+//  func Open(name string, log tiny.ErrorLogger) error {
+//  	// ...
+//  	return log.Err(err)
+//  }
+//
+// Pass a *tiny.Logger on embedded targets and an
+// errorlogger.ErrorLogger on servers.
+package tiny
+
+import (
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/skeptycal/errorlogger/internal/wraperr"
+)
+
+// Level is the severity of a line.
+type Level uint32
+
+// Levels, with the values of the levels of the same name in
+// the errorlogger package.
+const (
+	ErrorLevel Level = iota + 2
+	WarnLevel
+	InfoLevel
+	DebugLevel
+)
+
+// DefaultLogLevel is the level of a new Logger.
+const DefaultLogLevel = InfoLevel
+
+type (
+	// ErrorLogger is the subset of the errorlogger
+	// ErrorLogger interface implemented by Logger.
+	ErrorLogger interface {
+		Err(err error) error
+		Enable()
+		Disable()
+		SetErrorWrap(wrap error)
+
+		Error(args ...interface{})
+		Warn(args ...interface{})
+		Info(args ...interface{})
+		Debug(args ...interface{})
+	}
+
+	// Logger is a minimal ErrorLogger that writes one line
+	// per entry to an io.Writer. It is safe for concurrent
+	// use.
+	Logger struct {
+		mu       sync.Mutex
+		out      io.Writer
+		wrap     error
+		buf      []byte
+		level    uint32 // atomic
+		disabled uint32 // atomic
+	}
+)
+
+// New returns a new Logger, with logging enabled, that
+// writes to out. If out is nil, the default output is
+// used: os.Stdout, which is the serial console, on TinyGo,
+// and os.Stderr otherwise.
+func New(out io.Writer) *Logger {
+	if out == nil {
+		out = defaultOutput()
+	}
+	return &Logger{out: out, level: uint32(DefaultLogLevel)}
+}
+
+// Err logs err at ErrorLevel, wrapped with the error set by
+// SetErrorWrap, and returns it. Err(nil) returns nil. While
+// logging is disabled, err is returned unchanged.
+func (l *Logger) Err(err error) error {
+	if err == nil {
+		return nil
+	}
+	if atomic.LoadUint32(&l.disabled) == 1 {
+		return err
+	}
+	l.mu.Lock()
+	wrap := l.wrap
+	l.mu.Unlock()
+	if wrap != nil {
+		err = wraperr.New(err, wrap)
+	}
+	l.log(ErrorLevel, err.Error())
+	return err
+}
+
+// Enable enables logging.
+func (l *Logger) Enable() { atomic.StoreUint32(&l.disabled, 0) }
+
+// Disable disables logging; Err only returns its error.
+func (l *Logger) Disable() { atomic.StoreUint32(&l.disabled, 1) }
+
+// SetErrorWrap sets the error that wraps the errors logged
+// by Err. A nil wrap disables wrapping.
+func (l *Logger) SetErrorWrap(wrap error) {
+	l.mu.Lock()
+	l.wrap = wrap
+	l.mu.Unlock()
+}
+
+// SetLevel sets the least severe level written.
+func (l *Logger) SetLevel(level Level) { atomic.StoreUint32(&l.level, uint32(level)) }
+
+// SetOutput sets the output of the logger.
+func (l *Logger) SetOutput(out io.Writer) {
+	l.mu.Lock()
+	l.out = out
+	l.mu.Unlock()
+}
+
+// Error logs args at ErrorLevel.
+func (l *Logger) Error(args ...interface{}) { l.logArgs(ErrorLevel, args) }
+
+// Warn logs args at WarnLevel.
+func (l *Logger) Warn(args ...interface{}) { l.logArgs(WarnLevel, args) }
+
+// Info logs args at InfoLevel.
+func (l *Logger) Info(args ...interface{}) { l.logArgs(InfoLevel, args) }
+
+// Debug logs args at DebugLevel.
+func (l *Logger) Debug(args ...interface{}) { l.logArgs(DebugLevel, args) }
+
+func (l *Logger) logArgs(level Level, args []interface{}) {
+	if !l.enabled(level) {
+		return
+	}
+	l.log(level, sprint(args))
+}
+
+func (l *Logger) enabled(level Level) bool {
+	return atomic.LoadUint32(&l.disabled) == 0 && uint32(level) <= atomic.LoadUint32(&l.level)
+}
+
+// log writes a line with msg at level.
+func (l *Logger) log(level Level, msg string) {
+	if !l.enabled(level) {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf = append(l.buf[:0], level.String()...)
+	l.buf = append(l.buf, ' ')
+	l.buf = append(l.buf, msg...)
+	l.buf = append(l.buf, '\n')
+	_, _ = l.out.Write(l.buf)
+}
+
+// String returns the upper case name of the level.
+func (level Level) String() string {
+	switch level {
+	case ErrorLevel:
+		return "ERROR"
+	case WarnLevel:
+		return "WARN"
+	case InfoLevel:
+		return "INFO"
+	case DebugLevel:
+		return "DEBUG"
+	}
+	return "LEVEL(" + strconv.Itoa(int(level)) + ")"
+}
+
+// sprint formats args like fmt.Sprint for strings, errors,
+// Stringers, booleans, and numbers, without reflection.
+// Values of other types are written as "?".
+func sprint(args []interface{}) string {
+	var b []byte
+	for i, arg := range args {
+		_, isString := arg.(string)
+		if i > 0 && !isString {
+			if _, prevString := args[i-1].(string); !prevString {
+				b = append(b, ' ')
+			}
+		}
+		switch v := arg.(type) {
+		case string:
+			b = append(b, v...)
+		case error:
+			b = append(b, v.Error()...)
+		case interface{ String() string }:
+			b = append(b, v.String()...)
+		case bool:
+			b = strconv.AppendBool(b, v)
+		case int:
+			b = strconv.AppendInt(b, int64(v), 10)
+		case int8:
+			b = strconv.AppendInt(b, int64(v), 10)
+		case int16:
+			b = strconv.AppendInt(b, int64(v), 10)
+		case int32:
+			b = strconv.AppendInt(b, int64(v), 10)
+		case int64:
+			b = strconv.AppendInt(b, v, 10)
+		case uint:
+			b = strconv.AppendUint(b, uint64(v), 10)
+		case uint8:
+			b = strconv.AppendUint(b, uint64(v), 10)
+		case uint16:
+			b = strconv.AppendUint(b, uint64(v), 10)
+		case uint32:
+			b = strconv.AppendUint(b, uint64(v), 10)
+		case uint64:
+			b = strconv.AppendUint(b, v, 10)
+		case float32:
+			b = strconv.AppendFloat(b, float64(v), 'g', -1, 32)
+		case float64:
+			b = strconv.AppendFloat(b, v, 'g', -1, 64)
+		case nil:
+			b = append(b, "<nil>"...)
+		default:
+			b = append(b, '?')
+		}
+	}
+	return string(b)
+}
+
+var _ ErrorLogger = (*Logger)(nil)
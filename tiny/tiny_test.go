@@ -0,0 +1,59 @@
+package tiny
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/skeptycal/errorlogger"
+)
+
+var _ ErrorLogger = errorlogger.New()
+
+var errSvc = errors.New("svc")
+
+func TestLogger(t *testing.T) {
+	tests := []struct {
+		name    string
+		log     func(l *Logger) error
+		want    string
+		wantErr string
+	}{
+		{"err", func(l *Logger) error { return l.Err(errors.New("boom")) }, "ERROR boom\n", "boom"},
+		{"nil err", func(l *Logger) error { return l.Err(nil) }, "", ""},
+		{"wrap", func(l *Logger) error { l.SetErrorWrap(errSvc); return l.Err(errors.New("boom")) }, "ERROR svc: boom\n", "svc: boom"},
+		{"zero wrap", func(l *Logger) error { l.SetErrorWrap(&os.PathError{}); return l.Err(errors.New("boom")) }, "ERROR boom\n", "boom"},
+		{"disabled", func(l *Logger) error { l.Disable(); l.Error("x"); return l.Err(errors.New("boom")) }, "", "boom"},
+		{"enabled", func(l *Logger) error { l.Disable(); l.Enable(); l.Warn("x"); return nil }, "WARN x\n", ""},
+		{"level", func(l *Logger) error { l.Debug("hidden"); l.SetLevel(DebugLevel); l.Debug("shown"); return nil }, "DEBUG shown\n", ""},
+		{"sprint", func(l *Logger) error { l.Info("n=", 1, 2, true, 1.5, nil, struct{}{}, ErrorLevel); return nil }, "INFO n=1 2 true 1.5 <nil> ? ERROR\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := tt.log(New(&buf))
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.wantErr {
+				t.Errorf("error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+
+	l := New(&bytes.Buffer{})
+	l.SetErrorWrap(errSvc)
+	if err := l.Err(errors.New("boom")); !errors.Is(err, errSvc) || errors.Unwrap(err).Error() != "boom" {
+		t.Errorf("Err() = %v, want it to match the wrap and unwrap to the original error", err)
+	}
+	l.SetErrorWrap(&os.PathError{})
+	var pathErr *os.PathError
+	if err := l.Err(errors.New("boom")); !errors.As(err, &pathErr) {
+		t.Errorf("Err() = %v, want errors.As to find the wrap", err)
+	}
+}
@@ -0,0 +1,78 @@
+package errorlogger
+
+import (
+	"strconv"
+	"time"
+)
+
+// ByteSize is a size in bytes. Fields of this type are
+// written by TextFormatter in binary units, such as 3.1MiB,
+// and by JSONFormatter as the raw number of bytes:
+//  log.WithField("body", errorlogger.ByteSize(n)).Warn("request too large")
+type ByteSize int64
+
+// String returns the size in the largest binary unit with an
+// integer part of at least one, with one decimal, e.g.
+// "512B", "2KiB", or "3.1MiB".
+func (s ByteSize) String() string {
+	const units = "KMGTPE"
+	n := int64(s)
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	if n < 1024 {
+		return sign + strconv.FormatInt(n, 10) + "B"
+	}
+	v, u := float64(n)/1024, 0
+	for v >= 1024 && u < len(units)-1 {
+		v /= 1024
+		u++
+	}
+	num := strconv.FormatFloat(v, 'f', 1, 64)
+	if len(num) > 2 && num[len(num)-2:] == ".0" {
+		num = num[:len(num)-2]
+	}
+	return sign + num + units[u:u+1] + "iB"
+}
+
+// roundDuration returns d rounded to three significant
+// digits, so that 1.243456789s is written as 1.24s.
+func roundDuration(d time.Duration) time.Duration {
+	m := d
+	if m < 0 {
+		m = -m
+	}
+	unit := time.Duration(1)
+	for m >= 1000 {
+		m /= 10
+		unit *= 10
+	}
+	return d.Round(unit)
+}
+
+// humanizeEntry returns entry, or a copy of it with the
+// time.Duration field values rounded by roundDuration and
+// written as strings.
+func humanizeEntry(entry *Entry) *Entry {
+	var c *Entry
+	for k, v := range entry.Data {
+		d, ok := v.(time.Duration)
+		if !ok {
+			continue
+		}
+		if c == nil {
+			e := *entry
+			e.Data = make(Fields, len(entry.Data))
+			for k, v := range entry.Data {
+				e.Data[k] = v
+			}
+			c = &e
+		}
+		c.Data[k] = roundDuration(d).String()
+	}
+	if c == nil {
+		return entry
+	}
+	return c
+}
@@ -0,0 +1,90 @@
+package errorlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestByteSize_String(t *testing.T) {
+	tests := []struct {
+		size ByteSize
+		want string
+	}{
+		{0, "0B"},
+		{512, "512B"},
+		{1023, "1023B"},
+		{1024, "1KiB"},
+		{2048, "2KiB"},
+		{1536, "1.5KiB"},
+		{3250586, "3.1MiB"},
+		{5 << 30, "5GiB"},
+		{-2048, "-2KiB"},
+		{1 << 62, "4EiB"},
+	}
+	for _, tt := range tests {
+		if got := tt.size.String(); got != tt.want {
+			t.Errorf("ByteSize(%d).String() = %q, want %q", int64(tt.size), got, tt.want)
+		}
+	}
+}
+
+func Test_roundDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{999, "999ns"},
+		{1243456789, "1.24s"},
+		{1234567, "1.23ms"},
+		{-1243456789, "-1.24s"},
+		{90 * time.Minute, "1h30m0s"},
+		{1500 * time.Millisecond, "1.5s"},
+	}
+	for _, tt := range tests {
+		if got := roundDuration(tt.d).String(); got != tt.want {
+			t.Errorf("roundDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatter_units(t *testing.T) {
+	fields := Fields{"took": 1243456789 * time.Nanosecond, "size": ByteSize(3250586)}
+	tests := []struct {
+		name string
+		f    Formatter
+		want []string
+	}{
+		{"text", &TextFormatter{}, []string{"took=1.24s", "size=3.1MiB"}},
+		{"text precise", &TextFormatter{DisableHumanize: true}, []string{"took=1.243456789s", "size=3.1MiB"}},
+		{"json", NewJSONFormatter(false), []string{`"took":1243456789`, `"size":3250586`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, buf := newBufferLogger(InfoLevel)
+			e.SetFormatter(tt.f)
+			e.WithFields(fields).Info("done")
+			for _, w := range tt.want {
+				if !strings.Contains(buf.String(), w) {
+					t.Errorf("output = %q, want %q", buf.String(), w)
+				}
+			}
+			if _, ok := tt.f.(*JSONFormatter); ok {
+				var m map[string]interface{}
+				if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &m); err != nil {
+					t.Fatal(err)
+				}
+				if _, ok := m["took"].(float64); !ok {
+					t.Errorf("took = %#v, want a number", m["took"])
+				}
+			}
+		})
+	}
+
+	if d, ok := fields["took"].(time.Duration); !ok || d != 1243456789 {
+		t.Errorf("fields[took] = %v, want the field unchanged", fields["took"])
+	}
+}
@@ -0,0 +1,22 @@
+package errorlogger
+
+import "sync"
+
+// warned records the keys passed to WarnOnce.
+var warned sync.Map
+
+// WarnOnce logs a warning with the global logger the first
+// time it is called with key and does nothing on later
+// calls, regardless of how often or from how many
+// goroutines it is called.
+//
+// It is intended for libraries that embed errorlogger and
+// want to surface misconfiguration exactly once per
+// process:
+//  errorlogger.WarnOnce("mylib.timeout", "mylib: timeout %v is too short; using %v", cfg.Timeout, minTimeout)
+func WarnOnce(key, format string, args ...interface{}) {
+	if _, loaded := warned.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+	Log.WithField("warn_once", key).Warnf(format, args...)
+}
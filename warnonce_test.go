@@ -0,0 +1,35 @@
+package errorlogger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWarnOnce(t *testing.T) {
+	var buf bytes.Buffer
+	l := Log.(*errorLogger)
+	out := l.output()
+	l.SetOutput(&buf)
+	defer l.SetOutput(out)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			WarnOnce("test.once", "misconfigured %d", i)
+		}(i)
+	}
+	wg.Wait()
+	WarnOnce("test.other", "other warning")
+
+	got := buf.String()
+	if n := strings.Count(got, "misconfigured"); n != 1 {
+		t.Errorf("WarnOnce() logged %d times, want 1:\n%s", n, got)
+	}
+	if !strings.Contains(got, "other warning") || !strings.Contains(got, "warn_once=test.other") {
+		t.Errorf("WarnOnce() with a new key = %q, want warning", got)
+	}
+}
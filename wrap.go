@@ -0,0 +1,53 @@
+package errorlogger
+
+import (
+	"errors"
+
+	"github.com/skeptycal/errorlogger/internal/wraperr"
+)
+
+// wrapError is an error wrapped by the error type set with
+// SetErrorWrap. It satisfies errors.Is and errors.As for
+// both the wrap and the original error, so that
+//  log.SetErrorWrap(&os.PathError{})
+//  var pathErr *os.PathError
+//  errors.As(log.Err(err), &pathErr) // true
+// holds while err can still be recovered with errors.As or
+// errors.Unwrap.
+type wrapError struct {
+	err  error
+	wrap error
+}
+
+// wrapWith returns err wrapped by wrap.
+func wrapWith(err, wrap error) error {
+	return &wrapError{err: err, wrap: wrap}
+}
+
+// Error returns the message of the wrap followed by that of
+// the original error. A wrap without a message, such as
+// &os.PathError{}, adds nothing.
+func (w *wrapError) Error() string {
+	if msg := errorMessage(w.wrap); msg != "" {
+		return msg + ": " + w.err.Error()
+	}
+	return w.err.Error()
+}
+
+// Unwrap returns the original error.
+func (w *wrapError) Unwrap() error { return w.err }
+
+// Cause returns the original error, for code that still
+// uses errors.Cause from github.com/pkg/errors.
+func (w *wrapError) Cause() error { return w.err }
+
+// Is reports whether the wrap matches target.
+func (w *wrapError) Is(target error) bool { return errors.Is(w.wrap, target) }
+
+// As sets target to the wrap if it can be assigned to it.
+func (w *wrapError) As(target interface{}) bool { return errors.As(w.wrap, target) }
+
+// errorMessage returns err.Error(), or "" if it panics, as
+// the Error methods of zero values such as &os.PathError{}
+// do.
+func errorMessage(err error) string { return wraperr.Message(err) }
@@ -0,0 +1,93 @@
+package errorlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestErrorLogger_SetErrorWrap_as(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrap    error
+		wantMsg string
+	}{
+		{"syscall error", fakeSysCallError, "fake syscall error: fake syscall error: fake"},
+		{"blank syscall error", blankSysCallError, "fake"},
+		{"blank path error", blankPathError, "fake"},
+		{"os path error", &os.PathError{Op: "open", Path: "/x", Err: os.ErrNotExist}, "open /x: file does not exist: fake"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newBufferLogger(InfoLevel)
+			e.SetErrorWrap(tt.wrap)
+			err := e.Err(errFake)
+
+			if err.Error() != tt.wantMsg {
+				t.Errorf("Err() = %q, want %q", err, tt.wantMsg)
+			}
+			if !errors.Is(err, errFake) {
+				t.Error("errors.Is(Err(), original) = false")
+			}
+			if !errors.Is(err, tt.wrap) {
+				t.Error("errors.Is(Err(), wrap) = false")
+			}
+
+			var syscallErr *SyscallError
+			var pathErr *PathError
+			var osPathErr *os.PathError
+			switch tt.wrap.(type) {
+			case *SyscallError:
+				if !errors.As(err, &syscallErr) || syscallErr != tt.wrap {
+					t.Errorf("errors.As(Err(), *SyscallError) = %v", syscallErr)
+				}
+			case *PathError:
+				if !errors.As(err, &pathErr) || pathErr != tt.wrap {
+					t.Errorf("errors.As(Err(), *PathError) = %v", pathErr)
+				}
+			case *os.PathError:
+				if !errors.As(err, &osPathErr) || osPathErr != tt.wrap {
+					t.Errorf("errors.As(Err(), *os.PathError) = %v", osPathErr)
+				}
+			}
+			if errors.Unwrap(err) != errFake {
+				t.Errorf("errors.Unwrap(Err()) = %v, want the original error", errors.Unwrap(err))
+			}
+		})
+	}
+}
+
+func TestErrorLogger_SetErrorWrap_chain(t *testing.T) {
+	errService := errors.New("service unavailable")
+	pathErr := &os.PathError{Op: "open", Path: "/x", Err: os.ErrNotExist}
+	orig := fmt.Errorf("load config: %w", pathErr)
+
+	e, _ := newBufferLogger(InfoLevel)
+	e.SetErrorWrap(errService)
+	err := e.Err(orig)
+
+	tests := []struct {
+		name string
+		got  bool
+	}{
+		{"Is wrap", errors.Is(err, errService)},
+		{"Is original", errors.Is(err, orig)},
+		{"Is deep in original chain", errors.Is(err, os.ErrNotExist)},
+		{"Is not unrelated", !errors.Is(err, os.ErrExist)},
+		{"Unwrap", errors.Unwrap(err) == orig},
+	}
+	for _, tt := range tests {
+		if !tt.got {
+			t.Errorf("%s = false for %v", tt.name, err)
+		}
+	}
+
+	var target *os.PathError
+	if !errors.As(err, &target) || target != pathErr {
+		t.Errorf("errors.As(Err(), *os.PathError) = %v, want the error in the original chain", target)
+	}
+	if got, want := err.Error(), "service unavailable: load config: open /x: file does not exist"; got != want {
+		t.Errorf("Err() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package errorlogger
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// WrapAuditKey is the field that holds the kind of problem,
+// "type" or "sentinel", of the warnings logged by the wrap
+// audit of the development preset.
+const WrapAuditKey = "wrap_audit"
+
+// wrapAuditWarned records the call sites and problems that
+// were already warned about.
+var wrapAuditWarned sync.Map
+
+// auditWrap warns, once per call site and problem, if
+// wrapping orig as wrapped changed its concrete type, which
+// breaks type switches and assertions on the returned error,
+// or broke errors.Is for a sentinel registered with
+// NewSentinel. It runs with the development preset, where
+// such propagation bugs are cheap to fix.
+func (e *errorLogger) auditWrap(orig, wrapped error) {
+	var site string
+	warn := func(problem, key string, fields Fields) {
+		if site == "" {
+			site = callSite()
+		}
+		if _, loaded := wrapAuditWarned.LoadOrStore(site+"\x00"+problem+"\x00"+key, true); loaded {
+			return
+		}
+		fields[WrapAuditKey] = problem
+		fields["call_site"] = site
+		e.WithFields(fields).Warn("error wrap changes how the returned error matches")
+	}
+
+	from, to := reflect.TypeOf(orig), reflect.TypeOf(wrapped)
+	if from != to {
+		warn("type", from.String(), Fields{
+			"from_type": from.String(),
+			"to_type":   to.String(),
+		})
+	}
+
+	codesMu.RLock()
+	sentinels := make([]*Sentinel, 0, len(codes))
+	for _, s := range codes {
+		sentinels = append(sentinels, s)
+	}
+	codesMu.RUnlock()
+	for _, s := range sentinels {
+		if errors.Is(orig, s) && !errors.Is(wrapped, s) {
+			warn("sentinel", s.code, Fields{
+				"sentinel": fmt.Sprintf("%s (%s)", s.code, s.msg),
+			})
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package errorlogger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// opaqueError hides the error it was created from.
+type opaqueError struct{ msg string }
+
+func (e opaqueError) Error() string { return e.msg }
+
+func TestErrorLogger_auditWrap(t *testing.T) {
+	defer func() { wrapAuditWarned = sync.Map{} }()
+
+	e, buf := newBufferLogger(InfoLevel)
+	e.SetErrorWrap(fakeSysCallError)
+
+	_ = e.Err(errTestSentinel) // production: no audit
+	if strings.Contains(buf.String(), WrapAuditKey) {
+		t.Fatalf("audit without the development preset: %q", buf.String())
+	}
+
+	e.SetPreset(Preset{Level: InfoLevel, Development: true})
+	for i := 0; i < 2; i++ {
+		_ = e.Err(errTestSentinel)
+	}
+	out := buf.String()
+	if n := strings.Count(out, "wrap_audit=type"); n != 1 {
+		t.Errorf("logged %d type warnings, want 1: %q", n, out)
+	}
+	for _, want := range []string{`from_type="*errorlogger.Sentinel"`, `to_type="*errorlogger.wrapError"`, "call_site=", "wrapaudit_test.go"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+	}
+	if strings.Contains(out, "wrap_audit=sentinel") {
+		t.Errorf("sentinel warning for a wrap that preserves errors.Is: %q", out)
+	}
+
+	buf.Reset()
+	orig := fmt.Errorf("load: %w", errTestSentinel)
+	e.auditWrap(orig, opaqueError{orig.Error()})
+	if out := buf.String(); !strings.Contains(out, "wrap_audit=sentinel") || !strings.Contains(out, "ETEST1") {
+		t.Errorf("output = %q, want a sentinel warning", out)
+	}
+}
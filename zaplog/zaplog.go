@@ -0,0 +1,106 @@
+// Package zaplog is an error logger that writes to a zap
+// Logger, for teams that have standardized on zap:
+//  log := zaplog.NewWithZap(zapLogger)
+//  log.SetErrorWrap(ErrService)
+//  return log.Err(err) // written by zapLogger at ErrorLevel
+//
+// It does not import the errorlogger package, so programs
+// using it link zap but not logrus. Its Logger implements
+// tiny.ErrorLogger, the Err-centric subset of the
+// errorlogger ErrorLogger interface, so that a library
+// accepting it can be used with either.
+package zaplog
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/skeptycal/errorlogger/internal/wraperr"
+	"github.com/skeptycal/errorlogger/tiny"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type (
+	// Logger is an error logger that writes its entries to
+	// a zap Logger, whose level, output, and encoding
+	// apply. It is safe for concurrent use.
+	Logger struct {
+		l        *zap.Logger
+		mu       sync.Mutex
+		wrap     error
+		disabled uint32 // atomic
+	}
+)
+
+// NewWithZap returns a new Logger, with logging enabled,
+// that writes its entries to l. If l is nil, zap.L() is
+// used.
+func NewWithZap(l *zap.Logger) *Logger {
+	if l == nil {
+		l = zap.L()
+	}
+	return &Logger{l: l}
+}
+
+// Err logs err at ErrorLevel, wrapped with the error set by
+// SetErrorWrap, and returns it. Err(nil) returns nil. While
+// logging is disabled, err is returned unchanged.
+func (l *Logger) Err(err error) error {
+	if err == nil {
+		return nil
+	}
+	if atomic.LoadUint32(&l.disabled) == 1 {
+		return err
+	}
+	l.mu.Lock()
+	wrap := l.wrap
+	l.mu.Unlock()
+	if wrap != nil {
+		err = wraperr.New(err, wrap)
+	}
+	l.l.Error(err.Error())
+	return err
+}
+
+// Enable enables logging.
+func (l *Logger) Enable() { atomic.StoreUint32(&l.disabled, 0) }
+
+// Disable disables logging; Err only returns its error.
+func (l *Logger) Disable() { atomic.StoreUint32(&l.disabled, 1) }
+
+// SetErrorWrap sets the error that wraps the errors logged
+// by Err. A nil wrap disables wrapping.
+func (l *Logger) SetErrorWrap(wrap error) {
+	l.mu.Lock()
+	l.wrap = wrap
+	l.mu.Unlock()
+}
+
+// Zap returns the zap Logger written to.
+func (l *Logger) Zap() *zap.Logger { return l.l }
+
+// Error logs args at ErrorLevel.
+func (l *Logger) Error(args ...interface{}) { l.log(zapcore.ErrorLevel, args) }
+
+// Warn logs args at WarnLevel.
+func (l *Logger) Warn(args ...interface{}) { l.log(zapcore.WarnLevel, args) }
+
+// Info logs args at InfoLevel.
+func (l *Logger) Info(args ...interface{}) { l.log(zapcore.InfoLevel, args) }
+
+// Debug logs args at DebugLevel.
+func (l *Logger) Debug(args ...interface{}) { l.log(zapcore.DebugLevel, args) }
+
+// log writes args, formatted like fmt.Sprint, at level.
+func (l *Logger) log(level zapcore.Level, args []interface{}) {
+	if atomic.LoadUint32(&l.disabled) == 1 || !l.l.Core().Enabled(level) {
+		return
+	}
+	if ce := l.l.Check(level, fmt.Sprint(args...)); ce != nil {
+		ce.Write()
+	}
+}
+
+var _ tiny.ErrorLogger = (*Logger)(nil)
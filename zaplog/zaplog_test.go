@@ -0,0 +1,71 @@
+package zaplog
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var errSvc = errors.New("svc")
+
+func TestNewWithZap(t *testing.T) {
+	tests := []struct {
+		name    string
+		log     func(l *Logger) error
+		level   zapcore.Level
+		msg     string
+		wantErr string
+	}{
+		{"err", func(l *Logger) error { return l.Err(errors.New("boom")) }, zapcore.ErrorLevel, "boom", "boom"},
+		{"wrap", func(l *Logger) error { l.SetErrorWrap(errSvc); return l.Err(errors.New("boom")) }, zapcore.ErrorLevel, "svc: boom", "svc: boom"},
+		{"zero wrap", func(l *Logger) error { l.SetErrorWrap(&os.PathError{}); return l.Err(errors.New("boom")) }, zapcore.ErrorLevel, "boom", "boom"},
+		{"warn", func(l *Logger) error { l.Warn("slow ", 3); return nil }, zapcore.WarnLevel, "slow 3", ""},
+		{"enabled", func(l *Logger) error { l.Disable(); l.Enable(); l.Info("up"); return nil }, zapcore.InfoLevel, "up", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			err := tt.log(NewWithZap(zap.New(core)))
+
+			entries := logs.AllUntimed()
+			if len(entries) != 1 {
+				t.Fatalf("zap entries = %v, want 1", entries)
+			}
+			if got := entries[0]; got.Level != tt.level || got.Message != tt.msg {
+				t.Errorf("zap entry = %v %q, want %v %q", got.Level, got.Message, tt.level, tt.msg)
+			}
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.wantErr {
+				t.Errorf("error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	l := NewWithZap(zap.New(core))
+	l.SetErrorWrap(errSvc)
+	l.Debug("below level")
+	l.Disable()
+	if err := l.Err(errors.New("boom")); err == nil || errors.Is(err, errSvc) {
+		t.Errorf("Err() while disabled = %v, want the error unchanged", err)
+	}
+	l.Enable()
+	if err := l.Err(errors.New("boom")); !errors.Is(err, errSvc) {
+		t.Errorf("Err() = %v, want it to wrap errSvc", err)
+	}
+	l.SetErrorWrap(&os.PathError{})
+	var pathErr *os.PathError
+	if err := l.Err(errors.New("boom")); !errors.As(err, &pathErr) {
+		t.Errorf("Err() = %v, want errors.As to find the wrap", err)
+	}
+	if logs.Len() != 2 {
+		t.Errorf("zap entries = %v, want 2", logs.AllUntimed())
+	}
+}